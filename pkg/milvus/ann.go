@@ -0,0 +1,333 @@
+package milvus
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/scigolib/hdf5"
+)
+
+// annReadElements bounds the values read from the file at once, as the HDF5 reader returns float64
+const annReadElements = 1 << 20
+
+// annMetricTypes maps the ann-benchmarks distance attribute to the Milvus metric type
+var annMetricTypes = map[string]string{
+	"angular":   "COSINE",
+	"cosine":    "COSINE",
+	"euclidean": "L2",
+	"l2":        "L2",
+	"dot":       "IP",
+	"ip":        "IP",
+	"hamming":   "HAMMING",
+	"jaccard":   "JACCARD",
+}
+
+// annShape matches the shape of a 2D dataset in hdf5.Dataset.Info(), e.g. "2D array [10000 x 128]"
+var annShape = regexp.MustCompile(`2D array \[(\d+) x (\d+)\]`)
+
+// AnnDatasetOptions configures milvus.annDataset()
+type AnnDatasetOptions struct {
+	Distance string `json:"distance,omitempty"` // Overrides the distance of the file, e.g. "angular"
+}
+
+// AnnDataset is a dataset in the ann-benchmarks HDF5 layout: train vectors to insert, test query
+// vectors, and for each query the row indices of its true nearest neighbors and their distances.
+// Neighbors are train row indices, so insert train row i with primary key i.
+//
+// A dataset is loaded once per test and shared by all VUs. Its arrays must be treated as read-only.
+//
+// Usage in k6:
+//
+//	const ds = milvus.annDataset('data/glove-100-angular.hdf5');
+//	export default function () {
+//	    const q = exec.scenario.iterationInTest % ds.testSize();
+//	    client.search(ds.test(q, 1), 10, { vectorField: 'embedding', metricType: ds.metricType(), groundTruth: ds.neighbors(q, 1, 10) });
+//	}
+type AnnDataset struct {
+	train     [][]float32
+	test      [][]float32
+	neighbors [][]int64
+	distances [][]float32
+	distance  string
+}
+
+// AnnDataset loads an ann-benchmarks HDF5 file, e.g. sift-128-euclidean.hdf5 or
+// glove-100-angular.hdf5. Relative paths are resolved against the working directory of the
+// k6 process. The train, test and neighbors datasets are required; distances is optional.
+func (m *Milvus) AnnDataset(path string, optionsInput ...interface{}) (*AnnDataset, error) {
+	var options AnnDatasetOptions
+	if len(optionsInput) > 0 && optionsInput[0] != nil {
+		if err := convertViaJSON(optionsInput[0], &options); err != nil {
+			return nil, fmt.Errorf("invalid ann dataset options: %v", err)
+		}
+	}
+
+	path, err := localDataset(m.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	dataset, err := sharedValue(m.shared, filepath.Clean(path), func() (*AnnDataset, error) {
+		return loadAnnDataset(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Distance != "" {
+		// Share the loaded arrays, not the distance
+		copied := *dataset
+		copied.distance = options.Distance
+		dataset = &copied
+	}
+	return dataset, nil
+}
+
+func loadAnnDataset(path string) (*AnnDataset, error) {
+	file, err := hdf5.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open HDF5 file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	datasets := make(map[string]*hdf5.Dataset)
+	for _, child := range file.Root().Children() {
+		if dataset, ok := child.(*hdf5.Dataset); ok {
+			datasets[dataset.Name()] = dataset
+		}
+	}
+	for _, name := range []string{"train", "test", "neighbors"} {
+		if datasets[name] == nil {
+			return nil, fmt.Errorf("HDF5 file %s has no %q dataset", path, name)
+		}
+	}
+
+	ds := &AnnDataset{distance: annDistance(file, path)}
+	if ds.train, err = readAnnFloats(datasets["train"]); err != nil {
+		return nil, fmt.Errorf("HDF5 file %s: %v", path, err)
+	}
+	if ds.test, err = readAnnFloats(datasets["test"]); err != nil {
+		return nil, fmt.Errorf("HDF5 file %s: %v", path, err)
+	}
+	if ds.neighbors, err = readAnnInts(datasets["neighbors"]); err != nil {
+		return nil, fmt.Errorf("HDF5 file %s: %v", path, err)
+	}
+	if datasets["distances"] != nil {
+		if ds.distances, err = readAnnFloats(datasets["distances"]); err != nil {
+			return nil, fmt.Errorf("HDF5 file %s: %v", path, err)
+		}
+	}
+
+	if ds.Dimension() != len(firstRow(ds.test)) {
+		return nil, fmt.Errorf("HDF5 file %s: train dimension %d does not match test dimension %d",
+			path, ds.Dimension(), len(firstRow(ds.test)))
+	}
+	if len(ds.neighbors) != len(ds.test) {
+		return nil, fmt.Errorf("HDF5 file %s: %d neighbors rows for %d test vectors", path, len(ds.neighbors), len(ds.test))
+	}
+	return ds, nil
+}
+
+// annDistance returns the distance attribute of the file. When it is missing or unreadable, the
+// distance is taken from the ann-benchmarks file name, e.g. glove-100-angular.hdf5.
+func annDistance(file *hdf5.File, path string) string {
+	if attributes, err := file.Root().Attributes(); err == nil {
+		for _, attribute := range attributes {
+			if attribute.Name != "distance" {
+				continue
+			}
+			if value, err := attribute.ReadValue(); err == nil {
+				if distance, ok := value.(string); ok && distance != "" {
+					return distance
+				}
+			}
+		}
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	suffix := name[strings.LastIndex(name, "-")+1:]
+	if _, known := annMetricTypes[suffix]; known {
+		return suffix
+	}
+	return ""
+}
+
+// annDatasetShape returns the rows and columns of a 2D dataset
+func annDatasetShape(dataset *hdf5.Dataset) (int, int, error) {
+	info, err := dataset.Info()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read %q dataset: %v", dataset.Name(), err)
+	}
+	match := annShape.FindStringSubmatch(info)
+	if match == nil {
+		return 0, 0, fmt.Errorf("%q dataset must be a 2D array, got %s", dataset.Name(), info)
+	}
+	rows, _ := strconv.Atoi(match[1])
+	cols, _ := strconv.Atoi(match[2])
+	return rows, cols, nil
+}
+
+// readAnnRows reads a rows x cols dataset in blocks of rows, calling set for each value
+func readAnnRows(dataset *hdf5.Dataset, rows, cols int, set func(row, col int, value float64)) error {
+	block := max(annReadElements/max(cols, 1), 1)
+	for start := 0; start < rows; start += block {
+		count := min(block, rows-start)
+		data, err := dataset.ReadSlice([]uint64{uint64(start), 0}, []uint64{uint64(count), uint64(cols)})
+		if err != nil {
+			return fmt.Errorf("failed to read %q dataset: %v", dataset.Name(), err)
+		}
+		values, ok := data.([]float64)
+		if !ok || len(values) != count*cols {
+			return fmt.Errorf("failed to read %q dataset: unexpected %T", dataset.Name(), data)
+		}
+		for i, value := range values {
+			set(start+i/cols, i%cols, value)
+		}
+	}
+	return nil
+}
+
+func readAnnFloats(dataset *hdf5.Dataset) ([][]float32, error) {
+	rows, cols, err := annDatasetShape(dataset)
+	if err != nil {
+		return nil, err
+	}
+	out := newMatrix[float32](rows, cols)
+	err = readAnnRows(dataset, rows, cols, func(row, col int, value float64) { out[row][col] = float32(value) })
+	return out, err
+}
+
+func readAnnInts(dataset *hdf5.Dataset) ([][]int64, error) {
+	rows, cols, err := annDatasetShape(dataset)
+	if err != nil {
+		return nil, err
+	}
+	out := newMatrix[int64](rows, cols)
+	err = readAnnRows(dataset, rows, cols, func(row, col int, value float64) { out[row][col] = int64(value) })
+	return out, err
+}
+
+// newMatrix allocates rows x cols values in one block
+func newMatrix[T any](rows, cols int) [][]T {
+	return newMatrixFrom(make([]T, rows*cols), rows, cols)
+}
+
+// newMatrixFrom splits a block of rows x cols values into rows
+func newMatrixFrom[T any](backing []T, rows, cols int) [][]T {
+	matrix := make([][]T, rows)
+	for i := range matrix {
+		matrix[i] = backing[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return matrix
+}
+
+func firstRow[T any](matrix [][]T) []T {
+	if len(matrix) == 0 {
+		return nil
+	}
+	return matrix[0]
+}
+
+// rowIndices returns the row indices in [start, end)
+func rowIndices(start, end int) []int64 {
+	ids := make([]int64, end-start)
+	for i := range ids {
+		ids[i] = int64(start + i)
+	}
+	return ids
+}
+
+// rowRange clamps [offset, offset+count) to n rows
+func rowRange(n, offset, count int) (int, int, error) {
+	if offset < 0 || count < 0 {
+		return 0, 0, fmt.Errorf("offset and count must not be negative, got %d and %d", offset, count)
+	}
+	start := min(offset, n)
+	return start, start + min(count, n-start), nil
+}
+
+// Dimension returns the vector dimension
+func (d *AnnDataset) Dimension() int {
+	return len(firstRow(d.train))
+}
+
+// Distance returns the distance of the dataset, e.g. "angular" or "euclidean"
+func (d *AnnDataset) Distance() string {
+	return d.distance
+}
+
+// MetricType returns the Milvus metric type matching the distance, or "" when it is unknown
+func (d *AnnDataset) MetricType() string {
+	return annMetricTypes[d.distance]
+}
+
+// memorySize returns the bytes of the dataset's vectors, neighbors and distances
+func (d *AnnDataset) memorySize() int64 {
+	return matrixBytes(d.train) + matrixBytes(d.test) + matrixBytes(d.neighbors) + matrixBytes(d.distances)
+}
+
+// TrainSize returns the number of train vectors
+func (d *AnnDataset) TrainSize() int {
+	return len(d.train)
+}
+
+// TestSize returns the number of test query vectors
+func (d *AnnDataset) TestSize() int {
+	return len(d.test)
+}
+
+// Train returns count train vectors from offset, e.g. to insert in batches
+func (d *AnnDataset) Train(offset, count int) ([][]float32, error) {
+	start, end, err := rowRange(len(d.train), offset, count)
+	if err != nil {
+		return nil, err
+	}
+	return d.train[start:end], nil
+}
+
+// IDs returns the primary keys of count train vectors from offset: their row indices, which
+// the neighbors refer to
+func (d *AnnDataset) IDs(offset, count int) ([]int64, error) {
+	start, end, err := rowRange(len(d.train), offset, count)
+	if err != nil {
+		return nil, err
+	}
+	return rowIndices(start, end), nil
+}
+
+// Test returns count test query vectors from offset
+func (d *AnnDataset) Test(offset, count int) ([][]float32, error) {
+	start, end, err := rowRange(len(d.test), offset, count)
+	if err != nil {
+		return nil, err
+	}
+	return d.test[start:end], nil
+}
+
+// Neighbors returns the true nearest neighbors of count test vectors from offset, best first,
+// truncated to the top k when k is given. Pass it as the groundTruth search param.
+func (d *AnnDataset) Neighbors(offset, count int, k ...int) ([][]int64, error) {
+	start, end, err := rowRange(len(d.neighbors), offset, count)
+	if err != nil {
+		return nil, err
+	}
+	neighbors := d.neighbors[start:end]
+	if len(k) == 0 || k[0] <= 0 || k[0] >= len(firstRow(neighbors)) {
+		return neighbors, nil
+	}
+	top := make([][]int64, len(neighbors))
+	for i, row := range neighbors {
+		top[i] = row[:k[0]:k[0]]
+	}
+	return top, nil
+}
+
+// Distances returns the distances to the true nearest neighbors of count test vectors from offset
+func (d *AnnDataset) Distances(offset, count int) ([][]float32, error) {
+	start, end, err := rowRange(len(d.distances), offset, count)
+	if err != nil {
+		return nil, err
+	}
+	return d.distances[start:end], nil
+}
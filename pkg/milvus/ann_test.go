@@ -0,0 +1,108 @@
+package milvus
+
+import (
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/scigolib/hdf5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAnnDataset writes a small ann-benchmarks file with 5 train vectors of dimension 3 and
+// 2 test queries with 4 neighbors each
+func writeAnnDataset(t *testing.T, skip ...string) string {
+	path := filepath.Join(t.TempDir(), "tiny-3-euclidean.hdf5")
+	fw, err := hdf5.CreateForWrite(path, hdf5.CreateTruncate)
+	require.NoError(t, err)
+
+	datasets := []struct {
+		name  string
+		dtype hdf5.Datatype
+		dims  []uint64
+		data  interface{}
+	}{
+		{"train", hdf5.Float32, []uint64{5, 3}, []float32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}},
+		{"test", hdf5.Float32, []uint64{2, 3}, []float32{0.5, 1, 2, 9, 10, 11}},
+		{"neighbors", hdf5.Int32, []uint64{2, 4}, []int32{0, 1, 2, 3, 3, 4, 2, 1}},
+		{"distances", hdf5.Float32, []uint64{2, 4}, []float32{0.5, 5, 10, 15, 0, 5, 5, 10}},
+	}
+	for _, d := range datasets {
+		if slices.Contains(skip, d.name) {
+			continue
+		}
+		dw, err := fw.CreateDataset("/"+d.name, d.dtype, d.dims)
+		require.NoError(t, err)
+		require.NoError(t, dw.Write(d.data))
+	}
+	require.NoError(t, fw.Close())
+	return path
+}
+
+func TestAnnDataset(t *testing.T) {
+	path := writeAnnDataset(t)
+	m := &Milvus{shared: &sync.Map{}}
+	ds, err := m.AnnDataset(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, ds.Dimension())
+	assert.Equal(t, 5, ds.TrainSize())
+	assert.Equal(t, 2, ds.TestSize())
+	assert.Equal(t, "euclidean", ds.Distance())
+	assert.Equal(t, "L2", ds.MetricType())
+
+	train, err := ds.Train(3, 10)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{9, 10, 11}, {12, 13, 14}}, train)
+	ids, err := ds.IDs(3, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, ids)
+
+	test, err := ds.Test(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{9, 10, 11}}, test)
+	neighbors, err := ds.Neighbors(0, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]int64{{0, 1}, {3, 4}}, neighbors)
+	neighbors, err = ds.Neighbors(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]int64{{3, 4, 2, 1}}, neighbors)
+	distances, err := ds.Distances(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0, 5, 5, 10}}, distances)
+
+	// Past the end is empty; negative is an error
+	train, err = ds.Train(5, 1)
+	require.NoError(t, err)
+	assert.Empty(t, train)
+	_, err = ds.Test(-1, 1)
+	assert.Error(t, err)
+
+	// Loaded once and shared; the distance override is per call
+	again, err := m.AnnDataset(path, map[string]interface{}{"distance": "angular"})
+	require.NoError(t, err)
+	assert.Equal(t, "angular", again.Distance())
+	assert.Equal(t, "COSINE", again.MetricType())
+	assert.Equal(t, "euclidean", ds.Distance())
+	againTrain, err := again.Train(0, 1)
+	require.NoError(t, err)
+	assert.Same(t, &ds.train[0][0], &againTrain[0][0])
+}
+
+func TestAnnDatasetInvalid(t *testing.T) {
+	m := &Milvus{}
+
+	_, err := m.AnnDataset(filepath.Join(t.TempDir(), "missing.hdf5"))
+	assert.Error(t, err)
+	_, err = m.AnnDataset(writeAnnDataset(t, "neighbors"))
+	assert.ErrorContains(t, err, `no "neighbors" dataset`)
+
+	// distances is optional
+	ds, err := m.AnnDataset(writeAnnDataset(t, "distances"))
+	require.NoError(t, err)
+	distances, err := ds.Distances(0, 2)
+	require.NoError(t, err)
+	assert.Empty(t, distances)
+}
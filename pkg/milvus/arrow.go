@@ -0,0 +1,380 @@
+package milvus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/milvus-io/milvus/client/v2/column"
+)
+
+// ArrowReaderConfig configures milvus.arrowReader()
+type ArrowReaderConfig struct {
+	BatchSize int               `json:"batchSize,omitempty"` // Rows per batch of Parquet files (default: 1000); Arrow IPC files keep their record batches
+	Fields    map[string]string `json:"fields,omitempty"`    // Schema field to Arrow column; default reads every column under its own name
+}
+
+// arrowRecords is a source of Arrow record batches: an IPC stream or an IPC file
+type arrowRecords interface {
+	Schema() *arrow.Schema
+	Next() bool
+	RecordBatch() arrow.RecordBatch
+	Err() error
+	Release()
+}
+
+// ArrowReader reads Arrow record batches from an Arrow IPC file or stream and turns each into a
+// batch of Milvus columns that share the Arrow buffers. No value goes through a JavaScript or
+// interface{} conversion, which makes it the fastest way to ingest a large dataset. Parquet
+// files are read by milvus.parquetReader(), in batches of the same form.
+//
+// Usage in k6:
+//
+//	const reader = milvus.arrowReader('data/embeddings.arrow', { fields: { embedding: 'emb' } });
+//	export default function () {
+//	    const batch = reader.next();
+//	    if (batch) client.insert(batch, 'bench');
+//	}
+type ArrowReader struct {
+	path    string
+	file    *os.File
+	records arrowRecords
+	parquet *ParquetReader // Set instead of records for a Parquet file
+	fields  []arrowField
+	config  ArrowReaderConfig
+}
+
+// arrowField maps an Arrow column to a Milvus field
+type arrowField struct {
+	field  string
+	column string
+	index  int // Column index in the Arrow schema
+}
+
+// arrowAllocator allocates Arrow buffers on the Go heap, so the columns of a batch stay valid
+// for as long as the script holds the batch, without releasing records by hand
+var arrowAllocator = memory.NewGoAllocator()
+
+// ArrowReader opens an Arrow IPC file or stream, or a Parquet file, for batched inserts. The
+// format is detected from the content of the file.
+func (m *Milvus) ArrowReader(path string, configInput ...interface{}) (*ArrowReader, error) {
+	var config ArrowReaderConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid arrow reader config: %v", err)
+		}
+	}
+	path, err := localDataset(m.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	return openArrowReader(path, config)
+}
+
+func openArrowReader(path string, config ArrowReaderConfig) (*ArrowReader, error) {
+	if config.BatchSize < 0 {
+		return nil, fmt.Errorf("arrow reader batchSize must not be negative")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultParquetBatchSize
+	}
+	r := &ArrowReader{path: path, config: config}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	if r.parquet != nil {
+		for _, col := range r.parquet.columns {
+			r.fields = append(r.fields, arrowField{field: col.field, column: col.column, index: col.index})
+		}
+		return r, nil
+	}
+	fields, err := arrowFields(r.records.Schema(), config.Fields)
+	if err != nil {
+		_ = r.Close()
+		return nil, fmt.Errorf("arrow file %s: %v", path, err)
+	}
+	r.fields = fields
+	return r, nil
+}
+
+// open opens the file and positions the reader at its first record batch
+func (r *ArrowReader) open() error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to open arrow file: %v", err)
+	}
+	magic := make([]byte, 6)
+	n, _ := io.ReadFull(f, magic)
+	magic = magic[:n]
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to read arrow file %s: %v", r.path, err)
+	}
+
+	if bytes.HasPrefix(magic, []byte("PAR1")) {
+		_ = f.Close()
+		r.parquet, err = openParquetReader(nil, nil, r.path, ParquetReaderConfig{BatchSize: r.config.BatchSize, Fields: r.config.Fields})
+		return err
+	}
+	switch {
+	case bytes.Equal(magic, []byte("ARROW1")):
+		var reader *ipc.FileReader
+		if reader, err = ipc.NewFileReader(f, ipc.WithAllocator(arrowAllocator)); err == nil {
+			r.records = &arrowFileRecords{reader: reader}
+		}
+	default:
+		r.records, err = ipc.NewReader(f, ipc.WithAllocator(arrowAllocator))
+	}
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to read arrow file %s: %v", r.path, err)
+	}
+	r.file = f
+	return nil
+}
+
+// arrowFields resolves the columns to read
+func arrowFields(schema *arrow.Schema, mapping map[string]string) ([]arrowField, error) {
+	var fields []arrowField
+	if len(mapping) == 0 {
+		for i, f := range schema.Fields() {
+			fields = append(fields, arrowField{field: f.Name, column: f.Name, index: i})
+		}
+		return fields, nil
+	}
+	for field, name := range mapping {
+		indices := schema.FieldIndices(name)
+		if len(indices) == 0 {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+		fields = append(fields, arrowField{field: field, column: name, index: indices[0]})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].field < fields[j].field })
+	return fields, nil
+}
+
+// Next returns the next record batch as a batch for client.insert(), inserted into the
+// collection passed to insert(), or null once all batches have been read
+func (r *ArrowReader) Next() (*PreparedBatch, error) {
+	if r.parquet != nil {
+		return r.nextParquet()
+	}
+	if r.records == nil {
+		return nil, fmt.Errorf("arrow reader %s is closed", r.path)
+	}
+	for r.records.Next() {
+		record := r.records.RecordBatch()
+		if record.NumRows() == 0 {
+			continue
+		}
+		// The reader releases the record on its next call; the batch keeps it
+		record.Retain()
+		batch := &PreparedBatch{rows: int(record.NumRows()), vectors: make(map[string]interface{}), pkIndex: -1}
+		for _, f := range r.fields {
+			col, err := arrowColumn(f.field, record.Column(f.index))
+			if err != nil {
+				return nil, fmt.Errorf("arrow file %s: column %q: %v", r.path, f.column, err)
+			}
+			if vectors, ok := col.(*column.ColumnFloatVector); ok {
+				rows := make([][]float32, vectors.Len())
+				for i, vector := range vectors.Data() {
+					rows[i] = vector
+				}
+				batch.vectors[f.field] = rows
+			}
+			batch.columns = append(batch.columns, col)
+		}
+		return batch, nil
+	}
+	if err := r.records.Err(); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read arrow file %s: %v", r.path, err)
+	}
+	return nil, nil
+}
+
+// nextParquet returns the next batch of the Parquet reader as a batch for client.insert()
+func (r *ArrowReader) nextParquet() (*PreparedBatch, error) {
+	values, err := r.parquet.Next()
+	if values == nil || err != nil {
+		return nil, err
+	}
+	batch := &PreparedBatch{vectors: make(map[string]interface{}), pkIndex: -1}
+	for _, f := range r.fields {
+		var col column.Column
+		switch v := values[f.field].(type) {
+		case [][]float32:
+			col = column.NewColumnFloatVector(f.field, len(firstRow(v)), v)
+			batch.vectors[f.field] = v
+		case []bool:
+			col = column.NewColumnBool(f.field, v)
+		case []int32:
+			col = column.NewColumnInt32(f.field, v)
+		case []int64:
+			col = column.NewColumnInt64(f.field, v)
+		case []float32:
+			col = column.NewColumnFloat(f.field, v)
+		case []float64:
+			col = column.NewColumnDouble(f.field, v)
+		case []string:
+			col = column.NewColumnVarChar(f.field, v)
+		default:
+			return nil, fmt.Errorf("parquet file %s: column %q has type %T", r.path, f.column, v)
+		}
+		batch.columns = append(batch.columns, col)
+		batch.rows = col.Len()
+	}
+	return batch, nil
+}
+
+// arrowColumn returns a Milvus column over the values of an Arrow array. Numeric columns and
+// vector rows share the Arrow buffers; booleans and strings are unpacked.
+func arrowColumn(name string, arr arrow.Array) (column.Column, error) {
+	if arr.NullN() > 0 {
+		return nil, fmt.Errorf("%d null values, nulls are not supported", arr.NullN())
+	}
+	switch a := arr.(type) {
+	case *array.Boolean:
+		values := make([]bool, a.Len())
+		for i := range values {
+			values[i] = a.Value(i)
+		}
+		return column.NewColumnBool(name, values), nil
+	case *array.Int8:
+		return column.NewColumnInt8(name, a.Int8Values()), nil
+	case *array.Int16:
+		return column.NewColumnInt16(name, a.Int16Values()), nil
+	case *array.Int32:
+		return column.NewColumnInt32(name, a.Int32Values()), nil
+	case *array.Int64:
+		return column.NewColumnInt64(name, a.Int64Values()), nil
+	case *array.Float32:
+		return column.NewColumnFloat(name, a.Float32Values()), nil
+	case *array.Float64:
+		return column.NewColumnDouble(name, a.Float64Values()), nil
+	case *array.String:
+		values := make([]string, a.Len())
+		for i := range values {
+			values[i] = a.Value(i)
+		}
+		return column.NewColumnVarChar(name, values), nil
+	case *array.LargeString:
+		values := make([]string, a.Len())
+		for i := range values {
+			values[i] = a.Value(i)
+		}
+		return column.NewColumnVarChar(name, values), nil
+	case array.ListLike:
+		vectors, err := arrowVectors(a)
+		if err != nil {
+			return nil, err
+		}
+		return column.NewColumnFloatVector(name, len(firstRow(vectors)), vectors), nil
+	}
+	return nil, fmt.Errorf("unsupported Arrow type %s", arr.DataType())
+}
+
+// arrowVectors returns the rows of a list of float or double values as float vectors of one
+// dimension. Float rows share the Arrow buffer; double rows are converted.
+func arrowVectors(list array.ListLike) ([][]float32, error) {
+	var values []float32
+	switch child := list.ListValues().(type) {
+	case *array.Float32:
+		values = child.Float32Values()
+	case *array.Float64:
+		values = make([]float32, child.Len())
+		for i, v := range child.Float64Values() {
+			values[i] = float32(v)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Arrow type %s, vectors must be lists of float or double", list.DataType())
+	}
+	if list.ListValues().NullN() > 0 {
+		return nil, fmt.Errorf("vectors hold null values")
+	}
+	vectors := make([][]float32, list.Len())
+	for i := range vectors {
+		start, end := list.ValueOffsets(i)
+		vectors[i] = values[start:end:end]
+		if len(vectors[i]) != len(vectors[0]) {
+			return nil, fmt.Errorf("vector %d has dimension %d, expected %d", i, len(vectors[i]), len(vectors[0]))
+		}
+	}
+	return vectors, nil
+}
+
+// Reset rewinds the reader to the first record batch
+func (r *ArrowReader) Reset() error {
+	if r.parquet != nil {
+		return r.parquet.Reset()
+	}
+	if r.records == nil {
+		return fmt.Errorf("arrow reader %s is closed", r.path)
+	}
+	r.records.Release()
+	_ = r.file.Close()
+	r.records = nil
+	return r.open()
+}
+
+// Fields returns the field names of each batch
+func (r *ArrowReader) Fields() []string {
+	fields := make([]string, len(r.fields))
+	for i, f := range r.fields {
+		fields[i] = f.field
+	}
+	return fields
+}
+
+// Close closes the underlying file
+func (r *ArrowReader) Close() error {
+	if r.parquet != nil {
+		return r.parquet.Close()
+	}
+	if r.records == nil {
+		return nil
+	}
+	r.records.Release()
+	r.records = nil
+	return r.file.Close()
+}
+
+// arrowFileRecords reads the record batches of an Arrow IPC file in order
+type arrowFileRecords struct {
+	reader *ipc.FileReader
+	next   int
+	record arrow.RecordBatch
+	err    error
+}
+
+func (f *arrowFileRecords) Schema() *arrow.Schema { return f.reader.Schema() }
+
+func (f *arrowFileRecords) Next() bool {
+	if f.record != nil {
+		f.record.Release()
+		f.record = nil
+	}
+	if f.err != nil || f.next >= f.reader.NumRecords() {
+		return false
+	}
+	f.record, f.err = f.reader.RecordBatchAt(f.next)
+	f.next++
+	return f.err == nil
+}
+
+func (f *arrowFileRecords) RecordBatch() arrow.RecordBatch { return f.record }
+
+func (f *arrowFileRecords) Err() error { return f.err }
+
+func (f *arrowFileRecords) Release() {
+	if f.record != nil {
+		f.record.Release()
+		f.record = nil
+	}
+	_ = f.reader.Close()
+}
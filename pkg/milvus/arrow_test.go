@@ -0,0 +1,167 @@
+package milvus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var arrowTestSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "title", Type: arrow.BinaryTypes.String},
+	{Name: "in_stock", Type: arrow.FixedWidthTypes.Boolean},
+	{Name: "emb", Type: arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Float32)},
+}, nil)
+
+// arrowRecord builds a record of n rows with IDs counted from first
+func arrowRecord(first, n int) arrow.RecordBatch {
+	builder := array.NewRecordBuilder(arrowAllocator, arrowTestSchema)
+	defer builder.Release()
+	emb := builder.Field(3).(*array.FixedSizeListBuilder)
+	for i := first; i < first+n; i++ {
+		builder.Field(0).(*array.Int64Builder).Append(int64(i))
+		builder.Field(1).(*array.StringBuilder).Append("product")
+		builder.Field(2).(*array.BooleanBuilder).Append(i%2 == 0)
+		emb.Append(true)
+		emb.ValueBuilder().(*array.Float32Builder).AppendValues([]float32{float32(i), 1}, nil)
+	}
+	return builder.NewRecordBatch()
+}
+
+// writeArrow writes two record batches of 2 and 1 rows as an Arrow IPC file, or stream
+func writeArrow(t *testing.T, stream bool) string {
+	path := filepath.Join(t.TempDir(), "products.arrow")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var writer interface {
+		Write(arrow.RecordBatch) error
+		Close() error
+	}
+	if stream {
+		writer = ipc.NewWriter(f, ipc.WithSchema(arrowTestSchema))
+	} else {
+		writer, err = ipc.NewFileWriter(f, ipc.WithSchema(arrowTestSchema))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Write(arrowRecord(0, 2)))
+	require.NoError(t, writer.Write(arrowRecord(2, 1)))
+	require.NoError(t, writer.Close())
+	return path
+}
+
+func TestArrowReader(t *testing.T) {
+	for name, stream := range map[string]bool{"file": false, "stream": true} {
+		t.Run(name, func(t *testing.T) {
+			reader, err := (&Milvus{}).ArrowReader(writeArrow(t, stream), map[string]interface{}{
+				"fields": map[string]interface{}{"pk": "id", "embedding": "emb", "in_stock": "in_stock", "title": "title"},
+			})
+			require.NoError(t, err)
+			defer reader.Close()
+			assert.Equal(t, []string{"embedding", "in_stock", "pk", "title"}, reader.Fields())
+
+			batch, err := reader.Next()
+			require.NoError(t, err)
+			require.NotNil(t, batch)
+			assert.Equal(t, 2, batch.Rows())
+			assert.Empty(t, batch.Collection(), "inserted into the collection passed to insert()")
+			columns := batch.insertColumns()
+			assert.Equal(t, []int64{0, 1}, columnByName(columns, "pk").(*column.ColumnInt64).Data())
+			assert.Equal(t, []string{"product", "product"}, columnByName(columns, "title").(*column.ColumnVarChar).Data())
+			assert.Equal(t, []bool{true, false}, columnByName(columns, "in_stock").(*column.ColumnBool).Data())
+			assert.Equal(t, 2, columnByName(columns, "embedding").(*column.ColumnFloatVector).Dim())
+			assert.Equal(t, [][]float32{{0, 1}, {1, 1}}, batch.vectors["embedding"])
+
+			batch, err = reader.Next()
+			require.NoError(t, err)
+			assert.Equal(t, [][]float32{{2, 1}}, batch.vectors["embedding"])
+			batch, err = reader.Next()
+			require.NoError(t, err)
+			assert.Nil(t, batch)
+
+			require.NoError(t, reader.Reset())
+			batch, err = reader.Next()
+			require.NoError(t, err)
+			assert.Equal(t, 2, batch.Rows())
+
+			require.NoError(t, reader.Close())
+			_, err = reader.Next()
+			assert.ErrorContains(t, err, "closed")
+		})
+	}
+}
+
+func TestArrowReaderParquet(t *testing.T) {
+	reader, err := (&Milvus{}).ArrowReader(writeParquet(t, 5), map[string]interface{}{"batchSize": 3})
+	require.NoError(t, err)
+	defer reader.Close()
+	assert.Equal(t, []string{"emb", "features", "id", "in_stock", "price", "title"}, reader.Fields())
+
+	var rows int
+	for {
+		batch, err := reader.Next()
+		require.NoError(t, err)
+		if batch == nil {
+			break
+		}
+		columns := batch.insertColumns()
+		assert.Equal(t, int64(rows), columnByName(columns, "id").(*column.ColumnInt64).Data()[0])
+		assert.Equal(t, []float32{float32(rows), 1, 2}, batch.vectors["emb"].([][]float32)[0])
+		assert.Equal(t, []float32{float32(rows), 0.5}, batch.vectors["features"].([][]float32)[0], "double lists become float vectors")
+		rows += batch.Rows()
+	}
+	assert.Equal(t, 5, rows)
+}
+
+func TestArrowColumnErrors(t *testing.T) {
+	builder := array.NewInt64Builder(arrowAllocator)
+	builder.AppendValues([]int64{1, 2}, []bool{true, false})
+	_, err := arrowColumn("id", builder.NewArray())
+	assert.ErrorContains(t, err, "1 null values")
+
+	dates := array.NewDate32Builder(arrowAllocator)
+	dates.Append(1)
+	_, err = arrowColumn("day", dates.NewArray())
+	assert.ErrorContains(t, err, "unsupported Arrow type date32")
+
+	lists := array.NewListBuilder(arrowAllocator, arrow.PrimitiveTypes.Float32)
+	values := lists.ValueBuilder().(*array.Float32Builder)
+	lists.Append(true)
+	values.AppendValues([]float32{1, 2}, nil)
+	lists.Append(true)
+	values.AppendValues([]float32{3}, nil)
+	_, err = arrowColumn("emb", lists.NewArray())
+	assert.ErrorContains(t, err, "vector 1 has dimension 1, expected 2")
+
+	m := &Milvus{}
+	_, err = m.ArrowReader(filepath.Join(t.TempDir(), "missing.arrow"))
+	assert.Error(t, err)
+	_, err = m.ArrowReader(writeArrow(t, false), map[string]interface{}{"fields": map[string]interface{}{"id": "pk"}})
+	assert.ErrorContains(t, err, `column "pk" not found`)
+	_, err = m.ArrowReader(writeArrow(t, false), map[string]interface{}{"batchSize": -1})
+	assert.Error(t, err)
+}
+
+func TestInsertArrowBatch(t *testing.T) {
+	service := &chunkServer{}
+	client := benchClient(t, service, &metricsVU{})
+	reader, err := (&Milvus{}).ArrowReader(writeArrow(t, false), map[string]interface{}{
+		"fields": map[string]interface{}{"id": "id", "embedding": "emb"},
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+	batch, err := reader.Next()
+	require.NoError(t, err)
+
+	result := client.Insert(batch, map[string]interface{}{"collectionName": "bench", "batchSize": 1}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, float64(2), result["result"].(map[string]interface{})["insert_count"])
+	assert.ElementsMatch(t, [][]int64{{0}, {1}}, service.batches)
+}
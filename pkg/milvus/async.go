@@ -0,0 +1,186 @@
+package milvus
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/grafana/sobek"
+)
+
+// InsertAsync is Insert returning a Promise, so one VU can keep several inserts in flight.
+// The Promise resolves with the same result object as insert(), failures included; it is
+// never rejected. The data is copied before insertAsync() returns, so the script may reuse its
+// typed arrays right away.
+//
+// Usage in k6:
+//
+//	export default async function () {
+//	    const results = await Promise.all(batches.map((batch) => client.insertAsync(batch)));
+//	}
+func (c *Client) InsertAsync(dataInput interface{}, args ...interface{}) (*sobek.Promise, error) {
+	rt := c.runtime()
+	dataInput = ownedInput(rt, dataInput)
+	args = ownedInput(rt, args).([]interface{})
+	return c.async("insertAsync", nil, func(d *Client) interface{} {
+		return d.Insert(dataInput, args...)
+	})
+}
+
+// SearchAsync is Search returning a Promise, so one VU can keep several searches in flight.
+// The Promise resolves with the same result object as search(), failures included; it is
+// never rejected. The vectors and params are copied before searchAsync() returns.
+//
+// Usage in k6:
+//
+//	export default async function () {
+//	    const [a, b] = await Promise.all([
+//	        client.searchAsync([gen.next(1)[0]], 10, { vectorField: 'embedding' }),
+//	        client.searchAsync([gen.next(1)[0]], 10, { vectorField: 'embedding' }),
+//	    ]);
+//	}
+func (c *Client) SearchAsync(vectorsInput interface{}, topK int, params map[string]interface{}, collectionName ...string) (*sobek.Promise, error) {
+	rt := c.runtime()
+	vectorsInput = ownedInput(rt, vectorsInput)
+	params, _ = ownedInput(rt, params).(map[string]interface{})
+	return c.async("searchAsync", nil, func(d *Client) interface{} {
+		return d.Search(vectorsInput, topK, params, collectionName...)
+	})
+}
+
+// FlushAsync is Flush running on one of the client's background workers, so waiting for the
+// flush does not block the VU. The Promise resolves with the same result object as flush(),
+// failures included; it is never rejected.
+//
+// Usage in k6:
+//
+//	export default async function () {
+//	    client.insert(batch);
+//	    const flushed = client.flushAsync();
+//	    client.search(gen.next(1), 10, { vectorField: 'embedding' }); // Runs while the flush is waited for
+//	    await flushed;
+//	}
+func (c *Client) FlushAsync(collectionName ...string) (*sobek.Promise, error) {
+	return c.async("flushAsync", c.workers, func(d *Client) interface{} {
+		return d.Flush(collectionName...)
+	})
+}
+
+// LoadCollectionAsync is LoadCollection running on one of the client's background workers, so
+// waiting for the load does not block the VU. The Promise resolves with the same result object
+// as loadCollection(), failures included.
+func (c *Client) LoadCollectionAsync(collectionName ...string) (*sobek.Promise, error) {
+	return c.async("loadCollectionAsync", c.workers, func(d *Client) interface{} {
+		return d.LoadCollection(collectionName...)
+	})
+}
+
+// BuildIndexAsync is CreateIndex, waiting for the build to finish, running on one of the
+// client's background workers. Unlike createIndexAsync(), which returns once the build is
+// submitted, its Promise resolves with the result object of createIndex() when the index is
+// built, or when the build failed.
+func (c *Client) BuildIndexAsync(fieldName string, indexParams map[string]interface{}, collectionName ...string) (*sobek.Promise, error) {
+	return c.async("buildIndexAsync", c.workers, func(d *Client) interface{} {
+		return d.CreateIndex(fieldName, indexParams, collectionName...)
+	})
+}
+
+// async runs an operation on a detached client in its own goroutine, on a worker of the pool
+// when one is given, and resolves the returned Promise with its result on the VU event loop
+func (c *Client) async(name string, pool *workerPool, run func(*Client) interface{}) (*sobek.Promise, error) {
+	if c.vu == nil || c.vu.Runtime() == nil || c.vu.State() == nil {
+		return nil, fmt.Errorf("%s is only available in VU code", name)
+	}
+	promise, resolve, _ := c.vu.Runtime().NewPromise()
+	callback := c.vu.RegisterCallback()
+	detached := c.detached()
+	go func() {
+		if pool != nil {
+			release := pool.acquire()
+			defer release()
+		}
+		result := run(detached)
+		callback(func() error {
+			return resolve(c.jsResult(result))
+		})
+	}()
+	return promise, nil
+}
+
+// runtime returns the JavaScript runtime of the VU, nil outside of it
+func (c *Client) runtime() *sobek.Runtime {
+	if c.vu == nil {
+		return nil
+	}
+	return c.vu.Runtime()
+}
+
+// ownedInput deep-copies the maps, arrays, typed arrays and ArrayBuffers of an operation's
+// input. Exported typed arrays are views of JavaScript memory the script may reuse as soon as
+// the async call returns, so the copy is made on the VU goroutine, before the operation leaves
+// it. Other values are returned as is.
+func ownedInput(rt *sobek.Runtime, input interface{}) interface{} {
+	switch v := input.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		owned := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			owned[key] = ownedInput(rt, value)
+		}
+		return owned
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		owned := make([]interface{}, len(v))
+		for i, value := range v {
+			owned[i] = ownedInput(rt, value)
+		}
+		return owned
+	case [][]float32:
+		owned := make([][]float32, len(v))
+		for i, row := range v {
+			owned[i] = slices.Clone(row)
+		}
+		return owned
+	case []float32:
+		return slices.Clone(v)
+	case []float64:
+		return slices.Clone(v)
+	case []int64:
+		return slices.Clone(v)
+	case []int32:
+		return slices.Clone(v)
+	case []int16:
+		return slices.Clone(v)
+	case []int8:
+		return slices.Clone(v)
+	case []uint8:
+		return slices.Clone(v)
+	case sobek.ArrayBuffer:
+		if rt == nil {
+			return v
+		}
+		return rt.NewArrayBuffer(slices.Clone(v.Bytes()))
+	}
+	return input
+}
+
+// detached returns a copy of the client for an operation running outside the VU goroutine.
+// It uses the current connection, re-dialed first if broken, without re-dialing later, and its
+// own copies of the cached index types and search specs, so it shares no unguarded state with
+// the VU's client.
+func (c *Client) detached() *Client {
+	root := c.root()
+	d := *c
+	d.base = nil
+	d.client = root.milvus()
+	d.redial = nil
+	d.offLoop = true
+	d.version = root.version
+	d.indexTypes = maps.Clone(root.indexTypes)
+	d.searchSpecs = maps.Clone(root.searchSpecs)
+	return &d
+}
@@ -0,0 +1,130 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/js/modulestest"
+)
+
+func asyncRuntime(t *testing.T, service milvuspb.MilvusServiceServer) (*modulestest.Runtime, *Client) {
+	rt := modulestest.NewRuntime(t)
+	client := fakeClient(t, &Milvus{vu: rt.VU}, service, WithCollection("bench"))
+
+	// Async calls are refused in the init context
+	_, err := client.InsertAsync(chunkData(1))
+	assert.ErrorContains(t, err, "only available in VU code")
+
+	vu, _ := newMetricsVU(t)
+	rt.MoveToVUContext(vu.state)
+	require.NoError(t, rt.VU.Runtime().Set("client", client))
+	require.NoError(t, rt.VU.Runtime().Set("data", chunkData(3)))
+	return rt, client
+}
+
+func TestInsertAsync(t *testing.T) {
+	service := &chunkServer{}
+	rt, _ := asyncRuntime(t, service)
+
+	_, err := rt.RunOnEventLoop(`
+		Promise.all([client.insertAsync(data), client.insertAsync(data), client.insertAsync(data)])
+			.then((results) => { globalThis.counts = results.map((r) => r.result.insert_count); });
+	`)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(3), int64(3), int64(3)}, rt.VU.Runtime().Get("counts").Export())
+	assert.Equal(t, int32(3), service.peak.Load(), "one VU keeps all inserts in flight")
+}
+
+func TestSearchAsyncResolvesFailures(t *testing.T) {
+	rt, _ := asyncRuntime(t, &chunkServer{})
+
+	_, err := rt.RunOnEventLoop(`
+		client.searchAsync("not vectors", 10, { vectorField: "embedding" })
+			.then((r) => { globalThis.result = r; });
+	`)
+	require.NoError(t, err)
+	result := rt.VU.Runtime().Get("result").Export().(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "failed to convert search vectors")
+}
+
+// flushServer records the most flushes in flight at once
+type flushServer struct {
+	chunkServer
+}
+
+func (s *flushServer) Flush(context.Context, *milvuspb.FlushRequest) (*milvuspb.FlushResponse, error) {
+	current := s.inflight.Add(1)
+	defer s.inflight.Add(-1)
+	for peak := s.peak.Load(); current > peak && !s.peak.CompareAndSwap(peak, current); peak = s.peak.Load() {
+	}
+	time.Sleep(20 * time.Millisecond)
+	return &milvuspb.FlushResponse{Status: &commonpb.Status{}}, nil
+}
+
+func (s *flushServer) GetFlushState(context.Context, *milvuspb.GetFlushStateRequest) (*milvuspb.GetFlushStateResponse, error) {
+	return &milvuspb.GetFlushStateResponse{Status: &commonpb.Status{}, Flushed: true}, nil
+}
+
+func TestFlushAsyncWorkers(t *testing.T) {
+	service := &flushServer{}
+	rt, client := asyncRuntime(t, service)
+	client.workers = newWorkerPool(2)
+
+	_, err := rt.RunOnEventLoop(`
+		Promise.all([client.flushAsync(), client.flushAsync(), client.flushAsync(), client.flushAsync()])
+			.then((results) => { globalThis.flushed = results.every((r) => r.success); });
+	`)
+	require.NoError(t, err)
+	assert.Equal(t, true, rt.VU.Runtime().Get("flushed").Export())
+	assert.Equal(t, int32(2), service.peak.Load(), "at most workers flushes in flight")
+}
+
+func TestInsertAsyncCopiesTypedArrays(t *testing.T) {
+	service := &vectorServer{}
+	rt, _ := asyncRuntime(t, service)
+
+	_, err := rt.RunOnEventLoop(`
+		const row = new Float32Array([1, 2]);
+		const done = client.insertAsync({ id: [1], embedding: [row] });
+		row.fill(9);
+		done.then((r) => { globalThis.result = r; });
+	`)
+	require.NoError(t, err)
+	assert.Equal(t, true, rt.VU.Runtime().Get("result").Export().(map[string]interface{})["success"])
+	assert.Equal(t, []float32{1, 2}, service.vectors, "the VU reuses the typed array while the insert is in flight")
+}
+
+// vectorServer records the vectors of the last insert
+type vectorServer struct {
+	chunkServer
+	vectors []float32
+}
+
+func (s *vectorServer) Insert(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+	for _, data := range req.GetFieldsData() {
+		if data.GetFieldName() == "embedding" {
+			s.mu.Lock()
+			s.vectors = data.GetVectors().GetFloatVector().GetData()
+			s.mu.Unlock()
+		}
+	}
+	return s.chunkServer.Insert(ctx, req)
+}
+
+func TestOwnedInput(t *testing.T) {
+	row := []float32{1, 2}
+	input := map[string]interface{}{"embedding": []interface{}{row}, "vectors": [][]float32{row}, "id": []int64{1}}
+	owned := ownedInput(nil, input).(map[string]interface{})
+	row[0] = 9
+	input["id"].([]int64)[0] = 9
+	assert.Equal(t, []interface{}{[]float32{1, 2}}, owned["embedding"])
+	assert.Equal(t, [][]float32{{1, 2}}, owned["vectors"])
+	assert.Equal(t, []int64{1}, owned["id"])
+	assert.Equal(t, "text", ownedInput(nil, "text"))
+}
@@ -0,0 +1,165 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// BarrierConfig configures milvus.barrier()
+type BarrierConfig struct {
+	Parties int64 `json:"parties,omitempty"` // Arrivals that open the barrier (default: only open() opens it)
+}
+
+// barrierState is the state shared by the barriers of a name
+type barrierState struct {
+	mu       sync.Mutex
+	parties  int64 // 0 until a barrier of the name is created with parties
+	arrivals int64
+	opened   chan struct{} // Closed when the barrier opens
+}
+
+// open opens the barrier once; the caller holds mu
+func (s *barrierState) open() {
+	select {
+	case <-s.opened:
+	default:
+		close(s.opened)
+	}
+}
+
+// Barrier synchronizes the phases of a test across VUs and scenarios without sleep(): VUs block
+// in wait() until the barrier opens, once parties VUs have called arrive() or any VU calls
+// open(). Barriers of the same name share their state, and an open barrier stays open, so a
+// barrier without parties is a flag such as "all inserts done".
+//
+// Usage in k6:
+//
+//	const loaded = milvus.barrier('loaded', { parties: 4 });
+//	export function ingest() {
+//	    client.insert(batch);
+//	    if (exec.vu.iterationInScenario === 99) loaded.arrive(); // Last of 100 per-vu-iterations
+//	}
+//	export function query() {
+//	    loaded.wait();
+//	    client.search(gen.next(1), 10, { vectorField: 'embedding' });
+//	}
+type Barrier struct {
+	vu    modules.VU
+	state *barrierState
+}
+
+// Barrier returns the barrier of a name, shared by all VUs
+func (m *Milvus) Barrier(name string, configInput ...map[string]interface{}) (*Barrier, error) {
+	var config BarrierConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid barrier config: %v", err)
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("barrier name required")
+	}
+	if config.Parties < 0 {
+		return nil, fmt.Errorf("barrier parties must not be negative, got %d", config.Parties)
+	}
+	state, err := sharedValue(m.shared, "barrier\x00"+name, func() (*barrierState, error) {
+		return &barrierState{parties: config.Parties, opened: make(chan struct{})}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := state.adopt(name, config.Parties); err != nil {
+		return nil, err
+	}
+	return &Barrier{vu: m.vu, state: state}, nil
+}
+
+// adopt checks the parties of a barrier of the name, setting them when the barrier was first
+// created without parties, e.g. by a VU that only waits
+func (s *barrierState) adopt(name string, parties int64) error {
+	if parties == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.parties == 0 {
+		s.parties = parties
+		if s.arrivals >= parties {
+			s.open()
+		}
+	}
+	if s.parties != parties {
+		return fmt.Errorf("barrier %s has %d parties, got %d", name, s.parties, parties)
+	}
+	return nil
+}
+
+// Arrive counts the arrival of the calling VU, opening the barrier at the parties-th arrival,
+// and returns the arrivals so far. It does not block.
+func (b *Barrier) Arrive() int64 {
+	b.state.mu.Lock()
+	defer b.state.mu.Unlock()
+	b.state.arrivals++
+	if b.state.parties > 0 && b.state.arrivals >= b.state.parties {
+		b.state.open()
+	}
+	return b.state.arrivals
+}
+
+// Open opens the barrier whatever the arrivals, releasing every waiting VU
+func (b *Barrier) Open() {
+	b.state.mu.Lock()
+	defer b.state.mu.Unlock()
+	b.state.open()
+}
+
+// IsOpen reports whether the barrier is open
+func (b *Barrier) IsOpen() bool {
+	select {
+	case <-b.state.opened:
+		return true
+	default:
+		return false
+	}
+}
+
+// Arrivals returns the arrivals so far by all VUs
+func (b *Barrier) Arrivals() int64 {
+	b.state.mu.Lock()
+	defer b.state.mu.Unlock()
+	return b.state.arrivals
+}
+
+// Wait blocks until the barrier opens and returns true. It returns false after timeout, e.g.
+// "5m", or when the test ends; without timeout it waits until either happens.
+func (b *Barrier) Wait(timeout ...string) (bool, error) {
+	ctx := context.Background()
+	if b.vu != nil && b.vu.Context() != nil {
+		ctx = b.vu.Context()
+	}
+	if len(timeout) > 0 && timeout[0] != "" {
+		d, err := time.ParseDuration(timeout[0])
+		if err != nil {
+			return false, fmt.Errorf("invalid barrier timeout %q: %v", timeout[0], err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	select {
+	case <-b.state.opened:
+		return true, nil
+	case <-ctx.Done():
+		return b.IsOpen(), nil
+	}
+}
+
+// ArriveAndWait arrives at the barrier and waits until it opens, as arrive() then wait()
+func (b *Barrier) ArriveAndWait(timeout ...string) (bool, error) {
+	b.Arrive()
+	return b.Wait(timeout...)
+}
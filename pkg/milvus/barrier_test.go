@@ -0,0 +1,78 @@
+package milvus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarrier(t *testing.T) {
+	shared := &sync.Map{}
+	ingest, err := (&Milvus{shared: shared}).Barrier("loaded", map[string]interface{}{"parties": 2})
+	require.NoError(t, err)
+	query, err := (&Milvus{shared: shared}).Barrier("loaded")
+	require.NoError(t, err)
+
+	opened, err := query.Wait("50ms")
+	require.NoError(t, err)
+	assert.False(t, opened, "no arrivals")
+
+	released := make(chan bool)
+	go func() {
+		opened, _ := query.Wait()
+		released <- opened
+	}()
+	assert.Equal(t, int64(1), ingest.Arrive())
+	assert.False(t, query.IsOpen())
+	select {
+	case <-released:
+		t.Fatal("barrier opened before all parties arrived")
+	case <-time.After(50 * time.Millisecond):
+	}
+	opened, err = ingest.ArriveAndWait("1s")
+	require.NoError(t, err)
+	assert.True(t, opened)
+	assert.True(t, <-released, "waiting VUs are released")
+	assert.Equal(t, int64(2), query.Arrivals(), "state is shared by name")
+
+	_, err = (&Milvus{shared: shared}).Barrier("loaded", map[string]interface{}{"parties": 3})
+	assert.Error(t, err, "parties differ from the barrier of the name")
+	_, err = query.Wait("soon")
+	assert.Error(t, err)
+}
+
+func TestBarrierWaiterFirst(t *testing.T) {
+	shared := &sync.Map{}
+	query, err := (&Milvus{shared: shared}).Barrier("loaded")
+	require.NoError(t, err)
+	query.Arrive()
+	ingest, err := (&Milvus{shared: shared}).Barrier("loaded", map[string]interface{}{"parties": 2})
+	require.NoError(t, err, "the creator sets the parties of a barrier first created by a waiter")
+	assert.False(t, query.IsOpen())
+
+	ingest.Arrive()
+	assert.True(t, query.IsOpen(), "the waiter's barrier has the creator's parties")
+	_, err = (&Milvus{shared: shared}).Barrier("loaded", map[string]interface{}{"parties": 3})
+	assert.Error(t, err)
+}
+
+func TestBarrierFlag(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	flag, err := m.Barrier("inserts done")
+	require.NoError(t, err)
+	flag.Arrive()
+	assert.False(t, flag.IsOpen(), "only open() opens a barrier without parties")
+	flag.Open()
+	flag.Open()
+	opened, err := flag.Wait()
+	require.NoError(t, err)
+	assert.True(t, opened)
+
+	_, err = m.Barrier("")
+	assert.Error(t, err)
+	_, err = m.Barrier("negative", map[string]interface{}{"parties": -1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,226 @@
+package milvus
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ComputeGroundTruthOptions configures milvus.computeGroundTruth()
+type ComputeGroundTruthOptions struct {
+	IDs     []int64 `json:"ids,omitempty"`     // Primary keys of the base vectors (default: their row indices)
+	Workers int     `json:"workers,omitempty"` // Parallel workers (default: GOMAXPROCS)
+}
+
+// ComputeGroundTruth finds the exact topK nearest base vectors of each query vector by brute
+// force, using parallel workers. Metric is L2, IP or COSINE. The result is used like a loaded
+// groundTruth file, with query row indices as query IDs.
+//
+// Each query is compared with every base vector, so this suits small and medium datasets; use
+// precomputed ground truth files for large ones.
+//
+// Usage in k6:
+//
+//	const base = JSON.parse(open('data/base.json'));
+//	const queries = JSON.parse(open('data/queries.json'));
+//	const truth = milvus.computeGroundTruth(base, queries, 10, 'COSINE');
+//	client.search([queries[q]], 10, { vectorField: 'embedding', groundTruth: truth, queryIds: [q] });
+func (m *Milvus) ComputeGroundTruth(baseInput, queryInput interface{}, topK int, metric string, optionsInput ...interface{}) (*GroundTruth, error) {
+	var options ComputeGroundTruthOptions
+	if len(optionsInput) > 0 && optionsInput[0] != nil {
+		if err := convertViaJSON(optionsInput[0], &options); err != nil {
+			return nil, fmt.Errorf("invalid compute ground truth options: %v", err)
+		}
+	}
+	base, err := denseVectors(baseInput)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base vectors: %v", err)
+	}
+	queries, err := denseVectors(queryInput)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query vectors: %v", err)
+	}
+	return computeGroundTruth(base, queries, topK, metric, options)
+}
+
+func computeGroundTruth(base, queries [][]float32, topK int, metric string, options ComputeGroundTruthOptions) (*GroundTruth, error) {
+	if topK <= 0 {
+		return nil, fmt.Errorf("topK must be positive, got %d", topK)
+	}
+	if len(base[0]) != len(queries[0]) {
+		return nil, fmt.Errorf("base dimension %d does not match query dimension %d", len(base[0]), len(queries[0]))
+	}
+	if options.IDs != nil && len(options.IDs) != len(base) {
+		return nil, fmt.Errorf("ids has %d entries for %d base vectors", len(options.IDs), len(base))
+	}
+	distance, err := bruteForceDistance(strings.ToUpper(metric), base)
+	if err != nil {
+		return nil, err
+	}
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	k := min(topK, len(base))
+	truth := &GroundTruth{neighbors: newMatrix[int64](len(queries), k)}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(queries)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nearest := make(neighborHeap, 0, k)
+			for q := int(next.Add(1) - 1); q < len(queries); q = int(next.Add(1) - 1) {
+				nearest = nearest[:0]
+				query := distance.query(queries[q])
+				for i := range base {
+					d := distance.to(query, i)
+					if len(nearest) < k {
+						heap.Push(&nearest, neighbor{row: i, distance: d})
+					} else if d < nearest[0].distance {
+						// Rows are visited in order, so ties keep the lower row
+						nearest[0] = neighbor{row: i, distance: d}
+						heap.Fix(&nearest, 0)
+					}
+				}
+				row := truth.neighbors[q]
+				for i := len(nearest) - 1; i >= 0; i-- {
+					best := heap.Pop(&nearest).(neighbor)
+					row[i] = int64(best.row)
+					if options.IDs != nil {
+						row[i] = options.IDs[best.row]
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return truth, nil
+}
+
+// denseVectors converts JavaScript arrays, or vectors returned by other module functions, to
+// non-empty float vectors of one dimension
+func denseVectors(input interface{}) ([][]float32, error) {
+	vectors, ok := input.([][]float32)
+	if !ok {
+		typed, err := typedArrayVectors(input)
+		if err != nil {
+			return nil, err
+		}
+		vectors = typed
+	}
+	if vectors == nil {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &vectors); err != nil {
+			return nil, fmt.Errorf("expected an array of number arrays")
+		}
+	}
+	if len(vectors) == 0 || len(vectors[0]) == 0 {
+		return nil, fmt.Errorf("no vectors")
+	}
+	for i, vector := range vectors {
+		if len(vector) != len(vectors[0]) {
+			return nil, fmt.Errorf("vector %d has dimension %d, expected %d", i, len(vector), len(vectors[0]))
+		}
+	}
+	return vectors, nil
+}
+
+// bruteForce computes the distance of a query to each base vector; lower is nearer.
+// It is read-only once created, so workers share it.
+type bruteForce struct {
+	base   [][]float32
+	norms  []float64 // Base vector norms, for COSINE
+	metric string
+}
+
+// bruteForceQuery is a query vector with its norm, for COSINE
+type bruteForceQuery struct {
+	vector []float32
+	norm   float64
+}
+
+func bruteForceDistance(metric string, base [][]float32) (*bruteForce, error) {
+	b := &bruteForce{base: base, metric: metric}
+	switch metric {
+	case "L2", "IP":
+	case "COSINE":
+		b.norms = make([]float64, len(base))
+		for i, vector := range base {
+			b.norms[i] = math.Sqrt(dot(vector, vector))
+		}
+	default:
+		return nil, fmt.Errorf("metric must be L2, IP or COSINE, got %q", metric)
+	}
+	return b, nil
+}
+
+func (b *bruteForce) query(vector []float32) bruteForceQuery {
+	query := bruteForceQuery{vector: vector}
+	if b.norms != nil {
+		query.norm = math.Sqrt(dot(vector, vector))
+	}
+	return query
+}
+
+func (b *bruteForce) to(query bruteForceQuery, i int) float64 {
+	vector := b.base[i]
+	switch b.metric {
+	case "L2":
+		sum := 0.0
+		for j, v := range vector {
+			d := float64(v) - float64(query.vector[j])
+			sum += d * d
+		}
+		return sum
+	case "IP":
+		return -dot(vector, query.vector)
+	default:
+		if b.norms[i] == 0 || query.norm == 0 {
+			return 0
+		}
+		return -dot(vector, query.vector) / (b.norms[i] * query.norm)
+	}
+}
+
+func dot(a, b []float32) float64 {
+	sum := 0.0
+	for i, v := range a {
+		sum += float64(v) * float64(b[i])
+	}
+	return sum
+}
+
+// neighbor is a base row and its distance to a query
+type neighbor struct {
+	row      int
+	distance float64
+}
+
+// neighborHeap is a max-heap of the nearest neighbors found so far, farthest first
+type neighborHeap []neighbor
+
+func (h neighborHeap) Len() int { return len(h) }
+func (h neighborHeap) Less(i, j int) bool {
+	if h[i].distance != h[j].distance {
+		return h[i].distance > h[j].distance
+	}
+	return h[i].row > h[j].row
+}
+func (h neighborHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(neighbor)) }
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
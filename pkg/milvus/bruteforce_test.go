@@ -0,0 +1,95 @@
+package milvus
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeGroundTruth(t *testing.T) {
+	m := &Milvus{}
+	base := []interface{}{
+		[]interface{}{0.0, 0.0},
+		[]interface{}{1.0, 0.0},
+		[]interface{}{0.0, 3.0},
+		[]interface{}{2.0, 2.0},
+		[]interface{}{-1.0, 0.0},
+	}
+	queries := []interface{}{[]interface{}{0.9, 0.1}, []interface{}{0.0, 2.0}}
+
+	truth, err := m.ComputeGroundTruth(base, queries, 3, "l2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, truth.Size())
+	neighbors, _ := truth.Neighbors(0)
+	assert.Equal(t, []int64{1, 0, 4}, neighbors)
+	neighbors, _ = truth.Neighbors(1)
+	assert.Equal(t, []int64{2, 0, 3}, neighbors) // 0 and 3 tie at 4; the lower row wins
+
+	// IP favors long vectors, COSINE only the angle
+	truth, err = m.ComputeGroundTruth(base, queries, 2, "IP")
+	require.NoError(t, err)
+	neighbors, _ = truth.Neighbors(0)
+	assert.Equal(t, []int64{3, 1}, neighbors)
+	truth, err = m.ComputeGroundTruth(base, queries, 2, "COSINE", map[string]interface{}{"ids": []interface{}{10, 11, 12, 13, 14}})
+	require.NoError(t, err)
+	neighbors, _ = truth.Neighbors(0)
+	assert.Equal(t, []int64{11, 13}, neighbors)
+
+	// topK is capped at the number of base vectors
+	truth, err = m.ComputeGroundTruth([][]float32{{1}, {2}}, [][]float32{{0}}, 10, "L2")
+	require.NoError(t, err)
+	neighbors, _ = truth.Neighbors(0)
+	assert.Equal(t, []int64{0, 1}, neighbors)
+}
+
+func TestComputeGroundTruthWorkers(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	vectors := func(n int) [][]float32 {
+		out := newMatrix[float32](n, 8)
+		for _, vector := range out {
+			for j := range vector {
+				vector[j] = rng.Float32()
+			}
+		}
+		return out
+	}
+	base, queries := vectors(500), vectors(40)
+
+	serial, err := computeGroundTruth(base, queries, 10, "L2", ComputeGroundTruthOptions{Workers: 1})
+	require.NoError(t, err)
+	parallel, err := computeGroundTruth(base, queries, 10, "L2", ComputeGroundTruthOptions{Workers: 7})
+	require.NoError(t, err)
+	assert.Equal(t, serial.neighbors, parallel.neighbors)
+
+	// The nearest neighbor of a base vector is itself
+	self, err := computeGroundTruth(base, base[:20], 1, "COSINE", ComputeGroundTruthOptions{})
+	require.NoError(t, err)
+	for q, row := range self.neighbors {
+		assert.Equal(t, int64(q), row[0])
+	}
+}
+
+func TestComputeGroundTruthErrors(t *testing.T) {
+	m := &Milvus{}
+	base := [][]float32{{1, 2}, {3, 4}}
+
+	for _, tc := range []struct {
+		base, queries interface{}
+		topK          int
+		metric        string
+		options       interface{}
+	}{
+		{base, [][]float32{{1, 2}}, 0, "L2", nil},
+		{base, [][]float32{{1, 2, 3}}, 1, "L2", nil},
+		{base, [][]float32{{1, 2}}, 1, "HAMMING", nil},
+		{base, [][]float32{{1, 2}}, 1, "L2", map[string]interface{}{"ids": []interface{}{1}}},
+		{[]interface{}{}, [][]float32{{1, 2}}, 1, "L2", nil},
+		{[]interface{}{[]interface{}{1.0}, []interface{}{1.0, 2.0}}, [][]float32{{1}}, 1, "L2", nil},
+		{"vectors", [][]float32{{1, 2}}, 1, "L2", nil},
+	} {
+		_, err := m.ComputeGroundTruth(tc.base, tc.queries, tc.topK, tc.metric, tc.options)
+		assert.Error(t, err, tc)
+	}
+}
@@ -0,0 +1,256 @@
+package milvus
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// Capacity test defaults
+const (
+	defaultCapacityBatchSize    = 1000
+	defaultCapacityWindow       = 50
+	defaultCapacityMaxErrorRate = 0.01
+)
+
+// CapacityTestConfig configures client.capacityTest()
+type CapacityTestConfig struct {
+	CollectionName string  `json:"collectionName,omitempty"` // Default: the collection of the client
+	VectorField    string  `json:"vectorField,omitempty"`    // Field of the source vectors (default: the only FloatVector field)
+	IDStart        int64   `json:"idStart,omitempty"`        // First primary key of inserted rows (default: 0)
+	BatchSize      int     `json:"batchSize,omitempty"`      // Rows per Insert RPC (default: 1000)
+	Concurrency    int     `json:"concurrency,omitempty"`    // Insert RPCs in flight at once (default: 1)
+	Window         int     `json:"window,omitempty"`         // Latest Insert RPCs the limits are checked against (default: 50)
+	MaxErrorRate   float64 `json:"maxErrorRate,omitempty"`   // Fraction of failed RPCs in the window that stops the test (default: 0.01)
+	MaxP99         float64 `json:"maxP99,omitempty"`         // p99 RPC time in the window, in milliseconds, that stops the test (default: none)
+	MaxRows        int64   `json:"maxRows,omitempty"`        // Rows after which the test stops (default: none)
+	MaxDuration    string  `json:"maxDuration,omitempty"`    // Time after which the test stops, e.g. "30m" (default: none)
+}
+
+// capacityWindow holds the outcome of the latest Insert RPCs of a capacity test
+type capacityWindow struct {
+	elapsed []time.Duration
+	failed  []bool
+	next    int
+	full    bool
+	sorted  []time.Duration // Scratch of p99
+}
+
+func (w *capacityWindow) add(elapsed time.Duration, failed bool) {
+	w.elapsed[w.next] = elapsed
+	w.failed[w.next] = failed
+	if w.next++; w.next == len(w.elapsed) {
+		w.next, w.full = 0, true
+	}
+}
+
+// stats returns the fraction of failed RPCs and the p99 RPC time of the window
+func (w *capacityWindow) stats() (float64, time.Duration) {
+	n := len(w.elapsed)
+	if !w.full {
+		n = w.next
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	failed := 0
+	for _, f := range w.failed[:n] {
+		if f {
+			failed++
+		}
+	}
+	w.sorted = append(w.sorted[:0], w.elapsed[:n]...)
+	slices.Sort(w.sorted)
+	return float64(failed) / float64(n), w.sorted[int(math.Ceil(0.99*float64(n)))-1]
+}
+
+// CapacityTest inserts vectors from a vector generator or dataset as fast as the collection takes
+// them, until the error rate or p99 time of the latest Insert RPCs crosses its limit, or maxRows or
+// maxDuration is reached, or the test ends. The result reports the rows inserted, the throughput
+// and the reason the test stopped: the "max capacity" run of a deployment.
+//
+// Inserted rows get fresh Int64 primary keys from the counter of client.prepareBatch() newIds,
+// unless Milvus assigns them. Each Insert RPC emits its own metrics, as with the batchSize option
+// of insert().
+//
+// Usage in k6:
+//
+//	export function setup() {
+//	    const client = milvus.client('localhost:19530', 'bench');
+//	    const result = client.capacityTest({ source: gen, batchSize: 1000, concurrency: 8, maxP99: 500 });
+//	    console.log(`${result.result.rows} rows at ${result.result.rows_per_sec} rows/s: ${result.result.stop_reason}`);
+//	}
+func (c *Client) CapacityTest(configInput map[string]interface{}) interface{} {
+	start := time.Now()
+	fail := func(err string) interface{} {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err,
+		})
+	}
+
+	var config CapacityTestConfig
+	if err := convertViaJSON(splitModuleObjects(configInput, "source", "scalars"), &config); err != nil {
+		return fail(fmt.Sprintf("invalid capacity test config: %v", err))
+	}
+	source := configInput["source"]
+	if !isVectorSource(source) {
+		return fail(fmt.Sprintf("capacity test source must be a vectorGenerator, annDataset or sharedVectors, got %T", source))
+	}
+	var scalars *DataFaker
+	if value, ok := configInput["scalars"]; ok && value != nil {
+		if scalars, ok = value.(*DataFaker); !ok {
+			return fail(fmt.Sprintf("capacity test scalars must be a milvus.dataFaker() object, got %T", value))
+		}
+	}
+	if config.BatchSize < 0 || config.Concurrency < 0 || config.Window < 0 || config.MaxErrorRate < 0 || config.MaxP99 < 0 || config.MaxRows < 0 {
+		return fail("batchSize, concurrency, window, maxErrorRate, maxP99 and maxRows must not be negative")
+	}
+	batchSize := optionalPositive(config.BatchSize, defaultCapacityBatchSize)
+	concurrency := max(1, config.Concurrency)
+	window := optionalPositive(config.Window, defaultCapacityWindow)
+	maxErrorRate := config.MaxErrorRate
+	if maxErrorRate == 0 {
+		maxErrorRate = defaultCapacityMaxErrorRate
+	}
+	maxP99 := time.Duration(config.MaxP99 * float64(time.Millisecond))
+	var maxDuration time.Duration
+	if config.MaxDuration != "" {
+		d, err := time.ParseDuration(config.MaxDuration)
+		if err != nil {
+			return fail(fmt.Sprintf("invalid maxDuration %q: %v", config.MaxDuration, err))
+		}
+		maxDuration = d
+	}
+
+	coll := c.getCollectionName(config.CollectionName)
+	if coll == "" {
+		return fail("collection name required")
+	}
+	collection, err := c.milvus().DescribeCollection(c.context(), milvusclient.NewDescribeCollectionOption(coll))
+	if err != nil {
+		return fail(fmt.Sprintf("failed to describe collection %s: %v", coll, err))
+	}
+	vectorField, pkField, err := capacityFields(collection.Schema, config.VectorField)
+	if err != nil {
+		return fail(err.Error())
+	}
+	ids, err := idCounter(c.shared, coll, pkField, config.IDStart)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	stats := &capacityWindow{elapsed: make([]time.Duration, window), failed: make([]bool, window)}
+	rng := rand.New(rand.NewSource(start.UnixNano()))
+	ctx := c.context()
+	var rows, batches, failedBatches int64
+	var errorRate float64
+	var p99 time.Duration
+	stopReason := ""
+	for stopReason == "" {
+		count := batchSize * concurrency
+		if config.MaxRows > 0 {
+			count = int(min(int64(count), config.MaxRows-rows))
+		}
+		vectors, err := workloadVectors(source, rng, count, false)
+		if err != nil {
+			return fail(err.Error())
+		}
+		data := map[string]interface{}{vectorField: vectors}
+		if scalars != nil {
+			values, err := scalars.Next(count)
+			if err != nil {
+				return fail(err.Error())
+			}
+			for field, column := range values {
+				data[field] = column
+			}
+		}
+		if pkField != "" {
+			first := ids.Add(int64(count)) - int64(count)
+			data[pkField] = rowIndices(int(first), int(first)+count)
+		}
+		columns, err := c.convertDataToColumns(data)
+		if err != nil {
+			return fail(fmt.Sprintf("failed to convert data: %v", err))
+		}
+		chunks, err := insertChunks(columns, batchSize)
+		if err != nil {
+			return fail(err.Error())
+		}
+		c.insertChunks(coll, "", chunks, concurrency)
+
+		for _, chunk := range chunks {
+			batches++
+			stats.add(chunk.elapsed, chunk.err != nil)
+			if chunk.err != nil {
+				failedBatches++
+				continue
+			}
+			rows += chunk.result.InsertCount
+		}
+		errorRate, p99 = stats.stats()
+		switch {
+		case stats.full && errorRate > maxErrorRate:
+			stopReason = "error_rate"
+		case stats.full && maxP99 > 0 && p99 > maxP99:
+			stopReason = "p99"
+		case config.MaxRows > 0 && rows >= config.MaxRows:
+			stopReason = "max_rows"
+		case maxDuration > 0 && time.Since(start) >= maxDuration:
+			stopReason = "max_duration"
+		case ctx.Err() != nil:
+			stopReason = "canceled"
+		}
+	}
+
+	elapsed := time.Since(start)
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(elapsed.Milliseconds()),
+		Result: map[string]interface{}{
+			"collection":     coll,
+			"rows":           rows,
+			"batches":        batches,
+			"failed_batches": failedBatches,
+			"rows_per_sec":   float64(rows) / elapsed.Seconds(),
+			"stop_reason":    stopReason,
+			"error_rate":     errorRate,
+			"p99":            float64(p99.Microseconds()) / 1000,
+		},
+	})
+}
+
+// capacityFields returns the vector field of the source and the Int64 primary key to fill with
+// fresh keys, or "" when Milvus assigns the keys
+func capacityFields(schema *entity.Schema, vectorField string) (string, string, error) {
+	var vectorFields []string
+	var pkField string
+	for _, field := range schema.Fields {
+		if field.DataType == entity.FieldTypeFloatVector {
+			vectorFields = append(vectorFields, field.Name)
+		}
+		if field.PrimaryKey && !field.AutoID {
+			if field.DataType != entity.FieldTypeInt64 {
+				return "", "", fmt.Errorf("capacity test requires an Int64 or auto ID primary key, got %s %s", field.DataType.String(), field.Name)
+			}
+			pkField = field.Name
+		}
+	}
+	if vectorField == "" {
+		if len(vectorFields) != 1 {
+			return "", "", fmt.Errorf("capacity test requires vectorField with %d FloatVector fields in the collection", len(vectorFields))
+		}
+		vectorField = vectorFields[0]
+	}
+	if !slices.Contains(vectorFields, vectorField) {
+		return "", "", fmt.Errorf("vectorField %s is not a FloatVector field of the collection", vectorField)
+	}
+	return vectorField, pkField, nil
+}
@@ -0,0 +1,119 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capacityServer accepts inserts until failAfter batches, then rejects them
+type capacityServer struct {
+	chunkServer
+	failAfter int
+}
+
+func (s *capacityServer) Insert(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+	s.mu.Lock()
+	full := s.failAfter > 0 && len(s.batches) >= s.failAfter
+	s.mu.Unlock()
+	if full {
+		return nil, fmt.Errorf("quota exceeded")
+	}
+	return s.chunkServer.Insert(ctx, req)
+}
+
+func capacityClient(t *testing.T, service *capacityServer) *Client {
+	return fakeClient(t, &Milvus{shared: &sync.Map{}}, service, WithCollection("bench"))
+}
+
+func TestCapacityTest(t *testing.T) {
+	gen, err := (&Milvus{}).VectorGenerator(map[string]interface{}{"dim": 2})
+	require.NoError(t, err)
+
+	t.Run("max rows", func(t *testing.T) {
+		service := &capacityServer{}
+		client := capacityClient(t, service)
+		result := client.CapacityTest(map[string]interface{}{"source": gen, "batchSize": 2, "concurrency": 2, "maxRows": 6, "idStart": 10}).(map[string]interface{})
+		require.Equal(t, true, result["success"], result["error"])
+		details := result["result"].(map[string]interface{})
+		assert.Equal(t, "max_rows", details["stop_reason"])
+		assert.Equal(t, float64(6), details["rows"])
+		assert.Equal(t, float64(3), details["batches"])
+		assert.ElementsMatch(t, [][]int64{{10, 11}, {12, 13}, {14, 15}}, service.batches, "fresh keys from idStart")
+		assert.Positive(t, details["rows_per_sec"])
+	})
+
+	t.Run("error rate", func(t *testing.T) {
+		service := &capacityServer{failAfter: 4}
+		client := capacityClient(t, service)
+		result := client.CapacityTest(map[string]interface{}{"source": gen, "batchSize": 1, "window": 4, "maxErrorRate": 0.2, "maxRows": 100}).(map[string]interface{})
+		require.Equal(t, true, result["success"], result["error"])
+		details := result["result"].(map[string]interface{})
+		assert.Equal(t, "error_rate", details["stop_reason"])
+		assert.Equal(t, float64(4), details["rows"])
+		assert.Equal(t, float64(5), details["batches"], "one failure in a window of 4 crosses 0.2")
+		assert.Equal(t, 0.25, details["error_rate"])
+	})
+
+	t.Run("p99", func(t *testing.T) {
+		// Each insert of the server takes 20ms
+		client := capacityClient(t, &capacityServer{})
+		result := client.CapacityTest(map[string]interface{}{"source": gen, "batchSize": 1, "window": 2, "maxP99": 5, "maxRows": 100}).(map[string]interface{})
+		require.Equal(t, true, result["success"], result["error"])
+		details := result["result"].(map[string]interface{})
+		assert.Equal(t, "p99", details["stop_reason"])
+		assert.Equal(t, float64(2), details["rows"], "limits apply once the window is full")
+		assert.GreaterOrEqual(t, details["p99"], 20.0)
+	})
+
+	client := capacityClient(t, &capacityServer{})
+	for _, config := range []map[string]interface{}{
+		{"source": "vectors"},
+		{"source": gen, "scalars": 1},
+		{"source": gen, "window": -1},
+		{"source": gen, "maxDuration": "soon"},
+		{"source": gen, "vectorField": "missing", "maxRows": 1},
+	} {
+		result := client.CapacityTest(config).(map[string]interface{})
+		assert.Equal(t, false, result["success"], config)
+	}
+}
+
+func TestCapacityFields(t *testing.T) {
+	schema := entity.NewSchema().
+		WithField(entity.NewField().WithName("pk").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true)).
+		WithField(entity.NewField().WithName("a").WithDataType(entity.FieldTypeFloatVector)).
+		WithField(entity.NewField().WithName("b").WithDataType(entity.FieldTypeFloatVector))
+	_, _, err := capacityFields(schema, "")
+	assert.ErrorContains(t, err, "requires vectorField")
+	field, pk, err := capacityFields(schema, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", field)
+	assert.Empty(t, pk, "Milvus assigns auto IDs")
+
+	schema = entity.NewSchema().WithField(entity.NewField().WithName("pk").WithDataType(entity.FieldTypeVarChar).WithIsPrimaryKey(true))
+	_, _, err = capacityFields(schema, "v")
+	assert.ErrorContains(t, err, "Int64 or auto ID")
+}
+
+func TestCapacityWindow(t *testing.T) {
+	w := &capacityWindow{elapsed: make([]time.Duration, 3), failed: make([]bool, 3)}
+	w.add(time.Millisecond, false)
+	rate, p99 := w.stats()
+	assert.Zero(t, rate)
+	assert.Equal(t, time.Millisecond, p99)
+	w.add(3*time.Millisecond, true)
+	w.add(2*time.Millisecond, false)
+	w.add(time.Millisecond, false)
+	rate, p99 = w.stats()
+	assert.True(t, w.full)
+	assert.InDelta(t, 1.0/3, rate, 1e-9)
+	assert.Equal(t, 3*time.Millisecond, p99)
+}
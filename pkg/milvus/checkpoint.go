@@ -0,0 +1,337 @@
+package milvus
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Checkpoint defaults
+const (
+	defaultCheckpointBatchSize = 1000
+	defaultCheckpointInterval  = 30 * time.Second
+	// checkpointVersion is the version of the checkpoint file format
+	checkpointVersion = 1
+)
+
+// CheckpointConfig configures milvus.checkpoint(). Seed and idStart only apply to a new
+// checkpoint: a resumed run keeps those of the file.
+type CheckpointConfig struct {
+	IDStart   int64  `json:"idStart,omitempty"`   // First primary key (default: 0)
+	BatchSize int    `json:"batchSize,omitempty"` // Keys per claim() (default: 1000)
+	Seed      int64  `json:"seed,omitempty"`      // Seed of the data of each range, derived with the range start
+	Interval  string `json:"interval,omitempty"`  // Least time between writes of the file by complete() (default: "30s")
+}
+
+// checkpointFile is the content of a checkpoint file
+type checkpointFile struct {
+	Version int                    `json:"version"`
+	Seed    int64                  `json:"seed"`
+	IDStart int64                  `json:"idStart"`
+	Next    int64                  `json:"next"` // First key never claimed
+	Done    [][2]int64             `json:"done"` // Completed key ranges [start, end), merged and sorted
+	Rows    int64                  `json:"rows"`
+	Values  map[string]interface{} `json:"values,omitempty"`
+	Updated time.Time              `json:"updated"`
+}
+
+// checkpointState is the progress of a checkpoint file, shared by all VUs
+type checkpointState struct {
+	mu       sync.Mutex
+	path     string
+	file     checkpointFile
+	gaps     [][2]int64      // Ranges below next neither done nor claimed, reissued first
+	claimed  map[int64]int64 // Ranges claimed and not completed, by start
+	resumed  bool
+	interval time.Duration
+	saved    time.Time
+}
+
+// Checkpoint persists the progress of a long ingest, such as a multi-day soak test, so that a
+// restarted run resumes where the previous one stopped. claim() hands out primary key ranges
+// with the seed of their data; complete() records a range as inserted and writes the file at
+// most every interval; release() returns a failed range. Ranges claimed but not completed when
+// the run stopped are handed out again first on resume, so no key is lost; ranges completed
+// after the last write are handed out again too, so upserts keep keys unique. The seed of a
+// range is the one a per-iteration generator of the checkpoint seed draws from after
+// replay(0, range.start), so a range handed out again gets the same data. The file is replaced
+// atomically, so a crash while writing keeps the previous checkpoint. Checkpoints of the same
+// path share their progress across all VUs.
+//
+// Usage in k6:
+//
+//	const cp = milvus.checkpoint('soak.checkpoint.json', { batchSize: 1000, seed: 42 });
+//	const gen = milvus.vectorGenerator({ dim: 128, seed: cp.progress().seed, perIteration: true });
+//	export default function () {
+//	    const range = cp.claim();
+//	    gen.replay(0, range.start);
+//	    const result = client.upsert({ id: range.ids, embedding: gen.next(range.count) });
+//	    result.success ? cp.complete(range) : cp.release(range);
+//	}
+//	export function teardown() { cp.save(); }
+type Checkpoint struct {
+	state     *checkpointState
+	batchSize int
+}
+
+// Checkpoint opens the checkpoint of a file, resuming from it if it exists
+func (m *Milvus) Checkpoint(path string, configInput ...interface{}) (*Checkpoint, error) {
+	var config CheckpointConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid checkpoint config: %v", err)
+		}
+	}
+	if path == "" {
+		return nil, fmt.Errorf("checkpoint path required")
+	}
+	if config.BatchSize < 0 {
+		return nil, fmt.Errorf("checkpoint batchSize must be positive, got %d", config.BatchSize)
+	}
+	interval := defaultCheckpointInterval
+	if config.Interval != "" {
+		var err error
+		if interval, err = time.ParseDuration(config.Interval); err != nil || interval < 0 {
+			return nil, fmt.Errorf("checkpoint interval must be a duration, got %q", config.Interval)
+		}
+	}
+	state, err := sharedValue(m.shared, "checkpoint\x00"+path, func() (*checkpointState, error) {
+		return loadCheckpoint(path, config, interval)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Checkpoint{state: state, batchSize: optionalPositive(config.BatchSize, defaultCheckpointBatchSize)}, nil
+}
+
+// loadCheckpoint reads the checkpoint file, or starts a new checkpoint if there is none
+func loadCheckpoint(path string, config CheckpointConfig, interval time.Duration) (*checkpointState, error) {
+	s := &checkpointState{path: path, claimed: make(map[int64]int64), interval: interval, saved: time.Now()}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.file = checkpointFile{Version: checkpointVersion, Seed: config.Seed, IDStart: config.IDStart, Next: config.IDStart, Done: [][2]int64{}}
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &s.file); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %v", path, err)
+	}
+	if s.file.Version != checkpointVersion {
+		return nil, fmt.Errorf("checkpoint %s has version %d, expected %d", path, s.file.Version, checkpointVersion)
+	}
+	if s.file.Done == nil {
+		s.file.Done = [][2]int64{}
+	}
+	// Keys below next that are not done were claimed by the stopped run and never inserted
+	at := s.file.IDStart
+	for _, done := range s.file.Done {
+		if done[0] < at || done[1] <= done[0] || done[1] > s.file.Next {
+			return nil, fmt.Errorf("invalid checkpoint %s: range [%d, %d) out of order", path, done[0], done[1])
+		}
+		if done[0] > at {
+			s.gaps = append(s.gaps, [2]int64{at, done[0]})
+		}
+		at = done[1]
+	}
+	if at < s.file.Next {
+		s.gaps = append(s.gaps, [2]int64{at, s.file.Next})
+	}
+	s.resumed = true
+	return s, nil
+}
+
+// Claim hands out the next key range of count keys (default: batchSize) as { start, end, count,
+// ids, seed }: the keys start to end-1, with the seed of their data. Ranges left over by a
+// stopped run come first, and may be shorter than count.
+func (c *Checkpoint) Claim(count ...int) (map[string]interface{}, error) {
+	n := c.batchSize
+	if len(count) > 0 {
+		if count[0] <= 0 {
+			return nil, fmt.Errorf("claim count must be positive, got %d", count[0])
+		}
+		n = count[0]
+	}
+	s := c.state
+	s.mu.Lock()
+	var start, end int64
+	if len(s.gaps) > 0 {
+		gap := &s.gaps[0]
+		start, end = gap[0], min(gap[1], gap[0]+int64(n))
+		if gap[0] = end; gap[0] == gap[1] {
+			s.gaps = s.gaps[1:]
+		}
+	} else {
+		start, end = s.file.Next, s.file.Next+int64(n)
+		s.file.Next = end
+	}
+	s.claimed[start] = end
+	seed := streamSeed(s.file.Seed, 0, start)
+	s.mu.Unlock()
+
+	ids := make([]int64, end-start)
+	for i := range ids {
+		ids[i] = start + int64(i)
+	}
+	return map[string]interface{}{"start": start, "end": end, "count": int(end - start), "ids": ids, "seed": seed}, nil
+}
+
+// checkpointRange reads the range returned by claim()
+func checkpointRange(input interface{}) (int64, int64, error) {
+	var r struct {
+		Start *int64 `json:"start"`
+		End   *int64 `json:"end"`
+	}
+	if err := convertViaJSON(input, &r); err != nil || r.Start == nil || r.End == nil {
+		return 0, 0, fmt.Errorf("range must be a range returned by claim()")
+	}
+	return *r.Start, *r.End, nil
+}
+
+// Complete records a claimed range as inserted, and writes the checkpoint file if interval has
+// passed since the last write
+func (c *Checkpoint) Complete(rangeInput interface{}) error {
+	start, end, err := checkpointRange(rangeInput)
+	if err != nil {
+		return err
+	}
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.claimed[start] != end {
+		return fmt.Errorf("range [%d, %d) is not claimed", start, end)
+	}
+	delete(s.claimed, start)
+	s.file.Done = mergeRange(s.file.Done, [2]int64{start, end})
+	s.file.Rows += end - start
+	if time.Since(s.saved) >= s.interval {
+		return s.save()
+	}
+	return nil
+}
+
+// Release returns a claimed range that was not inserted, to be claimed again
+func (c *Checkpoint) Release(rangeInput interface{}) error {
+	start, end, err := checkpointRange(rangeInput)
+	if err != nil {
+		return err
+	}
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.claimed[start] != end {
+		return fmt.Errorf("range [%d, %d) is not claimed", start, end)
+	}
+	delete(s.claimed, start)
+	s.gaps = mergeRange(s.gaps, [2]int64{start, end})
+	return nil
+}
+
+// mergeRange inserts a range into sorted disjoint ranges, joining adjacent ones
+func mergeRange(ranges [][2]int64, r [2]int64) [][2]int64 {
+	i, _ := slices.BinarySearchFunc(ranges, r, func(a, b [2]int64) int { return cmp.Compare(a[0], b[0]) })
+	ranges = slices.Insert(ranges, i, r)
+	if i+1 < len(ranges) && ranges[i][1] == ranges[i+1][0] {
+		ranges[i][1] = ranges[i+1][1]
+		ranges = slices.Delete(ranges, i+1, i+2)
+	}
+	if i > 0 && ranges[i-1][1] == ranges[i][0] {
+		ranges[i-1][1] = ranges[i][1]
+		ranges = slices.Delete(ranges, i, i+1)
+	}
+	return ranges
+}
+
+// Save writes the checkpoint file now, e.g. in teardown()
+func (c *Checkpoint) Save() error {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	return c.state.save()
+}
+
+// save writes the file next to the checkpoint and renames it over the checkpoint
+func (s *checkpointState) save() error {
+	s.file.Updated = time.Now().UTC()
+	data, err := json.MarshalIndent(&s.file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".part-*")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	s.saved = time.Now()
+	return nil
+}
+
+// Set stores a JSON value in the checkpoint, e.g. the phase of a soak test, written with the
+// progress
+func (c *Checkpoint) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("checkpoint value %s is not JSON: %v", key, err)
+	}
+	var stored interface{}
+	_ = json.Unmarshal(data, &stored)
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	if c.state.file.Values == nil {
+		c.state.file.Values = make(map[string]interface{})
+	}
+	c.state.file.Values[key] = stored
+	return nil
+}
+
+// Get returns a value stored with set(), or null
+func (c *Checkpoint) Get(key string) interface{} {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	return c.state.file.Values[key]
+}
+
+// Progress returns { rows, next, pending, claimed, resumed, seed }: the rows completed, the
+// first key never claimed, the keys left over by a stopped run and not yet claimed again, the
+// keys claimed and not completed, whether the checkpoint was resumed from its file, and the
+// seed of the checkpoint
+func (c *Checkpoint) Progress() map[string]interface{} {
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pending, claimed int64
+	for _, gap := range s.gaps {
+		pending += gap[1] - gap[0]
+	}
+	for start, end := range s.claimed {
+		claimed += end - start
+	}
+	return map[string]interface{}{
+		"rows":    s.file.Rows,
+		"next":    s.file.Next,
+		"pending": pending,
+		"claimed": claimed,
+		"resumed": s.resumed,
+		"seed":    s.file.Seed,
+	}
+}
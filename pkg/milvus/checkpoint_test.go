@@ -0,0 +1,112 @@
+package milvus
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "soak.json")
+	config := map[string]interface{}{"batchSize": 10, "idStart": 100, "seed": 7, "interval": "0s"}
+
+	cp, err := (&Milvus{shared: &sync.Map{}}).Checkpoint(path, config)
+	require.NoError(t, err)
+	first, err := cp.Claim()
+	require.NoError(t, err)
+	second, err := cp.Claim()
+	require.NoError(t, err)
+	third, err := cp.Claim(5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), first["start"])
+	assert.Equal(t, int64(110), first["end"])
+	assert.Equal(t, []int64{120, 121, 122, 123, 124}, third["ids"])
+	seed, _ := (&Milvus{}).Seed(7, 0, 100)
+	assert.Equal(t, seed, first["seed"], "the seed a per-iteration generator replays for the range")
+
+	require.NoError(t, cp.Set("phase", map[string]interface{}{"day": 2}))
+	require.NoError(t, cp.Complete(first))
+	require.NoError(t, cp.Complete(third))
+	assert.Error(t, cp.Complete(first), "completed twice")
+	assert.Equal(t, map[string]interface{}{"rows": int64(15), "next": int64(125), "pending": int64(0), "claimed": int64(10), "resumed": false, "seed": int64(7)}, cp.Progress())
+
+	// The run stops with the second range claimed and not inserted
+	resumed, err := (&Milvus{shared: &sync.Map{}}).Checkpoint(path, map[string]interface{}{"batchSize": 10, "seed": 9})
+	require.NoError(t, err)
+	progress := resumed.Progress()
+	assert.Equal(t, true, progress["resumed"])
+	assert.Equal(t, int64(15), progress["rows"])
+	assert.Equal(t, int64(10), progress["pending"])
+	assert.Equal(t, int64(7), progress["seed"], "the seed of the file")
+	assert.Equal(t, map[string]interface{}{"day": float64(2)}, resumed.Get("phase"))
+
+	again, err := resumed.Claim()
+	require.NoError(t, err)
+	assert.Equal(t, second["start"], again["start"], "the lost range comes first")
+	assert.Equal(t, second["seed"], again["seed"])
+	next, err := resumed.Claim()
+	require.NoError(t, err)
+	assert.Equal(t, int64(125), next["start"])
+
+	require.NoError(t, resumed.Release(again))
+	retried, err := resumed.Claim(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{110, 111, 112}, retried["ids"], "released ranges are claimed again")
+}
+
+func TestCheckpointSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "soak.json")
+	cp, err := (&Milvus{shared: &sync.Map{}}).Checkpoint(path, map[string]interface{}{"batchSize": 4})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		r, err := cp.Claim()
+		require.NoError(t, err)
+		require.NoError(t, cp.Complete(r))
+	}
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "not written before the interval")
+
+	require.NoError(t, cp.Save())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var file map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &file))
+	assert.Equal(t, []interface{}{[]interface{}{float64(0), float64(12)}}, file["done"], "adjacent ranges are merged")
+	assert.Equal(t, float64(12), file["rows"])
+	matches, _ := filepath.Glob(path + ".part-*")
+	assert.Empty(t, matches)
+}
+
+func TestCheckpointErrors(t *testing.T) {
+	dir := t.TempDir()
+	m := &Milvus{shared: &sync.Map{}}
+	_, err := m.Checkpoint("")
+	assert.Error(t, err)
+	_, err = m.Checkpoint(filepath.Join(dir, "a.json"), map[string]interface{}{"interval": "often"})
+	assert.Error(t, err)
+
+	for name, content := range map[string]string{
+		"invalid JSON": `{"version": 1`,
+		"version":      `{"version": 2}`,
+		"overlap":      `{"version": 1, "next": 20, "done": [[0, 10], [5, 15]]}`,
+		"beyond next":  `{"version": 1, "next": 5, "done": [[0, 10]]}`,
+	} {
+		path := filepath.Join(dir, name+".json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := m.Checkpoint(path)
+		assert.Error(t, err, name)
+	}
+
+	cp, err := m.Checkpoint(filepath.Join(dir, "b.json"))
+	require.NoError(t, err)
+	_, err = cp.Claim(0)
+	assert.Error(t, err)
+	assert.Error(t, cp.Complete(map[string]interface{}{"start": 0, "end": 1000}), "not claimed")
+	assert.Error(t, cp.Release(map[string]interface{}{"start": 0}))
+	assert.Error(t, cp.Set("bad", func() {}))
+}
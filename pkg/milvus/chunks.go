@@ -0,0 +1,115 @@
+package milvus
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"go.k6.io/k6/metrics"
+)
+
+// insertChunk is a range of rows of insert columns, sent as one Insert RPC
+type insertChunk struct {
+	start   int
+	end     int
+	columns []column.Column
+	result  milvusclient.InsertResult
+	err     error
+	elapsed time.Duration // Time of the RPC
+}
+
+// insertChunks splits insert columns into chunks of batchSize rows; batchSize 0 sends all rows
+// in one chunk. Nullable columns hold only their valid values, so they cannot be split by row.
+func insertChunks(columns []column.Column, batchSize int) ([]*insertChunk, error) {
+	rows := columns[0].Len()
+	if batchSize <= 0 || batchSize >= rows {
+		return []*insertChunk{{start: 0, end: rows, columns: columns}}, nil
+	}
+	for _, col := range columns {
+		if col.Len() != rows {
+			return nil, fmt.Errorf("field %s has %d rows, expected %d", col.Name(), col.Len(), rows)
+		}
+		if col.Nullable() {
+			return nil, fmt.Errorf("batchSize cannot split nullable field %s", col.Name())
+		}
+	}
+	chunks := make([]*insertChunk, 0, (rows+batchSize-1)/batchSize)
+	for start := 0; start < rows; start += batchSize {
+		end := min(start+batchSize, rows)
+		chunk := &insertChunk{start: start, end: end, columns: make([]column.Column, len(columns))}
+		for i, col := range columns {
+			chunk.columns[i] = col.Slice(start, end)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// insertChunks sends chunks with at most concurrency Insert RPCs in flight. Each RPC emits its
+// own metrics through the client interceptors.
+func (c *Client) insertChunks(coll, partition string, chunks []*insertChunk, concurrency int) {
+	// The SDK client and context are resolved once, in the VU goroutine
+	sdk, ctx := c.milvus(), c.context()
+	send := func(chunk *insertChunk) {
+		option := milvusclient.NewColumnBasedInsertOption(coll, chunk.columns...)
+		if partition != "" {
+			option = option.WithPartition(partition)
+		}
+		start := time.Now()
+		chunk.result, chunk.err = sdk.Insert(ctx, option)
+		chunk.elapsed = time.Since(start)
+	}
+	runParallel(len(chunks), concurrency, func(i int) { send(chunks[i]) })
+}
+
+// pushChunkDurations emits the time of each Insert RPC of an insert split into chunks as
+// milvus_insert_chunk_duration, tagged with collection and concurrency
+func (c *Client) pushChunkDurations(coll string, chunks []*insertChunk, concurrency int) {
+	if c.metrics == nil || len(chunks) < 2 {
+		return
+	}
+	tags := map[string]string{"collection": coll, "concurrency": strconv.Itoa(max(concurrency, 1))}
+	samples := c.sampleBatch()
+	for _, chunk := range chunks {
+		samples.add(c.metrics.InsertChunkDuration, metrics.D(chunk.elapsed), tags)
+	}
+	samples.push()
+}
+
+// runParallel calls run for 0 to n-1 from up to concurrency goroutines; concurrency 1 or less
+// runs them one after the other in the calling goroutine
+func runParallel(n, concurrency int, run func(i int)) {
+	if concurrency <= 1 || n == 1 {
+		for i := 0; i < n; i++ {
+			run(i)
+		}
+		return
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < n; i = int(next.Add(1) - 1) {
+				run(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// sliceRows returns rows start to end of a vector column of insert data, for insert samples
+func sliceRows(rows interface{}, start, end int) interface{} {
+	switch v := rows.(type) {
+	case [][]float32:
+		return v[start:end]
+	case []interface{}:
+		return v[start:end]
+	}
+	return rows
+}
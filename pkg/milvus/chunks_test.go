@@ -0,0 +1,151 @@
+package milvus
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkServer records the primary keys of each insert and the most inserts in flight at once.
+// Inserts whose first key is failKey are rejected.
+type chunkServer struct {
+	milvuspb.UnimplementedMilvusServiceServer
+	mu       sync.Mutex
+	batches  [][]int64
+	inflight atomic.Int32
+	peak     atomic.Int32
+	failKey  int64
+}
+
+func (s *chunkServer) DescribeCollection(context.Context, *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error) {
+	schema := entity.NewSchema().WithName("bench").
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("embedding").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+	return &milvuspb.DescribeCollectionResponse{Status: &commonpb.Status{}, CollectionName: "bench", CollectionID: 1, Schema: schema.ProtoMessage()}, nil
+}
+
+func (s *chunkServer) Insert(_ context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+	current := s.inflight.Add(1)
+	defer s.inflight.Add(-1)
+	for peak := s.peak.Load(); current > peak && !s.peak.CompareAndSwap(peak, current); peak = s.peak.Load() {
+	}
+	time.Sleep(20 * time.Millisecond) // Keep the RPC in flight while the others start
+
+	var ids []int64
+	for _, data := range req.GetFieldsData() {
+		if data.GetFieldName() == "id" {
+			ids = data.GetScalars().GetLongData().GetData()
+		}
+	}
+	s.mu.Lock()
+	s.batches = append(s.batches, ids)
+	s.mu.Unlock()
+	if s.failKey != 0 && ids[0] == s.failKey {
+		return &milvuspb.MutationResult{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError, Code: 65535, Reason: "chunk rejected"}}, nil
+	}
+	return &milvuspb.MutationResult{
+		Status:    &commonpb.Status{},
+		InsertCnt: int64(len(ids)),
+		IDs:       &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+	}, nil
+}
+
+func chunkData(rows int) map[string]interface{} {
+	ids := make([]int64, rows)
+	vectors := make([][]float32, rows)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+		vectors[i] = []float32{float32(i), 1}
+	}
+	return map[string]interface{}{"id": ids, "embedding": vectors}
+}
+
+func TestInsertChunks(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	service := &chunkServer{}
+	client := benchClient(t, service, vu)
+	sample := newInsertSample(InsertSampleConfig{Size: 100})
+
+	result := client.Insert(chunkData(10), map[string]interface{}{"batchSize": 3, "concurrency": 2, "sample": sample}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, map[string]interface{}{"insert_count": float64(10), "chunks": float64(4)}, result["result"])
+	assert.Equal(t, int32(2), service.peak.Load(), "at most concurrency inserts in flight")
+
+	sort.Slice(service.batches, func(i, j int) bool { return service.batches[i][0] < service.batches[j][0] })
+	assert.Equal(t, [][]int64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10}}, service.batches)
+
+	// Each chunk is recorded with its own rows
+	require.Equal(t, 10, sample.Size())
+	for i, id := range sample.ids {
+		assert.Equal(t, []float32{float32(id.(int64) - 1), 1}, sample.vectors[i])
+	}
+
+	var batchSizes []float64
+	chunkDurations := 0
+	for len(samples) > 0 {
+		for _, s := range (<-samples).GetSamples() {
+			switch s.Metric.Name {
+			case "milvus_batch_size":
+				batchSizes = append(batchSizes, s.Value)
+			case "milvus_insert_chunk_duration":
+				chunkDurations++
+				assert.Equal(t, map[string]string{"collection": "bench", "concurrency": "2"}, s.Tags.Map())
+			}
+		}
+	}
+	sort.Float64s(batchSizes)
+	assert.Equal(t, []float64{1, 3, 3, 3}, batchSizes, "one batch size sample per chunk")
+	assert.Equal(t, 4, chunkDurations, "one duration sample per chunk")
+}
+
+func TestInsertChunksFailure(t *testing.T) {
+	service := &chunkServer{failKey: 4}
+	client := benchClient(t, service, &metricsVU{})
+
+	result := client.Insert(chunkData(6), map[string]interface{}{"batchSize": 3, "concurrency": 4}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "failed to insert 1 of 2 chunks")
+	assert.Contains(t, result["error"], "chunk rejected")
+	assert.Equal(t, map[string]interface{}{"insert_count": float64(3), "chunks": float64(2)}, result["result"], "the other chunk was inserted")
+
+	result = client.Insert(chunkData(6), map[string]interface{}{"batchSize": -1}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "must not be negative")
+}
+
+func TestInsertChunksSplit(t *testing.T) {
+	columns := []column.Column{
+		column.NewColumnInt64("id", []int64{1, 2, 3, 4, 5}),
+		column.NewColumnVarChar("title", []string{"a", "b", "c", "d", "e"}),
+	}
+	chunks, err := insertChunks(columns, 2)
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+	assert.Equal(t, []int64{5}, chunks[2].columns[0].(*column.ColumnInt64).Data())
+	assert.Equal(t, []string{"c", "d"}, chunks[1].columns[1].(*column.ColumnVarChar).Data())
+	assert.Equal(t, [2]int{4, 5}, [2]int{chunks[2].start, chunks[2].end})
+
+	whole, err := insertChunks(columns, 0)
+	require.NoError(t, err)
+	require.Len(t, whole, 1)
+	assert.Equal(t, columns, whole[0].columns)
+
+	nullable, err := column.NewNullableColumnInt64("stock", []int64{1}, []bool{true, false, false, false, false})
+	require.NoError(t, err)
+	_, err = insertChunks(append(columns, nullable), 2)
+	assert.ErrorContains(t, err, "nullable field stock")
+
+	assert.Equal(t, [][]float32{{2}}, sliceRows([][]float32{{1}, {2}, {3}}, 1, 2))
+	assert.Equal(t, []interface{}{"b"}, sliceRows([]interface{}{"a", "b"}, 1, 2))
+}
@@ -0,0 +1,249 @@
+package milvus
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Churn workload defaults
+const (
+	defaultChurnBatchSize   = 100
+	defaultChurnDeleteRatio = 0.1
+)
+
+// ChurnWorkloadConfig configures milvus.churnWorkload()
+type ChurnWorkloadConfig struct {
+	CollectionName string  `json:"collectionName,omitempty"` // Default: the collection of the client
+	Keys           int64   `json:"keys"`                     // Primary keys churned, idStart to idStart+keys-1: the rows loaded before the test
+	IDStart        int64   `json:"idStart,omitempty"`        // First churned primary key (default: 0)
+	DeleteRatio    float64 `json:"deleteRatio,omitempty"`    // Fraction of the keys kept deleted (default: 0.1)
+	BatchSize      int     `json:"batchSize,omitempty"`      // Keys per upsert or delete (default: 100)
+	VectorField    string  `json:"vectorField,omitempty"`    // Vector field of upserts (default: "vector")
+	PKField        string  `json:"pkField,omitempty"`        // Int64 primary key (default: "id")
+	Seed           int64   `json:"seed,omitempty"`           // Seed of the key draws, together with the VU ID
+}
+
+// churnKeys is the state of the churned keys, shared by all VUs
+type churnKeys struct {
+	mu      sync.Mutex
+	deleted []bool
+	count   int64 // Keys deleted
+}
+
+// pick draws the keys of the next operation and applies it to the state: a delete of live keys
+// while fewer than ratio of the keys are deleted, else an upsert of keys drawn among all keys,
+// which restores deleted keys and updates live ones. It returns the operation, the key offsets
+// and, for upserts, which of them were deleted.
+func (k *churnKeys) pick(rng *rand.Rand, n int, ratio float64) (string, []int64, []bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	size := int64(len(k.deleted))
+	op := "upsert"
+	if float64(k.count+int64(n)) <= ratio*float64(size) {
+		op = "delete"
+	}
+	seen := make(map[int64]bool, n)
+	offsets := make([]int64, 0, n)
+	var restored []bool
+	// Bounded draws, as at most 1-ratio of the keys are live
+	for tries := 0; len(offsets) < n && tries < 10*n; tries++ {
+		offset := rng.Int63n(size)
+		if seen[offset] || (op == "delete" && k.deleted[offset]) {
+			continue
+		}
+		seen[offset] = true
+		offsets = append(offsets, offset)
+		if op == "delete" {
+			k.deleted[offset] = true
+			k.count++
+			continue
+		}
+		restored = append(restored, k.deleted[offset])
+		if k.deleted[offset] {
+			k.deleted[offset] = false
+			k.count--
+		}
+	}
+	return op, offsets, restored
+}
+
+// set marks keys deleted or live, undoing a failed operation
+func (k *churnKeys) set(offsets []int64, deleted []bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for i, offset := range offsets {
+		if k.deleted[offset] != deleted[i] {
+			k.deleted[offset] = deleted[i]
+			if deleted[i] {
+				k.count++
+			} else {
+				k.count--
+			}
+		}
+	}
+}
+
+// ratio returns the fraction of the keys deleted
+func (k *churnKeys) ratio() float64 {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return float64(k.count) / float64(len(k.deleted))
+}
+
+// ChurnWorkload continuously deletes and upserts primary keys of a loaded collection, keeping
+// deleteRatio of them deleted, to measure how deleted entities and the compactions that purge
+// them affect search latency over time. Each call deletes a batch of live keys while fewer than
+// deleteRatio of the keys are deleted, and otherwise upserts a batch of keys drawn among all
+// keys, restoring deleted ones and rewriting live ones. Every metric of an operation is tagged
+// with churn_op, and the milvus_churn_deleted_ratio Gauge reports the deleted fraction, tagged
+// with collection. The state of the keys is shared by all VUs churning the same keys.
+//
+// Usage in k6:
+//
+//	const churn = milvus.churnWorkload({ keys: 1e6, deleteRatio: 0.2, data: gen, vectorField: 'embedding' });
+//	export function churner() {
+//	    churn.run(milvus.getClient('localhost:19530', 'bench'));
+//	}
+type ChurnWorkload struct {
+	config  ChurnWorkloadConfig
+	data    interface{}
+	scalars *DataFaker
+	shared  *sync.Map
+	stream  seedStream
+}
+
+// ChurnWorkload creates an upsert and delete churn driver
+func (m *Milvus) ChurnWorkload(configInput map[string]interface{}) (*ChurnWorkload, error) {
+	var config ChurnWorkloadConfig
+	if err := convertViaJSON(splitModuleObjects(configInput, "data", "scalars"), &config); err != nil {
+		return nil, fmt.Errorf("invalid churn workload config: %v", err)
+	}
+	if config.Keys <= 0 {
+		return nil, fmt.Errorf("churn workload keys must be positive, got %d", config.Keys)
+	}
+	if config.DeleteRatio < 0 || config.DeleteRatio >= 1 {
+		return nil, fmt.Errorf("churn workload deleteRatio must be at least 0 and below 1, got %v", config.DeleteRatio)
+	}
+	if config.BatchSize < 0 {
+		return nil, fmt.Errorf("churn workload batchSize must not be negative, got %d", config.BatchSize)
+	}
+	if config.DeleteRatio == 0 {
+		config.DeleteRatio = defaultChurnDeleteRatio
+	}
+	config.BatchSize = int(min(int64(optionalPositive(config.BatchSize, defaultChurnBatchSize)), config.Keys))
+	if config.VectorField == "" {
+		config.VectorField = "vector"
+	}
+	if config.PKField == "" {
+		config.PKField = "id"
+	}
+
+	w := &ChurnWorkload{config: config, data: configInput["data"], shared: m.shared, stream: newSeedStream(m.vu, config.Seed, false)}
+	if !isVectorSource(w.data) {
+		return nil, fmt.Errorf("churn workload data must be a vectorGenerator, annDataset or sharedVectors, got %T", w.data)
+	}
+	if value, ok := configInput["scalars"]; ok && value != nil {
+		if w.scalars, ok = value.(*DataFaker); !ok {
+			return nil, fmt.Errorf("churn workload scalars must be a milvus.dataFaker() object, got %T", value)
+		}
+	}
+	return w, nil
+}
+
+// Run deletes or upserts one batch of keys with the client. The result is that of the operation,
+// with its name in "operation" and the fraction of the keys deleted after it in "deleted_ratio".
+func (w *ChurnWorkload) Run(client *Client) interface{} {
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "run requires a client"})
+	}
+	coll := client.getCollectionName(w.config.CollectionName)
+	if coll == "" {
+		return toMap(&OperationResult{Success: false, Error: "collection name required"})
+	}
+	keys, err := w.keys(coll)
+	if err != nil {
+		return toMap(&OperationResult{Success: false, Error: err.Error()})
+	}
+
+	rng := w.stream.rand()
+	op, offsets, restored := keys.pick(rng, w.config.BatchSize, w.config.DeleteRatio)
+	ids := make([]int64, len(offsets))
+	for i, offset := range offsets {
+		ids[i] = w.config.IDStart + offset
+	}
+	tagged := client.withTags(map[string]string{"churn_op": op})
+	var result map[string]interface{}
+	if op == "delete" {
+		result = w.delete(tagged, coll, ids)
+		if result["success"] != true {
+			keys.set(offsets, make([]bool, len(offsets)))
+		}
+	} else {
+		result = w.upsert(tagged, coll, rng, ids)
+		if result["success"] != true {
+			keys.set(offsets, restored)
+		}
+	}
+
+	ratio := keys.ratio()
+	if client.metrics != nil {
+		client.pushMetric(client.metrics.ChurnDeletedRatio, ratio, map[string]string{"collection": coll})
+	}
+	result["operation"] = op
+	result["deleted_ratio"] = ratio
+	return result
+}
+
+// DeletedRatio returns the fraction of the keys of the collection deleted so far by all VUs
+func (w *ChurnWorkload) DeletedRatio(collectionName ...string) (float64, error) {
+	coll := w.config.CollectionName
+	if len(collectionName) > 0 && collectionName[0] != "" {
+		coll = collectionName[0]
+	}
+	if coll == "" {
+		return 0, fmt.Errorf("collection name required")
+	}
+	keys, err := w.keys(coll)
+	if err != nil {
+		return 0, err
+	}
+	return keys.ratio(), nil
+}
+
+// keys returns the state of the churned keys of a collection, shared by all VUs
+func (w *ChurnWorkload) keys(coll string) (*churnKeys, error) {
+	key := fmt.Sprintf("churn\x00%s\x00%s\x00%d\x00%d", coll, w.config.PKField, w.config.IDStart, w.config.Keys)
+	return sharedValue(w.shared, key, func() (*churnKeys, error) {
+		return &churnKeys{deleted: make([]bool, w.config.Keys)}, nil
+	})
+}
+
+func (w *ChurnWorkload) upsert(client *Client, coll string, rng *rand.Rand, ids []int64) map[string]interface{} {
+	vectors, err := workloadVectors(w.data, rng, len(ids), false)
+	if err != nil {
+		return toMap(&OperationResult{Success: false, Error: err.Error()})
+	}
+	data := map[string]interface{}{w.config.VectorField: vectors, w.config.PKField: ids}
+	if w.scalars != nil {
+		values, err := w.scalars.Next(len(ids))
+		if err != nil {
+			return toMap(&OperationResult{Success: false, Error: err.Error()})
+		}
+		for field, column := range values {
+			data[field] = column
+		}
+	}
+	return client.Upsert(data, coll).(map[string]interface{})
+}
+
+func (w *ChurnWorkload) delete(client *Client, coll string, ids []int64) map[string]interface{} {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = strconv.FormatInt(id, 10)
+	}
+	filter := fmt.Sprintf("%s in [%s]", w.config.PKField, strings.Join(keys, ","))
+	return client.Delete(filter, coll).(map[string]interface{})
+}
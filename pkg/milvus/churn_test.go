@@ -0,0 +1,122 @@
+package milvus
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// churnServer serves upserts and deletes, recording the keys of each upsert
+type churnServer struct {
+	workloadServer
+	upserts [][]int64
+}
+
+func (s *churnServer) Upsert(_ context.Context, req *milvuspb.UpsertRequest) (*milvuspb.MutationResult, error) {
+	var ids []int64
+	for _, data := range req.GetFieldsData() {
+		if data.GetFieldName() == "id" {
+			ids = data.GetScalars().GetLongData().GetData()
+		}
+	}
+	s.mu.Lock()
+	s.upserts = append(s.upserts, ids)
+	s.mu.Unlock()
+	return &milvuspb.MutationResult{
+		Status:    &commonpb.Status{},
+		UpsertCnt: int64(len(ids)),
+		IDs:       &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+	}, nil
+}
+
+func TestChurnWorkload(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+
+	service := &churnServer{}
+	client := benchClient(t, service, vu)
+	gen, err := (&Milvus{}).VectorGenerator(map[string]interface{}{"dim": 2, "seed": 1})
+	require.NoError(t, err)
+	churn, err := (&Milvus{vu: vu, shared: &sync.Map{}}).ChurnWorkload(map[string]interface{}{
+		"keys": 100, "idStart": 1000, "deleteRatio": 0.2, "batchSize": 10, "data": gen, "vectorField": "embedding",
+	})
+	require.NoError(t, err)
+
+	var ops []interface{}
+	for i := 0; i < 4; i++ {
+		result := churn.Run(client).(map[string]interface{})
+		require.Equal(t, true, result["success"], result["error"])
+		ops = append(ops, result["operation"])
+	}
+	// Deletes until 20 of the 100 keys are deleted, then upserts
+	assert.Equal(t, []interface{}{"delete", "delete", "upsert", "upsert"}, ops)
+	assert.Len(t, service.deletes, 2)
+	assert.Contains(t, service.deletes[0], "id in [10")
+	ratio, err := churn.DeletedRatio("bench")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, ratio, 0.2)
+
+	var ratios []float64
+	opTags := map[string]bool{}
+	for _, sample := range drainSamples(samples) {
+		if sample.Metric.Name == "milvus_churn_deleted_ratio" {
+			ratios = append(ratios, sample.Value)
+		}
+		if op, ok := sample.Tags.Get("churn_op"); ok {
+			opTags[op] = true
+		}
+	}
+	assert.Equal(t, []float64{0.1, 0.2}, ratios[:2])
+	assert.Equal(t, map[string]bool{"delete": true, "upsert": true}, opTags)
+}
+
+func TestChurnKeys(t *testing.T) {
+	keys := &churnKeys{deleted: make([]bool, 10)}
+	rng := rand.New(rand.NewSource(1))
+	op, offsets, _ := keys.pick(rng, 5, 0.5)
+	assert.Equal(t, "delete", op)
+	assert.Len(t, offsets, 5)
+	assert.Equal(t, 0.5, keys.ratio())
+
+	// Upserts restore the deleted keys they draw, and a failed upsert deletes them again
+	op, offsets, restored := keys.pick(rng, 10, 0.5)
+	assert.Equal(t, "upsert", op)
+	assert.Len(t, offsets, 10)
+	assert.Zero(t, keys.ratio())
+	keys.set(offsets, restored)
+	assert.Equal(t, 0.5, keys.ratio())
+
+	// Deletes draw only live keys
+	op, offsets, _ = keys.pick(rng, 5, 1)
+	assert.Equal(t, "delete", op)
+	for _, offset := range offsets {
+		assert.True(t, keys.deleted[offset])
+	}
+	assert.Equal(t, 1.0, keys.ratio())
+}
+
+func TestChurnWorkloadConfig(t *testing.T) {
+	gen, err := (&Milvus{}).VectorGenerator(map[string]interface{}{"dim": 2})
+	require.NoError(t, err)
+	m := &Milvus{shared: &sync.Map{}}
+	for _, config := range []map[string]interface{}{
+		{"data": gen},
+		{"keys": 10, "data": gen, "deleteRatio": 1},
+		{"keys": 10, "data": gen, "batchSize": -1},
+		{"keys": 10},
+	} {
+		_, err := m.ChurnWorkload(config)
+		assert.Error(t, err, config)
+	}
+	churn, err := m.ChurnWorkload(map[string]interface{}{"keys": 10, "data": gen})
+	require.NoError(t, err)
+	assert.Equal(t, 10, churn.config.BatchSize, "batches hold at most every key")
+	_, err = churn.DeletedRatio()
+	assert.Error(t, err, "collection name required")
+}
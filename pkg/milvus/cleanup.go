@@ -0,0 +1,239 @@
+package milvus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// EnvRunID sets the run ID of the test instead of a random one, e.g. to clean up after an
+// aborted run with the ID it logged
+const EnvRunID = "MILVUS_RUN_ID"
+
+// runIDProperty is the collection property holding the run ID of the test that created it
+const runIDProperty = "k6.run_id"
+
+// Kinds of tracked resources, in the order cleanup() drops them
+var resourceKinds = []string{"alias", "partition", "collection", "database"}
+
+// trackedResource is a resource created through a client during the test
+type trackedResource struct {
+	kind       string
+	name       string
+	collection string       // Of partitions and aliases
+	config     ClientConfig // Connection the resource was created with, in its database
+}
+
+// key identifies the resource across clients of the same cluster and database
+func (r *trackedResource) key() string {
+	database, collection := r.config.DBName, r.collection
+	switch r.kind {
+	case "database":
+		database = ""
+	case "alias":
+		collection = "" // Aliases are unique in their database
+	}
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s", r.kind, r.config.Address, database, collection, r.name)
+}
+
+// resourceRegistry tracks the collections, partitions, databases and aliases created through
+// clients during the test, for milvus.cleanup()
+type resourceRegistry struct {
+	once      sync.Once
+	runID     string
+	mu        sync.Mutex
+	resources []trackedResource // In creation order
+}
+
+// id returns the run ID of the test: MILVUS_RUN_ID, or random hex digits
+func (r *resourceRegistry) id() string {
+	r.once.Do(func() {
+		if r.runID = os.Getenv(EnvRunID); r.runID == "" {
+			r.runID = fmt.Sprintf("%08x", rand.Uint32())
+		}
+	})
+	return r.runID
+}
+
+func (r *resourceRegistry) track(resource trackedResource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := resource.key()
+	if !slices.ContainsFunc(r.resources, func(existing trackedResource) bool { return existing.key() == key }) {
+		r.resources = append(r.resources, resource)
+	}
+}
+
+// untrack forgets a dropped resource, and the partitions of a dropped collection
+func (r *resourceRegistry) untrack(resource trackedResource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := resource.key()
+	r.resources = slices.DeleteFunc(r.resources, func(existing trackedResource) bool {
+		if resource.kind == "collection" && existing.kind == "partition" {
+			return existing.collection == resource.name && existing.config.Address == resource.config.Address && existing.config.DBName == resource.config.DBName
+		}
+		return existing.key() == key
+	})
+}
+
+// snapshot returns the tracked resources
+func (r *resourceRegistry) snapshot() []trackedResource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.resources)
+}
+
+// trackResource records a resource created through the client, when the client tracks them
+func (c *Client) trackResource(kind, name, collection string) {
+	if c.resources != nil && c.config != nil {
+		c.resources.track(trackedResource{kind: kind, name: name, collection: collection, config: *c.config})
+	}
+}
+
+// untrackResource forgets a resource dropped through the client
+func (c *Client) untrackResource(kind, name, collection string) {
+	if c.resources != nil && c.config != nil {
+		c.resources.untrack(trackedResource{kind: kind, name: name, collection: collection, config: *c.config})
+	}
+}
+
+// RunID returns the run ID of the test, stored in the k6.run_id property of the collections it
+// creates. It is random unless MILVUS_RUN_ID is set.
+func (m *Milvus) RunID() string {
+	return m.resources.id()
+}
+
+// Cleanup drops the aliases, partitions, collections and databases created through gRPC clients
+// during the test and not dropped since, each with a connection to the cluster and database it
+// was created in. Given a client, it also drops the collections of the client's database whose
+// k6.run_id property is runId, the run ID of this test by default, so that the collections of an
+// aborted run can be removed by a later one. The result lists the resources dropped and those
+// that failed.
+//
+// Usage in k6:
+//
+//	export function teardown() {
+//	    const result = milvus.cleanup(milvus.getClient('localhost:19530'));
+//	    console.log(`dropped ${result.result.dropped.length} resources of run ${milvus.runId()}`);
+//	}
+func (m *Milvus) Cleanup(client *Client, runID ...string) interface{} {
+	start := time.Now()
+	id := m.RunID()
+	if len(runID) > 0 && runID[0] != "" {
+		id = runID[0]
+	}
+
+	dropped := []interface{}{}
+	failed := []interface{}{}
+	record := func(kind, name, collection, database string, result interface{}) {
+		entry := map[string]interface{}{"kind": kind, "name": name, "database": database}
+		if collection != "" {
+			entry["collection"] = collection
+		}
+		outcome, _ := result.(map[string]interface{})
+		if outcome["success"] == true {
+			dropped = append(dropped, entry)
+			return
+		}
+		entry["error"] = outcome["error"]
+		failed = append(failed, entry)
+	}
+
+	// Tracked resources are dropped newest first within each kind, with one connection per config.
+	// Partitions of tracked collections go with them, as loaded partitions cannot be dropped.
+	resources := m.resources.snapshot()
+	collections := make(map[string]bool)
+	for _, resource := range resources {
+		if resource.kind == "collection" {
+			collections[resource.key()] = true
+		}
+	}
+	clients := make(map[string]*Client)
+	defer func() {
+		for _, c := range clients {
+			_ = c.Close()
+		}
+	}()
+	for _, kind := range resourceKinds {
+		for i := len(resources) - 1; i >= 0; i-- {
+			resource := resources[i]
+			if resource.kind != kind {
+				continue
+			}
+			if kind == "partition" && collections[(&trackedResource{kind: "collection", name: resource.collection, config: resource.config}).key()] {
+				continue
+			}
+			key, err := json.Marshal(resource.config)
+			if err != nil {
+				record(kind, resource.name, resource.collection, resource.config.DBName, toMap(&OperationResult{Error: err.Error()}))
+				continue
+			}
+			c, ok := clients[string(key)]
+			if !ok {
+				config := resource.config
+				if c, err = m.newClient(&config); err != nil {
+					record(kind, resource.name, resource.collection, resource.config.DBName, toMap(&OperationResult{Error: err.Error()}))
+					continue
+				}
+				clients[string(key)] = c
+			}
+			var result interface{}
+			switch kind {
+			case "alias":
+				result = c.DropAlias(resource.name)
+			case "partition":
+				result = c.DropPartition(resource.name, resource.collection)
+			case "collection":
+				result = c.DropCollection(resource.name)
+			case "database":
+				result = c.DropDatabase(resource.name)
+			}
+			record(kind, resource.name, resource.collection, resource.config.DBName, result)
+		}
+	}
+
+	// Collections of the run not tracked by this process, e.g. of an aborted run
+	if client != nil {
+		database := ""
+		if client.config != nil {
+			database = client.config.DBName
+		}
+		names, err := client.milvus().ListCollections(client.context(), milvusclient.NewListCollectionOption())
+		if err != nil {
+			record("collection", "*", "", database, toMap(&OperationResult{Error: fmt.Sprintf("failed to list collections: %v", err)}))
+		}
+		for _, name := range names {
+			collection, err := client.milvus().DescribeCollection(client.context(), milvusclient.NewDescribeCollectionOption(name))
+			if err != nil || collection.Properties[runIDProperty] != id {
+				continue
+			}
+			aliases, _ := client.milvus().ListAliases(client.context(), milvusclient.NewListAliasesOption(name))
+			for _, alias := range aliases {
+				record("alias", alias, name, database, client.DropAlias(alias))
+			}
+			record("collection", name, "", database, client.DropCollection(name))
+		}
+	}
+
+	result := map[string]interface{}{"run_id": id, "dropped": dropped, "failed": failed}
+	if len(failed) > 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to drop %d of %d resources", len(failed), len(failed)+len(dropped)),
+			Result:       result,
+		})
+	}
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       result,
+	})
+}
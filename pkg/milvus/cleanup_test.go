@@ -0,0 +1,164 @@
+package milvus
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cleanupServer keeps the run ID of each collection and records the resources created and dropped
+type cleanupServer struct {
+	milvuspb.UnimplementedMilvusServiceServer
+	mu          sync.Mutex
+	collections map[string]string
+	calls       []string
+}
+
+func (s *cleanupServer) record(call string) *commonpb.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+	return &commonpb.Status{}
+}
+
+func (s *cleanupServer) CreateCollection(_ context.Context, req *milvuspb.CreateCollectionRequest) (*commonpb.Status, error) {
+	s.mu.Lock()
+	for _, property := range req.GetProperties() {
+		if property.GetKey() == runIDProperty {
+			s.collections[req.GetCollectionName()] = property.GetValue()
+		}
+	}
+	s.mu.Unlock()
+	return s.record("CreateCollection " + req.GetCollectionName()), nil
+}
+
+func (s *cleanupServer) DropCollection(_ context.Context, req *milvuspb.DropCollectionRequest) (*commonpb.Status, error) {
+	s.mu.Lock()
+	delete(s.collections, req.GetCollectionName())
+	s.mu.Unlock()
+	return s.record("DropCollection " + req.GetCollectionName()), nil
+}
+
+func (s *cleanupServer) CreatePartition(_ context.Context, req *milvuspb.CreatePartitionRequest) (*commonpb.Status, error) {
+	return s.record("CreatePartition " + req.GetPartitionName()), nil
+}
+
+func (s *cleanupServer) DropPartition(_ context.Context, req *milvuspb.DropPartitionRequest) (*commonpb.Status, error) {
+	return s.record("DropPartition " + req.GetPartitionName()), nil
+}
+
+func (s *cleanupServer) CreateAlias(_ context.Context, req *milvuspb.CreateAliasRequest) (*commonpb.Status, error) {
+	return s.record("CreateAlias " + req.GetAlias()), nil
+}
+
+func (s *cleanupServer) DropAlias(_ context.Context, req *milvuspb.DropAliasRequest) (*commonpb.Status, error) {
+	return s.record("DropAlias " + req.GetAlias()), nil
+}
+
+func (s *cleanupServer) ListAliases(context.Context, *milvuspb.ListAliasesRequest) (*milvuspb.ListAliasesResponse, error) {
+	return &milvuspb.ListAliasesResponse{Status: &commonpb.Status{}}, nil
+}
+
+func (s *cleanupServer) CreateDatabase(_ context.Context, req *milvuspb.CreateDatabaseRequest) (*commonpb.Status, error) {
+	return s.record("CreateDatabase " + req.GetDbName()), nil
+}
+
+func (s *cleanupServer) DropDatabase(_ context.Context, req *milvuspb.DropDatabaseRequest) (*commonpb.Status, error) {
+	return s.record("DropDatabase " + req.GetDbName()), nil
+}
+
+func (s *cleanupServer) ShowCollections(context.Context, *milvuspb.ShowCollectionsRequest) (*milvuspb.ShowCollectionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &milvuspb.ShowCollectionsResponse{Status: &commonpb.Status{}, CollectionNames: names}, nil
+}
+
+func (s *cleanupServer) DescribeCollection(_ context.Context, req *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schema := entity.NewSchema().WithName(req.GetCollectionName()).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true))
+	return &milvuspb.DescribeCollectionResponse{
+		Status:         &commonpb.Status{},
+		CollectionName: req.GetCollectionName(),
+		Schema:         schema.ProtoMessage(),
+		Properties:     []*commonpb.KeyValuePair{{Key: runIDProperty, Value: s.collections[req.GetCollectionName()]}},
+	}, nil
+}
+
+func TestCleanup(t *testing.T) {
+	t.Setenv(EnvRunID, "run1")
+	// "stale" was created by an aborted run of the same ID
+	service := &cleanupServer{collections: map[string]string{"stale": "run1", "kept": "run0"}}
+	m := &Milvus{resources: &resourceRegistry{}}
+	client := fakeClient(t, m, service)
+
+	schema := func(name string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "fields": []interface{}{
+			map[string]interface{}{"name": "id", "dataType": "Int64", "isPrimaryKey": true},
+			map[string]interface{}{"name": "embedding", "dataType": "FloatVector", "dimension": 2},
+		}}
+	}
+	for _, result := range []interface{}{
+		client.CreateCollection(schema("bench")),
+		client.CreatePartition("p1", "bench"),
+		client.CreateAlias("current", "bench"),
+		client.CreateDatabase("scratch"),
+		client.CreateCollection(schema("temp")),
+		client.DropCollection("temp"),
+	} {
+		require.Equal(t, true, result.(map[string]interface{})["success"], result)
+	}
+	assert.Equal(t, "run1", m.RunID())
+	assert.Equal(t, "run1", service.collections["bench"], "collections carry the run ID")
+	assert.Len(t, m.resources.snapshot(), 4, "dropped resources are not tracked")
+
+	service.calls = nil
+	result := m.Cleanup(client).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	// Partitions go with their collection; untracked collections of the run are found by property
+	assert.Equal(t, []string{"DropAlias current", "DropCollection bench", "DropDatabase scratch", "DropCollection stale"}, service.calls)
+	assert.Equal(t, map[string]string{"kept": "run0"}, service.collections)
+	details := result["result"].(map[string]interface{})
+	assert.Equal(t, "run1", details["run_id"])
+	assert.Len(t, details["dropped"], 4)
+	assert.Empty(t, m.resources.snapshot())
+
+	// Other runs are cleaned up by ID
+	service.calls = nil
+	result = m.Cleanup(client, "run0").(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, []string{"DropCollection kept"}, service.calls)
+}
+
+func TestResourceRegistry(t *testing.T) {
+	registry := &resourceRegistry{}
+	config := ClientConfig{Address: "a:19530", DBName: "db1"}
+	registry.track(trackedResource{kind: "partition", name: "p1", collection: "c1", config: config})
+	registry.track(trackedResource{kind: "alias", name: "current", collection: "c1", config: config})
+	registry.track(trackedResource{kind: "alias", name: "current", collection: "c1", config: config})
+	other := config
+	other.DBName = "db2"
+	registry.track(trackedResource{kind: "alias", name: "current", collection: "c1", config: other})
+	assert.Len(t, registry.snapshot(), 3, "resources are tracked once per database")
+
+	// Dropping a collection forgets its partitions; aliases are dropped without their collection
+	registry.untrack(trackedResource{kind: "collection", name: "c1", config: config})
+	registry.untrack(trackedResource{kind: "alias", name: "current", config: config})
+	remaining := registry.snapshot()
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "db2", remaining[0].config.DBName)
+	assert.NotEmpty(t, registry.id())
+}
@@ -0,0 +1,174 @@
+package milvus
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/metrics"
+)
+
+// closedAddress returns a local address with nothing listening on it
+func closedAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return address
+}
+
+func TestConnectRetry(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	m := &Milvus{vu: vu, metrics: registerMetrics(vu)}
+
+	config := DefaultClientConfig()
+	config.ApplyOptions(
+		WithAddress(closedAddress(t)),
+		WithDialTimeout(100*time.Millisecond),
+		WithConnectRetry(2, 10*time.Millisecond),
+	)
+
+	start := time.Now()
+	_, err := m.newClient(config)
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	// Three attempts bounded by the dial timeout, plus 10ms and 20ms of backoff
+	assert.GreaterOrEqual(t, elapsed, 330*time.Millisecond)
+	assert.Less(t, elapsed, 5*time.Second)
+
+	require.Len(t, samples, 1)
+	sample := (<-samples).(metrics.Sample)
+	assert.Equal(t, "milvus_connect_duration", sample.Metric.Name)
+	operation, _ := sample.Tags.Get("operation")
+	status, _ := sample.Tags.Get("status")
+	assert.Equal(t, "connect", operation)
+	assert.Equal(t, "error", status)
+	errorType, _ := sample.Tags.Get("error_type")
+	assert.NotEmpty(t, errorType)
+}
+
+func TestConnectInvalidPolicy(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{}}
+	config := DefaultClientConfig()
+	config.ApplyOptions(WithAddress(closedAddress(t)))
+	config.DialTimeout = "soon"
+
+	_, err := m.newClient(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid dialTimeout")
+}
+
+func TestValidateConnection(t *testing.T) {
+	address := newFakeMilvus(t, nil)
+	m := &Milvus{vu: &metricsVU{}}
+
+	// The SDK tolerates an unimplemented Connect RPC, so the connect itself succeeds
+	config := DefaultClientConfig()
+	config.ApplyOptions(WithAddress(address), WithDialTimeout(time.Second))
+	client, err := m.newClient(config)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	config.ApplyOptions(WithValidateConnection(true))
+	_, err = m.newClient(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection validation failed for "+address)
+}
+
+func TestCloseConnectionGauge(t *testing.T) {
+	address := newFakeMilvus(t, nil)
+	vu, samples := newMetricsVU(t)
+	root := &RootModule{}
+	m := &Milvus{vu: vu, metrics: registerMetrics(vu), connections: &root.connections}
+
+	config := DefaultClientConfig()
+	config.ApplyOptions(WithAddress(address), WithDialTimeout(time.Second))
+	first, err := m.newClient(config)
+	require.NoError(t, err)
+	second, err := m.newClient(config)
+	require.NoError(t, err)
+
+	require.NoError(t, first.Close())
+	require.NoError(t, first.Close()) // Closing twice does not decrement again
+	assert.Nil(t, first.redial)
+	require.NoError(t, second.Close())
+
+	var gauge []float64
+	close(samples)
+	for container := range samples {
+		for _, sample := range container.GetSamples() {
+			if sample.Metric.Name == "milvus_connections" {
+				gauge = append(gauge, sample.Value)
+			}
+		}
+	}
+	assert.Equal(t, []float64{1, 2, 1, 0}, gauge)
+	assert.Zero(t, root.connections.Load())
+}
+
+func TestNewClientOutsideVU(t *testing.T) {
+	// Init context / setup helpers: no VU context and no VU state
+	for name, m := range map[string]*Milvus{
+		"nil vu":         {},
+		"nil vu context": {vu: &initContextVU{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultClientConfig()
+			config.ApplyOptions(WithAddress(closedAddress(t)), WithDialTimeout(50*time.Millisecond))
+
+			assert.NotPanics(t, func() {
+				_, err := m.newClient(config)
+				assert.Error(t, err)
+			})
+		})
+	}
+}
+
+func TestClientCachedPerVU(t *testing.T) {
+	address := newFakeMilvus(t, nil)
+	m := &Milvus{clients: make(map[string]*Client)}
+	config := map[string]interface{}{"address": address, "dialTimeout": "1s"}
+
+	first, err := m.ClientWithConfig(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = first.Close() })
+	again, err := m.ClientWithConfig(map[string]interface{}{"address": address, "dialTimeout": "1s"})
+	require.NoError(t, err)
+	assert.Same(t, first, again, "the same config reuses the VU's connection")
+
+	bound, err := m.ClientWithConfig(map[string]interface{}{"address": address, "dialTimeout": "1s", "collection": "bench"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bound.Close() })
+	assert.NotSame(t, first, bound)
+
+	fresh, err := m.ClientWithConfig(map[string]interface{}{"address": address, "dialTimeout": "1s", "newConnection": true})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = fresh.Close() })
+	assert.NotSame(t, first, fresh)
+
+	require.NoError(t, first.Close())
+	redialed, err := m.ClientWithConfig(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = redialed.Close() })
+	assert.NotSame(t, first, redialed, "a closed client is replaced")
+	assert.False(t, redialed.closed)
+
+	named, err := m.GetClient(address, "bench", "root:secret")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = named.Close() })
+	again, err = m.ClientWithCollection(address, "bench", "root:secret")
+	require.NoError(t, err)
+	assert.Same(t, named, again, "getClient() shares the cache of client()")
+	require.NoError(t, named.Close())
+	again, err = m.GetClient(address, "bench", "root:secret")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = again.Close() })
+	assert.NotSame(t, named, again, "getClient() replaces a closed client")
+	for key := range m.clients {
+		assert.NotContains(t, key, "secret")
+	}
+}
@@ -0,0 +1,75 @@
+package milvus
+
+import (
+	"strings"
+
+	"github.com/milvus-io/milvus/client/v2/index"
+)
+
+// scalarIndexTypes are the index types kept in cloud mode; vector indexes become AUTOINDEX
+var scalarIndexTypes = map[string]bool{
+	"INVERTED": true,
+	"STL_SORT": true,
+	"BITMAP":   true,
+	"TRIE":     true,
+}
+
+// cloudDBName is the database of cloud clients without dbName, the only one of serverless clusters
+const cloudDBName = "default"
+
+// applyCloudDefaults adjusts the config for Zilliz Cloud when cloud mode is on:
+// TLS is always on, the database defaults to the default one and credentials are sent as a
+// token, the only auth serverless clusters accept.
+func (c *ClientConfig) applyCloudDefaults() error {
+	if !c.Cloud {
+		return nil
+	}
+	c.EnableTLS = true
+	if c.DBName == "" {
+		c.DBName = cloudDBName
+	}
+	if c.Token == "" && c.Username != "" {
+		c.Token = c.Username + ":" + c.Password
+		c.Username, c.Password = "", ""
+	}
+	if c.Token == "" {
+		return ErrCloudTokenRequired
+	}
+	return nil
+}
+
+// cloud reports whether the client runs in cloud mode
+func (c *Client) cloud() bool {
+	return c.config != nil && c.config.Cloud
+}
+
+// indexFor builds the index for createIndex / rebuildIndex. In cloud mode vector indexes
+// are built as AUTOINDEX with the requested metric type, since Zilliz Cloud rejects other types.
+func (c *Client) indexFor(indexParams map[string]interface{}) (index.Index, string, string, error) {
+	if c.cloud() {
+		indexParams = cloudIndexParams(indexParams)
+	}
+	return buildIndex(indexParams)
+}
+
+// cloudIndexParams replaces a vector index type with AUTOINDEX, dropping build params that
+// only apply to the original type. Scalar index params are returned unchanged.
+func cloudIndexParams(indexParams map[string]interface{}) map[string]interface{} {
+	params := flattenIndexParams(indexParams)
+	indexType, ok := stringOption(params, "indexType")
+	if !ok || indexType == "" {
+		indexType, _ = stringOption(params, "index_type")
+	}
+	if scalarIndexTypes[strings.ToUpper(indexType)] {
+		return indexParams
+	}
+
+	cloudParams := make(map[string]interface{}, len(params))
+	for _, key := range []string{"metricType", "metric_type", "indexName", "index_name", "waitTimeout", "wait_timeout"} {
+		if value, exists := params[key]; exists {
+			cloudParams[key] = value
+		}
+	}
+	cloudParams["indexType"] = "AUTOINDEX"
+	return cloudParams
+}
@@ -0,0 +1,81 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyCloudDefaults(t *testing.T) {
+	t.Run("off by default", func(t *testing.T) {
+		config := DefaultClientConfig()
+		config.ApplyOptions(WithAuth("root", "Milvus"))
+		require.NoError(t, config.applyCloudDefaults())
+		assert.False(t, config.EnableTLS)
+		assert.Equal(t, "root", config.Username)
+	})
+
+	t.Run("credentials become a token", func(t *testing.T) {
+		config := DefaultClientConfig()
+		config.ApplyOptions(WithCloud(true), WithAuth("db_admin", "secret"))
+		require.NoError(t, config.applyCloudDefaults())
+		assert.True(t, config.EnableTLS)
+		assert.Equal(t, "db_admin:secret", config.Token)
+		assert.Empty(t, config.Username)
+		assert.Empty(t, config.Password)
+		assert.Equal(t, "default", config.DBName)
+	})
+
+	t.Run("database kept", func(t *testing.T) {
+		config := DefaultClientConfig()
+		config.ApplyOptions(WithCloud(true), WithToken("key"), WithDBName("analytics"))
+		require.NoError(t, config.applyCloudDefaults())
+		assert.Equal(t, "analytics", config.DBName)
+	})
+
+	t.Run("token required", func(t *testing.T) {
+		config := DefaultClientConfig()
+		config.ApplyOptions(WithCloud(true))
+		assert.ErrorIs(t, config.applyCloudDefaults(), ErrCloudTokenRequired)
+	})
+}
+
+func TestCloudIndex(t *testing.T) {
+	client := &Client{config: &ClientConfig{Cloud: true}}
+
+	idx, indexType, indexName, err := client.indexFor(map[string]interface{}{
+		"index_type":  "HNSW",
+		"metric_type": "COSINE",
+		"index_name":  "vec_idx",
+		"params":      map[string]interface{}{"M": 32, "efConstruction": 400},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "AUTOINDEX", indexType)
+	assert.Equal(t, "vec_idx", indexName)
+	params := idx.Params()
+	assert.Equal(t, string(index.AUTOINDEX), params[index.IndexTypeKey])
+	assert.Equal(t, "COSINE", params[index.MetricTypeKey])
+	assert.NotContains(t, params, "M")
+
+	// Scalar indexes are kept
+	_, indexType, _, err = client.indexFor(map[string]interface{}{"indexType": "INVERTED"})
+	require.NoError(t, err)
+	assert.Equal(t, "INVERTED", indexType)
+
+	// Without cloud mode the requested type is used
+	_, indexType, _, err = (&Client{config: DefaultClientConfig()}).indexFor(map[string]interface{}{"indexType": "HNSW"})
+	require.NoError(t, err)
+	assert.Equal(t, "HNSW", indexType)
+}
+
+func TestCloudDatabaseManagement(t *testing.T) {
+	client := &Client{config: &ClientConfig{Cloud: true}}
+
+	for _, result := range []interface{}{client.CreateDatabase("bench"), client.DropDatabase("bench")} {
+		resultMap := result.(map[string]interface{})
+		assert.Equal(t, false, resultMap["success"])
+		assert.Contains(t, resultMap["error"], ErrUnsupportedInCloud.Error())
+	}
+}
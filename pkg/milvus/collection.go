@@ -3,10 +3,12 @@ package milvus
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/milvus-io/milvus/client/v2/entity"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"go.k6.io/k6/metrics"
 )
 
 // CreateCollectionFromJSON creates a collection from a JSON schema string
@@ -54,132 +56,14 @@ func (c *Client) CreateCollection(schemaInput interface{}) interface{} {
 		WithDescription(schema.Description)
 
 	for _, field := range schema.Fields {
-		entityField := entity.NewField().
-			WithName(field.Name).
-			WithDescription(field.Description)
-
-		// Set data type
-		if field.DataType == "" {
+		entityField, err := buildEntityField(field)
+		if err != nil {
 			return toMap(&OperationResult{
 				Success:      false,
 				ResponseTime: float64(time.Since(start).Milliseconds()),
-				Error:        fmt.Sprintf("field %s has empty dataType", field.Name),
+				Error:        err.Error(),
 			})
 		}
-
-		switch field.DataType {
-		case "Int64":
-			entityField = entityField.WithDataType(entity.FieldTypeInt64)
-		case "Int32":
-			entityField = entityField.WithDataType(entity.FieldTypeInt32)
-		case "Int16":
-			entityField = entityField.WithDataType(entity.FieldTypeInt16)
-		case "Int8":
-			entityField = entityField.WithDataType(entity.FieldTypeInt8)
-		case "Bool":
-			entityField = entityField.WithDataType(entity.FieldTypeBool)
-		case "Float":
-			entityField = entityField.WithDataType(entity.FieldTypeFloat)
-		case "Double":
-			entityField = entityField.WithDataType(entity.FieldTypeDouble)
-		case "String":
-			entityField = entityField.WithDataType(entity.FieldTypeString)
-		case "VarChar":
-			entityField = entityField.WithDataType(entity.FieldTypeVarChar)
-		case "JSON":
-			entityField = entityField.WithDataType(entity.FieldTypeJSON)
-		case "FloatVector":
-			entityField = entityField.WithDataType(entity.FieldTypeFloatVector).WithDim(field.Dimension)
-		case "BinaryVector":
-			entityField = entityField.WithDataType(entity.FieldTypeBinaryVector).WithDim(field.Dimension)
-		case "Float16Vector":
-			entityField = entityField.WithDataType(entity.FieldTypeFloat16Vector).WithDim(field.Dimension)
-		case "BFloat16Vector":
-			entityField = entityField.WithDataType(entity.FieldTypeBFloat16Vector).WithDim(field.Dimension)
-		case "SparseFloatVector":
-			entityField = entityField.WithDataType(entity.FieldTypeSparseVector)
-		case "Array":
-			entityField = entityField.WithDataType(entity.FieldTypeArray)
-			switch field.ElementType {
-			case "Bool":
-				entityField = entityField.WithElementType(entity.FieldTypeBool)
-			case "Int8":
-				entityField = entityField.WithElementType(entity.FieldTypeInt8)
-			case "Int16":
-				entityField = entityField.WithElementType(entity.FieldTypeInt16)
-			case "Int32":
-				entityField = entityField.WithElementType(entity.FieldTypeInt32)
-			case "Int64":
-				entityField = entityField.WithElementType(entity.FieldTypeInt64)
-			case "Float":
-				entityField = entityField.WithElementType(entity.FieldTypeFloat)
-			case "Double":
-				entityField = entityField.WithElementType(entity.FieldTypeDouble)
-			case "VarChar":
-				entityField = entityField.WithElementType(entity.FieldTypeVarChar)
-			case "Struct":
-				entityField = entityField.WithElementType(entity.FieldTypeStruct)
-				if len(field.StructFields) > 0 {
-					structSchema := entity.NewStructSchema()
-					for _, sf := range field.StructFields {
-						structField := entity.NewField().WithName(sf.Name)
-						switch sf.DataType {
-						case "Int64":
-							structField = structField.WithDataType(entity.FieldTypeInt64)
-						case "Int32":
-							structField = structField.WithDataType(entity.FieldTypeInt32)
-						case "Float":
-							structField = structField.WithDataType(entity.FieldTypeFloat)
-						case "Double":
-							structField = structField.WithDataType(entity.FieldTypeDouble)
-						case "VarChar":
-							structField = structField.WithDataType(entity.FieldTypeVarChar)
-							if sf.MaxLength > 0 {
-								structField = structField.WithMaxLength(sf.MaxLength)
-							}
-						case "Bool":
-							structField = structField.WithDataType(entity.FieldTypeBool)
-						case "FloatVector":
-							structField = structField.WithDataType(entity.FieldTypeFloatVector).WithDim(sf.Dimension)
-						}
-						structSchema = structSchema.WithField(structField)
-					}
-					entityField = entityField.WithStructSchema(structSchema)
-				}
-			}
-			if field.MaxCapacity > 0 {
-				entityField = entityField.WithMaxCapacity(field.MaxCapacity)
-			}
-		default:
-			return toMap(&OperationResult{
-				Success:      false,
-				ResponseTime: float64(time.Since(start).Milliseconds()),
-				Error:        fmt.Sprintf("unsupported data type: '%s' for field '%s'", field.DataType, field.Name),
-			})
-		}
-
-		if field.IsPrimaryKey {
-			entityField = entityField.WithIsPrimaryKey(true)
-		}
-		if field.IsAutoID {
-			entityField = entityField.WithIsAutoID(true)
-		}
-		if field.MaxLength > 0 {
-			entityField = entityField.WithMaxLength(field.MaxLength)
-		}
-		if field.EnableAnalyzer {
-			entityField = entityField.WithEnableAnalyzer(true)
-			if field.AnalyzerParams != nil {
-				entityField = entityField.WithAnalyzerParams(field.AnalyzerParams)
-			}
-		}
-		if field.EnableMatch {
-			entityField = entityField.WithEnableMatch(true)
-		}
-		if field.Nullable != nil && *field.Nullable {
-			entityField = entityField.WithNullable(true)
-		}
-
 		entitySchema = entitySchema.WithField(entityField)
 	}
 
@@ -214,15 +98,24 @@ func (c *Client) CreateCollection(schemaInput interface{}) interface{} {
 	if schema.NumShards > 0 {
 		option = option.WithShardNum(schema.NumShards)
 	}
+	for key, value := range indexProperties(schema.Properties) {
+		option = option.WithProperty(key, value)
+	}
+	// The run ID lets milvus.cleanup() find the collection after an aborted run
+	if c.resources != nil {
+		option = option.WithProperty(runIDProperty, c.resources.id())
+	}
 
-	err = c.client.CreateCollection(c.context(), option)
+	err = c.milvus().CreateCollection(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to create collection: %v", err),
+			Cause:        err,
 		})
 	}
+	c.trackResource("collection", schema.Name, "")
 
 	return toMap(&OperationResult{
 		Success:      true,
@@ -231,6 +124,225 @@ func (c *Client) CreateCollection(schemaInput interface{}) interface{} {
 	})
 }
 
+// buildEntityField converts a Field definition into an SDK entity.Field
+func buildEntityField(field Field) (*entity.Field, error) {
+	entityField := entity.NewField().
+		WithName(field.Name).
+		WithDescription(field.Description)
+
+	// Set data type
+	if field.DataType == "" {
+		return nil, fmt.Errorf("field %s has empty dataType", field.Name)
+	}
+
+	switch field.DataType {
+	case "Int64":
+		entityField = entityField.WithDataType(entity.FieldTypeInt64)
+	case "Int32":
+		entityField = entityField.WithDataType(entity.FieldTypeInt32)
+	case "Int16":
+		entityField = entityField.WithDataType(entity.FieldTypeInt16)
+	case "Int8":
+		entityField = entityField.WithDataType(entity.FieldTypeInt8)
+	case "Bool":
+		entityField = entityField.WithDataType(entity.FieldTypeBool)
+	case "Float":
+		entityField = entityField.WithDataType(entity.FieldTypeFloat)
+	case "Double":
+		entityField = entityField.WithDataType(entity.FieldTypeDouble)
+	case "String":
+		entityField = entityField.WithDataType(entity.FieldTypeString)
+	case "VarChar":
+		entityField = entityField.WithDataType(entity.FieldTypeVarChar)
+	case "JSON":
+		entityField = entityField.WithDataType(entity.FieldTypeJSON)
+	case "FloatVector":
+		entityField = entityField.WithDataType(entity.FieldTypeFloatVector).WithDim(field.Dimension)
+	case "BinaryVector":
+		entityField = entityField.WithDataType(entity.FieldTypeBinaryVector).WithDim(field.Dimension)
+	case "Float16Vector":
+		entityField = entityField.WithDataType(entity.FieldTypeFloat16Vector).WithDim(field.Dimension)
+	case "BFloat16Vector":
+		entityField = entityField.WithDataType(entity.FieldTypeBFloat16Vector).WithDim(field.Dimension)
+	case "SparseFloatVector":
+		entityField = entityField.WithDataType(entity.FieldTypeSparseVector)
+	case "Array":
+		entityField = entityField.WithDataType(entity.FieldTypeArray)
+		switch field.ElementType {
+		case "Bool":
+			entityField = entityField.WithElementType(entity.FieldTypeBool)
+		case "Int8":
+			entityField = entityField.WithElementType(entity.FieldTypeInt8)
+		case "Int16":
+			entityField = entityField.WithElementType(entity.FieldTypeInt16)
+		case "Int32":
+			entityField = entityField.WithElementType(entity.FieldTypeInt32)
+		case "Int64":
+			entityField = entityField.WithElementType(entity.FieldTypeInt64)
+		case "Float":
+			entityField = entityField.WithElementType(entity.FieldTypeFloat)
+		case "Double":
+			entityField = entityField.WithElementType(entity.FieldTypeDouble)
+		case "VarChar":
+			entityField = entityField.WithElementType(entity.FieldTypeVarChar)
+		case "Struct":
+			entityField = entityField.WithElementType(entity.FieldTypeStruct)
+			if len(field.StructFields) > 0 {
+				structSchema := entity.NewStructSchema()
+				for _, sf := range field.StructFields {
+					structField := entity.NewField().WithName(sf.Name)
+					switch sf.DataType {
+					case "Int64":
+						structField = structField.WithDataType(entity.FieldTypeInt64)
+					case "Int32":
+						structField = structField.WithDataType(entity.FieldTypeInt32)
+					case "Float":
+						structField = structField.WithDataType(entity.FieldTypeFloat)
+					case "Double":
+						structField = structField.WithDataType(entity.FieldTypeDouble)
+					case "VarChar":
+						structField = structField.WithDataType(entity.FieldTypeVarChar)
+						if sf.MaxLength > 0 {
+							structField = structField.WithMaxLength(sf.MaxLength)
+						}
+					case "Bool":
+						structField = structField.WithDataType(entity.FieldTypeBool)
+					case "FloatVector":
+						structField = structField.WithDataType(entity.FieldTypeFloatVector).WithDim(sf.Dimension)
+					}
+					structSchema = structSchema.WithField(structField)
+				}
+				entityField = entityField.WithStructSchema(structSchema)
+			}
+		}
+		if field.MaxCapacity > 0 {
+			entityField = entityField.WithMaxCapacity(field.MaxCapacity)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported data type: '%s' for field '%s'", field.DataType, field.Name)
+	}
+
+	if field.IsPrimaryKey {
+		entityField = entityField.WithIsPrimaryKey(true)
+	}
+	if field.IsAutoID {
+		entityField = entityField.WithIsAutoID(true)
+	}
+	if field.MaxLength > 0 {
+		entityField = entityField.WithMaxLength(field.MaxLength)
+	}
+	if field.EnableAnalyzer {
+		entityField = entityField.WithEnableAnalyzer(true)
+		if field.AnalyzerParams != nil {
+			entityField = entityField.WithAnalyzerParams(field.AnalyzerParams)
+		}
+	}
+	if field.EnableMatch {
+		entityField = entityField.WithEnableMatch(true)
+	}
+	if field.Nullable != nil && *field.Nullable {
+		entityField = entityField.WithNullable(true)
+	}
+
+	return entityField, nil
+}
+
+// AddCollectionField adds a new field to an existing (possibly loaded) collection.
+// Milvus only accepts nullable fields here, so nullable defaults to true when not set.
+func (c *Client) AddCollectionField(fieldInput interface{}, collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        ErrCollectionNameRequired.Error(),
+		})
+	}
+
+	var field Field
+	if err := convertViaJSON(fieldInput, &field); err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to parse field: %v", err),
+		})
+	}
+	if field.Nullable == nil {
+		nullable := true
+		field.Nullable = &nullable
+	}
+
+	entityField, err := buildEntityField(field)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err.Error(),
+		})
+	}
+
+	option := milvusclient.NewAddCollectionFieldOption(coll, entityField)
+	err = c.milvus().AddCollectionField(c.context(), option)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to add collection field: %v", err),
+			Cause:        err,
+		})
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"collection": coll, "field": field.Name},
+	})
+}
+
+// AlterCollectionProperties sets properties such as collection.ttl.seconds on an existing collection
+func (c *Client) AlterCollectionProperties(properties map[string]interface{}, collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        ErrCollectionNameRequired.Error(),
+		})
+	}
+	props := indexProperties(properties)
+	if len(props) == 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "at least one collection property required",
+		})
+	}
+
+	option := milvusclient.NewAlterCollectionPropertiesOption(coll)
+	for key, value := range props {
+		option = option.WithProperty(key, value)
+	}
+	err := c.milvus().AlterCollectionProperties(c.context(), option)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to alter collection properties: %v", err),
+			Cause:        err,
+		})
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"collection": coll, "properties": props},
+	})
+}
+
 // DropCollection drops a collection
 func (c *Client) DropCollection(collectionName ...string) interface{} {
 	start := time.Now()
@@ -241,15 +353,17 @@ func (c *Client) DropCollection(collectionName ...string) interface{} {
 	}
 
 	option := milvusclient.NewDropCollectionOption(name)
-	err := c.client.DropCollection(c.context(), option)
+	err := c.milvus().DropCollection(c.context(), option)
 
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to drop collection: %v", err),
+			Cause:        err,
 		})
 	}
+	c.untrackResource("collection", name, "")
 
 	return toMap(&OperationResult{
 		Success:      true,
@@ -276,13 +390,14 @@ func (c *Client) HasCollection(collectionName ...string) interface{} {
 	}
 
 	option := milvusclient.NewHasCollectionOption(name)
-	has, err := c.client.HasCollection(c.context(), option)
+	has, err := c.milvus().HasCollection(c.context(), option)
 
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to check collection: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -311,12 +426,13 @@ func (c *Client) LoadCollection(collectionName ...string) interface{} {
 	}
 
 	option := milvusclient.NewLoadCollectionOption(name)
-	task, err := c.client.LoadCollection(c.context(), option)
+	task, err := c.milvus().LoadCollection(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to load collection: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -327,8 +443,12 @@ func (c *Client) LoadCollection(collectionName ...string) interface{} {
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to wait for collection load: %v", err),
+			Cause:        err,
 		})
 	}
+	if c.metrics != nil {
+		c.pushMetric(c.metrics.LoadDuration, metrics.D(time.Since(start)), c.replicaTags(name))
+	}
 
 	return toMap(&OperationResult{
 		Success:      true,
@@ -354,16 +474,26 @@ func (c *Client) ReleaseCollection(collectionName ...string) interface{} {
 		})
 	}
 
+	// Replicas are counted before they are released
+	var tags map[string]string
+	if c.metrics != nil {
+		tags = c.replicaTags(name)
+	}
+	released := time.Now()
 	option := milvusclient.NewReleaseCollectionOption(name)
-	err := c.client.ReleaseCollection(c.context(), option)
+	err := c.milvus().ReleaseCollection(c.context(), option)
 
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to release collection: %v", err),
+			Cause:        err,
 		})
 	}
+	if c.metrics != nil {
+		c.pushMetric(c.metrics.ReleaseDuration, metrics.D(time.Since(released)), tags)
+	}
 
 	return toMap(&OperationResult{
 		Success:      true,
@@ -372,6 +502,17 @@ func (c *Client) ReleaseCollection(collectionName ...string) interface{} {
 	})
 }
 
+// replicaTags returns the collection and replicas tags for the load and release duration metrics.
+// replicas is the number of loaded replicas, omitted when they cannot be described.
+func (c *Client) replicaTags(collection string) map[string]string {
+	tags := map[string]string{"collection": collection}
+	replicas, err := c.milvus().DescribeReplica(c.context(), milvusclient.NewDescribeReplicaOption(collection))
+	if err == nil {
+		tags["replicas"] = strconv.Itoa(len(replicas))
+	}
+	return tags
+}
+
 // CreatePartition creates a partition in a collection
 func (c *Client) CreatePartition(partitionName string, collectionName ...string) interface{} {
 	start := time.Now()
@@ -383,13 +524,15 @@ func (c *Client) CreatePartition(partitionName string, collectionName ...string)
 		})
 	}
 	option := milvusclient.NewCreatePartitionOption(coll, partitionName)
-	err := c.client.CreatePartition(c.context(), option)
+	err := c.milvus().CreatePartition(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success: false, ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error: fmt.Sprintf("failed to create partition: %v", err),
+			Cause: err,
 		})
 	}
+	c.trackResource("partition", partitionName, coll)
 	return toMap(&OperationResult{
 		Success: true, ResponseTime: float64(time.Since(start).Milliseconds()),
 		Result: map[string]interface{}{"partition": partitionName},
@@ -407,15 +550,85 @@ func (c *Client) DropPartition(partitionName string, collectionName ...string) i
 		})
 	}
 	option := milvusclient.NewDropPartitionOption(coll, partitionName)
-	err := c.client.DropPartition(c.context(), option)
+	err := c.milvus().DropPartition(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success: false, ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error: fmt.Sprintf("failed to drop partition: %v", err),
+			Cause: err,
 		})
 	}
+	c.untrackResource("partition", partitionName, coll)
 	return toMap(&OperationResult{
 		Success: true, ResponseTime: float64(time.Since(start).Milliseconds()),
 		Result: map[string]interface{}{"partition": partitionName},
 	})
 }
+
+// CreateAlias creates an alias for a collection
+func (c *Client) CreateAlias(alias string, collectionName ...string) interface{} {
+	start := time.Now()
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success: false, ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error: "collection name required",
+		})
+	}
+	err := c.milvus().CreateAlias(c.context(), milvusclient.NewCreateAliasOption(coll, alias))
+	if err != nil {
+		return toMap(&OperationResult{
+			Success: false, ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error: fmt.Sprintf("failed to create alias: %v", err),
+			Cause: err,
+		})
+	}
+	c.trackResource("alias", alias, coll)
+	return toMap(&OperationResult{
+		Success: true, ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result: map[string]interface{}{"alias": alias, "collection": coll},
+	})
+}
+
+// AlterAlias points an existing alias at another collection
+func (c *Client) AlterAlias(alias string, collectionName ...string) interface{} {
+	start := time.Now()
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success: false, ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error: "collection name required",
+		})
+	}
+	err := c.milvus().AlterAlias(c.context(), milvusclient.NewAlterAliasOption(alias, coll))
+	if err != nil {
+		return toMap(&OperationResult{
+			Success: false, ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error: fmt.Sprintf("failed to alter alias: %v", err),
+			Cause: err,
+		})
+	}
+	c.trackResource("alias", alias, coll)
+	return toMap(&OperationResult{
+		Success: true, ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result: map[string]interface{}{"alias": alias, "collection": coll},
+	})
+}
+
+// DropAlias drops an alias
+func (c *Client) DropAlias(alias string) interface{} {
+	start := time.Now()
+	err := c.milvus().DropAlias(c.context(), milvusclient.NewDropAliasOption(alias))
+	if err != nil {
+		return toMap(&OperationResult{
+			Success: false, ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error: fmt.Sprintf("failed to drop alias: %v", err),
+			Cause: err,
+		})
+	}
+	c.untrackResource("alias", alias, "")
+	return toMap(&OperationResult{
+		Success: true, ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result: map[string]interface{}{"alias": alias},
+	})
+}
@@ -352,3 +352,69 @@ func TestCreateCollectionWithComplexSchema_Integration(t *testing.T) {
 		defer client.DropCollection(collectionName)
 	})
 }
+
+func TestAddCollectionField_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, _, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	t.Run("add_nullable_field_to_loaded_collection", func(t *testing.T) {
+		result := client.AddCollectionField(map[string]interface{}{
+			"name":     "category",
+			"dataType": "Int64",
+		})
+		resultMap, ok := result.(map[string]interface{})
+		require.True(t, ok)
+
+		assert.Equal(t, true, resultMap["success"], "add field should succeed: %v", resultMap["error"])
+	})
+
+	t.Run("add_field_invalid_type", func(t *testing.T) {
+		result := client.AddCollectionField(map[string]interface{}{
+			"name":     "bad",
+			"dataType": "NotAType",
+		})
+		resultMap, ok := result.(map[string]interface{})
+		require.True(t, ok)
+
+		assert.Equal(t, false, resultMap["success"])
+		assert.Contains(t, resultMap["error"], "unsupported data type")
+	})
+}
+
+func TestClientWithConfig_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	milvusHost := os.Getenv("MILVUS_HOST")
+	if milvusHost == "" {
+		milvusHost = "localhost:19530"
+	}
+
+	milvusModule := &Milvus{
+		vu: &mockVU{ctx: context.Background()},
+	}
+
+	t.Run("connect_with_config", func(t *testing.T) {
+		client, err := milvusModule.ClientWithConfig(map[string]interface{}{
+			"address":    milvusHost,
+			"dbName":     "default",
+			"collection": "config_bound_collection",
+		})
+		require.NoError(t, err)
+		defer client.Close()
+
+		assert.Equal(t, "config_bound_collection", client.defaultCollection)
+		resultMap := client.HasCollection().(map[string]interface{})
+		assert.Equal(t, true, resultMap["success"], resultMap["error"])
+	})
+
+	t.Run("missing_address", func(t *testing.T) {
+		_, err := milvusModule.ClientWithConfig(map[string]interface{}{"username": "root"})
+		assert.ErrorIs(t, err, ErrAddressRequired)
+	})
+}
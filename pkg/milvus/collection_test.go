@@ -0,0 +1,97 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEntityField(t *testing.T) {
+	nullable := true
+
+	tests := []struct {
+		name     string
+		field    Field
+		wantType entity.FieldType
+	}{
+		{name: "int64 pk", field: Field{Name: "id", DataType: "Int64", IsPrimaryKey: true}, wantType: entity.FieldTypeInt64},
+		{name: "varchar", field: Field{Name: "title", DataType: "VarChar", MaxLength: 64}, wantType: entity.FieldTypeVarChar},
+		{name: "float vector", field: Field{Name: "vec", DataType: "FloatVector", Dimension: 8}, wantType: entity.FieldTypeFloatVector},
+		{name: "nullable json", field: Field{Name: "meta", DataType: "JSON", Nullable: &nullable}, wantType: entity.FieldTypeJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entityField, err := buildEntityField(tt.field)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.field.Name, entityField.Name)
+			assert.Equal(t, tt.wantType, entityField.DataType)
+			assert.Equal(t, tt.field.IsPrimaryKey, entityField.PrimaryKey)
+			assert.Equal(t, tt.field.Nullable != nil && *tt.field.Nullable, entityField.Nullable)
+		})
+	}
+}
+
+func TestBuildEntityFieldErrors(t *testing.T) {
+	_, err := buildEntityField(Field{Name: "empty"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "empty dataType")
+
+	_, err = buildEntityField(Field{Name: "bad", DataType: "NotAType"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported data type")
+}
+
+// loadServer is a Milvus service that loads collections instantly with two replicas
+type loadServer struct {
+	milvuspb.UnimplementedMilvusServiceServer
+}
+
+func (loadServer) LoadCollection(context.Context, *milvuspb.LoadCollectionRequest) (*commonpb.Status, error) {
+	return &commonpb.Status{}, nil
+}
+
+func (loadServer) GetLoadingProgress(context.Context, *milvuspb.GetLoadingProgressRequest) (*milvuspb.GetLoadingProgressResponse, error) {
+	return &milvuspb.GetLoadingProgressResponse{Status: &commonpb.Status{}, Progress: 100}, nil
+}
+
+func (loadServer) ReleaseCollection(context.Context, *milvuspb.ReleaseCollectionRequest) (*commonpb.Status, error) {
+	return &commonpb.Status{}, nil
+}
+
+func (loadServer) GetReplicas(context.Context, *milvuspb.GetReplicasRequest) (*milvuspb.GetReplicasResponse, error) {
+	return &milvuspb.GetReplicasResponse{
+		Status:   &commonpb.Status{},
+		Replicas: []*milvuspb.ReplicaInfo{{ReplicaID: 1}, {ReplicaID: 2}},
+	}, nil
+}
+
+func TestLoadReleaseDuration(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := fakeClient(t, &Milvus{vu: vu, metrics: registerMetrics(vu)}, loadServer{}, WithCollection("docs"))
+
+	assert.Equal(t, true, client.LoadCollection().(map[string]interface{})["success"])
+	assert.Equal(t, true, client.ReleaseCollection().(map[string]interface{})["success"])
+	require.NoError(t, client.Close())
+
+	var got []string
+	close(samples)
+	for container := range samples {
+		for _, sample := range container.GetSamples() {
+			switch sample.Metric.Name {
+			case "milvus_load_duration", "milvus_release_duration":
+				tags := sample.Tags.Map()
+				assert.Equal(t, "docs", tags["collection"])
+				assert.Equal(t, "2", tags["replicas"])
+				got = append(got, sample.Metric.Name)
+			}
+		}
+	}
+	assert.Equal(t, []string{"milvus_load_duration", "milvus_release_duration"}, got)
+}
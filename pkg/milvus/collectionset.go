@@ -0,0 +1,150 @@
+package milvus
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CollectionSetConfig configures milvus.collectionSet()
+type CollectionSetConfig struct {
+	Prefix      string                 `json:"prefix"`                // Collections are named prefix_0 to prefix_<count-1>
+	Count       int                    `json:"count"`                 // Collections of the set
+	Schema      *Schema                `json:"schema,omitempty"`      // Schema of the collections ensure() creates; its name is ignored
+	VectorField string                 `json:"vectorField,omitempty"` // Field ensure() indexes (default: the only vector field)
+	Index       map[string]interface{} `json:"index,omitempty"`       // Index params of the vector field, as for createIndex()
+	SkipLoad    bool                   `json:"skipLoad,omitempty"`    // Leave the collections ensure() creates released
+}
+
+// CollectionSet spreads identical operations across N collections of the same schema, so that
+// "many small collections" can be compared with "one big collection". next() hands out the
+// collections round robin across all VUs, and the client returned by bind() targets the next
+// one, with every metric tagged with collection.
+//
+// Usage in k6:
+//
+//	const set = milvus.collectionSet({ prefix: 'tenant', count: 100, schema, index: { indexType: 'HNSW' } });
+//	export function setup() {
+//	    set.ensure(milvus.client('localhost:19530'));
+//	}
+//	export default function () {
+//	    const client = set.bind(milvus.getClient('localhost:19530'));
+//	    client.insert({ id: ids, embedding: gen.next(100) });
+//	    client.search(gen.next(1), 10, { vectorField: 'embedding' });
+//	}
+type CollectionSet struct {
+	config CollectionSetConfig
+	names  []string
+	turn   *atomic.Int64 // Next collection, shared by the sets of the same prefix and count
+}
+
+// CollectionSet creates a set of count collections named prefix_0 to prefix_<count-1>
+func (m *Milvus) CollectionSet(configInput map[string]interface{}) (*CollectionSet, error) {
+	var config CollectionSetConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid collection set config: %v", err)
+	}
+	if config.Prefix == "" {
+		return nil, fmt.Errorf("collection set requires a prefix")
+	}
+	if config.Count <= 0 {
+		return nil, fmt.Errorf("collection set count must be positive, got %d", config.Count)
+	}
+	if config.Schema != nil && config.VectorField == "" {
+		var vectorFields []string
+		for _, field := range config.Schema.Fields {
+			if strings.HasSuffix(field.DataType, "Vector") {
+				vectorFields = append(vectorFields, field.Name)
+			}
+		}
+		if len(vectorFields) != 1 {
+			return nil, fmt.Errorf("collection set requires vectorField with %d vector fields in the schema", len(vectorFields))
+		}
+		config.VectorField = vectorFields[0]
+	}
+	names := make([]string, config.Count)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s_%d", config.Prefix, i)
+	}
+	turn, err := sharedValue(m.shared, fmt.Sprintf("collectionset\x00%s\x00%d", config.Prefix, config.Count), func() (*atomic.Int64, error) {
+		return &atomic.Int64{}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CollectionSet{config: config, names: names, turn: turn}, nil
+}
+
+// Names returns the names of the collections of the set
+func (s *CollectionSet) Names() []string {
+	return s.names
+}
+
+// Next returns the next collection of the set, round robin across all VUs
+func (s *CollectionSet) Next() string {
+	return s.names[(s.turn.Add(1)-1)%int64(len(s.names))]
+}
+
+// Bind returns the client bound to the next collection of the set: calls without a collection
+// name target it, and every metric of its calls is tagged with collection
+func (s *CollectionSet) Bind(client *Client) (*Client, error) {
+	if client == nil {
+		return nil, fmt.Errorf("bind requires a client")
+	}
+	name := s.Next()
+	bound := client.withTags(map[string]string{"collection": name})
+	bound.defaultCollection = name
+	return bound, nil
+}
+
+// Ensure creates the collections of the set that do not exist, each with the schema, the index of
+// its vector field, and loaded unless skipLoad, usually in setup(). Existing collections are left
+// as they are. The result lists the collections created.
+func (s *CollectionSet) Ensure(client *Client) interface{} {
+	start := time.Now()
+	fail := func(err string, created []string) interface{} {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err,
+			Result:       map[string]interface{}{"created": created},
+		})
+	}
+	if client == nil {
+		return fail("ensure requires a client", nil)
+	}
+	if s.config.Schema == nil {
+		return fail("ensure requires the schema of the collection set", nil)
+	}
+	created := []string{}
+	for _, name := range s.names {
+		exists := client.HasCollection(name).(map[string]interface{})
+		if exists["success"] != true {
+			return fail(fmt.Sprintf("ensure %s: %v", name, exists["error"]), created)
+		}
+		if exists["result"] == true {
+			continue
+		}
+		schema := *s.config.Schema
+		schema.Name = name
+		steps := []func() interface{}{
+			func() interface{} { return client.CreateCollection(schema) },
+			func() interface{} { return client.CreateIndex(s.config.VectorField, s.config.Index, name) },
+		}
+		if !s.config.SkipLoad {
+			steps = append(steps, func() interface{} { return client.LoadCollection(name) })
+		}
+		for _, step := range steps {
+			if result := step().(map[string]interface{}); result["success"] != true {
+				return fail(fmt.Sprintf("ensure %s: %v", name, result["error"]), created)
+			}
+		}
+		created = append(created, name)
+	}
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"created": created, "collections": len(s.names)},
+	})
+}
@@ -0,0 +1,81 @@
+package milvus
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectionSetServer serves the RPCs of client.prepare() for several collections
+type collectionSetServer struct {
+	prepareServer
+	collections map[string]bool
+}
+
+func (s *collectionSetServer) DescribeCollection(ctx context.Context, req *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error) {
+	s.callsMu.Lock()
+	exists := s.collections[req.GetCollectionName()]
+	s.callsMu.Unlock()
+	if !exists {
+		return &milvuspb.DescribeCollectionResponse{Status: merr.Status(merr.ErrCollectionNotFound)}, nil
+	}
+	return s.chunkServer.DescribeCollection(ctx, req)
+}
+
+func (s *collectionSetServer) CreateCollection(_ context.Context, req *milvuspb.CreateCollectionRequest) (*commonpb.Status, error) {
+	s.record("create " + req.GetCollectionName())
+	s.callsMu.Lock()
+	s.collections[req.GetCollectionName()] = true
+	s.callsMu.Unlock()
+	return &commonpb.Status{}, nil
+}
+
+func TestCollectionSet(t *testing.T) {
+	service := &collectionSetServer{collections: map[string]bool{"tenant_1": true}}
+	client := fakeClient(t, &Milvus{}, service)
+
+	shared := &sync.Map{}
+	config := map[string]interface{}{"prefix": "tenant", "count": 3, "schema": prepareSchema, "index": map[string]interface{}{"indexType": "FLAT", "metricType": "L2"}}
+	set, err := (&Milvus{shared: shared}).CollectionSet(config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant_0", "tenant_1", "tenant_2"}, set.Names())
+
+	result := set.Ensure(client).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, []interface{}{"tenant_0", "tenant_2"}, result["result"].(map[string]interface{})["created"], "existing collections are kept")
+	assert.Equal(t, []string{"create tenant_0", "index", "load", "create tenant_2", "index", "load"}, service.calls)
+
+	// Sets of the same prefix and count take turns across VUs
+	other, err := (&Milvus{shared: shared}).CollectionSet(map[string]interface{}{"prefix": "tenant", "count": 3})
+	require.NoError(t, err)
+	assert.Equal(t, "tenant_0", set.Next())
+	assert.Equal(t, "tenant_1", other.Next())
+	bound, err := set.Bind(client)
+	require.NoError(t, err)
+	assert.Equal(t, "tenant_2", bound.getCollectionName())
+	assert.Equal(t, map[string]string{"collection": "tenant_2"}, bound.tags)
+	assert.Empty(t, client.getCollectionName(), "the client itself is not bound")
+	assert.Equal(t, "tenant_0", set.Next())
+
+	result = other.Ensure(client).(map[string]interface{})
+	assert.Equal(t, false, result["success"], "ensure requires a schema")
+}
+
+func TestCollectionSetConfig(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	for _, config := range []map[string]interface{}{
+		{"count": 2},
+		{"prefix": "tenant"},
+		{"prefix": "tenant", "count": -1},
+		{"prefix": "tenant", "count": 2, "schema": map[string]interface{}{"fields": []interface{}{map[string]interface{}{"name": "id", "dataType": "Int64"}}}},
+	} {
+		_, err := m.CollectionSet(config)
+		assert.Error(t, err, config)
+	}
+}
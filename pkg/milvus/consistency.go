@@ -0,0 +1,296 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// Insert-then-verify defaults
+const (
+	defaultVerifyTopK = 10
+	// verifyRetryInterval is the wait between reads of rows not yet visible, within timeout
+	verifyRetryInterval = 100 * time.Millisecond
+	// verifyMaxNq is the most inserted vectors searched for in one Search RPC
+	verifyMaxNq = 1000
+	// verifyMissingIDs is the most keys of missing rows listed in the result
+	verifyMissingIDs = 100
+)
+
+// InsertVerifyOptions configures client.insertAndVerify()
+type InsertVerifyOptions struct {
+	CollectionName   string `json:"collectionName,omitempty"`   // Default: the collection of the client
+	Flush            bool   `json:"flush,omitempty"`            // Flush between the insert and the reads
+	Verify           string `json:"verify,omitempty"`           // "query" reads back each key (default), "search" searches for each inserted vector
+	ConsistencyLevel string `json:"consistencyLevel,omitempty"` // Consistency level of the reads (default: "Strong")
+	Timeout          string `json:"timeout,omitempty"`          // Read back missing rows until then, e.g. "10s" (default: a single read)
+	VectorField      string `json:"vectorField,omitempty"`      // Vectors searched for with verify "search" (default: the only FloatVector field of data)
+	TopK             int    `json:"topK,omitempty"`             // Hits searched for each vector with verify "search" (default: 10)
+}
+
+// InsertAndVerify inserts rows, optionally flushes, then reads back every inserted primary key to
+// confirm the rows are visible: by query, or by searching for each inserted vector and looking for
+// its key among the hits. Rows still missing after timeout are consistency failures: they fail the
+// call and are counted in the milvus_consistency_failures Counter, tagged with collection and
+// verify. It checks durability while a chaos experiment kills or restarts Milvus components.
+//
+// Usage in k6:
+//
+//	export default function () {
+//	    const result = client.insertAndVerify({ id: ids, embedding: gen.next(100) }, { flush: true, timeout: '10s' });
+//	    check(result, { 'inserted rows visible': (r) => r.success });
+//	}
+func (c *Client) InsertAndVerify(data map[string]interface{}, args ...interface{}) interface{} {
+	start := time.Now()
+	fail := func(err string, cause error, result map[string]interface{}) interface{} {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err,
+			Cause:        cause,
+			Result:       result,
+		})
+	}
+
+	coll, optionsInput := c.parseQueryArgs(args...)
+	var options InsertVerifyOptions
+	if err := convertViaJSON(optionsInput, &options); err != nil {
+		return fail(fmt.Sprintf("invalid insert and verify options: %v", err), nil, nil)
+	}
+	if coll == "" {
+		return fail("collection name required", nil, nil)
+	}
+	tags, err := tagsOption(optionsInput)
+	if err != nil {
+		return fail(fmt.Sprintf("invalid tags: %v", err), nil, nil)
+	}
+	client := c.withTags(tags)
+	mode := options.Verify
+	if mode == "" {
+		mode = "query"
+	}
+	if mode != "query" && mode != "search" {
+		return fail(fmt.Sprintf("verify must be query or search, got %q", mode), nil, nil)
+	}
+	level, err := parseConsistencyLevel(options.ConsistencyLevel)
+	if err != nil {
+		return fail(err.Error(), nil, nil)
+	}
+	var timeout time.Duration
+	if options.Timeout != "" {
+		if timeout, err = time.ParseDuration(options.Timeout); err != nil {
+			return fail(fmt.Sprintf("invalid timeout %q: %v", options.Timeout, err), nil, nil)
+		}
+	}
+
+	columns, err := c.convertDataToColumns(data)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to convert data: %v", err), nil, nil)
+	}
+	verifier := &rowVerifier{coll: coll, mode: mode, level: level, topK: optionalPositive(options.TopK, defaultVerifyTopK)}
+	if mode == "search" {
+		if verifier.vectors, err = verifyVectors(columns, options.VectorField); err != nil {
+			return fail(err.Error(), nil, nil)
+		}
+	}
+
+	timings := make(map[string]float64)
+	phaseStart := time.Now()
+	chunks, err := insertChunks(columns, 0)
+	if err != nil {
+		return fail(err.Error(), nil, nil)
+	}
+	client.insertChunks(coll, "", chunks, 1)
+	timings["insert"] = float64(time.Since(phaseStart).Milliseconds())
+	if chunks[0].err != nil {
+		return fail(fmt.Sprintf("failed to insert: %v", chunks[0].err), chunks[0].err, nil)
+	}
+	verifier.ids = chunks[0].result.IDs
+	rows := verifier.ids.Len()
+
+	if options.Flush {
+		phaseStart = time.Now()
+		flushed := client.Flush(coll).(map[string]interface{})
+		timings["flush"] = float64(time.Since(phaseStart).Milliseconds())
+		if flushed["success"] != true {
+			return fail(fmt.Sprintf("failed to flush: %v", flushed["error"]), nil, map[string]interface{}{"insert_count": rows, "timings": timings})
+		}
+	}
+
+	// Rows are read back until they are all visible or timeout passes, rereading only the missing
+	phaseStart = time.Now()
+	ctx := client.context()
+	missing := rowIndices(0, rows)
+	for {
+		var readErr error
+		if missing, readErr = verifier.missing(ctx, client.milvus(), missing); readErr != nil && time.Since(phaseStart) >= timeout {
+			timings["verify"] = float64(time.Since(phaseStart).Milliseconds())
+			return fail(fmt.Sprintf("failed to verify: %v", readErr), readErr, map[string]interface{}{"insert_count": rows, "timings": timings})
+		}
+		if (readErr == nil && len(missing) == 0) || time.Since(phaseStart) >= timeout || !sleepContext(ctx, verifyRetryInterval) {
+			break
+		}
+	}
+	timings["verify"] = float64(time.Since(phaseStart).Milliseconds())
+
+	if c.metrics != nil {
+		c.pushMetric(c.metrics.ConsistencyFailures, float64(len(missing)), map[string]string{"collection": coll, "verify": mode})
+	}
+	missingIDs := make([]interface{}, 0, min(len(missing), verifyMissingIDs))
+	for _, row := range missing[:min(len(missing), verifyMissingIDs)] {
+		id, _ := verifier.ids.Get(int(row))
+		missingIDs = append(missingIDs, id)
+	}
+	result := map[string]interface{}{
+		"insert_count": rows,
+		"missing":      len(missing),
+		"missing_ids":  missingIDs,
+		"timings":      timings,
+	}
+	if len(missing) > 0 {
+		return fail(fmt.Sprintf("%d of %d inserted rows not visible by %s", len(missing), rows, mode), nil, result)
+	}
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       result,
+	})
+}
+
+// rowVerifier reads back inserted rows by their primary keys
+type rowVerifier struct {
+	coll    string
+	mode    string
+	level   entity.ConsistencyLevel
+	topK    int
+	ids     column.Column             // Primary keys returned by the insert
+	vectors *column.ColumnFloatVector // Inserted vectors, with mode search
+}
+
+// missing reads back the given rows and returns those not visible. On a read error, the rows
+// are returned unchanged.
+func (v *rowVerifier) missing(ctx context.Context, sdk *milvusclient.Client, rows []int64) ([]int64, error) {
+	if v.mode == "search" {
+		return v.missingBySearch(ctx, sdk, rows)
+	}
+	keys, err := idSubset(v.ids, rows)
+	if err != nil {
+		return rows, err
+	}
+	option := milvusclient.NewQueryOption(v.coll).
+		WithIDs(keys).
+		WithOutputFields(v.ids.Name()).
+		WithConsistencyLevel(v.level)
+	resultSet, err := sdk.Query(ctx, option)
+	if err != nil {
+		return rows, err
+	}
+	found := make(map[interface{}]bool, resultSet.ResultCount)
+	if returned := resultSet.GetColumn(v.ids.Name()); returned != nil {
+		for i := 0; i < returned.Len(); i++ {
+			id, _ := returned.Get(i)
+			found[id] = true
+		}
+	}
+	return v.notFound(rows, func(_ int, id interface{}) bool { return found[id] }), nil
+}
+
+// missingBySearch searches for the vectors of the given rows and returns the rows whose key
+// is not among the hits of their own vector
+func (v *rowVerifier) missingBySearch(ctx context.Context, sdk *milvusclient.Client, rows []int64) ([]int64, error) {
+	var missing []int64
+	for offset := 0; offset < len(rows); offset += verifyMaxNq {
+		batch := rows[offset:min(offset+verifyMaxNq, len(rows))]
+		queries := make([]entity.Vector, len(batch))
+		for i, row := range batch {
+			queries[i] = entity.FloatVector(v.vectors.Data()[row])
+		}
+		option := milvusclient.NewSearchOption(v.coll, v.topK, queries).
+			WithANNSField(v.vectors.Name()).
+			WithConsistencyLevel(v.level)
+		resultSets, err := sdk.Search(ctx, option)
+		if err != nil {
+			return rows, err
+		}
+		missing = append(missing, v.notFound(batch, func(i int, id interface{}) bool {
+			if i >= len(resultSets) || resultSets[i].IDs == nil {
+				return false
+			}
+			for j := 0; j < resultSets[i].IDs.Len(); j++ {
+				if hit, _ := resultSets[i].IDs.Get(j); hit == id {
+					return true
+				}
+			}
+			return false
+		})...)
+	}
+	return missing, nil
+}
+
+// notFound returns the rows for which found is false, given the position and key of each row
+func (v *rowVerifier) notFound(rows []int64, found func(i int, id interface{}) bool) []int64 {
+	var missing []int64
+	for i, row := range rows {
+		if id, _ := v.ids.Get(int(row)); !found(i, id) {
+			missing = append(missing, row)
+		}
+	}
+	return missing
+}
+
+// idSubset returns the primary keys of the given rows as a column of the same field
+func idSubset(ids column.Column, rows []int64) (column.Column, error) {
+	switch keys := ids.(type) {
+	case *column.ColumnInt64:
+		values := make([]int64, len(rows))
+		for i, row := range rows {
+			values[i] = keys.Data()[row]
+		}
+		return column.NewColumnInt64(keys.Name(), values), nil
+	case *column.ColumnVarChar:
+		values := make([]string, len(rows))
+		for i, row := range rows {
+			values[i] = keys.Data()[row]
+		}
+		return column.NewColumnVarChar(keys.Name(), values), nil
+	}
+	return nil, fmt.Errorf("unsupported primary key type %T", ids)
+}
+
+// verifyVectors returns the FloatVector column of insert data searched for with verify "search"
+func verifyVectors(columns []column.Column, vectorField string) (*column.ColumnFloatVector, error) {
+	var vectors []*column.ColumnFloatVector
+	for _, col := range columns {
+		if v, ok := col.(*column.ColumnFloatVector); ok && (vectorField == "" || col.Name() == vectorField) {
+			vectors = append(vectors, v)
+		}
+	}
+	switch {
+	case len(vectors) == 1:
+		return vectors[0], nil
+	case vectorField != "":
+		return nil, fmt.Errorf("verify search requires FloatVector field %s in data", vectorField)
+	}
+	return nil, fmt.Errorf("verify search requires vectorField with %d FloatVector fields in data", len(vectors))
+}
+
+// parseConsistencyLevel returns the consistency level of a name such as "Strong" or "bounded";
+// an empty name is Strong, so that reads see every completed write
+func parseConsistencyLevel(name string) (entity.ConsistencyLevel, error) {
+	switch strings.ToLower(name) {
+	case "", "strong":
+		return entity.ClStrong, nil
+	case "session":
+		return entity.ClSession, nil
+	case "bounded":
+		return entity.ClBounded, nil
+	case "eventually":
+		return entity.ClEventually, nil
+	}
+	return 0, fmt.Errorf("consistencyLevel must be Strong, Session, Bounded or Eventually, got %q", name)
+}
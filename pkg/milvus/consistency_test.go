@@ -0,0 +1,151 @@
+package milvus
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// verifyServer reads back the inserted keys, except lost keys, and no keys at all during the
+// first lagReads reads. Searches find the key in the first component of each query vector.
+type verifyServer struct {
+	chunkServer
+	lost     map[int64]bool
+	lagReads int
+	levels   []commonpb.ConsistencyLevel
+}
+
+// visible returns the inserted keys a read sees
+func (s *verifyServer) visible(level commonpb.ConsistencyLevel) map[int64]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.levels = append(s.levels, level)
+	keys := make(map[int64]bool)
+	if s.lagReads > 0 {
+		s.lagReads--
+		return keys
+	}
+	for _, batch := range s.batches {
+		for _, id := range batch {
+			keys[id] = !s.lost[id]
+		}
+	}
+	return keys
+}
+
+func (s *verifyServer) Query(_ context.Context, req *milvuspb.QueryRequest) (*milvuspb.QueryResults, error) {
+	var ids []int64
+	for id, ok := range s.visible(req.GetConsistencyLevel()) {
+		if ok {
+			ids = append(ids, id)
+		}
+	}
+	return &milvuspb.QueryResults{Status: &commonpb.Status{}, FieldsData: []*schemapb.FieldData{{
+		FieldName: "id",
+		Type:      schemapb.DataType_Int64,
+		Field:     &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: ids}}}},
+	}}}, nil
+}
+
+func (s *verifyServer) Search(_ context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	keys := s.visible(req.GetConsistencyLevel())
+	var group commonpb.PlaceholderGroup
+	if err := proto.Unmarshal(req.GetPlaceholderGroup(), &group); err != nil {
+		return nil, err
+	}
+	queries := group.GetPlaceholders()[0].GetValues()
+	data := &schemapb.SearchResultData{NumQueries: int64(len(queries)), TopK: 1, Ids: &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{}}}}
+	for _, query := range queries {
+		id := int64(math.Float32frombits(binary.LittleEndian.Uint32(query)))
+		if !keys[id] {
+			id = -1
+		}
+		data.Topks = append(data.Topks, 1)
+		data.Scores = append(data.Scores, 0)
+		data.Ids.GetIntId().Data = append(data.Ids.GetIntId().Data, id)
+	}
+	return &milvuspb.SearchResults{Status: &commonpb.Status{}, Results: data}, nil
+}
+
+// verifyData returns rows of keys 1 to n whose vectors start with their key
+func verifyData(n int) map[string]interface{} {
+	ids := make([]interface{}, n)
+	vectors := make([]interface{}, n)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+		vectors[i] = []interface{}{float64(i + 1), 0.0}
+	}
+	return map[string]interface{}{"id": ids, "embedding": vectors}
+}
+
+func TestInsertAndVerify(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+
+	service := &verifyServer{}
+	client := benchClient(t, service, vu)
+	result := client.InsertAndVerify(verifyData(3)).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	details := result["result"].(map[string]interface{})
+	assert.Equal(t, float64(3), details["insert_count"])
+	assert.Equal(t, float64(0), details["missing"])
+	assert.Equal(t, []commonpb.ConsistencyLevel{commonpb.ConsistencyLevel_Strong}, service.levels)
+
+	service.lost = map[int64]bool{2: true}
+	result = client.InsertAndVerify(verifyData(3), map[string]interface{}{"verify": "search"}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "1 of 3 inserted rows not visible by search")
+	details = result["result"].(map[string]interface{})
+	assert.Equal(t, []interface{}{float64(2)}, details["missing_ids"])
+
+	var failures []float64
+	for _, sample := range drainSamples(samples) {
+		if sample.Metric.Name == "milvus_consistency_failures" {
+			verify, _ := sample.Tags.Get("verify")
+			failures = append(failures, sample.Value)
+			assert.Contains(t, []string{"query", "search"}, verify)
+		}
+	}
+	assert.Equal(t, []float64{0, 1}, failures)
+}
+
+func TestInsertAndVerifyTimeout(t *testing.T) {
+	// Rows that become visible within timeout are not failures
+	service := &verifyServer{lagReads: 2}
+	client := benchClient(t, service, &metricsVU{})
+	result := client.InsertAndVerify(verifyData(2), map[string]interface{}{"timeout": "5s", "consistencyLevel": "eventually"}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Len(t, service.levels, 3)
+	assert.Equal(t, commonpb.ConsistencyLevel_Eventually, service.levels[0])
+
+	service.lagReads = 100
+	result = client.InsertAndVerify(verifyData(2), map[string]interface{}{"timeout": "250ms"}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Equal(t, float64(2), result["result"].(map[string]interface{})["missing"])
+
+	for _, options := range []map[string]interface{}{
+		{"verify": "scan"},
+		{"consistencyLevel": "linearizable"},
+		{"timeout": "later"},
+		{"verify": "search", "vectorField": "other"},
+	} {
+		result := client.InsertAndVerify(verifyData(1), options).(map[string]interface{})
+		assert.Equal(t, false, result["success"], options)
+	}
+}
+
+func TestIDSubset(t *testing.T) {
+	keys, err := idSubset(column.NewColumnVarChar("pk", []string{"a", "b", "c"}), []int64{2, 0})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a"}, keys.(*column.ColumnVarChar).Data())
+	_, err = idSubset(column.NewColumnBool("pk", []bool{true}), []int64{0})
+	assert.Error(t, err)
+}
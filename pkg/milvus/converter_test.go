@@ -3,6 +3,7 @@ package milvus
 import (
 	"testing"
 
+	"github.com/grafana/sobek"
 	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
 	"github.com/milvus-io/milvus/client/v2/column"
 	"github.com/milvus-io/milvus/client/v2/entity"
@@ -469,6 +470,50 @@ func TestConvertToSearchVectorsFloatVectorArray(t *testing.T) {
 	assert.Equal(t, entity.FloatVector{0.3, 0.4}, vectorArray[1])
 }
 
+func TestTypedArrayVectors(t *testing.T) {
+	rt := sobek.New()
+	value, err := rt.RunString(`[
+		new Float32Array([1, 2]),
+		new Float64Array([3, 4]),
+		new Float32Array([5, 6]).buffer,
+	]`)
+	require.NoError(t, err)
+	input := value.Export()
+	expected := []entity.FloatVector{{1, 2}, {3, 4}, {5, 6}}
+
+	c := &Client{}
+	col, err := c.convertFieldToColumn("embedding", input)
+	require.NoError(t, err)
+	require.IsType(t, &column.ColumnFloatVector{}, col)
+	assert.Equal(t, 2, col.(*column.ColumnFloatVector).Dim())
+	assert.Equal(t, expected, col.(*column.ColumnFloatVector).Data())
+
+	vectors, err := convertToSearchVectors(input)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Vector{entity.FloatVector{1, 2}, entity.FloatVector{3, 4}, entity.FloatVector{5, 6}}, vectors)
+
+	// A single Float32Array is one query
+	value, err = rt.RunString(`new Float32Array([7, 8])`)
+	require.NoError(t, err)
+	vectors, err = convertToSearchVectors(value.Export())
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Vector{entity.FloatVector{7, 8}}, vectors)
+
+	for script, message := range map[string]string{
+		`[new Float32Array([1, 2]), new Float32Array([1])]`: "vector 1 has dimension 1, expected 2",
+		`[new Float32Array([1, 2]), [1, 2]]`:                "vector 1 has type",
+		`[new Float32Array([])]`:                            "vector 0 is empty",
+		`[new Uint8Array([1, 2, 3]).buffer]`:                "ArrayBuffer of 3 bytes",
+	} {
+		value, err := rt.RunString(script)
+		require.NoError(t, err)
+		_, err = c.convertFieldToColumn("embedding", value.Export())
+		assert.ErrorContains(t, err, message, script)
+		_, err = convertToSearchVectors(value.Export())
+		assert.ErrorContains(t, err, message, script)
+	}
+}
+
 func TestSchemaStructure(t *testing.T) {
 	// Test Schema structure
 	schema := Schema{
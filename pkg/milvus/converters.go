@@ -1,8 +1,12 @@
 package milvus
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
+
+	"github.com/grafana/sobek"
 
 	"github.com/milvus-io/milvus/client/v2/column"
 	"github.com/milvus-io/milvus/client/v2/entity"
@@ -32,6 +36,9 @@ func (c *Client) convertDataToColumns(data map[string]interface{}) ([]column.Col
 // convertFieldToColumn converts a single field to a Milvus column
 func (c *Client) convertFieldToColumn(fieldName string, fieldData interface{}) (column.Column, error) {
 	switch v := fieldData.(type) {
+	case column.Column: // Typed by the collection schema, e.g. from client.fileLoader()
+		return v, nil
+
 	case [][]float32:
 		if len(v) == 0 {
 			return nil, nil // skip empty arrays
@@ -45,6 +52,12 @@ func (c *Client) convertFieldToColumn(fieldName string, fieldData interface{}) (
 	case []int32:
 		return column.NewColumnInt32(fieldName, v), nil
 
+	case []int16:
+		return column.NewColumnInt16(fieldName, v), nil
+
+	case []int8:
+		return column.NewColumnInt8(fieldName, v), nil
+
 	case []float32:
 		return column.NewColumnFloat(fieldName, v), nil
 
@@ -57,7 +70,35 @@ func (c *Client) convertFieldToColumn(fieldName string, fieldData interface{}) (
 	case []bool:
 		return column.NewColumnBool(fieldName, v), nil
 
+	case []entity.SparseEmbedding: // e.g. from milvus.sparseReader()
+		if len(v) == 0 {
+			return nil, nil // skip empty arrays
+		}
+		return column.NewColumnSparseVectors(fieldName, v), nil
+
+	case []map[string]interface{}: // JSON objects, e.g. from milvus.dataFaker()
+		jsonBytes := make([][]byte, len(v))
+		for i, val := range v {
+			b, err := json.Marshal(val)
+			if err != nil {
+				return nil, newError("convertFieldToColumn", ErrInvalidDataType,
+					fmt.Sprintf("field %s: failed to marshal JSON at index %d", fieldName, i))
+			}
+			jsonBytes[i] = b
+		}
+		return column.NewColumnJSONBytes(fieldName, jsonBytes), nil
+
 	case []interface{}:
+		if sparse := sparseEmbeddings(v); sparse != nil {
+			return column.NewColumnSparseVectors(fieldName, sparse), nil
+		}
+		vectors, err := typedArrayVectors(v)
+		if err != nil {
+			return nil, newError("convertFieldToColumn", ErrInvalidDataType, fmt.Sprintf("field %s: %v", fieldName, err))
+		}
+		if vectors != nil {
+			return column.NewColumnFloatVector(fieldName, len(vectors[0]), vectors), nil
+		}
 		return c.convertInterfaceSlice(fieldName, v)
 
 	default:
@@ -592,7 +633,7 @@ func (c *Client) convertNestedStructArrays(fieldName string, v []interface{}) (c
 }
 
 // convertToSearchVectors converts various input types to []entity.Vector for search.
-// Supports: [][]float32 (dense), []string (BM25 text), and mixed via JSON round-trip.
+// Supports: [][]float32 (dense), typed arrays, []string (BM25 text), and mixed via JSON round-trip.
 func convertToSearchVectors(input interface{}) ([]entity.Vector, error) {
 	// Fast path: already [][]float32
 	if vecs, ok := input.([][]float32); ok {
@@ -602,6 +643,28 @@ func convertToSearchVectors(input interface{}) ([]entity.Vector, error) {
 		}
 		return result, nil
 	}
+	// A single Float32Array query
+	if vec, ok := input.([]float32); ok && len(vec) > 0 {
+		return []entity.Vector{entity.FloatVector(vec)}, nil
+	}
+	vecs, err := typedArrayVectors(input)
+	if err != nil {
+		return nil, err
+	}
+	if vecs != nil {
+		result := make([]entity.Vector, len(vecs))
+		for i, v := range vecs {
+			result[i] = entity.FloatVector(v)
+		}
+		return result, nil
+	}
+	if sparse := sparseEmbeddings(input); sparse != nil {
+		result := make([]entity.Vector, len(sparse))
+		for i, v := range sparse {
+			result[i] = v
+		}
+		return result, nil
+	}
 
 	// JSON round-trip for Goja runtime values
 	data, err := json.Marshal(input)
@@ -674,3 +737,73 @@ func convertToSearchVectors(input interface{}) ([]entity.Vector, error) {
 
 	return nil, fmt.Errorf("unsupported search vector format")
 }
+
+// sparseEmbeddings returns sparse vectors parsed in Go, e.g. by milvus.sparseReader(), either as
+// returned or picked into a JavaScript array; nil for other input
+func sparseEmbeddings(input interface{}) []entity.SparseEmbedding {
+	switch v := input.(type) {
+	case []entity.SparseEmbedding:
+		if len(v) > 0 {
+			return v
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		sparse := make([]entity.SparseEmbedding, len(v))
+		for i, item := range v {
+			embedding, ok := item.(entity.SparseEmbedding)
+			if !ok {
+				return nil
+			}
+			sparse[i] = embedding
+		}
+		return sparse
+	}
+	return nil
+}
+
+// typedArrayVectors reads an array of JavaScript typed arrays without converting each element
+// through the runtime: Float32Array rows are used as is, Float64Array rows are narrowed in Go,
+// and ArrayBuffer rows are read as little-endian float32. It returns nil for other input.
+func typedArrayVectors(input interface{}) ([][]float32, error) {
+	v, ok := input.([]interface{})
+	if !ok || len(v) == 0 {
+		return nil, nil
+	}
+	vectors := make([][]float32, len(v))
+	for i, item := range v {
+		switch row := item.(type) {
+		case []float32:
+			vectors[i] = row
+		case []float64:
+			vector := make([]float32, len(row))
+			for j, x := range row {
+				vector[j] = float32(x)
+			}
+			vectors[i] = vector
+		case sobek.ArrayBuffer:
+			data := row.Bytes()
+			if len(data)%4 != 0 {
+				return nil, fmt.Errorf("vector %d: ArrayBuffer of %d bytes is not float32 data", i, len(data))
+			}
+			vector := make([]float32, len(data)/4)
+			for j := range vector {
+				vector[j] = math.Float32frombits(binary.LittleEndian.Uint32(data[j*4:]))
+			}
+			vectors[i] = vector
+		default:
+			if i > 0 {
+				return nil, fmt.Errorf("vector %d has type %T, expected a typed array like vector 0", i, item)
+			}
+			return nil, nil
+		}
+		if len(vectors[i]) == 0 {
+			return nil, fmt.Errorf("vector %d is empty", i)
+		}
+		if len(vectors[i]) != len(vectors[0]) {
+			return nil, fmt.Errorf("vector %d has dimension %d, expected %d", i, len(vectors[i]), len(vectors[0]))
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,287 @@
+package milvus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Text corpus formats
+const (
+	corpusFormatText  = "text"  // One document per line
+	corpusFormatJSONL = "jsonl" // One JSON object per line
+)
+
+// TextCorpusConfig configures milvus.textCorpus()
+type TextCorpusConfig struct {
+	Format    string `json:"format,omitempty"`    // "text" or "jsonl" (default: jsonl for .jsonl and .ndjson files, otherwise text)
+	BatchSize int    `json:"batchSize,omitempty"` // Documents per batch (default: 1000)
+	TextField string `json:"textField,omitempty"` // Batch field of the document text (default: "text")
+	TextKey   string `json:"textKey,omitempty"`   // JSONL key of the document text (default: "text")
+	TitleKey  string `json:"titleKey,omitempty"`  // JSONL key of a title prepended to the text, as in BEIR corpora
+	IDField   string `json:"idField,omitempty"`   // Batch field of document IDs (default: no IDs)
+	IDKey     string `json:"idKey,omitempty"`     // JSONL key of document IDs (default: the document index)
+	MaxLength int    `json:"maxLength,omitempty"` // Truncates texts to this many bytes, e.g. the VarChar maxLength
+	Loop      bool   `json:"loop,omitempty"`      // Starts over at the end of the file, e.g. for query texts
+}
+
+// TextCorpus reads a plain-text or JSONL document corpus in insert-ready batches, to drive
+// BM25 and text match workloads. Batches hold the document texts as a VARCHAR column, with
+// document IDs when idField is set.
+//
+// Usage in k6:
+//
+//	const corpus = milvus.textCorpus('data/msmarco/corpus.jsonl', { idField: 'id', idKey: '_id', titleKey: 'title' });
+//	export default function () {
+//	    const batch = corpus.next();
+//	    if (batch) client.insert(batch);
+//	}
+type TextCorpus struct {
+	path   string
+	format string
+	config TextCorpusConfig
+	file   *os.File
+	lines  *bufio.Reader
+	line   int   // Line of the last document read
+	index  int64 // Index of the next document, its ID without idKey
+}
+
+// TextCorpus opens a document corpus for batched inserts. Relative paths are resolved against
+// the working directory of the k6 process.
+func (m *Milvus) TextCorpus(path string, configInput ...interface{}) (*TextCorpus, error) {
+	var config TextCorpusConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid text corpus config: %v", err)
+		}
+	}
+	path, err := localDataset(m.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	return openTextCorpus(path, config)
+}
+
+func openTextCorpus(path string, config TextCorpusConfig) (*TextCorpus, error) {
+	if config.BatchSize < 0 || config.MaxLength < 0 {
+		return nil, fmt.Errorf("text corpus batchSize and maxLength must not be negative")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultLoaderBatchSize
+	}
+	if config.TextField == "" {
+		config.TextField = "text"
+	}
+	if config.TextKey == "" {
+		config.TextKey = "text"
+	}
+	if config.IDField != "" && config.IDField == config.TextField {
+		return nil, fmt.Errorf("text corpus idField and textField must differ, both are %q", config.IDField)
+	}
+	format := strings.ToLower(config.Format)
+	if format == "" {
+		format = corpusFormatText
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".jsonl" || ext == ".ndjson" {
+			format = corpusFormatJSONL
+		}
+	}
+	if format != corpusFormatText && format != corpusFormatJSONL {
+		return nil, fmt.Errorf("text corpus format must be text or jsonl, got %q", config.Format)
+	}
+	if format == corpusFormatText && (config.TitleKey != "" || config.IDKey != "") {
+		return nil, fmt.Errorf("text corpus titleKey and idKey require the jsonl format")
+	}
+	if config.IDKey != "" && config.IDField == "" {
+		return nil, fmt.Errorf("text corpus idKey requires idField")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open text corpus: %v", err)
+	}
+	return &TextCorpus{path: path, format: format, config: config, file: file, lines: bufio.NewReaderSize(file, 1<<20)}, nil
+}
+
+// Next returns the next batch as { [textField]: texts, [idField]: ids }, or null once the file
+// is read. With loop, reading starts over at the end of the file instead.
+func (t *TextCorpus) Next() (map[string]interface{}, error) {
+	if t.file == nil {
+		return nil, fmt.Errorf("text corpus %s is closed", t.path)
+	}
+	texts := make([]string, 0, t.config.BatchSize)
+	var intIDs []int64
+	var stringIDs []string
+	restarted := false
+	for len(texts) < t.config.BatchSize {
+		text, id, err := t.readDocument()
+		if errors.Is(err, io.EOF) {
+			// Start over once, so a corpus without documents does not loop forever
+			if !t.config.Loop || restarted {
+				break
+			}
+			if err := t.Reset(); err != nil {
+				return nil, err
+			}
+			restarted = true
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", t.path, t.line, err)
+		}
+		restarted = false
+		texts = append(texts, text)
+		switch v := id.(type) {
+		case int64:
+			intIDs = append(intIDs, v)
+		case string:
+			stringIDs = append(stringIDs, v)
+		}
+		if intIDs != nil && stringIDs != nil {
+			return nil, fmt.Errorf("%s line %d: %s mixes integer and string IDs", t.path, t.line, t.config.IDKey)
+		}
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	batch := map[string]interface{}{t.config.TextField: texts}
+	if t.config.IDField != "" {
+		if stringIDs != nil {
+			batch[t.config.IDField] = stringIDs
+		} else {
+			batch[t.config.IDField] = intIDs
+		}
+	}
+	return batch, nil
+}
+
+// readDocument returns the text and ID of the next document, skipping blank lines. The ID is
+// an int64 or a string, nil without idField.
+func (t *TextCorpus) readDocument() (string, interface{}, error) {
+	for {
+		line, err := t.lines.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return "", nil, err
+		}
+		t.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var text string
+		var id interface{}
+		if t.config.IDField != "" && t.config.IDKey == "" {
+			id = t.index
+		}
+		if t.format == corpusFormatText {
+			text = string(line)
+		} else {
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.UseNumber()
+			var values map[string]interface{}
+			if err := decoder.Decode(&values); err != nil {
+				return "", nil, fmt.Errorf("invalid JSON: %v", err)
+			}
+			if text, err = corpusString(values, t.config.TextKey); err != nil {
+				return "", nil, err
+			}
+			if t.config.TitleKey != "" {
+				title, err := corpusString(values, t.config.TitleKey)
+				if err == nil && title != "" {
+					text = title + " " + text
+				}
+			}
+			if t.config.IDKey != "" {
+				if id, err = corpusID(values, t.config.IDKey); err != nil {
+					return "", nil, err
+				}
+			}
+		}
+		t.index++
+		return truncateText(text, t.config.MaxLength), id, nil
+	}
+}
+
+// corpusString returns the string value of a JSONL key
+func corpusString(values map[string]interface{}, key string) (string, error) {
+	value, ok := values[key]
+	if !ok || value == nil {
+		return "", fmt.Errorf("missing %q", key)
+	}
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%q must be a string, got %T", key, value)
+	}
+	return text, nil
+}
+
+// corpusID returns the ID of a JSONL document: an int64 for integers, otherwise a string
+func corpusID(values map[string]interface{}, key string) (interface{}, error) {
+	switch v := values[key].(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		id, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q must be an integer or a string, got %s", key, v)
+		}
+		return id, nil
+	case nil:
+		return nil, fmt.Errorf("missing %q", key)
+	default:
+		return nil, fmt.Errorf("%q must be an integer or a string, got %T", key, v)
+	}
+}
+
+// truncateText cuts text to at most maxLength bytes without splitting a UTF-8 character;
+// 0 keeps the whole text
+func truncateText(text string, maxLength int) string {
+	if maxLength <= 0 || len(text) <= maxLength {
+		return text
+	}
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end]
+}
+
+// Reset starts over at the first document
+func (t *TextCorpus) Reset() error {
+	if t.file == nil {
+		return fmt.Errorf("text corpus %s is closed", t.path)
+	}
+	if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %v", t.path, err)
+	}
+	t.lines.Reset(t.file)
+	t.line, t.index = 0, 0
+	return nil
+}
+
+// Fields returns the field names of each batch
+func (t *TextCorpus) Fields() []string {
+	if t.config.IDField == "" {
+		return []string{t.config.TextField}
+	}
+	return []string{t.config.IDField, t.config.TextField}
+}
+
+// Close closes the underlying file
+func (t *TextCorpus) Close() error {
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
@@ -0,0 +1,134 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextCorpusPlainText(t *testing.T) {
+	path := writeLoaderFile(t, "docs.txt", "first document\n\n  second document  \r\nthird\n")
+	corpus, err := openTextCorpus(path, TextCorpusConfig{BatchSize: 2, IDField: "id"})
+	require.NoError(t, err)
+	defer func() { _ = corpus.Close() }()
+	assert.Equal(t, []string{"id", "text"}, corpus.Fields())
+
+	batch, err := corpus.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"text": []string{"first document", "second document"},
+		"id":   []int64{0, 1},
+	}, batch)
+	batch, err = corpus.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"text": []string{"third"}, "id": []int64{2}}, batch)
+	batch, err = corpus.Next()
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+
+	require.NoError(t, corpus.Reset())
+	batch, err = corpus.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1}, batch["id"])
+}
+
+func TestTextCorpusJSONL(t *testing.T) {
+	path := writeLoaderFile(t, "corpus.jsonl", `{"_id": "doc1", "title": "Milvus", "text": "a vector database"}
+{"_id": "doc2", "title": "", "text": "full-text search"}
+`)
+	corpus, err := openTextCorpus(path, TextCorpusConfig{TextField: "body", TitleKey: "title", IDField: "pk", IDKey: "_id"})
+	require.NoError(t, err)
+	defer func() { _ = corpus.Close() }()
+
+	batch, err := corpus.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"body": []string{"Milvus a vector database", "full-text search"},
+		"pk":   []string{"doc1", "doc2"},
+	}, batch)
+}
+
+func TestTextCorpusIntegerIDs(t *testing.T) {
+	path := writeLoaderFile(t, "corpus.ndjson", `{"id": 7, "text": "a"}
+{"id": 9, "text": "b"}
+`)
+	corpus, err := openTextCorpus(path, TextCorpusConfig{IDField: "id", IDKey: "id"})
+	require.NoError(t, err)
+	defer func() { _ = corpus.Close() }()
+	batch, err := corpus.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{7, 9}, batch["id"])
+}
+
+func TestTextCorpusLoop(t *testing.T) {
+	path := writeLoaderFile(t, "queries.txt", "q1\nq2\nq3\n")
+	corpus, err := openTextCorpus(path, TextCorpusConfig{BatchSize: 2, Loop: true})
+	require.NoError(t, err)
+	defer func() { _ = corpus.Close() }()
+
+	var texts []string
+	for i := 0; i < 3; i++ {
+		batch, err := corpus.Next()
+		require.NoError(t, err)
+		texts = append(texts, batch["text"].([]string)...)
+	}
+	assert.Equal(t, []string{"q1", "q2", "q3", "q1", "q2", "q3"}, texts)
+
+	empty, err := openTextCorpus(writeLoaderFile(t, "empty.txt", "\n"), TextCorpusConfig{Loop: true})
+	require.NoError(t, err)
+	defer func() { _ = empty.Close() }()
+	batch, err := empty.Next()
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+}
+
+func TestTextCorpusMaxLength(t *testing.T) {
+	assert.Equal(t, "héllo", truncateText("héllo", 0))
+	assert.Equal(t, "hé", truncateText("héllo", 3))
+	assert.Equal(t, "h", truncateText("héllo", 2), "a character is never split")
+
+	path := writeLoaderFile(t, "docs.txt", "abcdef\n")
+	corpus, err := openTextCorpus(path, TextCorpusConfig{MaxLength: 4})
+	require.NoError(t, err)
+	defer func() { _ = corpus.Close() }()
+	batch, err := corpus.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abcd"}, batch["text"])
+}
+
+func TestTextCorpusErrors(t *testing.T) {
+	text := writeLoaderFile(t, "docs.txt", "a\n")
+	for name, config := range map[string]TextCorpusConfig{
+		"format":        {Format: "csv"},
+		"batch size":    {BatchSize: -1},
+		"id key":        {IDKey: "_id", IDField: "id"},
+		"same fields":   {IDField: "text"},
+		"title of text": {TitleKey: "title"},
+	} {
+		_, err := openTextCorpus(text, config)
+		assert.Error(t, err, name)
+	}
+	_, err := openTextCorpus(writeLoaderFile(t, "c.jsonl", "{}\n"), TextCorpusConfig{IDKey: "_id"})
+	assert.ErrorContains(t, err, "idKey requires idField")
+
+	for content, message := range map[string]string{
+		"{\"body\": \"x\"}\n":              `line 1: missing "text"`,
+		"{\"text\": 1}\n":                  `"text" must be a string`,
+		"not json\n":                       "line 1: invalid JSON",
+		"{\"text\": \"a\", \"id\": 1.5}\n": `"id" must be an integer or a string`,
+		"{\"text\": \"a\", \"id\": 1}\n{\"text\": \"b\", \"id\": \"x\"}\n": "mixes integer and string IDs",
+	} {
+		corpus, err := openTextCorpus(writeLoaderFile(t, "c.jsonl", content), TextCorpusConfig{IDField: "id", IDKey: "id"})
+		require.NoError(t, err)
+		_, err = corpus.Next()
+		assert.ErrorContains(t, err, message)
+		_ = corpus.Close()
+	}
+
+	corpus, err := openTextCorpus(text, TextCorpusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, corpus.Close())
+	_, err = corpus.Next()
+	assert.Error(t, err)
+}
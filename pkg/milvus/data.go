@@ -4,15 +4,35 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/milvus-io/milvus/client/v2/column"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
 )
 
 // Insert inserts data into a collection
-// Supports both collection-bound and explicit collection name
-func (c *Client) Insert(data map[string]interface{}, collectionName ...string) interface{} {
+// Supports both collection-bound and explicit collection name, either as a string or as
+// options { collectionName, partitionName, tags, sample, batchSize, concurrency } where tags are added to the
+// metrics emitted by the call, sample is a milvus.insertSample() recording the inserted vectors, and batchSize
+// splits the rows into Insert RPCs of that many rows, up to concurrency of them in flight at once.
+// Data is columns by field name, or a client.prepareBatch() batch sent to its own collection.
+func (c *Client) Insert(dataInput interface{}, args ...interface{}) interface{} {
 	start := time.Now()
 
-	coll := c.getCollectionName(collectionName...)
+	coll, options := c.parseQueryArgs(args...)
+	batch, _ := dataInput.(*PreparedBatch)
+	data, _ := dataInput.(map[string]interface{})
+	if batch != nil {
+		// Batches of milvus.arrowReader() have no collection of their own
+		if batch.collection != "" {
+			coll = batch.collection
+		}
+		data = batch.vectors
+	} else if data == nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("insert data must be an object of columns or a prepared batch, got %T", dataInput),
+		})
+	}
 	if coll == "" {
 		return toMap(&OperationResult{
 			Success:      false,
@@ -21,8 +41,53 @@ func (c *Client) Insert(data map[string]interface{}, collectionName ...string) i
 		})
 	}
 
-	columns, err := c.convertDataToColumns(data)
+	tags, err := tagsOption(options)
 	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("invalid tags: %v", err),
+		})
+	}
+	partition, _ := stringOption(options, "partitionName")
+	var partitions []string
+	if partition != "" {
+		partitions = []string{partition}
+	}
+	client := c.withTags(tags).withTargetTags(partitions, "")
+	batchSize, _ := intOption(options, "batchSize")
+	concurrency, _ := intOption(options, "concurrency")
+	if batchSize < 0 || concurrency < 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "batchSize and concurrency must not be negative",
+		})
+	}
+
+	var sample *InsertSample
+	var sampleRows interface{}
+	if value, ok := options["sample"]; ok && value != nil {
+		if sample, ok = value.(*InsertSample); !ok {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        fmt.Sprintf("sample must be a milvus.insertSample() object, got %T", value),
+			})
+		}
+		if sampleRows, err = sample.vectorColumn(data); err != nil {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        err.Error(),
+			})
+		}
+	}
+
+	var columns []column.Column
+	if batch != nil {
+		columns = batch.insertColumns()
+	} else if columns, err = c.convertDataToColumns(data); err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
@@ -30,21 +95,58 @@ func (c *Client) Insert(data map[string]interface{}, collectionName ...string) i
 		})
 	}
 
-	option := milvusclient.NewColumnBasedInsertOption(coll, columns...)
-	result, err := c.client.Insert(c.context(), option)
+	chunks, err := insertChunks(columns, batchSize)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
-			Error:        fmt.Sprintf("failed to insert: %v", err),
+			Error:        err.Error(),
+		})
+	}
+	client.insertChunks(coll, partition, chunks, concurrency)
+	responseTime := float64(time.Since(start).Milliseconds())
+	client.pushChunkDurations(coll, chunks, concurrency)
+
+	var inserted int64
+	var failed []*insertChunk
+	for _, chunk := range chunks {
+		if chunk.err != nil {
+			failed = append(failed, chunk)
+			continue
+		}
+		inserted += chunk.result.InsertCount
+		if sample != nil {
+			sample.record(chunk.result.IDs, sliceRows(sampleRows, chunk.start, chunk.end))
+		}
+	}
+	switch {
+	case len(chunks) == 1 && len(failed) == 1:
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: responseTime,
+			Error:        fmt.Sprintf("failed to insert: %v", failed[0].err),
+			Cause:        failed[0].err,
+		})
+	case len(failed) > 0:
+		// Rows of the other chunks were inserted, so the count is still reported
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: responseTime,
+			Error:        fmt.Sprintf("failed to insert %d of %d chunks: %v", len(failed), len(chunks), failed[0].err),
+			Cause:        failed[0].err,
+			Result: map[string]interface{}{
+				"insert_count": inserted,
+				"chunks":       len(chunks),
+			},
 		})
 	}
 
 	return toMap(&OperationResult{
 		Success:      true,
-		ResponseTime: float64(time.Since(start).Milliseconds()),
+		ResponseTime: responseTime,
 		Result: map[string]interface{}{
-			"insert_count": result.InsertCount,
+			"insert_count": inserted,
+			"chunks":       len(chunks),
 		},
 	})
 }
@@ -72,12 +174,13 @@ func (c *Client) Upsert(data map[string]interface{}, collectionName ...string) i
 	}
 
 	option := milvusclient.NewColumnBasedInsertOption(coll, columns...)
-	result, err := c.client.Upsert(c.context(), option)
+	result, err := c.milvus().Upsert(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to upsert: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -105,12 +208,13 @@ func (c *Client) Flush(collectionName ...string) interface{} {
 	}
 
 	option := milvusclient.NewFlushOption(coll)
-	task, err := c.client.Flush(c.context(), option)
+	task, err := c.milvus().Flush(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to flush: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -121,6 +225,7 @@ func (c *Client) Flush(collectionName ...string) interface{} {
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to wait for flush: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -148,12 +253,13 @@ func (c *Client) Delete(filter string, collectionName ...string) interface{} {
 	}
 
 	option := milvusclient.NewDeleteOption(coll).WithExpr(filter)
-	result, err := c.client.Delete(c.context(), option)
+	result, err := c.milvus().Delete(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to delete: %v", err),
+			Cause:        err,
 		})
 	}
 
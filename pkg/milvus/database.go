@@ -0,0 +1,149 @@
+package milvus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// CreateDatabase creates a new database
+// Parameters:
+//   - name: database name
+//   - properties: optional database properties (e.g. "database.replica.number")
+func (c *Client) CreateDatabase(name string, properties ...map[string]interface{}) interface{} {
+	start := time.Now()
+
+	if name == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        ErrDatabaseNameRequired.Error(),
+		})
+	}
+	if c.cloud() {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to create database: %v", ErrUnsupportedInCloud),
+			Cause:        ErrUnsupportedInCloud,
+		})
+	}
+
+	option := milvusclient.NewCreateDatabaseOption(name)
+	if len(properties) > 0 {
+		for key, value := range indexProperties(properties[0]) {
+			option = option.WithProperty(key, value)
+		}
+	}
+
+	err := c.milvus().CreateDatabase(c.context(), option)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to create database: %v", err),
+			Cause:        err,
+		})
+	}
+	c.trackResource("database", name, "")
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"database": name},
+	})
+}
+
+// DropDatabase drops a database. The database must not contain any collections.
+func (c *Client) DropDatabase(name string) interface{} {
+	start := time.Now()
+
+	if name == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        ErrDatabaseNameRequired.Error(),
+		})
+	}
+	if c.cloud() {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to drop database: %v", ErrUnsupportedInCloud),
+			Cause:        ErrUnsupportedInCloud,
+		})
+	}
+
+	err := c.milvus().DropDatabase(c.context(), milvusclient.NewDropDatabaseOption(name))
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to drop database: %v", err),
+			Cause:        err,
+		})
+	}
+	c.untrackResource("database", name, "")
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"database": name},
+	})
+}
+
+// ListDatabases lists all databases visible to the current user
+func (c *Client) ListDatabases() interface{} {
+	start := time.Now()
+
+	databases, err := c.milvus().ListDatabase(c.context(), milvusclient.NewListDatabaseOption())
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to list databases: %v", err),
+			Cause:        err,
+		})
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       databases,
+		Empty:        len(databases) == 0,
+	})
+}
+
+// UseDatabase switches the client to another database.
+// Subsequent operations on this client target collections in that database.
+func (c *Client) UseDatabase(name string) interface{} {
+	start := time.Now()
+
+	if name == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        ErrDatabaseNameRequired.Error(),
+		})
+	}
+
+	err := c.milvus().UseDatabase(c.context(), milvusclient.NewUseDatabaseOption(name))
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to use database: %v", err),
+			Cause:        err,
+		})
+	}
+	if c.config != nil {
+		c.config.DBName = name
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"database": name},
+	})
+}
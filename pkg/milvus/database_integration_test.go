@@ -0,0 +1,67 @@
+//go:build integration
+// +build integration
+
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseLifecycle_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	milvusHost := os.Getenv("MILVUS_HOST")
+	if milvusHost == "" {
+		milvusHost = "localhost:19530"
+	}
+
+	milvusModule := &Milvus{
+		vu: &mockVU{ctx: context.Background()},
+	}
+
+	client, err := milvusModule.Client(milvusHost)
+	require.NoError(t, err)
+	defer client.Close()
+
+	dbName := fmt.Sprintf("test_db_%d", time.Now().UnixNano())
+
+	t.Run("create_database", func(t *testing.T) {
+		resultMap := client.CreateDatabase(dbName).(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+	})
+
+	t.Run("list_databases", func(t *testing.T) {
+		resultMap := client.ListDatabases().(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+		assert.Contains(t, resultMap["result"], dbName)
+	})
+
+	t.Run("use_database", func(t *testing.T) {
+		resultMap := client.UseDatabase(dbName).(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+		assert.Equal(t, dbName, client.config.DBName)
+
+		resultMap = client.UseDatabase("default").(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+	})
+
+	t.Run("drop_database", func(t *testing.T) {
+		resultMap := client.DropDatabase(dbName).(map[string]interface{})
+		assert.Equal(t, true, resultMap["success"], resultMap["error"])
+	})
+
+	t.Run("missing_name", func(t *testing.T) {
+		resultMap := client.CreateDatabase("").(map[string]interface{})
+		assert.Equal(t, false, resultMap["success"])
+		assert.Equal(t, ErrDatabaseNameRequired.Error(), resultMap["error"])
+	})
+}
@@ -0,0 +1,249 @@
+package milvus
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvDatasetCache is the directory of downloaded datasets (default: the user cache directory)
+const EnvDatasetCache = "MILVUS_DATASET_CACHE"
+
+// Environment variables of remote dataset sources, named as in the AWS and Google Cloud tools
+const (
+	envAWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	envAWSSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	envAWSSessionToken    = "AWS_SESSION_TOKEN"
+	envAWSRegion          = "AWS_REGION"
+	envAWSDefaultRegion   = "AWS_DEFAULT_REGION"
+	envAWSEndpointURL     = "AWS_ENDPOINT_URL"    // S3-compatible endpoint, e.g. MinIO, with path-style URLs
+	envAWSEndpointURLS3   = "AWS_ENDPOINT_URL_S3" // Takes precedence over AWS_ENDPOINT_URL
+	envGCSToken           = "GOOGLE_OAUTH_ACCESS_TOKEN"
+	envGCSEmulatorHost    = "STORAGE_EMULATOR_HOST" // host:port of a GCS emulator
+)
+
+// datasetHTTPClient downloads datasets. Large files take long, so only connecting and waiting for
+// the response headers are bounded.
+var datasetHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   30 * time.Second,
+		ResponseHeaderTimeout: time.Minute,
+	},
+}
+
+// isRemoteDataset reports whether a dataset source is a URL to download
+func isRemoteDataset(source string) bool {
+	for _, scheme := range []string{"http://", "https://", "s3://", "gs://"} {
+		if strings.HasPrefix(strings.ToLower(source), scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// localDataset returns the local path of a dataset source. URLs (http, https, s3 and gs) are
+// downloaded once per test into the dataset cache and reused by later tests; other sources are
+// local paths, returned as is.
+func localDataset(shared *sync.Map, source string) (string, error) {
+	if !isRemoteDataset(source) {
+		return source, nil
+	}
+	return sharedValue(shared, "download\x00"+source, func() (string, error) {
+		return downloadDataset(source)
+	})
+}
+
+// downloadDataset downloads a dataset into the cache, unless a previous download is there. The
+// cached file keeps the name of the remote file, so its format is still known from the extension.
+func downloadDataset(source string) (string, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid dataset URL %s: %v", source, err)
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "dataset"
+	}
+	dir, err := datasetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(source))
+	dir = filepath.Join(dir, hex.EncodeToString(sum[:8]))
+	local := filepath.Join(dir, name)
+	if _, err := os.Stat(local); err == nil {
+		return local, nil
+	}
+
+	req, err := datasetRequest(u)
+	if err != nil {
+		return "", fmt.Errorf("dataset %s: %v", source, err)
+	}
+	resp, err := datasetHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download dataset %s: %v", source, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("failed to download dataset %s: %s %s", source, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	// Download next to the cached file and rename it once complete, so an interrupted download
+	// is never taken for a cached one
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create dataset cache: %v", err)
+	}
+	tmp, err := os.CreateTemp(dir, name+".part-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create dataset cache: %v", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("failed to download dataset %s: %v", source, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write dataset cache: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), local); err != nil {
+		return "", fmt.Errorf("failed to write dataset cache: %v", err)
+	}
+	return local, nil
+}
+
+// datasetCacheDir returns MILVUS_DATASET_CACHE, or xk6-milvus/datasets in the user cache directory
+func datasetCacheDir() (string, error) {
+	if dir := os.Getenv(EnvDatasetCache); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no dataset cache directory, set %s: %v", EnvDatasetCache, err)
+	}
+	return filepath.Join(dir, "xk6-milvus", "datasets"), nil
+}
+
+// datasetRequest builds the download request of a dataset URL. s3:// and gs:// URLs are fetched
+// from the storage HTTP APIs, signed when credentials are set in the environment.
+func datasetRequest(u *url.URL) (*http.Request, error) {
+	switch strings.ToLower(u.Scheme) {
+	case "s3":
+		return s3Request(u.Host, strings.TrimPrefix(u.Path, "/"), time.Now())
+	case "gs":
+		base := "https://storage.googleapis.com"
+		if host := os.Getenv(envGCSEmulatorHost); host != "" {
+			base = host
+			if !strings.Contains(host, "://") {
+				base = "http://" + host
+			}
+		}
+		req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(base, "/")+"/"+u.Host+"/"+escapePath(strings.TrimPrefix(u.Path, "/")), nil)
+		if err != nil {
+			return nil, err
+		}
+		if token := os.Getenv(envGCSToken); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+	default:
+		return http.NewRequest(http.MethodGet, u.String(), nil)
+	}
+}
+
+// s3Request builds a GET request of an S3 object, signed with AWS Signature Version 4 when
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are set. Without them, the object must be public.
+func s3Request(bucket, key string, now time.Time) (*http.Request, error) {
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("s3 URLs must be s3://bucket/key")
+	}
+	region := os.Getenv(envAWSRegion)
+	if region == "" {
+		region = os.Getenv(envAWSDefaultRegion)
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := os.Getenv(envAWSEndpointURLS3)
+	if endpoint == "" {
+		endpoint = os.Getenv(envAWSEndpointURL)
+	}
+	var target string
+	if endpoint != "" {
+		target = strings.TrimSuffix(endpoint, "/") + "/" + bucket + "/" + escapePath(key)
+	} else {
+		target = "https://" + bucket + ".s3." + region + ".amazonaws.com/" + escapePath(key)
+	}
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	accessKey, secretKey := os.Getenv(envAWSAccessKeyID), os.Getenv(envAWSSecretAccessKey)
+	if accessKey == "" || secretKey == "" {
+		return req, nil
+	}
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := amzDate[:8] + "/" + region + "/s3/aws4_request"
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	headers := "host:" + req.URL.Host + "\nx-amz-content-sha256:UNSIGNED-PAYLOAD\nx-amz-date:" + amzDate + "\n"
+	if token := os.Getenv(envAWSSessionToken); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+		signed = append(signed, "x-amz-security-token")
+		headers += "x-amz-security-token:" + token + "\n"
+	}
+	signedHeaders := strings.Join(signed, ";")
+	canonical := strings.Join([]string{http.MethodGet, req.URL.EscapedPath(), "", headers, signedHeaders, "UNSIGNED-PAYLOAD"}, "\n")
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	signature := hex.EncodeToString(hmacSHA256(sigV4Key(secretKey, amzDate[:8], region, "s3"), stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+	return req, nil
+}
+
+// sigV4Key derives the AWS Signature Version 4 signing key of a day, region and service
+func sigV4Key(secretKey, date, region, service string) []byte {
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return key
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath escapes an object key for a URL path: every byte but unreserved characters and
+// slashes is percent-encoded, as AWS signatures require
+func escapePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c == '/' || c == '-' || c == '.' || c == '_' || c == '~' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
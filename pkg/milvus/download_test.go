@@ -0,0 +1,150 @@
+package milvus
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearStorageEnv isolates a test from the storage credentials of the environment
+func clearStorageEnv(t *testing.T) {
+	for _, name := range []string{
+		envAWSAccessKeyID, envAWSSecretAccessKey, envAWSSessionToken, envAWSRegion, envAWSDefaultRegion,
+		envAWSEndpointURL, envAWSEndpointURLS3, envGCSToken, envGCSEmulatorHost,
+	} {
+		t.Setenv(name, "")
+	}
+	t.Setenv(EnvDatasetCache, t.TempDir())
+}
+
+func TestLocalDatasetPaths(t *testing.T) {
+	path, err := localDataset(nil, "data/sift_base.fvecs")
+	require.NoError(t, err)
+	assert.Equal(t, "data/sift_base.fvecs", path)
+}
+
+func TestLocalDatasetHTTP(t *testing.T) {
+	clearStorageEnv(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("vectors"))
+	}))
+	defer server.Close()
+
+	path, err := localDataset(&sync.Map{}, server.URL+"/datasets/base.fvecs")
+	require.NoError(t, err)
+	assert.Equal(t, "base.fvecs", filepath.Base(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "vectors", string(data))
+
+	// Later tests reuse the cached file
+	again, err := localDataset(&sync.Map{}, server.URL+"/datasets/base.fvecs")
+	require.NoError(t, err)
+	assert.Equal(t, path, again)
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestLocalDatasetErrors(t *testing.T) {
+	clearStorageEnv(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such dataset", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := localDataset(nil, server.URL+"/missing.fvecs")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "no such dataset")
+	entries, _ := os.ReadDir(os.Getenv(EnvDatasetCache))
+	assert.Empty(t, entries, "failed downloads are not cached")
+
+	_, err = localDataset(nil, "s3://bucket-only")
+	assert.Error(t, err)
+}
+
+func TestLocalDatasetS3(t *testing.T) {
+	clearStorageEnv(t)
+	var path, auth, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth, token = r.URL.EscapedPath(), r.Header.Get("Authorization"), r.Header.Get("X-Amz-Security-Token")
+		_, _ = w.Write([]byte("rows"))
+	}))
+	defer server.Close()
+	t.Setenv(envAWSEndpointURL, server.URL)
+	t.Setenv(envAWSAccessKeyID, "AKID")
+	t.Setenv(envAWSSecretAccessKey, "secret")
+	t.Setenv(envAWSSessionToken, "session")
+	t.Setenv(envAWSRegion, "eu-west-1")
+
+	local, err := localDataset(nil, "s3://datasets/sift/base 1.fvecs")
+	require.NoError(t, err)
+	assert.Equal(t, "base 1.fvecs", filepath.Base(local))
+	assert.Equal(t, "/datasets/sift/base%201.fvecs", path)
+	assert.Regexp(t, `^AWS4-HMAC-SHA256 Credential=AKID/\d{8}/eu-west-1/s3/aws4_request, `+
+		`SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=[0-9a-f]{64}$`, auth)
+	assert.Equal(t, "session", token)
+}
+
+func TestS3RequestHosts(t *testing.T) {
+	clearStorageEnv(t)
+	req, err := s3Request("datasets", "sift/base.fvecs", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "https://datasets.s3.us-east-1.amazonaws.com/sift/base.fvecs", req.URL.String())
+	assert.Empty(t, req.Header.Get("Authorization"), "unsigned without credentials")
+
+	t.Setenv(envAWSDefaultRegion, "ap-south-1")
+	req, err = s3Request("datasets", "sift/base.fvecs", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "datasets.s3.ap-south-1.amazonaws.com", req.URL.Host)
+}
+
+func TestSigV4Key(t *testing.T) {
+	// Example of the AWS Signature Version 4 documentation
+	key := sigV4Key("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
+
+func TestLocalDatasetGCS(t *testing.T) {
+	clearStorageEnv(t)
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("rows"))
+	}))
+	defer server.Close()
+	t.Setenv(envGCSEmulatorHost, strings.TrimPrefix(server.URL, "http://"))
+	t.Setenv(envGCSToken, "token")
+
+	_, err := localDataset(nil, "gs://datasets/glove/test.parquet")
+	require.NoError(t, err)
+	assert.Equal(t, "/datasets/glove/test.parquet", path)
+	assert.Equal(t, "Bearer token", auth)
+}
+
+func TestVectorStreamURL(t *testing.T) {
+	clearStorageEnv(t)
+	data, err := os.ReadFile(writeFvecs(t, [][]float32{{1, 2}, {3, 4}}))
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	stream, err := (&Milvus{}).VectorStream(server.URL + "/base.fvecs")
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+	assert.EqualValues(t, 2, stream.NumRows())
+	assert.Equal(t, 2, stream.Dimension())
+}
@@ -0,0 +1,329 @@
+package milvus
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// EnvEmbeddingAPIKey is the API key of embedding endpoints without apiKey (default: OPENAI_API_KEY)
+const EnvEmbeddingAPIKey = "MILVUS_EMBEDDING_API_KEY"
+
+// envOpenAIAPIKey is the API key fallback, named as in the OpenAI tools
+const envOpenAIAPIKey = "OPENAI_API_KEY"
+
+// Embedder defaults
+const (
+	defaultEmbeddingBatchSize = 100
+	defaultEmbeddingCacheSize = 10000
+	defaultEmbeddingTimeout   = 30 * time.Second
+)
+
+// EmbedderConfig configures milvus.embedder()
+type EmbedderConfig struct {
+	URL        string            `json:"url"`                  // Base URL of an OpenAI-compatible API, e.g. "https://api.openai.com/v1"
+	Model      string            `json:"model"`                // Embedding model, e.g. "text-embedding-3-small"
+	APIKey     string            `json:"apiKey,omitempty"`     // Bearer token (default: MILVUS_EMBEDDING_API_KEY or OPENAI_API_KEY)
+	Dimensions int               `json:"dimensions,omitempty"` // Output dimension, for models that can shorten embeddings
+	BatchSize  int               `json:"batchSize,omitempty"`  // Texts per request (default: 100)
+	CacheSize  int               `json:"cacheSize,omitempty"`  // Embeddings kept by text, least recently used dropped first (default: 10000)
+	Timeout    string            `json:"timeout,omitempty"`    // Per-request timeout, e.g. "30s"
+	Headers    map[string]string `json:"headers,omitempty"`    // Extra HTTP headers, e.g. for a gateway
+}
+
+// Embedder turns texts into vectors with an OpenAI-compatible embeddings endpoint, so RAG query
+// load can be generated from text. Texts are sent in batches, and embeddings are cached by text
+// and shared by all VUs, so repeated queries cost no request.
+//
+// Usage in k6:
+//
+//	const embedder = milvus.embedder({ url: 'https://api.openai.com/v1', model: 'text-embedding-3-small' });
+//	export default function () {
+//	    client.search(['how do I reset my password'], 10, { vectorField: 'embedding', embedder });
+//	}
+type Embedder struct {
+	*embedderState
+	vu modules.VU // Requests run in the context of the VU
+}
+
+// embedderState is the endpoint and cache of an embedder, shared by all VUs
+type embedderState struct {
+	config   EmbedderConfig
+	endpoint string
+	apiKey   string
+	client   *http.Client
+
+	mu       sync.Mutex
+	cache    map[string]*list.Element
+	recent   *list.List // Cached embeddings, most recently used first
+	requests int64
+	hits     int64
+	misses   int64
+}
+
+// embedding is a cached vector with its text
+type embedding struct {
+	text   string
+	vector []float32
+}
+
+// Embedder returns the embedder of an endpoint and model, created on the first call of the test.
+// Later calls with the same url, model and dimensions share it, and its cache, and ignore the
+// rest of config.
+func (m *Milvus) Embedder(configInput interface{}) (*Embedder, error) {
+	var config EmbedderConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid embedder config: %v", err)
+	}
+	key := strings.Join([]string{"embedder", config.URL, config.Model, strconv.Itoa(config.Dimensions)}, "\x00")
+	shared, err := sharedValue(m.shared, key, func() (*Embedder, error) {
+		return newEmbedder(config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Embedder{embedderState: shared.embedderState, vu: m.vu}, nil
+}
+
+func newEmbedder(config EmbedderConfig) (*Embedder, error) {
+	if config.URL == "" || config.Model == "" {
+		return nil, fmt.Errorf("invalid embedder config: url and model are required")
+	}
+	if config.Dimensions < 0 || config.BatchSize < 0 || config.CacheSize < 0 {
+		return nil, fmt.Errorf("invalid embedder config: dimensions, batchSize and cacheSize must not be negative")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultEmbeddingBatchSize
+	}
+	if config.CacheSize == 0 {
+		config.CacheSize = defaultEmbeddingCacheSize
+	}
+	timeout := defaultEmbeddingTimeout
+	if config.Timeout != "" {
+		d, err := time.ParseDuration(config.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid embedder config: invalid timeout %q", config.Timeout)
+		}
+		timeout = d
+	}
+	apiKey := config.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv(EnvEmbeddingAPIKey)
+	}
+	if apiKey == "" {
+		apiKey = os.Getenv(envOpenAIAPIKey)
+	}
+	// A base URL gets the embeddings path; a full endpoint URL is used as is
+	endpoint := strings.TrimSuffix(config.URL, "/")
+	if !strings.HasSuffix(endpoint, "/embeddings") {
+		endpoint += "/embeddings"
+	}
+	return &Embedder{embedderState: &embedderState{
+		config:   config,
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+		cache:    make(map[string]*list.Element),
+		recent:   list.New(),
+	}}, nil
+}
+
+// Embed returns the embedding of each text, in order: a string or an array of strings
+func (e *Embedder) Embed(input interface{}) ([][]float32, error) {
+	texts, err := embeddingTexts(input)
+	if err != nil {
+		return nil, err
+	}
+	return e.embed(vuContext(e.vu), texts)
+}
+
+// embed looks texts up in the cache and requests the others, each distinct text once
+func (e *embedderState) embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	missing := make(map[string][]int) // Text to its positions in texts
+	var order []string
+	e.mu.Lock()
+	for i, text := range texts {
+		if element, ok := e.cache[text]; ok {
+			e.recent.MoveToFront(element)
+			vectors[i] = element.Value.(*embedding).vector
+			e.hits++
+			continue
+		}
+		if _, ok := missing[text]; !ok {
+			order = append(order, text)
+		}
+		missing[text] = append(missing[text], i)
+		e.misses++
+	}
+	e.mu.Unlock()
+
+	for start := 0; start < len(order); start += e.config.BatchSize {
+		batch := order[start:min(start+e.config.BatchSize, len(order))]
+		embeddings, err := e.request(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		e.mu.Lock()
+		for i, text := range batch {
+			for _, position := range missing[text] {
+				vectors[position] = embeddings[i]
+			}
+			e.store(text, embeddings[i])
+		}
+		e.mu.Unlock()
+	}
+	return vectors, nil
+}
+
+// memorySize returns the bytes of the cached embeddings
+func (e *embedderState) memorySize() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var total int64
+	for element := e.recent.Front(); element != nil; element = element.Next() {
+		cached := element.Value.(*embedding)
+		total += int64(len(cached.text) + 4*len(cached.vector))
+	}
+	return total
+}
+
+// store caches an embedding, dropping the least recently used one when full
+func (e *embedderState) store(text string, vector []float32) {
+	if element, ok := e.cache[text]; ok {
+		e.recent.MoveToFront(element)
+		return
+	}
+	e.cache[text] = e.recent.PushFront(&embedding{text: text, vector: vector})
+	if e.recent.Len() > e.config.CacheSize {
+		oldest := e.recent.Back()
+		e.recent.Remove(oldest)
+		delete(e.cache, oldest.Value.(*embedding).text)
+	}
+}
+
+// embeddingRequest and embeddingResponse are the bodies of the OpenAI embeddings API
+type embeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// request embeds one batch of texts
+func (e *embedderState) request(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingRequest{Model: e.config.Model, Input: texts, Dimensions: e.config.Dimensions, EncodingFormat: "float"})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid embedder url: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+	for name, value := range e.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	e.mu.Lock()
+	e.requests++
+	e.mu.Unlock()
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("embedding request failed: %s %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	var result embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid embedding response: %v", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("invalid embedding response: %d embeddings for %d texts", len(result.Data), len(texts))
+	}
+	embeddings := make([][]float32, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(texts) || embeddings[item.Index] != nil || len(item.Embedding) == 0 {
+			return nil, fmt.Errorf("invalid embedding response: bad embedding at index %d", item.Index)
+		}
+		embeddings[item.Index] = item.Embedding
+	}
+	return embeddings, nil
+}
+
+// Stats returns the number of requests sent and of texts found in and missing from the cache
+func (e *embedderState) Stats() map[string]int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return map[string]int64{"requests": e.requests, "cacheHits": e.hits, "cacheMisses": e.misses}
+}
+
+// embeddingTexts reads a string or an array of strings
+func embeddingTexts(input interface{}) ([]string, error) {
+	switch v := input.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		if len(v) > 0 {
+			return v, nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			break
+		}
+		texts := make([]string, len(v))
+		for i, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("texts[%d] must be a string, got %T", i, item)
+			}
+			texts[i] = text
+		}
+		return texts, nil
+	default:
+		return nil, fmt.Errorf("texts must be a string or an array of strings, got %T", input)
+	}
+	return nil, fmt.Errorf("texts must not be empty")
+}
+
+// embedderOption embeds the text queries of a search with the "embedder" search param, and
+// returns other query vectors unchanged
+func embedderOption(ctx context.Context, params map[string]interface{}, vectors interface{}) (interface{}, error) {
+	value, ok := params["embedder"]
+	if !ok || value == nil {
+		return vectors, nil
+	}
+	embedder, ok := value.(*Embedder)
+	if !ok {
+		return nil, fmt.Errorf("embedder must be a milvus.embedder() object, got %T", value)
+	}
+	texts, err := embeddingTexts(vectors)
+	if err != nil {
+		return nil, err
+	}
+	return embedder.embed(ctx, texts)
+}
@@ -0,0 +1,169 @@
+package milvus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// embeddingServer answers OpenAI embedding requests with [len(text), index], in reverse order to
+// check that embeddings are matched by index
+func embeddingServer(t *testing.T, requests *[]embeddingRequest, headers *http.Header) *httptest.Server {
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		var req embeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		*requests = append(*requests, req)
+		if headers != nil {
+			*headers = r.Header.Clone()
+		}
+		mu.Unlock()
+		var resp embeddingResponse
+		resp.Data = make([]struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}, len(req.Input))
+		for i := range req.Input {
+			j := len(req.Input) - 1 - i
+			resp.Data[i].Index = j
+			resp.Data[i].Embedding = []float32{float32(len(req.Input[j])), float32(j)}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEmbedderBatchesAndCaches(t *testing.T) {
+	var requests []embeddingRequest
+	var headers http.Header
+	server := embeddingServer(t, &requests, &headers)
+	embedder, err := newEmbedder(EmbedderConfig{
+		URL: server.URL + "/v1/", Model: "small", APIKey: "key", Dimensions: 2, BatchSize: 2,
+		Headers: map[string]string{"X-Gateway": "bench"},
+	})
+	require.NoError(t, err)
+
+	vectors, err := embedder.Embed([]interface{}{"a", "bbb", "a", "cc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {3, 1}, {1, 0}, {2, 0}}, vectors)
+	require.Len(t, requests, 2, "three distinct texts in batches of two")
+	assert.Equal(t, embeddingRequest{Model: "small", Input: []string{"a", "bbb"}, Dimensions: 2, EncodingFormat: "float"}, requests[0])
+	assert.Equal(t, []string{"cc"}, requests[1].Input)
+	assert.Equal(t, "Bearer key", headers.Get("Authorization"))
+	assert.Equal(t, "bench", headers.Get("X-Gateway"))
+
+	vectors, err = embedder.Embed("bbb")
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{3, 1}}, vectors)
+	assert.Len(t, requests, 2, "cached texts cost no request")
+	assert.Equal(t, map[string]int64{"requests": 2, "cacheHits": 1, "cacheMisses": 4}, embedder.Stats())
+}
+
+func TestEmbedderCacheEviction(t *testing.T) {
+	var requests []embeddingRequest
+	server := embeddingServer(t, &requests, nil)
+	embedder, err := newEmbedder(EmbedderConfig{URL: server.URL + "/v1/embeddings", Model: "small", CacheSize: 2})
+	require.NoError(t, err)
+
+	for _, text := range []string{"a", "b", "a", "c", "a", "b"} {
+		_, err := embedder.Embed(text)
+		require.NoError(t, err)
+	}
+	// "b" was the least recently used when "c" was cached, so only it was requested again
+	assert.Len(t, requests, 4)
+	assert.Equal(t, 2, embedder.recent.Len())
+}
+
+func TestEmbedderShared(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	config := map[string]interface{}{"url": "http://localhost:1/v1", "model": "small"}
+	first, err := m.Embedder(config)
+	require.NoError(t, err)
+	second, err := m.Embedder(config)
+	require.NoError(t, err)
+	assert.Same(t, first.embedderState, second.embedderState)
+	other, err := m.Embedder(map[string]interface{}{"url": "http://localhost:1/v1", "model": "large"})
+	require.NoError(t, err)
+	assert.NotSame(t, first.embedderState, other.embedderState)
+}
+
+// canceledVU is a VU whose iteration has ended
+type canceledVU struct {
+	metricsVU
+}
+
+func (v *canceledVU) Context() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestEmbedderVUContext(t *testing.T) {
+	var requests []embeddingRequest
+	server := embeddingServer(t, &requests, nil)
+	m := &Milvus{vu: &canceledVU{}, shared: &sync.Map{}}
+	embedder, err := m.Embedder(map[string]interface{}{"url": server.URL + "/v1", "model": "small"})
+	require.NoError(t, err)
+
+	_, err = embedder.Embed("a")
+	assert.ErrorContains(t, err, "context canceled")
+	assert.Empty(t, requests)
+}
+
+func TestEmbedderErrors(t *testing.T) {
+	for name, config := range map[string]EmbedderConfig{
+		"url":       {Model: "small"},
+		"model":     {URL: "http://localhost"},
+		"batchSize": {URL: "http://localhost", Model: "small", BatchSize: -1},
+		"timeout":   {URL: "http://localhost", Model: "small", Timeout: "soon"},
+	} {
+		_, err := newEmbedder(config)
+		assert.Error(t, err, name)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "rate limited"}`, http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	embedder, err := newEmbedder(EmbedderConfig{URL: server.URL, Model: "small"})
+	require.NoError(t, err)
+	_, err = embedder.Embed("a")
+	assert.ErrorContains(t, err, "429")
+	assert.ErrorContains(t, err, "rate limited")
+
+	_, err = embedder.Embed([]interface{}{"a", 1})
+	assert.ErrorContains(t, err, "texts[1] must be a string")
+	_, err = embedder.Embed([]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestEmbedderOption(t *testing.T) {
+	var requests []embeddingRequest
+	server := embeddingServer(t, &requests, nil)
+	embedder, err := newEmbedder(EmbedderConfig{URL: server.URL + "/v1", Model: "small"})
+	require.NoError(t, err)
+
+	vectors, err := embedderOption(context.Background(), map[string]interface{}{"embedder": embedder}, []interface{}{"query"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{5, 0}}, vectors)
+
+	dense := [][]float32{{1, 2}}
+	unchanged, err := embedderOption(context.Background(), map[string]interface{}{}, dense)
+	require.NoError(t, err)
+	assert.Equal(t, dense, unchanged)
+
+	_, err = embedderOption(context.Background(), map[string]interface{}{"embedder": "small"}, []interface{}{"query"})
+	assert.Error(t, err)
+	_, err = embedderOption(context.Background(), map[string]interface{}{"embedder": embedder}, dense)
+	assert.Error(t, err)
+	assert.NotContains(t, searchParamMap(map[string]interface{}{"embedder": embedder}), "embedder")
+}
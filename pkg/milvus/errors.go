@@ -8,6 +8,10 @@ import (
 // Error types for better error handling
 var (
 	ErrCollectionNameRequired = errors.New("collection name required")
+	ErrAddressRequired        = errors.New("address required")
+	ErrDatabaseNameRequired   = errors.New("database name required")
+	ErrCloudTokenRequired     = errors.New("cloud mode requires a token (API key or user:password)")
+	ErrUnsupportedInCloud     = errors.New("not supported in cloud mode")
 	ErrEmptyData              = errors.New("no valid columns provided")
 	ErrEmptyVectorArray       = errors.New("empty vector array")
 	ErrNoSearchRequests       = errors.New("at least one search request required")
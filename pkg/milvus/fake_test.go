@@ -0,0 +1,74 @@
+package milvus
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+	"google.golang.org/grpc"
+)
+
+// metricsVU is a modules.VU with a configurable init environment and state
+type metricsVU struct {
+	initEnv *common.InitEnvironment
+	state   *lib.State
+}
+
+func (v *metricsVU) Context() context.Context             { return context.Background() }
+func (v *metricsVU) Events() common.Events                { return common.Events{} }
+func (v *metricsVU) InitEnv() *common.InitEnvironment     { return v.initEnv }
+func (v *metricsVU) State() *lib.State                    { return v.state }
+func (v *metricsVU) Runtime() *sobek.Runtime              { return nil }
+func (v *metricsVU) RegisterCallback() func(func() error) { return func(func() error) {} }
+
+// newMetricsVU returns a VU with a metrics registry and a state whose samples are sent to the
+// returned channel, buffered so that no test blocks on it
+func newMetricsVU(t *testing.T) (*metricsVU, chan metrics.SampleContainer) {
+	t.Helper()
+	registry := metrics.NewRegistry()
+	samples := make(chan metrics.SampleContainer, 100000)
+	vu := &metricsVU{initEnv: &common.InitEnvironment{TestPreInitState: &lib.TestPreInitState{Registry: registry}}}
+	vu.state = &lib.State{Samples: samples, Tags: lib.NewVUStateTags(registry.RootTagSet())}
+	return vu, samples
+}
+
+// newFakeMilvus serves service on a local port until the test ends and returns its address. A
+// nil service serves nothing, so every RPC fails with Unimplemented.
+func newFakeMilvus(t *testing.T, service milvuspb.MilvusServiceServer) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	if service != nil {
+		milvuspb.RegisterMilvusServiceServer(server, service)
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// fakeClient connects a client of m to a fake Milvus serving service, closed when the test ends
+func fakeClient(t *testing.T, m *Milvus, service milvuspb.MilvusServiceServer, options ...ClientOption) *Client {
+	t.Helper()
+	config := DefaultClientConfig()
+	config.ApplyOptions(WithAddress(newFakeMilvus(t, service)), WithDialTimeout(time.Second))
+	config.ApplyOptions(options...)
+	client, err := m.newClient(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// benchClient connects a client of the collection bench, emitting its metrics on vu, to a fake
+// Milvus serving service
+func benchClient(t *testing.T, service milvuspb.MilvusServiceServer, vu *metricsVU) *Client {
+	t.Helper()
+	return fakeClient(t, &Milvus{vu: vu, metrics: registerMetrics(vu)}, service, WithCollection("bench"))
+}
@@ -0,0 +1,464 @@
+package milvus
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// Data faker defaults
+const (
+	defaultFakerIntMax    = 100
+	defaultFakerMaxLength = 16
+	defaultFakerTimeStart = "2020-01-01T00:00:00Z"
+	defaultFakerTimeEnd   = "2025-01-01T00:00:00Z"
+	fakerAlphabet         = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// fakerIntRanges are the value ranges of the integer field types
+var fakerIntRanges = map[string][2]float64{
+	"int8":  {math.MinInt8, math.MaxInt8},
+	"int16": {math.MinInt16, math.MaxInt16},
+	"int32": {math.MinInt32, math.MaxInt32},
+	"int64": {math.MinInt64, math.MaxInt64},
+}
+
+// DataFakerConfig configures milvus.dataFaker()
+type DataFakerConfig struct {
+	Seed         int64                       `json:"seed,omitempty"`         // Seed of the values, together with the VU ID
+	PerIteration bool                        `json:"perIteration,omitempty"` // Restart the value stream at every iteration
+	Fields       map[string]FakerFieldConfig `json:"fields"`                 // Field name to value generator
+}
+
+// FakerFieldConfig describes the values of one scalar field
+type FakerFieldConfig struct {
+	Type         string                      `json:"type"`                   // int8, int16, int32, int64, float, double, bool, varchar, timestamp or json
+	Min          *float64                    `json:"min,omitempty"`          // Numbers: lowest value (default: 0)
+	Max          *float64                    `json:"max,omitempty"`          // Numbers: highest value (default: 100 for integers, 1 for floats)
+	MinLength    int                         `json:"minLength,omitempty"`    // varchar: shortest string (default: maxLength)
+	MaxLength    int                         `json:"maxLength,omitempty"`    // varchar: longest string (default: 16)
+	Values       []interface{}               `json:"values,omitempty"`       // Categorical values to pick from, of the field type
+	Weights      []float64                   `json:"weights,omitempty"`      // Relative frequency of each value (default: uniform)
+	Probability  *float64                    `json:"probability,omitempty"`  // bool: probability of true (default: 0.5)
+	Start        string                      `json:"start,omitempty"`        // timestamp: earliest time, RFC 3339 (default: 2020-01-01)
+	End          string                      `json:"end,omitempty"`          // timestamp: latest time, RFC 3339 (default: 2025-01-01)
+	Unit         string                      `json:"unit,omitempty"`         // timestamp: "ms" (default) or "s" for int64 epochs, "rfc3339" for strings
+	Distribution string                      `json:"distribution,omitempty"` // "uniform" (default) or "zipf", for values and integer ranges
+	Skew         *float64                    `json:"skew,omitempty"`         // zipf: exponent, higher is more skewed (default: 1)
+	Fields       map[string]FakerFieldConfig `json:"fields,omitempty"`       // json: keys of each object (default: score, tag and count)
+}
+
+// DataFaker generates scalar field data with known distributions, so filtered searches of a
+// chosen selectivity can be run on synthetic data. Each field draws uniformly from a range or
+// from weighted categorical values: with values ['a', 'b'] and weights [1, 9], the filter
+// category == "a" matches 10% of the rows.
+//
+// Usage in k6:
+//
+//	const faker = milvus.dataFaker({ seed: 1, fields: {
+//	    category: { type: 'varchar', values: ['books', 'games', 'music'], weights: [1, 2, 7] },
+//	    price: { type: 'double', min: 1, max: 500 },
+//	}});
+//	export default function () {
+//	    client.insert({ ...faker.next(1000), embedding: gen.next(1000) });
+//	}
+type DataFaker struct {
+	fields []*fakerField
+	stream seedStream
+}
+
+// fakerField generates the values of one field
+type fakerField struct {
+	name       string
+	kind       string
+	min, max   float64
+	minLength  int
+	maxLength  int
+	values     []interface{} // Categorical values converted to the field type
+	cumulative []float64     // Cumulative weights of values
+	start, end time.Time
+	unit       string
+	zipf       *zipfTable    // Zipfian ranks of values, or of the integer range from min
+	fields     []*fakerField // json keys
+}
+
+// DataFaker creates a scalar data generator for the configured fields
+func (m *Milvus) DataFaker(configInput interface{}) (*DataFaker, error) {
+	var config DataFakerConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid data faker config: %v", err)
+	}
+	return newDataFaker(m.vu, m.shared, config)
+}
+
+func newDataFaker(vu modules.VU, shared *sync.Map, config DataFakerConfig) (*DataFaker, error) {
+	if len(config.Fields) == 0 {
+		return nil, fmt.Errorf("data faker requires at least one field")
+	}
+	fields, err := newFakerFields(config.Fields, shared)
+	if err != nil {
+		return nil, err
+	}
+	return &DataFaker{fields: fields, stream: newSeedStream(vu, config.Seed, config.PerIteration)}, nil
+}
+
+// newFakerFields validates field configs, sorted by name so values are drawn in a stable order
+func newFakerFields(configs map[string]FakerFieldConfig, shared *sync.Map) ([]*fakerField, error) {
+	fields := make([]*fakerField, 0, len(configs))
+	for name, config := range configs {
+		field, err := newFakerField(name, config, shared)
+		if err != nil {
+			return nil, fmt.Errorf("data faker field %q: %v", name, err)
+		}
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+	return fields, nil
+}
+
+func newFakerField(name string, config FakerFieldConfig, shared *sync.Map) (*fakerField, error) {
+	f := &fakerField{name: name, kind: strings.ToLower(config.Type)}
+	switch f.kind {
+	case "int8", "int16", "int32", "int64":
+		f.min, f.max = optionalFloat(config.Min, 0), optionalFloat(config.Max, defaultFakerIntMax)
+		bounds := fakerIntRanges[f.kind]
+		if f.min != math.Trunc(f.min) || f.max != math.Trunc(f.max) || f.min < bounds[0] || f.max > bounds[1] {
+			return nil, fmt.Errorf("min and max must be %s integers", f.kind)
+		}
+	case "float", "double":
+		f.min, f.max = optionalFloat(config.Min, 0), optionalFloat(config.Max, 1)
+	case "bool":
+		f.max = optionalFloat(config.Probability, 0.5)
+		if f.max < 0 || f.max > 1 {
+			return nil, fmt.Errorf("probability must be between 0 and 1, got %v", f.max)
+		}
+	case "varchar", "string":
+		f.kind = "varchar"
+		f.maxLength = config.MaxLength
+		if f.maxLength == 0 {
+			f.maxLength = defaultFakerMaxLength
+		}
+		f.minLength = config.MinLength
+		if f.minLength == 0 {
+			f.minLength = f.maxLength
+		}
+		if f.minLength < 0 || f.minLength > f.maxLength {
+			return nil, fmt.Errorf("minLength must be between 0 and maxLength %d", f.maxLength)
+		}
+	case "timestamp":
+		var err error
+		if f.start, err = time.Parse(time.RFC3339, stringOr(config.Start, defaultFakerTimeStart)); err != nil {
+			return nil, fmt.Errorf("invalid start: %v", err)
+		}
+		if f.end, err = time.Parse(time.RFC3339, stringOr(config.End, defaultFakerTimeEnd)); err != nil {
+			return nil, fmt.Errorf("invalid end: %v", err)
+		}
+		f.unit = stringOr(config.Unit, "ms")
+		if f.unit != "ms" && f.unit != "s" && f.unit != "rfc3339" {
+			return nil, fmt.Errorf("unit must be ms, s or rfc3339, got %q", f.unit)
+		}
+	case "json":
+		nested := config.Fields
+		if len(nested) == 0 {
+			nested = map[string]FakerFieldConfig{
+				"score": {Type: "double"},
+				"tag":   {Type: "varchar", MaxLength: 8},
+				"count": {Type: "int64"},
+			}
+		}
+		var err error
+		if f.fields, err = newFakerFields(nested, shared); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %q, expected int8, int16, int32, int64, float, double, bool, varchar, timestamp or json", config.Type)
+	}
+	if f.max < f.min {
+		return nil, fmt.Errorf("min must not be greater than max")
+	}
+	if f.end.Before(f.start) {
+		return nil, fmt.Errorf("start must not be after end")
+	}
+
+	if len(config.Values) > 0 {
+		if f.kind == "json" {
+			return nil, fmt.Errorf("values are not supported for json fields")
+		}
+		if err := f.setValues(config.Values, config.Weights); err != nil {
+			return nil, err
+		}
+	} else if len(config.Weights) > 0 {
+		return nil, fmt.Errorf("weights require values")
+	}
+	if err := f.setDistribution(config, shared); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// setDistribution applies a zipf distribution to the categorical values or the integer range,
+// so the first value, or min, is the most frequent
+func (f *fakerField) setDistribution(config FakerFieldConfig, shared *sync.Map) error {
+	switch strings.ToLower(config.Distribution) {
+	case "", "uniform":
+		if config.Skew != nil {
+			return fmt.Errorf("skew requires the zipf distribution")
+		}
+		return nil
+	case "zipf":
+	default:
+		return fmt.Errorf("distribution must be uniform or zipf, got %q", config.Distribution)
+	}
+
+	_, isInt := fakerIntRanges[f.kind]
+	var n float64
+	switch {
+	case len(config.Weights) > 0:
+		return fmt.Errorf("weights and the zipf distribution are exclusive")
+	case f.values != nil:
+		n = float64(len(f.values))
+	case isInt:
+		n = f.max - f.min + 1
+	default:
+		return fmt.Errorf("the zipf distribution requires values or an integer type")
+	}
+	if n > maxZipfValues {
+		return fmt.Errorf("the zipf distribution supports up to %d values, got %.0f", maxZipfValues, n)
+	}
+	var err error
+	f.zipf, err = zipfTableFor(shared, int(n), optionalFloat(config.Skew, defaultZipfSkew))
+	return err
+}
+
+// setValues converts categorical values to the field type and accumulates their weights
+func (f *fakerField) setValues(values []interface{}, weights []float64) error {
+	if len(weights) > 0 && len(weights) != len(values) {
+		return fmt.Errorf("%d weights for %d values", len(weights), len(values))
+	}
+	f.values = make([]interface{}, len(values))
+	f.cumulative = make([]float64, len(values))
+	total := 0.0
+	for i, value := range values {
+		converted, err := f.convert(value)
+		if err != nil {
+			return fmt.Errorf("values[%d]: %v", i, err)
+		}
+		f.values[i] = converted
+		weight := 1.0
+		if len(weights) > 0 {
+			weight = weights[i]
+		}
+		if weight < 0 {
+			return fmt.Errorf("weights must not be negative")
+		}
+		total += weight
+		f.cumulative[i] = total
+	}
+	if total <= 0 {
+		return fmt.Errorf("weights must not all be zero")
+	}
+	return nil
+}
+
+// convert returns a categorical value as the Go type of the field
+func (f *fakerField) convert(value interface{}) (interface{}, error) {
+	switch f.kind {
+	case "bool":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case "varchar":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case "timestamp":
+		if s, ok := value.(string); ok {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, err
+			}
+			return t, nil
+		}
+	default:
+		if number, ok := value.(float64); ok {
+			if f.kind == "float" || f.kind == "double" {
+				return number, nil
+			}
+			bounds := fakerIntRanges[f.kind]
+			if number == math.Trunc(number) && number >= bounds[0] && number <= bounds[1] {
+				return number, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%v is not a %s value", value, f.kind)
+}
+
+// Next returns count rows as a map of field name to column values, ready for client.insert().
+// Each VU gets its own value stream, derived from the seed and the VU ID.
+func (d *DataFaker) Next(count int) (map[string]interface{}, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", count)
+	}
+	rng := d.stream.rand()
+	batch := make(map[string]interface{}, len(d.fields))
+	for _, field := range d.fields {
+		batch[field.name] = field.column(rng, count)
+	}
+	return batch, nil
+}
+
+// Replay restarts the value stream of a VU, or of one of its iterations with perIteration, so
+// that later next() calls return the rows it generated
+func (d *DataFaker) Replay(vuID int64, iteration ...int64) error {
+	return d.stream.replay(vuID, iteration)
+}
+
+// Fields returns the field names of each batch
+func (d *DataFaker) Fields() []string {
+	names := make([]string, len(d.fields))
+	for i, field := range d.fields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// column draws count values into a slice of the field type
+func (f *fakerField) column(rng *rand.Rand, count int) interface{} {
+	switch f.kind {
+	case "int8":
+		return fakerSlice(count, func() int8 { return int8(f.number(rng)) })
+	case "int16":
+		return fakerSlice(count, func() int16 { return int16(f.number(rng)) })
+	case "int32":
+		return fakerSlice(count, func() int32 { return int32(f.number(rng)) })
+	case "int64":
+		return fakerSlice(count, func() int64 { return int64(f.number(rng)) })
+	case "float":
+		return fakerSlice(count, func() float32 { return float32(f.number(rng)) })
+	case "double":
+		return fakerSlice(count, func() float64 { return f.number(rng) })
+	case "bool":
+		return fakerSlice(count, func() bool { return f.boolean(rng) })
+	case "varchar":
+		return fakerSlice(count, func() string { return f.text(rng) })
+	case "timestamp":
+		if f.unit == "rfc3339" {
+			return fakerSlice(count, func() string { return f.time(rng).Format(time.RFC3339) })
+		}
+		return fakerSlice(count, func() int64 { return f.epoch(rng) })
+	default:
+		return fakerSlice(count, func() map[string]interface{} { return f.object(rng) })
+	}
+}
+
+// value draws one value, for json keys
+func (f *fakerField) value(rng *rand.Rand) interface{} {
+	switch f.kind {
+	case "int8", "int16", "int32", "int64":
+		return int64(f.number(rng))
+	case "float", "double":
+		return f.number(rng)
+	case "bool":
+		return f.boolean(rng)
+	case "varchar":
+		return f.text(rng)
+	case "timestamp":
+		if f.unit == "rfc3339" {
+			return f.time(rng).Format(time.RFC3339)
+		}
+		return f.epoch(rng)
+	default:
+		return f.object(rng)
+	}
+}
+
+// pick returns a categorical value by weight
+func (f *fakerField) pick(rng *rand.Rand) interface{} {
+	if f.zipf != nil {
+		return f.values[f.zipf.sample(rng)]
+	}
+	return f.values[weightedIndex(f.cumulative, rng)]
+}
+
+func (f *fakerField) number(rng *rand.Rand) float64 {
+	if f.values != nil {
+		return f.pick(rng).(float64)
+	}
+	if f.kind == "float" || f.kind == "double" {
+		return f.min + rng.Float64()*(f.max-f.min)
+	}
+	if f.zipf != nil {
+		return f.min + float64(f.zipf.sample(rng))
+	}
+	// Integers are drawn from [min, max] inclusive
+	return f.min + math.Floor(rng.Float64()*(f.max-f.min+1))
+}
+
+func (f *fakerField) boolean(rng *rand.Rand) bool {
+	if f.values != nil {
+		return f.pick(rng).(bool)
+	}
+	return rng.Float64() < f.max
+}
+
+func (f *fakerField) text(rng *rand.Rand) string {
+	if f.values != nil {
+		return f.pick(rng).(string)
+	}
+	b := make([]byte, f.minLength+rng.Intn(f.maxLength-f.minLength+1))
+	for i := range b {
+		b[i] = fakerAlphabet[rng.Intn(len(fakerAlphabet))]
+	}
+	return string(b)
+}
+
+func (f *fakerField) time(rng *rand.Rand) time.Time {
+	if f.values != nil {
+		return f.pick(rng).(time.Time)
+	}
+	return f.start.Add(time.Duration(rng.Int63n(int64(f.end.Sub(f.start)) + 1)))
+}
+
+func (f *fakerField) epoch(rng *rand.Rand) int64 {
+	t := f.time(rng)
+	if f.unit == "s" {
+		return t.Unix()
+	}
+	return t.UnixMilli()
+}
+
+func (f *fakerField) object(rng *rand.Rand) map[string]interface{} {
+	object := make(map[string]interface{}, len(f.fields))
+	for _, field := range f.fields {
+		object[field.name] = field.value(rng)
+	}
+	return object
+}
+
+func fakerSlice[T any](count int, draw func() T) []T {
+	out := make([]T, count)
+	for i := range out {
+		out[i] = draw()
+	}
+	return out
+}
+
+func optionalFloat(value *float64, fallback float64) float64 {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+func stringOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,146 @@
+package milvus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+)
+
+func TestDataFaker(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{state: &lib.State{VUID: 1}}}
+	faker, err := m.DataFaker(map[string]interface{}{
+		"seed": 3,
+		"fields": map[string]interface{}{
+			"category": map[string]interface{}{"type": "varchar", "values": []interface{}{"a", "b", "c"}, "weights": []interface{}{1, 0, 9}},
+			"stock":    map[string]interface{}{"type": "int16", "min": -5, "max": 5},
+			"price":    map[string]interface{}{"type": "double", "min": 10, "max": 20},
+			"sku":      map[string]interface{}{"type": "varchar", "minLength": 2, "maxLength": 4},
+			"active":   map[string]interface{}{"type": "bool", "probability": 0.25},
+			"created":  map[string]interface{}{"type": "timestamp", "start": "2024-01-01T00:00:00Z", "end": "2024-01-02T00:00:00Z", "unit": "s"},
+			"meta":     map[string]interface{}{"type": "json", "fields": map[string]interface{}{"rank": map[string]interface{}{"type": "int8", "values": []interface{}{1, 2}}}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"active", "category", "created", "meta", "price", "sku", "stock"}, faker.Fields())
+
+	const n = 10000
+	batch, err := faker.Next(n)
+	require.NoError(t, err)
+
+	// Categorical values follow their weights, which sets the selectivity of a filter on them
+	counts := map[string]int{}
+	for _, value := range batch["category"].([]string) {
+		counts[value]++
+	}
+	assert.Zero(t, counts["b"])
+	assert.InDelta(t, 0.1, float64(counts["a"])/n, 0.02)
+
+	trues := 0
+	for _, value := range batch["active"].([]bool) {
+		if value {
+			trues++
+		}
+	}
+	assert.InDelta(t, 0.25, float64(trues)/n, 0.02)
+
+	seen := map[int16]bool{}
+	for _, value := range batch["stock"].([]int16) {
+		require.GreaterOrEqual(t, value, int16(-5))
+		require.LessOrEqual(t, value, int16(5))
+		seen[value] = true
+	}
+	assert.Len(t, seen, 11) // Both bounds are included
+	for _, value := range batch["price"].([]float64) {
+		require.True(t, value >= 10 && value < 20, value)
+	}
+	for _, value := range batch["sku"].([]string) {
+		require.True(t, len(value) >= 2 && len(value) <= 4, value)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for _, value := range batch["created"].([]int64) {
+		require.True(t, value >= start && value <= start+86400, value)
+	}
+	meta := batch["meta"].([]map[string]interface{})
+	assert.Contains(t, []interface{}{int64(1), int64(2)}, meta[0]["rank"])
+
+	// Batches convert to typed columns for insert
+	columns, err := (&Client{}).convertDataToColumns(batch)
+	require.NoError(t, err)
+	types := map[string]entity.FieldType{}
+	for _, col := range columns {
+		types[col.Name()] = col.Type()
+	}
+	assert.Equal(t, entity.FieldTypeInt16, types["stock"])
+	assert.Equal(t, entity.FieldTypeJSON, types["meta"])
+
+	// The stream depends on the seed and the VU
+	same, err := newDataFaker(&metricsVU{state: &lib.State{VUID: 1}}, nil, DataFakerConfig{Seed: 3, Fields: map[string]FakerFieldConfig{"sku": {Type: "varchar"}}})
+	require.NoError(t, err)
+	again, err := newDataFaker(&metricsVU{state: &lib.State{VUID: 1}}, nil, DataFakerConfig{Seed: 3, Fields: map[string]FakerFieldConfig{"sku": {Type: "varchar"}}})
+	require.NoError(t, err)
+	other, err := newDataFaker(&metricsVU{state: &lib.State{VUID: 2}}, nil, DataFakerConfig{Seed: 3, Fields: map[string]FakerFieldConfig{"sku": {Type: "varchar"}}})
+	require.NoError(t, err)
+	first, _ := same.Next(5)
+	second, _ := again.Next(5)
+	third, _ := other.Next(5)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, third)
+	assert.Len(t, first["sku"].([]string)[0], defaultFakerMaxLength)
+}
+
+func TestDataFakerDefaults(t *testing.T) {
+	faker, err := newDataFaker(nil, nil, DataFakerConfig{Fields: map[string]FakerFieldConfig{
+		"count":   {Type: "int64"},
+		"at":      {Type: "timestamp", Unit: "rfc3339"},
+		"payload": {Type: "json"},
+	}})
+	require.NoError(t, err)
+	batch, err := faker.Next(100)
+	require.NoError(t, err)
+	for _, value := range batch["count"].([]int64) {
+		require.True(t, value >= 0 && value <= defaultFakerIntMax, value)
+	}
+	at, err := time.Parse(time.RFC3339, batch["at"].([]string)[0])
+	require.NoError(t, err)
+	assert.True(t, at.Year() >= 2020 && at.Year() <= 2025, at) // Within the default range
+	assert.ElementsMatch(t, []string{"score", "tag", "count"}, keys(batch["payload"].([]map[string]interface{})[0]))
+}
+
+func keys(m map[string]interface{}) []string {
+	out := make([]string, 0, len(m))
+	for key := range m {
+		out = append(out, key)
+	}
+	return out
+}
+
+func TestDataFakerInvalidConfig(t *testing.T) {
+	m := &Milvus{}
+	for _, fields := range []map[string]interface{}{
+		{},
+		{"x": map[string]interface{}{"type": "vector"}},
+		{"x": map[string]interface{}{"type": "int8", "max": 300}},
+		{"x": map[string]interface{}{"type": "int64", "min": 0.5}},
+		{"x": map[string]interface{}{"type": "double", "min": 2, "max": 1}},
+		{"x": map[string]interface{}{"type": "bool", "probability": 2}},
+		{"x": map[string]interface{}{"type": "varchar", "minLength": 10, "maxLength": 5}},
+		{"x": map[string]interface{}{"type": "varchar", "values": []interface{}{1}}},
+		{"x": map[string]interface{}{"type": "varchar", "values": []interface{}{"a"}, "weights": []interface{}{1, 2}}},
+		{"x": map[string]interface{}{"type": "varchar", "values": []interface{}{"a"}, "weights": []interface{}{0}}},
+		{"x": map[string]interface{}{"type": "varchar", "weights": []interface{}{1}}},
+		{"x": map[string]interface{}{"type": "timestamp", "start": "yesterday"}},
+		{"x": map[string]interface{}{"type": "timestamp", "unit": "ns"}},
+		{"x": map[string]interface{}{"type": "json", "fields": map[string]interface{}{"y": map[string]interface{}{"type": "nope"}}}},
+	} {
+		_, err := m.DataFaker(map[string]interface{}{"fields": fields})
+		assert.Error(t, err, fields)
+	}
+	faker, err := m.DataFaker(map[string]interface{}{"fields": map[string]interface{}{"x": map[string]interface{}{"type": "bool"}}})
+	require.NoError(t, err)
+	_, err = faker.Next(-1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package milvus
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// Vector generator defaults
+const (
+	defaultGeneratorClusters = 10
+	defaultGeneratorStddev   = 0.1
+)
+
+// VectorGeneratorConfig configures milvus.vectorGenerator()
+type VectorGeneratorConfig struct {
+	Dim       int     `json:"dim"`
+	Clusters  int     `json:"clusters,omitempty"`  // Number of Gaussian clusters (default: 10)
+	Stddev    float64 `json:"stddev,omitempty"`    // Per-dimension standard deviation around a centroid (default: 0.1)
+	Seed      int64   `json:"seed,omitempty"`      // Seed of the centroids, and of the samples together with the VU ID
+	Normalize bool    `json:"normalize,omitempty"` // Scale vectors to unit length, for COSINE and IP
+	// Restart the sample stream at every iteration, from the seed, VU ID and iteration
+	PerIteration bool `json:"perIteration,omitempty"`
+}
+
+// VectorGenerator draws dense vectors from k Gaussian clusters, so that recall and IVF
+// partitioning behave as with real embeddings rather than uniform noise.
+//
+// Usage in k6:
+//
+//	const gen = milvus.vectorGenerator({ dim: 128, clusters: 50, stddev: 0.05, seed: 42 });
+//	export default function () {
+//	    client.insert({ id: ids, embedding: gen.next(1000) });
+//	    client.search(gen.next(10), 10, { vectorField: 'embedding' });
+//	}
+type VectorGenerator struct {
+	config    VectorGeneratorConfig
+	centroids [][]float32
+	stream    seedStream
+}
+
+// VectorGenerator creates a clustered vector generator. Centroids depend only on the seed, so
+// every VU, and every run with the same seed, draws from the same clusters.
+func (m *Milvus) VectorGenerator(configInput interface{}) (*VectorGenerator, error) {
+	var config VectorGeneratorConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid vector generator config: %v", err)
+	}
+	return newVectorGenerator(m.vu, config)
+}
+
+func newVectorGenerator(vu modules.VU, config VectorGeneratorConfig) (*VectorGenerator, error) {
+	if config.Dim <= 0 {
+		return nil, fmt.Errorf("vector generator dim must be positive, got %d", config.Dim)
+	}
+	if config.Clusters < 0 || config.Stddev < 0 {
+		return nil, fmt.Errorf("vector generator clusters and stddev must not be negative")
+	}
+	if config.Clusters == 0 {
+		config.Clusters = defaultGeneratorClusters
+	}
+	if config.Stddev == 0 {
+		config.Stddev = defaultGeneratorStddev
+	}
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	centroids := make([][]float32, config.Clusters)
+	for i := range centroids {
+		centroid := make([]float32, config.Dim)
+		for j := range centroid {
+			centroid[j] = float32(rng.Float64()*2 - 1)
+		}
+		centroids[i] = centroid
+	}
+	return &VectorGenerator{
+		config:    config,
+		centroids: centroids,
+		stream:    newSeedStream(vu, config.Seed, config.PerIteration),
+	}, nil
+}
+
+// Next returns count vectors, each drawn from a uniformly chosen cluster. Each VU gets its own
+// sample stream, derived from the seed and the VU ID, so VUs do not insert the same vectors.
+func (g *VectorGenerator) Next(count int) ([][]float32, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", count)
+	}
+	rng := g.stream.rand()
+
+	vectors := make([][]float32, count)
+	for i := range vectors {
+		centroid := g.centroids[rng.Intn(len(g.centroids))]
+		vector := make([]float32, len(centroid))
+		for j, center := range centroid {
+			vector[j] = center + float32(rng.NormFloat64()*g.config.Stddev)
+		}
+		if g.config.Normalize {
+			normalize(vector)
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// Replay restarts the sample stream of a VU, or of one of its iterations with perIteration, so
+// that later next() calls return the vectors it generated, e.g. to query inserted data
+func (g *VectorGenerator) Replay(vuID int64, iteration ...int64) error {
+	return g.stream.replay(vuID, iteration)
+}
+
+// Centroids returns the cluster centers, normalized when the generator normalizes its vectors
+func (g *VectorGenerator) Centroids() [][]float32 {
+	centroids := make([][]float32, len(g.centroids))
+	for i, centroid := range g.centroids {
+		centroids[i] = append([]float32(nil), centroid...)
+		if g.config.Normalize {
+			normalize(centroids[i])
+		}
+	}
+	return centroids
+}
+
+// normalize scales a vector to unit length in place. Zero vectors are left unchanged.
+func normalize(vector []float32) {
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range vector {
+		vector[i] /= norm
+	}
+}
@@ -0,0 +1,85 @@
+package milvus
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+)
+
+func TestVectorGenerator(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{state: &lib.State{VUID: 1}}}
+	gen, err := m.VectorGenerator(map[string]interface{}{"dim": 32, "clusters": 4, "stddev": 0.01, "seed": 7})
+	require.NoError(t, err)
+	require.Len(t, gen.Centroids(), 4)
+
+	vectors, err := gen.Next(200)
+	require.NoError(t, err)
+	require.Len(t, vectors, 200)
+
+	// Every vector lies close to one of the centroids
+	for _, vector := range vectors {
+		require.Len(t, vector, 32)
+		nearest := math.Inf(1)
+		for _, centroid := range gen.Centroids() {
+			nearest = math.Min(nearest, l2(vector, centroid))
+		}
+		assert.Less(t, nearest, 0.1)
+	}
+
+	// Same seed, same clusters; another VU draws another sample stream
+	other, err := newVectorGenerator(&metricsVU{state: &lib.State{VUID: 2}}, gen.config)
+	require.NoError(t, err)
+	assert.Equal(t, gen.Centroids(), other.Centroids())
+	otherVectors, err := other.Next(200)
+	require.NoError(t, err)
+	assert.NotEqual(t, vectors, otherVectors)
+
+	same, err := newVectorGenerator(&metricsVU{state: &lib.State{VUID: 1}}, gen.config)
+	require.NoError(t, err)
+	sameVectors, err := same.Next(200)
+	require.NoError(t, err)
+	assert.Equal(t, vectors, sameVectors)
+}
+
+func TestVectorGeneratorNormalize(t *testing.T) {
+	gen, err := newVectorGenerator(nil, VectorGeneratorConfig{Dim: 8, Normalize: true})
+	require.NoError(t, err)
+	assert.Len(t, gen.Centroids(), defaultGeneratorClusters)
+
+	vectors, err := gen.Next(10)
+	require.NoError(t, err)
+	for _, vector := range append(vectors, gen.Centroids()...) {
+		assert.InDelta(t, 1, l2(vector, make([]float32, 8)), 1e-5)
+	}
+}
+
+func TestVectorGeneratorInvalidConfig(t *testing.T) {
+	m := &Milvus{}
+	for _, config := range []map[string]interface{}{
+		{},
+		{"dim": 0},
+		{"dim": 8, "clusters": -1},
+		{"dim": 8, "stddev": -0.1},
+		{"dim": "eight"},
+	} {
+		_, err := m.VectorGenerator(config)
+		assert.Error(t, err, config)
+	}
+
+	gen, err := m.VectorGenerator(map[string]interface{}{"dim": 8})
+	require.NoError(t, err)
+	_, err = gen.Next(-1)
+	assert.Error(t, err)
+}
+
+func l2(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i] - b[i])
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,427 @@
+package milvus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Ground truth file formats
+const (
+	groundTruthIvecs   = "ivecs"
+	groundTruthNpy     = "npy"
+	groundTruthParquet = "parquet"
+)
+
+// defaultGroundTruthColumn is the neighbors column of VectorDBBench neighbors.parquet files
+const defaultGroundTruthColumn = "neighbors_id"
+
+// npyDescr, npyFortran and npyShape match the fields of a .npy header, e.g. {'descr': '<i4', 'fortran_order': False, 'shape': (10000, 100), }
+var (
+	npyDescr   = regexp.MustCompile(`'descr':\s*'([^']*)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order':\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape':\s*\(\s*(\d+)\s*,\s*(\d+)\s*,?\s*\)`)
+)
+
+// GroundTruthOptions configures milvus.groundTruth()
+type GroundTruthOptions struct {
+	Format   string `json:"format,omitempty"`   // "ivecs", "npy" or "parquet" (default: from the file extension)
+	Column   string `json:"column,omitempty"`   // Parquet list column of neighbor IDs (default: "neighbors_id", or the only integer list column)
+	IDColumn string `json:"idColumn,omitempty"` // Parquet column of query IDs (default: "id" when present, otherwise the row index)
+}
+
+// GroundTruth holds the true nearest neighbors of each query, loaded once per test and shared
+// by all VUs. Pass it as the groundTruth search param together with queryIds, so recall is
+// computed without sending neighbor arrays from JavaScript on every call.
+//
+// Usage in k6:
+//
+//	const truth = milvus.groundTruth('data/sift_groundtruth.ivecs');
+//	export default function () {
+//	    const q = exec.scenario.iterationInTest % truth.size();
+//	    client.search([queries[q]], 10, { vectorField: 'embedding', groundTruth: truth, queryIds: [q] });
+//	}
+type GroundTruth struct {
+	neighbors [][]int64
+	index     map[int64]int // Query ID to row, when the file has a query ID column
+}
+
+// groundTruthSource is ground truth loaded in Go, looked up by query ID
+type groundTruthSource interface {
+	truthFor(queryID int64) ([]int64, error)
+}
+
+// GroundTruth loads the true nearest neighbors of each query from an ivecs, npy or Parquet file.
+// Query IDs are row indices, or the values of the ID column of Parquet files. Relative paths are
+// resolved against the working directory of the k6 process.
+func (m *Milvus) GroundTruth(path string, optionsInput ...interface{}) (*GroundTruth, error) {
+	var options GroundTruthOptions
+	if len(optionsInput) > 0 && optionsInput[0] != nil {
+		if err := convertViaJSON(optionsInput[0], &options); err != nil {
+			return nil, fmt.Errorf("invalid ground truth options: %v", err)
+		}
+	}
+	path, err := localDataset(m.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	format := strings.ToLower(options.Format)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	if format != groundTruthIvecs && format != groundTruthNpy && format != groundTruthParquet {
+		return nil, fmt.Errorf("ground truth format must be ivecs, npy or parquet, got %q", format)
+	}
+
+	key := strings.Join([]string{"groundTruth", format, options.Column, options.IDColumn, filepath.Clean(path)}, "\x00")
+	return sharedValue(m.shared, key, func() (*GroundTruth, error) {
+		return loadGroundTruth(path, format, options)
+	})
+}
+
+func loadGroundTruth(path, format string, options GroundTruthOptions) (*GroundTruth, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ground truth file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	truth := &GroundTruth{}
+	switch format {
+	case groundTruthIvecs:
+		truth.neighbors, err = readIvecs(bufio.NewReaderSize(file, 1<<20))
+	case groundTruthNpy:
+		truth.neighbors, err = readNpyInts(bufio.NewReaderSize(file, 1<<20))
+	default:
+		truth.neighbors, truth.index, err = readParquetNeighbors(file, options)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ground truth file %s: %v", path, err)
+	}
+	return truth, nil
+}
+
+// readIvecs reads rows of int32 values, each preceded by its int32 length, as in sift_groundtruth.ivecs
+func readIvecs(r io.Reader) ([][]int64, error) {
+	var rows [][]int64
+	var header [4]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			return nil, fmt.Errorf("truncated row %d", len(rows))
+		}
+		k := int32(binary.LittleEndian.Uint32(header[:]))
+		if k < 0 {
+			return nil, fmt.Errorf("row %d has negative length %d", len(rows), k)
+		}
+		data := make([]byte, 4*int(k))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("truncated row %d", len(rows))
+		}
+		row := make([]int64, k)
+		for i := range row {
+			row[i] = int64(int32(binary.LittleEndian.Uint32(data[4*i:])))
+		}
+		rows = append(rows, row)
+	}
+}
+
+// readNpyInts reads a 2D little-endian integer array in C order from a NumPy .npy file
+func readNpyInts(r io.Reader) ([][]int64, error) {
+	descr, rows, cols, err := readNpyHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	var width int
+	signed := true
+	switch descr {
+	case "<i4":
+		width = 4
+	case "<i8":
+		width = 8
+	case "<u4":
+		width, signed = 4, false
+	case "<u8":
+		width, signed = 8, false
+	default:
+		return nil, fmt.Errorf("unsupported dtype %s, expected 32 or 64-bit little-endian integers", descr)
+	}
+
+	out := newMatrix[int64](rows, cols)
+	data := make([]byte, width*cols)
+	for i := range out {
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("truncated data at row %d", i)
+		}
+		for j := range out[i] {
+			switch {
+			case width == 4 && signed:
+				out[i][j] = int64(int32(binary.LittleEndian.Uint32(data[4*j:])))
+			case width == 4:
+				out[i][j] = int64(binary.LittleEndian.Uint32(data[4*j:]))
+			default:
+				out[i][j] = int64(binary.LittleEndian.Uint64(data[8*j:]))
+			}
+		}
+	}
+	return out, nil
+}
+
+// readNpyHeader reads the header of a .npy file holding a 2D C-order array, returning its
+// dtype and shape
+func readNpyHeader(r io.Reader) (string, int, int, error) {
+	var preamble [8]byte
+	if _, err := io.ReadFull(r, preamble[:]); err != nil || string(preamble[:6]) != "\x93NUMPY" {
+		return "", 0, 0, fmt.Errorf("not a .npy file")
+	}
+	var headerLen int
+	switch preamble[6] {
+	case 1:
+		var size [2]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return "", 0, 0, fmt.Errorf("truncated .npy header")
+		}
+		headerLen = int(binary.LittleEndian.Uint16(size[:]))
+	case 2, 3:
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return "", 0, 0, fmt.Errorf("truncated .npy header")
+		}
+		headerLen = int(binary.LittleEndian.Uint32(size[:]))
+	default:
+		return "", 0, 0, fmt.Errorf("unsupported .npy version %d", preamble[6])
+	}
+	headerBytes := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, headerBytes); err != nil {
+		return "", 0, 0, fmt.Errorf("truncated .npy header")
+	}
+	header := string(headerBytes)
+
+	descr := npyDescr.FindStringSubmatch(header)
+	if descr == nil {
+		return "", 0, 0, fmt.Errorf("missing dtype in .npy header %q", header)
+	}
+	if fortran := npyFortran.FindStringSubmatch(header); fortran == nil || fortran[1] != "False" {
+		return "", 0, 0, fmt.Errorf("only C-order arrays are supported")
+	}
+	shape := npyShape.FindStringSubmatch(header)
+	if shape == nil {
+		return "", 0, 0, fmt.Errorf("expected a 2D array, got header %q", header)
+	}
+	rows, _ := strconv.Atoi(shape[1])
+	cols, _ := strconv.Atoi(shape[2])
+	return descr[1], rows, cols, nil
+}
+
+// readParquetNeighbors reads a list column of neighbor IDs and the optional query ID column
+func readParquetNeighbors(file *os.File, options GroundTruthOptions) ([][]int64, map[int64]int, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		return nil, nil, err
+	}
+	neighborsCol, idCol, err := groundTruthColumns(pf.Schema(), options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	neighbors := make([][]int64, 0, pf.NumRows())
+	var index map[int64]int
+	if idCol >= 0 {
+		index = make(map[int64]int, pf.NumRows())
+	}
+	reader := parquet.NewReader(pf)
+	defer func() { _ = reader.Close() }()
+	rows := make([]parquet.Row, 1024)
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			ids := make([]int64, 0)
+			hasID := false
+			for _, v := range row {
+				if v.IsNull() {
+					continue
+				}
+				switch v.Column() {
+				case neighborsCol:
+					ids = append(ids, v.Int64())
+				case idCol:
+					if _, dup := index[v.Int64()]; dup {
+						return nil, nil, fmt.Errorf("duplicate query id %d", v.Int64())
+					}
+					index[v.Int64()] = len(neighbors)
+					hasID = true
+				}
+			}
+			if idCol >= 0 && !hasID {
+				return nil, nil, fmt.Errorf("query id is null at row %d", len(neighbors))
+			}
+			neighbors = append(neighbors, ids)
+		}
+		if errors.Is(err, io.EOF) {
+			return neighbors, index, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// groundTruthColumns returns the leaf column indices of the neighbor IDs and the query IDs (-1 when absent)
+func groundTruthColumns(schema *parquet.Schema, options GroundTruthOptions) (int, int, error) {
+	type leaf struct {
+		index int
+		list  bool
+	}
+	ints := make(map[string]leaf)
+	var lists []string
+	for _, path := range schema.Columns() {
+		col, ok := schema.Lookup(path...)
+		if !ok || len(path) > 1 && col.MaxRepetitionLevel == 0 {
+			continue
+		}
+		if kind := col.Node.Type().Kind(); kind != parquet.Int32 && kind != parquet.Int64 {
+			continue
+		}
+		ints[path[0]] = leaf{index: col.ColumnIndex, list: col.MaxRepetitionLevel > 0}
+		if col.MaxRepetitionLevel > 0 {
+			lists = append(lists, path[0])
+		}
+	}
+
+	name := options.Column
+	if name == "" {
+		name = defaultGroundTruthColumn
+		if _, ok := ints[name]; !ok && len(lists) == 1 {
+			name = lists[0]
+		}
+	}
+	neighbors, ok := ints[name]
+	if !ok || !neighbors.list {
+		return 0, 0, fmt.Errorf("no integer list column %q", name)
+	}
+
+	idName := options.IDColumn
+	if idName == "" {
+		if _, ok := ints["id"]; !ok {
+			return neighbors.index, -1, nil
+		}
+		idName = "id"
+	}
+	id, ok := ints[idName]
+	if !ok || id.list {
+		return 0, 0, fmt.Errorf("no integer column %q", idName)
+	}
+	return neighbors.index, id.index, nil
+}
+
+// Size returns the number of queries
+func (g *GroundTruth) Size() int {
+	return len(g.neighbors)
+}
+
+// memorySize returns the bytes of the neighbors, and roughly those of the query ID index
+func (g *GroundTruth) memorySize() int64 {
+	return matrixBytes(g.neighbors) + 16*int64(len(g.index))
+}
+
+// Neighbors returns the true nearest neighbors of a query, best first, truncated to the top k
+// when k is given
+func (g *GroundTruth) Neighbors(queryID int64, k ...int) ([]int64, error) {
+	neighbors, err := g.truthFor(queryID)
+	if err != nil {
+		return nil, err
+	}
+	if len(k) > 0 && k[0] > 0 && k[0] < len(neighbors) {
+		neighbors = neighbors[:k[0]:k[0]]
+	}
+	return neighbors, nil
+}
+
+func (g *GroundTruth) truthFor(queryID int64) ([]int64, error) {
+	row := queryID
+	if g.index != nil {
+		i, ok := g.index[queryID]
+		if !ok {
+			return nil, fmt.Errorf("no ground truth for query id %d", queryID)
+		}
+		row = int64(i)
+	}
+	if row < 0 || row >= int64(len(g.neighbors)) {
+		return nil, fmt.Errorf("query id %d is out of range [0, %d)", queryID, len(g.neighbors))
+	}
+	return g.neighbors[row], nil
+}
+
+// truthFor returns the neighbors of a test query, by row index
+func (d *AnnDataset) truthFor(queryID int64) ([]int64, error) {
+	if queryID < 0 || queryID >= int64(len(d.neighbors)) {
+		return nil, fmt.Errorf("query id %d is out of range [0, %d)", queryID, len(d.neighbors))
+	}
+	return d.neighbors[queryID], nil
+}
+
+// queryIDsOption reads the "queryIds" search param: the ground truth query ID of each query
+// vector, or a single number for nq consecutive IDs starting at it
+func queryIDsOption(params map[string]interface{}, nq int) ([]int64, error) {
+	value, ok := params["queryIds"]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("queryIds is required with a groundTruth dataset")
+	}
+	var ids []int64
+	switch v := value.(type) {
+	case []interface{}:
+		ids = make([]int64, len(v))
+		for i, item := range v {
+			id, err := queryID(item)
+			if err != nil {
+				return nil, fmt.Errorf("queryIds[%d]: %v", i, err)
+			}
+			ids[i] = id
+		}
+	case []int64: // annDataset ids
+		ids = v
+	default:
+		first, err := queryID(value)
+		if err != nil {
+			return nil, fmt.Errorf("queryIds must be an array of query IDs or the first query ID, got %T", value)
+		}
+		ids = make([]int64, nq)
+		for i := range ids {
+			ids[i] = first + int64(i)
+		}
+	}
+	if len(ids) != nq {
+		return nil, fmt.Errorf("queryIds has %d entries for %d query vectors", len(ids), nq)
+	}
+	return ids, nil
+}
+
+func queryID(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+			return int64(v), nil
+		}
+	}
+	return 0, fmt.Errorf("query ID must be an integer, got %v", value)
+}
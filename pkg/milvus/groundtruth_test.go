@@ -0,0 +1,150 @@
+package milvus
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeIvecs writes rows of int32 values, each preceded by its length
+func writeIvecs(t *testing.T, rows [][]int32) string {
+	path := filepath.Join(t.TempDir(), "groundtruth.ivecs")
+	var data []byte
+	for _, row := range rows {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(row)))
+		for _, v := range row {
+			data = binary.LittleEndian.AppendUint32(data, uint32(v))
+		}
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// writeNpy writes a 2D int64 array in the NumPy 1.0 format
+func writeNpy(t *testing.T, rows [][]int64) string {
+	path := filepath.Join(t.TempDir(), "neighbors.npy")
+	header := "{'descr': '<i8', 'fortran_order': False, 'shape': (2, 3), }\n"
+	data := append([]byte("\x93NUMPY\x01\x00"), byte(len(header)), 0)
+	data = append(data, header...)
+	for _, row := range rows {
+		for _, v := range row {
+			data = binary.LittleEndian.AppendUint64(data, uint64(v))
+		}
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+type neighborsRow struct {
+	ID        int64   `parquet:"id"`
+	Neighbors []int32 `parquet:"neighbors_id,list"`
+}
+
+func TestGroundTruthFormats(t *testing.T) {
+	m := &Milvus{}
+
+	truth, err := m.GroundTruth(writeIvecs(t, [][]int32{{4, 2, 9}, {1, 0, 3}}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, truth.Size())
+	neighbors, err := truth.Neighbors(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 0, 3}, neighbors)
+	neighbors, err = truth.Neighbors(0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 2}, neighbors)
+	_, err = truth.Neighbors(2)
+	assert.ErrorContains(t, err, "out of range")
+
+	truth, err = m.GroundTruth(writeNpy(t, [][]int64{{7, 8, 9}, {1 << 40, 5, 6}}))
+	require.NoError(t, err)
+	neighbors, err = truth.Neighbors(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1 << 40, 5, 6}, neighbors)
+
+	// Parquet query IDs come from the id column
+	path := filepath.Join(t.TempDir(), "neighbors.parquet")
+	require.NoError(t, parquet.WriteFile(path, []neighborsRow{{ID: 100, Neighbors: []int32{3, 1}}, {ID: 200, Neighbors: []int32{-1, 2}}}))
+	truth, err = m.GroundTruth(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, truth.Size())
+	neighbors, err = truth.Neighbors(200)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{-1, 2}, neighbors)
+	_, err = truth.Neighbors(0)
+	assert.ErrorContains(t, err, "no ground truth for query id 0")
+}
+
+func TestGroundTruthShared(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	path := writeIvecs(t, [][]int32{{1}})
+	first, err := m.GroundTruth(path)
+	require.NoError(t, err)
+	second, err := m.GroundTruth(path, map[string]interface{}{"format": "ivecs"})
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+}
+
+func TestGroundTruthErrors(t *testing.T) {
+	m := &Milvus{}
+	dir := t.TempDir()
+
+	_, err := m.GroundTruth(filepath.Join(dir, "neighbors.csv"))
+	assert.ErrorContains(t, err, "format must be ivecs, npy or parquet")
+	_, err = m.GroundTruth(filepath.Join(dir, "missing.ivecs"))
+	assert.Error(t, err)
+
+	truncated := filepath.Join(dir, "truncated.ivecs")
+	require.NoError(t, os.WriteFile(truncated, []byte{3, 0, 0, 0, 1, 0}, 0o600))
+	_, err = m.GroundTruth(truncated)
+	assert.ErrorContains(t, err, "truncated row 0")
+
+	notNpy := filepath.Join(dir, "bad.npy")
+	require.NoError(t, os.WriteFile(notNpy, []byte("not numpy"), 0o600))
+	_, err = m.GroundTruth(notNpy)
+	assert.ErrorContains(t, err, "not a .npy file")
+
+	path := filepath.Join(dir, "neighbors.parquet")
+	require.NoError(t, parquet.WriteFile(path, []neighborsRow{{ID: 1, Neighbors: []int32{2}}}))
+	_, err = m.GroundTruth(path, map[string]interface{}{"column": "id"})
+	assert.ErrorContains(t, err, `no integer list column "id"`)
+}
+
+func TestGroundTruthSearchParam(t *testing.T) {
+	truth, err := (&Milvus{}).GroundTruth(writeIvecs(t, [][]int32{{4, 2}, {1, 0}, {3, 5}}))
+	require.NoError(t, err)
+
+	got, err := groundTruthOption(map[string]interface{}{"groundTruth": truth, "queryIds": []interface{}{float64(2), int64(0)}}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []queryTruth{{ints: []int64{3, 5}}, {ints: []int64{4, 2}}}, got)
+
+	// A single number is the first of consecutive query IDs
+	got, err = groundTruthOption(map[string]interface{}{"groundTruth": truth, "queryIds": float64(1)}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []queryTruth{{ints: []int64{1, 0}}, {ints: []int64{3, 5}}}, got)
+
+	// annDataset looks up test query neighbors the same way
+	ds, err := (&Milvus{}).AnnDataset(writeAnnDataset(t))
+	require.NoError(t, err)
+	got, err = groundTruthOption(map[string]interface{}{"groundTruth": ds, "queryIds": []int64{1}}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []queryTruth{{ints: []int64{3, 4, 2, 1}}}, got)
+
+	for _, params := range []map[string]interface{}{
+		{"groundTruth": truth},
+		{"groundTruth": truth, "queryIds": []interface{}{float64(0)}},
+		{"groundTruth": truth, "queryIds": []interface{}{1.5, float64(0)}},
+		{"groundTruth": truth, "queryIds": float64(2)},
+		{"groundTruth": truth, "queryIds": "0"},
+		{"groundTruth": []interface{}{[]interface{}{1}, []interface{}{2}}, "queryIds": float64(0)},
+		{"queryIds": float64(0)},
+	} {
+		_, err := groundTruthOption(params, 2)
+		assert.Error(t, err, params)
+	}
+}
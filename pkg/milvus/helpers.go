@@ -3,16 +3,43 @@ package milvus
 import (
 	"context"
 	"encoding/json"
+
+	"go.k6.io/k6/js/modules"
 )
 
 // context returns the current VU context for operations.
 // This ensures each operation uses the current iteration's context,
 // not a stale context from a previous iteration.
+// Headers configured on the client are attached as gRPC metadata, and the client itself
+// is attached for the RPC interceptors.
 func (c *Client) context() context.Context {
+	return context.WithValue(c.uninstrumentedContext(), clientContextKey{}, c)
+}
+
+// uninstrumentedContext is context() without the client, so the RPC interceptors neither
+// record nor trace the calls made with it.
+func (c *Client) uninstrumentedContext() context.Context {
+	ctx := c.ctx
 	if c.vu != nil {
-		return c.vu.Context()
+		if vuCtx := c.vu.Context(); vuCtx != nil {
+			ctx = vuCtx
+		}
+	}
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	return c.ctx
+	return c.withMetadata(ctx)
+}
+
+// vuContext returns the VU context, falling back to a background context
+// where no VU context is available (init context, setup/teardown helpers, tests).
+func vuContext(vu modules.VU) context.Context {
+	if vu != nil {
+		if ctx := vu.Context(); ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
 }
 
 // getCollectionName returns collection name from params or default collection
@@ -25,7 +52,12 @@ func (c *Client) getCollectionName(collectionName ...string) string {
 
 // toMap converts OperationResult to map[string]interface{} using JSON tags
 // This ensures JavaScript code can access fields using camelCase names defined in JSON tags
+// An RPC error in Cause is classified into error_code and error_type.
 func toMap(result *OperationResult) map[string]interface{} {
+	if result.Cause != nil {
+		result.ErrorCode = errorCode(result.Cause)
+		result.ErrorType = errorType(result.Cause)
+	}
 	data, err := json.Marshal(result)
 	if err != nil {
 		return map[string]interface{}{
@@ -1,6 +1,7 @@
 package milvus
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -246,3 +247,27 @@ func TestGetCollectionNameEdgeCases(t *testing.T) {
 		assert.Equal(t, "   ", got)
 	})
 }
+
+// initContextVU mimics a VU without a context, as seen by helpers running outside an iteration
+type initContextVU struct {
+	metricsVU
+}
+
+func (v *initContextVU) Context() context.Context { return nil }
+
+func TestClientContextFallback(t *testing.T) {
+	type ctxKey struct{}
+	stored := context.WithValue(context.Background(), ctxKey{}, "stored")
+
+	assert.NotNil(t, (&Client{}).context())
+	assert.Equal(t, "stored", (&Client{ctx: stored}).context().Value(ctxKey{}))
+	assert.Equal(t, "stored", (&Client{ctx: stored, vu: &initContextVU{}}).context().Value(ctxKey{}))
+	assert.Nil(t, (&Client{ctx: stored, vu: &metricsVU{}}).context().Value(ctxKey{}))
+
+	// The client is attached for the RPC interceptors
+	client := &Client{}
+	assert.Same(t, client, client.context().Value(clientContextKey{}))
+
+	assert.NotNil(t, vuContext(nil))
+	assert.NotNil(t, vuContext(&initContextVU{}))
+}
@@ -1,17 +1,33 @@
 package milvus
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
 	"github.com/milvus-io/milvus/client/v2/entity"
 	"github.com/milvus-io/milvus/client/v2/index"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+	"go.k6.io/k6/metrics"
 )
 
-// CreateIndex creates an index on a field
+// CreateIndex creates an index on a field and waits for the build to finish
 func (c *Client) CreateIndex(fieldName string, indexParams map[string]interface{}, collectionName ...string) interface{} {
+	return c.createIndex(fieldName, indexParams, true, collectionName...)
+}
+
+// CreateIndexAsync submits an index build without waiting for it to finish.
+// Use IndexBuildProgress to poll the build; on large datasets Await can take hours.
+func (c *Client) CreateIndexAsync(fieldName string, indexParams map[string]interface{}, collectionName ...string) interface{} {
+	return c.createIndex(fieldName, indexParams, false, collectionName...)
+}
+
+func (c *Client) createIndex(fieldName string, indexParams map[string]interface{}, wait bool, collectionName ...string) interface{} {
 	start := time.Now()
 
 	coll := c.getCollectionName(collectionName...)
@@ -23,7 +39,15 @@ func (c *Client) CreateIndex(fieldName string, indexParams map[string]interface{
 		})
 	}
 
-	idx, indexType, indexName, err := buildIndex(indexParams)
+	idx, indexType, indexName, err := c.indexFor(indexParams)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err.Error(),
+		})
+	}
+	waitTimeout, err := indexWaitTimeout(indexParams)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
@@ -35,33 +59,186 @@ func (c *Client) CreateIndex(fieldName string, indexParams map[string]interface{
 	option := milvusclient.NewCreateIndexOption(coll, fieldName, idx)
 	if indexName != "" {
 		option = option.WithIndexName(indexName)
+	} else {
+		// Milvus names unnamed indexes after their field
+		indexName = fieldName
 	}
-	task, err := c.client.CreateIndex(c.context(), option)
+	task, err := c.milvus().CreateIndex(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to create index: %v", err),
+			Cause:        err,
 		})
 	}
+	c.rememberIndexType(coll, fieldName, strings.ToUpper(indexType))
+
+	if wait {
+		// Wait for index creation to complete
+		err = c.awaitIndex(task, waitTimeout, coll, fieldName, indexType, start)
+		if err != nil {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        err.Error(),
+				Cause:        err,
+			})
+		}
+	}
 
-	// Wait for index creation to complete
-	err = task.Await(c.context())
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"field": fieldName, "index_type": indexType, "index_name": indexName},
+	})
+}
+
+// awaitIndex waits for an index build, giving up after waitTimeout when it is set.
+// The time from submission to completion is emitted as the milvus_index_build_duration Trend.
+func (c *Client) awaitIndex(task *milvusclient.CreateIndexTask, waitTimeout time.Duration, coll, fieldName, indexType string, submitted time.Time) error {
+	ctx := c.context()
+	if waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+		defer cancel()
+	}
+
+	if err := task.Await(ctx); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("index on field %s not ready within %s", fieldName, waitTimeout)
+		}
+		return fmt.Errorf("failed to wait for index creation: %w", err)
+	}
+
+	if c.metrics != nil {
+		c.pushMetric(c.metrics.IndexBuildDuration, metrics.D(time.Since(submitted)), map[string]string{
+			"collection": coll,
+			"field":      fieldName,
+			"index_type": indexType,
+		})
+	}
+	return nil
+}
+
+// indexWaitTimeout reads the waitTimeout (or wait_timeout) index param: a duration string
+// such as "30m", or a number of seconds. Zero or unset means wait indefinitely.
+func indexWaitTimeout(params map[string]interface{}) (time.Duration, error) {
+	key := "waitTimeout"
+	if value, ok := params[key]; !ok || value == nil {
+		key = "wait_timeout"
+	}
+	value, ok := params[key]
+	if !ok || value == nil {
+		return 0, nil
+	}
+
+	if text, isString := value.(string); isString {
+		timeout, err := time.ParseDuration(text)
+		if err != nil {
+			return 0, fmt.Errorf("invalid waitTimeout %q: %v", text, err)
+		}
+		return timeout, nil
+	}
+	seconds := floatIndexParam(params, key, -1)
+	if seconds < 0 {
+		return 0, fmt.Errorf("invalid waitTimeout: %v", value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// IndexBuildProgress reports the build progress of the index(es) on a field and
+// emits it as the milvus_index_build_progress Gauge (0..1), tagged with collection and field.
+func (c *Client) IndexBuildProgress(fieldName string, collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+
+	indexNames, err := c.milvus().ListIndexes(c.context(), milvusclient.NewListIndexOption(coll).WithFieldName(fieldName))
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
-			Error:        fmt.Sprintf("failed to wait for index creation: %v", err),
+			Error:        fmt.Sprintf("failed to list indexes: %v", err),
+			Cause:        err,
+		})
+	}
+	if len(indexNames) == 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("no index found on field %s", fieldName),
+		})
+	}
+
+	descs := make([]milvusclient.IndexDescription, 0, len(indexNames))
+	for _, indexName := range indexNames {
+		desc, describeErr := c.milvus().DescribeIndex(c.context(), milvusclient.NewDescribeIndexOption(coll, indexName))
+		if describeErr != nil {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        fmt.Sprintf("failed to describe index: %v", describeErr),
+				Cause:        describeErr,
+			})
+		}
+		descs = append(descs, desc)
+	}
+
+	result := indexProgressMap(fieldName, indexNames, descs)
+	if c.metrics != nil {
+		c.pushMetric(c.metrics.IndexBuildProgress, result["progress"].(float64), map[string]string{
+			"collection": coll,
+			"field":      fieldName,
 		})
 	}
 
 	return toMap(&OperationResult{
 		Success:      true,
 		ResponseTime: float64(time.Since(start).Milliseconds()),
-		Result:       map[string]interface{}{"field": fieldName, "index_type": indexType},
+		Result:       result,
 	})
 }
 
+// indexProgressMap aggregates the row counts of all indexes on a field.
+// The state is the first one that is not Finished, so a field is only done when all its indexes are.
+func indexProgressMap(fieldName string, indexNames []string, descs []milvusclient.IndexDescription) map[string]interface{} {
+	state := commonpb.IndexState_Finished
+	var totalRows, indexedRows, pendingRows int64
+	for _, desc := range descs {
+		totalRows += desc.TotalRows
+		indexedRows += desc.IndexedRows
+		pendingRows += desc.PendingIndexRows
+		if state == commonpb.IndexState_Finished {
+			state = commonpb.IndexState(desc.State)
+		}
+	}
+
+	progress := 0.0
+	if totalRows > 0 {
+		progress = float64(indexedRows) / float64(totalRows)
+	} else if state == commonpb.IndexState_Finished {
+		progress = 1
+	}
+
+	return map[string]interface{}{
+		"field":        fieldName,
+		"index_names":  indexNames,
+		"state":        state.String(),
+		"progress":     progress,
+		"total_rows":   totalRows,
+		"indexed_rows": indexedRows,
+		"pending_rows": pendingRows,
+	}
+}
+
 func buildIndex(indexParams map[string]interface{}) (index.Index, string, string, error) {
 	params := flattenIndexParams(indexParams)
 	indexType := "FLAT"
@@ -99,16 +276,50 @@ func buildIndex(indexParams map[string]interface{}) (index.Index, string, string
 			intIndexParam(params, "M", 16),
 			intIndexParam(params, "efConstruction", 200),
 		)
+	case "HNSW_SQ":
+		idx = newGenericIndex(normalizedIndexType, metricType, params, hnswBuildParams(params),
+			"sq_type", "refine", "refine_type")
+	case "HNSW_PQ":
+		idx = newGenericIndex(normalizedIndexType, metricType, params, hnswBuildParams(params),
+			"m", "nbits", "refine", "refine_type")
+	case "HNSW_PRQ":
+		idx = newGenericIndex(normalizedIndexType, metricType, params, hnswBuildParams(params),
+			"m", "nbits", "nrq", "refine", "refine_type")
+	case "SCANN":
+		idx = index.NewSCANNIndex(
+			metricType,
+			intIndexParam(params, "nlist", 1024),
+			boolIndexParam(params, "with_raw_data", "withRawData", true),
+		)
+	case "GPU_CAGRA":
+		idx = newGenericIndex(normalizedIndexType, metricType, params, map[string]string{
+			"intermediate_graph_degree": strconv.Itoa(intIndexParam(params, "intermediate_graph_degree", 128)),
+			"graph_degree":              strconv.Itoa(intIndexParam(params, "graph_degree", 64)),
+		}, "build_algo", "cache_dataset_on_device", "adapt_for_cpu")
+	case "GPU_IVF_FLAT":
+		idx = newGenericIndex(normalizedIndexType, metricType, params, map[string]string{
+			"nlist": strconv.Itoa(intIndexParam(params, "nlist", 1024)),
+		}, "cache_dataset_on_device")
+	case "GPU_IVF_PQ":
+		idx = newGenericIndex(normalizedIndexType, metricType, params, map[string]string{
+			"nlist": strconv.Itoa(intIndexParam(params, "nlist", 1024)),
+			"m":     strconv.Itoa(intIndexParam(params, "m", 4)),
+			"nbits": strconv.Itoa(intIndexParam(params, "nbits", 8)),
+		}, "cache_dataset_on_device")
+	case "GPU_BRUTE_FORCE":
+		idx = newGenericIndex(normalizedIndexType, metricType, params, map[string]string{})
 	case "AUTOINDEX", "AUTO_INDEX":
 		idx = index.NewAutoIndex(metricType)
 	case "SPARSE_INVERTED_INDEX":
-		idx = index.NewSparseInvertedIndex(metricType, floatIndexParam(params, "dropRatio", 0))
+		idx = index.NewSparseInvertedIndex(sparseMetricType(params, metricType), sparseDropRatio(params))
 	case "SPARSE_WAND":
-		idx = index.NewSparseWANDIndex(metricType, floatIndexParam(params, "dropRatio", 0))
-	case "INVERTED":
-		idx = index.NewInvertedIndex()
-	case "STL_SORT":
-		idx = index.NewSortedIndex()
+		idx = index.NewSparseWANDIndex(sparseMetricType(params, metricType), sparseDropRatio(params))
+	case "INVERTED", "STL_SORT":
+		var err error
+		idx, err = scalarIndex(normalizedIndexType, params)
+		if err != nil {
+			return nil, indexType, "", err
+		}
 	case "BITMAP":
 		idx = index.NewBitmapIndex()
 	case "TRIE":
@@ -117,6 +328,10 @@ func buildIndex(indexParams map[string]interface{}) (index.Index, string, string
 		return nil, indexType, "", fmt.Errorf("unsupported index type: %s", indexType)
 	}
 
+	if extra := extraIndexParams(params); len(extra) > 0 {
+		idx = withExtraIndexParams(idx, extra)
+	}
+
 	indexName, _ := stringOption(params, "indexName")
 	if indexName == "" {
 		indexName, _ = stringOption(params, "index_name")
@@ -124,6 +339,83 @@ func buildIndex(indexParams map[string]interface{}) (index.Index, string, string
 	return idx, indexType, indexName, nil
 }
 
+// extraIndexParams reads the extraParams (or extra_params) map, for build params not modeled by buildIndex
+func extraIndexParams(params map[string]interface{}) map[string]string {
+	raw, ok := params["extraParams"].(map[string]interface{})
+	if !ok {
+		raw, ok = params["extra_params"].(map[string]interface{})
+	}
+	if !ok {
+		return nil
+	}
+
+	extra := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if value != nil {
+			extra[key] = searchParamValue(value)
+		}
+	}
+	return extra
+}
+
+// withExtraIndexParams merges extra params into the index build params, overriding modeled ones.
+func withExtraIndexParams(idx index.Index, extra map[string]string) index.Index {
+	merged := make(map[string]string)
+	for key, value := range idx.Params() {
+		merged[key] = value
+	}
+	for key, value := range extra {
+		merged[key] = value
+	}
+	return index.NewGenericIndex(idx.Name(), merged)
+}
+
+// scalarIndex builds an INVERTED or STL_SORT index. When json_path is set the index targets
+// a path inside a JSON field and json_cast_type (BOOL, DOUBLE, VARCHAR, ARRAY_*) is required.
+func scalarIndex(indexType string, params map[string]interface{}) (index.Index, error) {
+	jsonPath, _ := stringOption(params, "json_path")
+	if jsonPath == "" {
+		jsonPath, _ = stringOption(params, "jsonPath")
+	}
+	if jsonPath == "" {
+		if indexType == "STL_SORT" {
+			return index.NewSortedIndex(), nil
+		}
+		return index.NewInvertedIndex(), nil
+	}
+
+	castType, _ := stringOption(params, "json_cast_type")
+	if castType == "" {
+		castType, _ = stringOption(params, "jsonCastType")
+	}
+	if castType == "" {
+		return nil, fmt.Errorf("json_cast_type required for json_path index on %s", jsonPath)
+	}
+	return index.NewJSONPathIndex(index.IndexType(indexType), strings.ToUpper(castType), jsonPath), nil
+}
+
+// newGenericIndex builds an index the SDK has no working constructor for (GPU, quantized HNSW) as a generic index.
+// The SDK GPU constructors drop build params and mislabel the index type, so params are set explicitly.
+// Optional keys are copied from params only when present so server-side defaults apply otherwise.
+func newGenericIndex(indexType string, metricType entity.MetricType, params map[string]interface{}, buildParams map[string]string, optionalKeys ...string) index.Index {
+	buildParams[index.IndexTypeKey] = indexType
+	buildParams[index.MetricTypeKey] = string(metricType)
+	for _, key := range optionalKeys {
+		if value, ok := params[key]; ok && value != nil {
+			buildParams[key] = searchParamValue(value)
+		}
+	}
+	return index.NewGenericIndex("", buildParams)
+}
+
+// hnswBuildParams returns the graph params shared by the HNSW family, with the same defaults as HNSW
+func hnswBuildParams(params map[string]interface{}) map[string]string {
+	return map[string]string{
+		"M":              strconv.Itoa(intIndexParam(params, "M", 16)),
+		"efConstruction": strconv.Itoa(intIndexParam(params, "efConstruction", 200)),
+	}
+}
+
 func flattenIndexParams(indexParams map[string]interface{}) map[string]interface{} {
 	params := make(map[string]interface{}, len(indexParams))
 	for key, val := range indexParams {
@@ -171,6 +463,22 @@ func metricTypeOption(params map[string]interface{}) entity.MetricType {
 	return metricType
 }
 
+// sparseMetricType defaults sparse indexes to IP, since L2 is not valid for SparseFloatVector fields
+func sparseMetricType(params map[string]interface{}, metricType entity.MetricType) entity.MetricType {
+	if name, ok := stringOption(params, "metricType"); ok && name != "" {
+		return metricType
+	}
+	if name, ok := stringOption(params, "metric_type"); ok && name != "" {
+		return metricType
+	}
+	return entity.IP
+}
+
+// sparseDropRatio reads drop_ratio_build, falling back to the dropRatio alias
+func sparseDropRatio(params map[string]interface{}) float64 {
+	return floatIndexParam(params, "drop_ratio_build", floatIndexParam(params, "dropRatio", 0))
+}
+
 func intIndexParam(params map[string]interface{}, key string, fallback int) int {
 	if value, ok := intOption(params, key); ok {
 		return value
@@ -178,6 +486,17 @@ func intIndexParam(params map[string]interface{}, key string, fallback int) int
 	return fallback
 }
 
+// boolIndexParam looks up a boolean index param by its Milvus (snake_case) key or camelCase alias
+func boolIndexParam(params map[string]interface{}, key, alias string, fallback bool) bool {
+	if value, ok := boolOption(params, key); ok {
+		return value
+	}
+	if value, ok := boolOption(params, alias); ok {
+		return value
+	}
+	return fallback
+}
+
 func floatIndexParam(params map[string]interface{}, key string, fallback float64) float64 {
 	value, ok := params[key]
 	if !ok || value == nil {
@@ -212,8 +531,100 @@ func floatIndexParam(params map[string]interface{}, key string, fallback float64
 	return fallback
 }
 
-// DropIndex drops an index by field name
-func (c *Client) DropIndex(fieldName string, collectionName ...string) interface{} {
+// DropIndex drops an index by field name or index name.
+// A field name drops every index on that field, including ones created with a custom indexName.
+func (c *Client) DropIndex(name string, collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+
+	indexNames := c.resolveIndexNames(coll, name)
+	for _, indexName := range indexNames {
+		option := milvusclient.NewDropIndexOption(coll, indexName)
+		err := c.milvus().DropIndex(c.context(), option)
+		if err != nil {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        fmt.Sprintf("failed to drop index: %v", err),
+				Cause:        err,
+			})
+		}
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"field": name, "index_names": indexNames},
+	})
+}
+
+// resolveIndexNames returns the names of the indexes on a field, so indexes can be addressed by field
+// even when created with a custom indexName. Names that are not a field are treated as index names.
+func (c *Client) resolveIndexNames(coll, name string) []string {
+	option := milvusclient.NewListIndexOption(coll).WithFieldName(name)
+	indexNames, err := c.milvus().ListIndexes(c.context(), option)
+	if err != nil || len(indexNames) == 0 {
+		return []string{name}
+	}
+	return indexNames
+}
+
+// AlterIndexProperties sets properties such as mmap.enabled on an existing index.
+// name may be a field name or an index name, as in DropIndex. Milvus requires the collection to be released.
+func (c *Client) AlterIndexProperties(name string, properties map[string]interface{}, collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+	if len(properties) == 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "at least one index property required",
+		})
+	}
+
+	props := indexProperties(properties)
+	indexNames := c.resolveIndexNames(coll, name)
+	for _, indexName := range indexNames {
+		option := milvusclient.NewAlterIndexPropertiesOption(coll, indexName)
+		for key, value := range props {
+			option = option.WithProperty(key, value)
+		}
+		err := c.milvus().AlterIndexProperties(c.context(), option)
+		if err != nil {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        fmt.Sprintf("failed to alter index properties: %v", err),
+				Cause:        err,
+			})
+		}
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"index_names": indexNames, "properties": props},
+	})
+}
+
+// DropIndexProperties resets index properties to their defaults
+func (c *Client) DropIndexProperties(name string, keys []string, collectionName ...string) interface{} {
 	start := time.Now()
 
 	coll := c.getCollectionName(collectionName...)
@@ -224,20 +635,213 @@ func (c *Client) DropIndex(fieldName string, collectionName ...string) interface
 			Error:        "collection name required",
 		})
 	}
+	if len(keys) == 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "at least one index property key required",
+		})
+	}
+
+	indexNames := c.resolveIndexNames(coll, name)
+	for _, indexName := range indexNames {
+		option := milvusclient.NewDropIndexPropertiesOption(coll, indexName, keys...)
+		err := c.milvus().DropIndexProperties(c.context(), option)
+		if err != nil {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        fmt.Sprintf("failed to drop index properties: %v", err),
+				Cause:        err,
+			})
+		}
+	}
 
-	option := milvusclient.NewDropIndexOption(coll, fieldName)
-	err := c.client.DropIndex(c.context(), option)
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"index_names": indexNames, "keys": keys},
+	})
+}
+
+// indexProperties stringifies JS property values, so { "mmap.enabled": true } becomes "true"
+func indexProperties(properties map[string]interface{}) map[string]string {
+	props := make(map[string]string, len(properties))
+	for key, value := range properties {
+		if value != nil {
+			props[key] = searchParamValue(value)
+		}
+	}
+	return props
+}
+
+// DescribeIndex returns the definition and build state of an index.
+// Indexes created without an explicit name are named after their field.
+func (c *Client) DescribeIndex(indexName string, collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+
+	option := milvusclient.NewDescribeIndexOption(coll, indexName)
+	desc, err := c.milvus().DescribeIndex(c.context(), option)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
-			Error:        fmt.Sprintf("failed to drop index: %v", err),
+			Error:        fmt.Sprintf("failed to describe index: %v", err),
+			Cause:        err,
+		})
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       indexDescriptionMap(indexName, desc),
+	})
+}
+
+func indexDescriptionMap(indexName string, desc milvusclient.IndexDescription) map[string]interface{} {
+	params := map[string]string{}
+	if desc.Index != nil {
+		params = desc.Params()
+	}
+	return map[string]interface{}{
+		"index_name":   indexName,
+		"index_type":   params[index.IndexTypeKey],
+		"metric_type":  params[index.MetricTypeKey],
+		"params":       params,
+		"state":        commonpb.IndexState(desc.State).String(),
+		"total_rows":   desc.TotalRows,
+		"indexed_rows": desc.IndexedRows,
+		"pending_rows": desc.PendingIndexRows,
+	}
+}
+
+// ListIndexes returns the names of all indexes on a collection
+func (c *Client) ListIndexes(collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+
+	option := milvusclient.NewListIndexOption(coll)
+	indexes, err := c.milvus().ListIndexes(c.context(), option)
+	// Milvus reports a collection without indexes as an error
+	if err != nil && !errors.Is(err, merr.ErrIndexNotFound) {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to list indexes: %v", err),
+			Cause:        err,
 		})
 	}
 
 	return toMap(&OperationResult{
 		Success:      true,
 		ResponseTime: float64(time.Since(start).Milliseconds()),
-		Result:       map[string]interface{}{"field": fieldName},
+		Result:       indexes,
+		Empty:        len(indexes) == 0,
+	})
+}
+
+// RebuildIndex drops the index on a field and recreates it with new params, waiting for the build to finish.
+// The total drop + build time is emitted as the milvus_index_rebuild_duration Trend.
+// Milvus refuses to drop the index of a loaded collection, so release it first and load it again afterwards.
+func (c *Client) RebuildIndex(fieldName string, indexParams map[string]interface{}, collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+
+	// Validate the new params before dropping anything
+	idx, indexType, indexName, err := c.indexFor(indexParams)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err.Error(),
+		})
+	}
+	waitTimeout, err := indexWaitTimeout(indexParams)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err.Error(),
+		})
+	}
+
+	for _, oldIndexName := range c.resolveIndexNames(coll, fieldName) {
+		err = c.milvus().DropIndex(c.context(), milvusclient.NewDropIndexOption(coll, oldIndexName))
+		if err != nil {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        fmt.Sprintf("failed to drop index: %v", err),
+				Cause:        err,
+			})
+		}
+	}
+
+	buildStart := time.Now()
+	option := milvusclient.NewCreateIndexOption(coll, fieldName, idx)
+	if indexName != "" {
+		option = option.WithIndexName(indexName)
+	} else {
+		indexName = fieldName
+	}
+	task, err := c.milvus().CreateIndex(c.context(), option)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to create index: %v", err),
+			Cause:        err,
+		})
+	}
+
+	err = c.awaitIndex(task, waitTimeout, coll, fieldName, indexType, buildStart)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err.Error(),
+			Cause:        err,
+		})
+	}
+
+	c.rememberIndexType(coll, fieldName, strings.ToUpper(indexType))
+	elapsed := time.Since(start)
+	if c.metrics != nil {
+		c.pushMetric(c.metrics.IndexRebuildDuration, metrics.D(elapsed), map[string]string{
+			"collection": coll,
+			"field":      fieldName,
+			"index_type": indexType,
+		})
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(elapsed.Milliseconds()),
+		Result:       map[string]interface{}{"field": fieldName, "index_type": indexType, "index_name": indexName},
 	})
 }
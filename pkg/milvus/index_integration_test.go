@@ -235,3 +235,241 @@ func TestCreateIndex_ResponseTime_Integration(t *testing.T) {
 		assert.Greater(t, resultMap["response_time_ms"].(float64), 0.0)
 	})
 }
+
+func TestIndexLifecycle_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, _, cleanup := setupTestClientWithoutIndex(t)
+	defer cleanup()
+
+	vectors := make([][]float32, 10)
+	ids := make([]int64, 10)
+	titles := make([]string, 10)
+	for i := 0; i < 10; i++ {
+		vectors[i] = make([]float32, 128)
+		for j := 0; j < 128; j++ {
+			vectors[i][j] = float32(i*128 + j)
+		}
+		ids[i] = int64(i + 1)
+		titles[i] = "Test " + string(rune('A'+i))
+	}
+
+	insertResult := client.Insert(map[string]interface{}{
+		"id":     ids,
+		"title":  titles,
+		"vector": vectors,
+	})
+	require.Equal(t, true, insertResult.(map[string]interface{})["success"])
+
+	createResult := client.CreateIndex("vector", map[string]interface{}{
+		"indexType":  "FLAT",
+		"metricType": "L2",
+	})
+	require.Equal(t, true, createResult.(map[string]interface{})["success"])
+
+	t.Run("list_indexes", func(t *testing.T) {
+		resultMap := client.ListIndexes().(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+		assert.Contains(t, resultMap["result"], "vector")
+	})
+
+	t.Run("describe_index", func(t *testing.T) {
+		resultMap := client.DescribeIndex("vector").(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+
+		resultData := resultMap["result"].(map[string]interface{})
+		assert.Equal(t, "vector", resultData["index_name"])
+		assert.Equal(t, "FLAT", resultData["index_type"])
+		assert.Equal(t, "Finished", resultData["state"])
+	})
+
+	t.Run("rebuild_index", func(t *testing.T) {
+		resultMap := client.RebuildIndex("vector", map[string]interface{}{
+			"indexType":  "IVF_FLAT",
+			"metricType": "L2",
+			"nlist":      16,
+		}).(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+
+		describeMap := client.DescribeIndex("vector").(map[string]interface{})
+		require.Equal(t, true, describeMap["success"])
+		assert.Equal(t, "IVF_FLAT", describeMap["result"].(map[string]interface{})["index_type"])
+	})
+
+	t.Run("rebuild_index_invalid_params_keeps_index", func(t *testing.T) {
+		resultMap := client.RebuildIndex("vector", map[string]interface{}{
+			"indexType": "UNSUPPORTED_INDEX_TYPE",
+		}).(map[string]interface{})
+		assert.Equal(t, false, resultMap["success"])
+
+		describeMap := client.DescribeIndex("vector").(map[string]interface{})
+		assert.Equal(t, true, describeMap["success"])
+	})
+}
+
+func TestCreateIndexAsync_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, _, cleanup := setupTestClientWithoutIndex(t)
+	defer cleanup()
+
+	vectors := make([][]float32, 10)
+	ids := make([]int64, 10)
+	titles := make([]string, 10)
+	for i := 0; i < 10; i++ {
+		vectors[i] = make([]float32, 128)
+		for j := 0; j < 128; j++ {
+			vectors[i][j] = float32(i*128 + j)
+		}
+		ids[i] = int64(i + 1)
+		titles[i] = "Test " + string(rune('A'+i))
+	}
+
+	insertResult := client.Insert(map[string]interface{}{
+		"id":     ids,
+		"title":  titles,
+		"vector": vectors,
+	})
+	require.Equal(t, true, insertResult.(map[string]interface{})["success"])
+
+	resultMap := client.CreateIndexAsync("vector", map[string]interface{}{
+		"indexType":  "HNSW",
+		"metricType": "L2",
+	}).(map[string]interface{})
+	require.Equal(t, true, resultMap["success"], resultMap["error"])
+	assert.Equal(t, "vector", resultMap["result"].(map[string]interface{})["index_name"])
+
+	// Poll until the build finishes
+	var progressData map[string]interface{}
+	for i := 0; i < 60; i++ {
+		progressMap := client.IndexBuildProgress("vector").(map[string]interface{})
+		require.Equal(t, true, progressMap["success"], progressMap["error"])
+
+		progressData = progressMap["result"].(map[string]interface{})
+		if progressData["state"] == "Finished" {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	assert.Equal(t, "Finished", progressData["state"])
+	assert.Equal(t, 1.0, progressData["progress"])
+
+	t.Run("progress_for_unindexed_field", func(t *testing.T) {
+		resultMap := client.IndexBuildProgress("title").(map[string]interface{})
+		assert.Equal(t, false, resultMap["success"])
+		assert.Contains(t, resultMap["error"], "no index found")
+	})
+}
+
+func TestNamedIndexes_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, _, cleanup := setupTestClientWithoutIndex(t)
+	defer cleanup()
+
+	resultMap := client.CreateIndex("vector", map[string]interface{}{
+		"indexType":  "HNSW",
+		"metricType": "L2",
+		"indexName":  "vector_hnsw",
+	}).(map[string]interface{})
+	require.Equal(t, true, resultMap["success"], resultMap["error"])
+	assert.Equal(t, "vector_hnsw", resultMap["result"].(map[string]interface{})["index_name"])
+
+	resultMap = client.CreateIndex("title", map[string]interface{}{
+		"indexType": "INVERTED",
+		"indexName": "title_inverted",
+	}).(map[string]interface{})
+	require.Equal(t, true, resultMap["success"], resultMap["error"])
+
+	listMap := client.ListIndexes().(map[string]interface{})
+	require.Equal(t, true, listMap["success"])
+	assert.ElementsMatch(t, []interface{}{"vector_hnsw", "title_inverted"}, listMap["result"])
+
+	t.Run("drop_by_field_name", func(t *testing.T) {
+		dropMap := client.DropIndex("vector").(map[string]interface{})
+		require.Equal(t, true, dropMap["success"], dropMap["error"])
+		assert.Equal(t, []interface{}{"vector_hnsw"}, dropMap["result"].(map[string]interface{})["index_names"])
+
+		listMap := client.ListIndexes().(map[string]interface{})
+		assert.Equal(t, []interface{}{"title_inverted"}, listMap["result"])
+	})
+
+	t.Run("drop_by_index_name", func(t *testing.T) {
+		dropMap := client.DropIndex("title_inverted").(map[string]interface{})
+		require.Equal(t, true, dropMap["success"], dropMap["error"])
+
+		listMap := client.ListIndexes().(map[string]interface{})
+		assert.Equal(t, true, listMap["empty"])
+	})
+}
+
+func TestAlterIndexProperties_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, _, cleanup := setupTestClientWithoutIndex(t)
+	defer cleanup()
+
+	createResult := client.CreateIndex("vector", map[string]interface{}{
+		"indexType":  "HNSW",
+		"metricType": "L2",
+	})
+	require.Equal(t, true, createResult.(map[string]interface{})["success"])
+
+	t.Run("enable_mmap", func(t *testing.T) {
+		resultMap := client.AlterIndexProperties("vector", map[string]interface{}{"mmap.enabled": true}).(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+
+		describeMap := client.DescribeIndex("vector").(map[string]interface{})
+		require.Equal(t, true, describeMap["success"])
+		params := describeMap["result"].(map[string]interface{})["params"].(map[string]interface{})
+		assert.Equal(t, "true", params["mmap.enabled"])
+	})
+
+	t.Run("reset_mmap", func(t *testing.T) {
+		resultMap := client.DropIndexProperties("vector", []string{"mmap.enabled"}).(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+	})
+
+	t.Run("empty_properties", func(t *testing.T) {
+		resultMap := client.AlterIndexProperties("vector", map[string]interface{}{}).(map[string]interface{})
+		assert.Equal(t, false, resultMap["success"])
+	})
+}
+
+func TestCreateIndex_WaitTimeout_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, _, cleanup := setupTestClientWithoutIndex(t)
+	defer cleanup()
+
+	t.Run("invalid_wait_timeout", func(t *testing.T) {
+		resultMap := client.CreateIndex("vector", map[string]interface{}{
+			"indexType":   "FLAT",
+			"metricType":  "L2",
+			"waitTimeout": "soon",
+		}).(map[string]interface{})
+		assert.Equal(t, false, resultMap["success"])
+		assert.Contains(t, resultMap["error"], "invalid waitTimeout")
+	})
+
+	t.Run("wait_timeout_exceeded", func(t *testing.T) {
+		resultMap := client.CreateIndex("vector", map[string]interface{}{
+			"indexType":   "FLAT",
+			"metricType":  "L2",
+			"waitTimeout": "1ms",
+		}).(map[string]interface{})
+		assert.Equal(t, false, resultMap["success"])
+		assert.Contains(t, resultMap["error"], "not ready within 1ms")
+	})
+}
@@ -2,7 +2,11 @@ package milvus
 
 import (
 	"testing"
+	"time"
 
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,6 +20,7 @@ func TestBuildIndexScalarTypes(t *testing.T) {
 		{name: "inverted", indexType: "INVERTED", wantType: "INVERTED"},
 		{name: "stl sort", indexType: "STL_SORT", wantType: "STL_SORT"},
 		{name: "bitmap", indexType: "BITMAP", wantType: "BITMAP"},
+		{name: "trie", indexType: "Trie", wantType: "Trie"},
 	}
 
 	for _, tt := range tests {
@@ -61,3 +66,343 @@ func TestBuildIndexUnsupportedType(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported index type")
 }
+
+func TestBuildIndexSCANN(t *testing.T) {
+	idx, indexType, _, err := buildIndex(map[string]interface{}{
+		"indexType":  "SCANN",
+		"metricType": "IP",
+		"params": map[string]interface{}{
+			"nlist":         float64(256),
+			"with_raw_data": false,
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "SCANN", indexType)
+	assert.Equal(t, "SCANN", idx.Params()["index_type"])
+	assert.Equal(t, "IP", idx.Params()["metric_type"])
+	assert.Equal(t, "256", idx.Params()["nlist"])
+	assert.Equal(t, "false", idx.Params()["with_raw_data"])
+
+	idx, _, _, err = buildIndex(map[string]interface{}{
+		"indexType":   "SCANN",
+		"withRawData": true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1024", idx.Params()["nlist"])
+	assert.Equal(t, "true", idx.Params()["with_raw_data"])
+}
+
+func TestBuildIndexGPUTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     map[string]interface{}
+		wantParams map[string]string
+	}{
+		{
+			name: "cagra",
+			params: map[string]interface{}{
+				"indexType": "GPU_CAGRA",
+				"params": map[string]interface{}{
+					"intermediate_graph_degree": float64(64),
+					"graph_degree":              float64(32),
+					"build_algo":                "NN_DESCENT",
+				},
+			},
+			wantParams: map[string]string{
+				"index_type":                "GPU_CAGRA",
+				"intermediate_graph_degree": "64",
+				"graph_degree":              "32",
+				"build_algo":                "NN_DESCENT",
+			},
+		},
+		{
+			name:   "ivf flat",
+			params: map[string]interface{}{"indexType": "GPU_IVF_FLAT", "nlist": float64(512), "cache_dataset_on_device": true},
+			wantParams: map[string]string{
+				"index_type":              "GPU_IVF_FLAT",
+				"nlist":                   "512",
+				"cache_dataset_on_device": "true",
+			},
+		},
+		{
+			name:   "ivf pq",
+			params: map[string]interface{}{"indexType": "GPU_IVF_PQ", "m": float64(8)},
+			wantParams: map[string]string{
+				"index_type": "GPU_IVF_PQ",
+				"nlist":      "1024",
+				"m":          "8",
+				"nbits":      "8",
+			},
+		},
+		{
+			name:       "brute force",
+			params:     map[string]interface{}{"indexType": "GPU_BRUTE_FORCE"},
+			wantParams: map[string]string{"index_type": "GPU_BRUTE_FORCE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params["metricType"] = "L2"
+			idx, _, _, err := buildIndex(tt.params)
+
+			require.NoError(t, err)
+			got := idx.Params()
+			assert.Equal(t, "L2", got["metric_type"])
+			for key, want := range tt.wantParams {
+				assert.Equal(t, want, got[key], key)
+			}
+		})
+	}
+}
+
+func TestBuildIndexQuantizedHNSW(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     map[string]interface{}
+		wantParams map[string]string
+		absentKeys []string
+	}{
+		{
+			name: "hnsw sq",
+			params: map[string]interface{}{
+				"indexType": "HNSW_SQ",
+				"params": map[string]interface{}{
+					"M":           float64(32),
+					"sq_type":     "SQ6",
+					"refine":      true,
+					"refine_type": "FP16",
+				},
+			},
+			wantParams: map[string]string{
+				"index_type":     "HNSW_SQ",
+				"M":              "32",
+				"efConstruction": "200",
+				"sq_type":        "SQ6",
+				"refine":         "true",
+				"refine_type":    "FP16",
+			},
+		},
+		{
+			name:   "hnsw pq",
+			params: map[string]interface{}{"indexType": "hnsw_pq", "m": float64(16), "nbits": float64(8)},
+			wantParams: map[string]string{
+				"index_type": "HNSW_PQ",
+				"M":          "16",
+				"m":          "16",
+				"nbits":      "8",
+			},
+			absentKeys: []string{"refine", "sq_type"},
+		},
+		{
+			name:   "hnsw prq",
+			params: map[string]interface{}{"indexType": "HNSW_PRQ", "m": float64(16), "nrq": float64(2), "efConstruction": float64(360)},
+			wantParams: map[string]string{
+				"index_type":     "HNSW_PRQ",
+				"efConstruction": "360",
+				"m":              "16",
+				"nrq":            "2",
+			},
+			absentKeys: []string{"nbits"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params["metricType"] = "IP"
+			idx, _, _, err := buildIndex(tt.params)
+
+			require.NoError(t, err)
+			got := idx.Params()
+			assert.Equal(t, "IP", got["metric_type"])
+			for key, want := range tt.wantParams {
+				assert.Equal(t, want, got[key], key)
+			}
+			for _, key := range tt.absentKeys {
+				assert.NotContains(t, got, key)
+			}
+		})
+	}
+}
+
+func TestBuildIndexSparseTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     map[string]interface{}
+		wantType   string
+		wantMetric string
+		wantDrop   string
+	}{
+		{
+			name:       "inverted default metric",
+			params:     map[string]interface{}{"indexType": "SPARSE_INVERTED_INDEX", "params": map[string]interface{}{"drop_ratio_build": 0.2}},
+			wantType:   "SPARSE_INVERTED_INDEX",
+			wantMetric: "IP",
+			wantDrop:   "0.2",
+		},
+		{
+			name:       "wand bm25 with alias",
+			params:     map[string]interface{}{"indexType": "SPARSE_WAND", "metricType": "BM25", "dropRatio": 0.1},
+			wantType:   "SPARSE_WAND",
+			wantMetric: "BM25",
+			wantDrop:   "0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, _, _, err := buildIndex(tt.params)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, idx.Params()["index_type"])
+			assert.Equal(t, tt.wantMetric, idx.Params()["metric_type"])
+			assert.Equal(t, tt.wantDrop, idx.Params()["drop_ratio_build"])
+		})
+	}
+}
+
+func TestBuildIndexJSONPath(t *testing.T) {
+	idx, _, _, err := buildIndex(map[string]interface{}{
+		"indexType": "INVERTED",
+		"params": map[string]interface{}{
+			"json_path":      `meta["category"]`,
+			"json_cast_type": "varchar",
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "INVERTED", idx.Params()["index_type"])
+	assert.Equal(t, `meta["category"]`, idx.Params()["json_path"])
+	assert.Equal(t, "VARCHAR", idx.Params()["json_cast_type"])
+
+	_, _, _, err = buildIndex(map[string]interface{}{
+		"indexType": "STL_SORT",
+		"json_path": `meta["price"]`,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "json_cast_type required")
+}
+
+func TestIndexDescriptionMap(t *testing.T) {
+	desc := milvusclient.IndexDescription{
+		Index: index.NewGenericIndex("vector", map[string]string{
+			"index_type":  "HNSW",
+			"metric_type": "COSINE",
+			"params":      `{"M":16,"efConstruction":200}`,
+		}),
+		State:            index.IndexState(commonpb.IndexState_InProgress),
+		TotalRows:        1000,
+		IndexedRows:      400,
+		PendingIndexRows: 600,
+	}
+
+	result := indexDescriptionMap("vector", desc)
+
+	assert.Equal(t, "vector", result["index_name"])
+	assert.Equal(t, "HNSW", result["index_type"])
+	assert.Equal(t, "COSINE", result["metric_type"])
+	assert.Equal(t, "InProgress", result["state"])
+	assert.Equal(t, int64(1000), result["total_rows"])
+	assert.Equal(t, int64(400), result["indexed_rows"])
+	assert.Equal(t, int64(600), result["pending_rows"])
+}
+
+func TestIndexProgressMap(t *testing.T) {
+	t.Run("in progress", func(t *testing.T) {
+		result := indexProgressMap("meta", []string{"meta_a", "meta_b"}, []milvusclient.IndexDescription{
+			{State: index.IndexState(commonpb.IndexState_Finished), TotalRows: 100, IndexedRows: 100},
+			{State: index.IndexState(commonpb.IndexState_InProgress), TotalRows: 100, IndexedRows: 50, PendingIndexRows: 50},
+		})
+
+		assert.Equal(t, "InProgress", result["state"])
+		assert.InDelta(t, 0.75, result["progress"], 1e-9)
+		assert.Equal(t, int64(200), result["total_rows"])
+		assert.Equal(t, int64(50), result["pending_rows"])
+		assert.Equal(t, []string{"meta_a", "meta_b"}, result["index_names"])
+	})
+
+	t.Run("finished empty collection", func(t *testing.T) {
+		result := indexProgressMap("vector", []string{"vector"}, []milvusclient.IndexDescription{
+			{State: index.IndexState(commonpb.IndexState_Finished)},
+		})
+
+		assert.Equal(t, "Finished", result["state"])
+		assert.Equal(t, 1.0, result["progress"])
+	})
+}
+
+func TestBuildIndexExtraParams(t *testing.T) {
+	idx, indexType, _, err := buildIndex(map[string]interface{}{
+		"indexType":  "HNSW",
+		"metricType": "COSINE",
+		"params":     map[string]interface{}{"M": float64(32)},
+		"extraParams": map[string]interface{}{
+			"refine":       true,
+			"refine_type":  "FP16",
+			"mmap.enabled": "true",
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "HNSW", indexType)
+
+	params := idx.Params()
+	assert.Equal(t, "HNSW", params["index_type"])
+	assert.Equal(t, "COSINE", params["metric_type"])
+	assert.Equal(t, "32", params["M"])
+	assert.Equal(t, "true", params["refine"])
+	assert.Equal(t, "FP16", params["refine_type"])
+	assert.Equal(t, "true", params["mmap.enabled"])
+}
+
+func TestBuildIndexExtraParamsOverride(t *testing.T) {
+	idx, _, _, err := buildIndex(map[string]interface{}{
+		"indexType":    "SPARSE_INVERTED_INDEX",
+		"extra_params": map[string]interface{}{"drop_ratio_build": 0.3, "inverted_index_algo": "DAAT_MAXSCORE"},
+	})
+
+	require.NoError(t, err)
+	params := idx.Params()
+	assert.Equal(t, "SPARSE_INVERTED_INDEX", params["index_type"])
+	assert.Equal(t, "0.3", params["drop_ratio_build"])
+	assert.Equal(t, "DAAT_MAXSCORE", params["inverted_index_algo"])
+}
+
+func TestIndexProperties(t *testing.T) {
+	props := indexProperties(map[string]interface{}{
+		"mmap.enabled": true,
+		"ratio":        0.5,
+		"skipped":      nil,
+	})
+
+	assert.Equal(t, map[string]string{"mmap.enabled": "true", "ratio": "0.5"}, props)
+}
+
+func TestIndexWaitTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "unset", params: map[string]interface{}{}, want: 0},
+		{name: "duration string", params: map[string]interface{}{"waitTimeout": "30m"}, want: 30 * time.Minute},
+		{name: "seconds", params: map[string]interface{}{"wait_timeout": float64(90)}, want: 90 * time.Second},
+		{name: "fractional seconds", params: map[string]interface{}{"waitTimeout": 0.5}, want: 500 * time.Millisecond},
+		{name: "invalid string", params: map[string]interface{}{"waitTimeout": "soon"}, wantErr: true},
+		{name: "negative", params: map[string]interface{}{"waitTimeout": float64(-1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := indexWaitTimeout(tt.params)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
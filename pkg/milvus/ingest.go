@@ -0,0 +1,147 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// ingestPollInterval is the wait between checks of the rows inserted, in waitFor()
+const ingestPollInterval = 50 * time.Millisecond
+
+// Phases of the phase tag of query-while-ingest calls
+const (
+	ingestPhaseIngest       = "ingest"
+	ingestPhaseDuringIngest = "during_ingest"
+	ingestPhaseAfterIngest  = "after_ingest"
+)
+
+// QueryWhileIngestConfig configures milvus.queryWhileIngest()
+type QueryWhileIngestConfig struct {
+	Name       string `json:"name,omitempty"`       // Coordinators of the same name share their progress across VUs and scenarios (default: "default")
+	TargetRows int64  `json:"targetRows,omitempty"` // Rows after which ingest is done (default: until finish() is called)
+}
+
+// ingestProgress is the ingest progress shared by the coordinators of a name
+type ingestProgress struct {
+	target   int64
+	rows     atomic.Int64
+	finished atomic.Bool
+}
+
+// QueryWhileIngest coordinates a scenario that ingests into a collection with another that
+// searches it. Inserts through it add to a row count shared by every VU and scenario, which
+// searches can read or wait for. Every metric of its calls is tagged with phase: "ingest" for
+// inserts, and "during_ingest" or "after_ingest" for searches, depending on whether ingest was
+// done when the search started.
+//
+// Usage in k6:
+//
+//	const qwi = milvus.queryWhileIngest({ name: 'bench', targetRows: 1e6 });
+//	export function ingest() {
+//	    qwi.insert(client, { embedding: gen.next(1000) }, { collectionName: 'bench' });
+//	}
+//	export function query() {
+//	    qwi.waitFor(10000);
+//	    qwi.search(client, gen.next(1), 10, { vectorField: 'embedding' }, 'bench');
+//	}
+type QueryWhileIngest struct {
+	vu       modules.VU
+	progress *ingestProgress
+}
+
+// QueryWhileIngest returns the query-while-ingest coordinator of a name, shared by all VUs
+func (m *Milvus) QueryWhileIngest(configInput ...map[string]interface{}) (*QueryWhileIngest, error) {
+	var config QueryWhileIngestConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid query while ingest config: %v", err)
+		}
+	}
+	if config.TargetRows < 0 {
+		return nil, fmt.Errorf("query while ingest targetRows must not be negative, got %d", config.TargetRows)
+	}
+	if config.Name == "" {
+		config.Name = "default"
+	}
+	// The first coordinator of a name sets the target of all of them
+	progress, err := sharedValue(m.shared, "ingest\x00"+config.Name, func() (*ingestProgress, error) {
+		return &ingestProgress{target: config.TargetRows}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &QueryWhileIngest{vu: m.vu, progress: progress}, nil
+}
+
+// Insert inserts data with the client, as client.insert(), tagged with phase "ingest", and adds
+// the rows inserted to the shared progress. The result holds the rows ingested after the insert
+// in "rows_ingested".
+func (q *QueryWhileIngest) Insert(client *Client, data interface{}, args ...interface{}) interface{} {
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "insert requires a client"})
+	}
+	result := client.withTags(map[string]string{"phase": ingestPhaseIngest}).Insert(data, args...).(map[string]interface{})
+	// A partly failed insert still reports the rows of the chunks that were inserted
+	details, _ := result["result"].(map[string]interface{})
+	count, _ := details["insert_count"].(float64)
+	result["rows_ingested"] = q.progress.rows.Add(int64(count))
+	result["phase"] = ingestPhaseIngest
+	return result
+}
+
+// Search searches with the client, as client.search(), tagged with phase "during_ingest" or
+// "after_ingest". The result holds the rows ingested when the search started in
+// "rows_ingested".
+func (q *QueryWhileIngest) Search(client *Client, vectors interface{}, topK int, params map[string]interface{}, collectionName ...string) interface{} {
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "search requires a client"})
+	}
+	rows := q.progress.rows.Load()
+	phase := ingestPhaseDuringIngest
+	if q.Done() {
+		phase = ingestPhaseAfterIngest
+	}
+	result := client.withTags(map[string]string{"phase": phase}).Search(vectors, topK, params, collectionName...).(map[string]interface{})
+	result["rows_ingested"] = rows
+	result["phase"] = phase
+	return result
+}
+
+// Rows returns the rows inserted so far by all VUs
+func (q *QueryWhileIngest) Rows() int64 {
+	return q.progress.rows.Load()
+}
+
+// Fraction returns the share of targetRows inserted so far, at most 1, or 0 without a target
+func (q *QueryWhileIngest) Fraction() float64 {
+	if q.progress.target == 0 {
+		return 0
+	}
+	return min(1, float64(q.progress.rows.Load())/float64(q.progress.target))
+}
+
+// Done reports whether ingest is done: finish() was called or targetRows were inserted
+func (q *QueryWhileIngest) Done() bool {
+	return q.progress.finished.Load() || (q.progress.target > 0 && q.progress.rows.Load() >= q.progress.target)
+}
+
+// Finish marks ingest as done, for ingest without targetRows
+func (q *QueryWhileIngest) Finish() {
+	q.progress.finished.Store(true)
+}
+
+// WaitFor blocks until rows rows are inserted or ingest is done, and returns the rows inserted.
+// It returns early when the test ends.
+func (q *QueryWhileIngest) WaitFor(rows int64) int64 {
+	ctx := context.Background()
+	if q.vu != nil && q.vu.Context() != nil {
+		ctx = q.vu.Context()
+	}
+	for q.progress.rows.Load() < rows && !q.Done() && sleepContext(ctx, ingestPollInterval) {
+	}
+	return q.progress.rows.Load()
+}
@@ -0,0 +1,65 @@
+package milvus
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryWhileIngest(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := benchClient(t, &workloadServer{}, vu)
+
+	shared := &sync.Map{}
+	ingest, err := (&Milvus{vu: vu, shared: shared}).QueryWhileIngest(map[string]interface{}{"name": "bench", "targetRows": 4})
+	require.NoError(t, err)
+	query, err := (&Milvus{vu: vu, shared: shared}).QueryWhileIngest(map[string]interface{}{"name": "bench"})
+	require.NoError(t, err)
+
+	result := query.Search(client, [][]float32{{1, 0}}, 2, map[string]interface{}{"vectorField": "embedding"}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, "during_ingest", result["phase"])
+	assert.Equal(t, int64(0), result["rows_ingested"])
+
+	data := map[string]interface{}{"id": []int64{1, 2}, "embedding": [][]float32{{1, 0}, {0, 1}}}
+	result = ingest.Insert(client, data).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, int64(2), result["rows_ingested"])
+	assert.Equal(t, int64(2), query.Rows(), "progress is shared by name")
+	assert.Equal(t, 0.5, query.Fraction())
+	assert.Equal(t, int64(2), query.WaitFor(2))
+	assert.False(t, query.Done())
+
+	ingest.Insert(client, data)
+	assert.True(t, query.Done(), "targetRows inserted")
+	result = query.Search(client, [][]float32{{1, 0}}, 2, map[string]interface{}{"vectorField": "embedding"}).(map[string]interface{})
+	assert.Equal(t, "after_ingest", result["phase"])
+	assert.Equal(t, int64(4), query.WaitFor(100), "done ingest does not block")
+
+	// Phases of the RPCs of each method
+	phases := map[string]map[string]bool{"Insert": {}, "Search": {}}
+	for _, sample := range drainSamples(samples) {
+		method, _ := sample.Tags.Get("method")
+		if phase, ok := sample.Tags.Get("phase"); ok && phases[method] != nil && sample.Metric.Name == "milvus_errors" {
+			phases[method][phase] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"ingest": true}, phases["Insert"])
+	assert.Equal(t, map[string]bool{"during_ingest": true, "after_ingest": true}, phases["Search"])
+}
+
+func TestQueryWhileIngestFinish(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	q, err := m.QueryWhileIngest()
+	require.NoError(t, err)
+	assert.Zero(t, q.Fraction(), "no target")
+	assert.False(t, q.Done())
+	q.Finish()
+	assert.True(t, q.Done())
+	assert.Zero(t, q.WaitFor(10))
+
+	_, err = m.QueryWhileIngest(map[string]interface{}{"targetRows": -1})
+	assert.Error(t, err)
+}
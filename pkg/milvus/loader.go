@@ -0,0 +1,634 @@
+package milvus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// Loader formats
+const (
+	loaderFormatJSONL = "jsonl"
+	loaderFormatCSV   = "csv"
+)
+
+// defaultLoaderBatchSize is the number of rows returned by each next() call
+const defaultLoaderBatchSize = 1000
+
+// FileLoaderConfig configures client.fileLoader()
+type FileLoaderConfig struct {
+	CollectionName string            `json:"collectionName,omitempty"` // Collection whose schema types the batches (default: the client's collection)
+	Format         string            `json:"format,omitempty"`         // "jsonl" or "csv" (default: from the file extension)
+	BatchSize      int               `json:"batchSize,omitempty"`      // Rows per batch (default: 1000)
+	Fields         map[string]string `json:"fields,omitempty"`         // Schema field to file column; default reads every field under its own name
+	Delimiter      string            `json:"delimiter,omitempty"`      // CSV delimiter (default: "," and tab for .tsv files)
+}
+
+// FileLoader streams a JSONL or CSV file as insert batches typed by a collection schema.
+// Each batch maps field names to columns of the field's data type, so client.insert()
+// sends them without guessing types from JavaScript values.
+//
+// In CSV files, vector, sparse vector, array and JSON cells hold JSON text, e.g. "[0.1, 0.2]".
+//
+// Usage in k6:
+//
+//	const loader = client.fileLoader('data/products.jsonl', { batchSize: 500 });
+//	export default function () {
+//	    const batch = loader.next();
+//	    if (batch) client.insert(batch);
+//	}
+type FileLoader struct {
+	path    string
+	format  string
+	config  FileLoaderConfig
+	fields  []loaderField
+	file    *os.File
+	lines   *bufio.Reader // JSONL
+	records *csv.Reader   // CSV
+	header  map[string]int
+	line    int // Line of the last row read
+}
+
+// loaderField converts one file column to a schema field
+type loaderField struct {
+	field    *entity.Field
+	column   string
+	nullable bool // Missing and null values are sent as null
+	newBatch func() loaderColumn
+}
+
+// loaderColumn accumulates the values of one field for a batch
+type loaderColumn interface {
+	append(value interface{}) error
+	appendNull()
+	build(name string) (column.Column, error)
+}
+
+// FileLoader opens a JSONL or CSV file for batched inserts into a collection. The collection
+// schema is read once, and each value is converted to the type of its field. Auto ID primary
+// keys and function outputs are not read. Relative paths are resolved against the working
+// directory of the k6 process.
+func (c *Client) FileLoader(path string, configInput ...interface{}) (*FileLoader, error) {
+	var config FileLoaderConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid file loader config: %v", err)
+		}
+	}
+	coll := c.getCollectionName(config.CollectionName)
+	if coll == "" {
+		return nil, fmt.Errorf("collection name required")
+	}
+	collection, err := c.milvus().DescribeCollection(c.context(), milvusclient.NewDescribeCollectionOption(coll))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe collection %s: %v", coll, err)
+	}
+	path, err = localDataset(c.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	return openFileLoader(path, collection.Schema, config)
+}
+
+func openFileLoader(path string, schema *entity.Schema, config FileLoaderConfig) (*FileLoader, error) {
+	if config.BatchSize < 0 {
+		return nil, fmt.Errorf("file loader batchSize must not be negative")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultLoaderBatchSize
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	format := strings.ToLower(config.Format)
+	if format == "" {
+		format = loaderFormatJSONL
+		if ext == ".csv" || ext == ".tsv" {
+			format = loaderFormatCSV
+		}
+	}
+	if format != loaderFormatJSONL && format != loaderFormatCSV {
+		return nil, fmt.Errorf("file loader format must be jsonl or csv, got %q", config.Format)
+	}
+	if config.Delimiter == "" && ext == ".tsv" {
+		config.Delimiter = "\t"
+	}
+	if len([]rune(config.Delimiter)) > 1 {
+		return nil, fmt.Errorf("file loader delimiter must be a single character, got %q", config.Delimiter)
+	}
+
+	fields, err := loaderFields(schema, config.Fields)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	l := &FileLoader{path: path, format: format, config: config, fields: fields, file: file}
+	if err := l.Reset(); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// loaderFields resolves the schema fields to read and their columns
+func loaderFields(schema *entity.Schema, columns map[string]string) ([]loaderField, error) {
+	outputs := make(map[string]bool)
+	for _, function := range schema.Functions {
+		for _, name := range function.OutputFieldNames {
+			outputs[name] = true
+		}
+	}
+	byName := make(map[string]*entity.Field, len(schema.Fields))
+	for _, field := range schema.Fields {
+		byName[field.Name] = field
+	}
+	if len(columns) == 0 {
+		columns = make(map[string]string, len(schema.Fields))
+		for _, field := range schema.Fields {
+			if !(field.PrimaryKey && field.AutoID) && !outputs[field.Name] && !field.IsDynamic {
+				columns[field.Name] = field.Name
+			}
+		}
+	}
+
+	fields := make([]loaderField, 0, len(columns))
+	for name, col := range columns {
+		field, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found in collection %s", name, schema.CollectionName)
+		}
+		newBatch, err := loaderColumnFor(field)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, loaderField{
+			field:    field,
+			column:   col,
+			nullable: loaderNullable(field),
+			newBatch: newBatch,
+		})
+	}
+	slices.SortFunc(fields, func(a, b loaderField) int { return strings.Compare(a.field.Name, b.field.Name) })
+	return fields, nil
+}
+
+// loaderNullable reports whether missing and null values of a field are sent as null, which
+// Milvus replaces with the default value when there is one. Vectors are never null.
+func loaderNullable(field *entity.Field) bool {
+	return (field.Nullable || field.DefaultValue != nil) && !field.DataType.IsVectorType()
+}
+
+// loaderColumnFor returns the column builder for the data type of a field
+func loaderColumnFor(field *entity.Field) (func() loaderColumn, error) {
+	nullable := loaderNullable(field)
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		return scalarBuilder(nullable, parseLoaderBool, column.NewColumnBool, column.NewNullableColumnBool), nil
+	case entity.FieldTypeInt8:
+		return scalarBuilder(nullable, parseLoaderInt[int8](8), column.NewColumnInt8, column.NewNullableColumnInt8), nil
+	case entity.FieldTypeInt16:
+		return scalarBuilder(nullable, parseLoaderInt[int16](16), column.NewColumnInt16, column.NewNullableColumnInt16), nil
+	case entity.FieldTypeInt32:
+		return scalarBuilder(nullable, parseLoaderInt[int32](32), column.NewColumnInt32, column.NewNullableColumnInt32), nil
+	case entity.FieldTypeInt64:
+		return scalarBuilder(nullable, parseLoaderInt[int64](64), column.NewColumnInt64, column.NewNullableColumnInt64), nil
+	case entity.FieldTypeFloat:
+		return scalarBuilder(nullable, parseLoaderFloat[float32](32), column.NewColumnFloat, column.NewNullableColumnFloat), nil
+	case entity.FieldTypeDouble:
+		return scalarBuilder(nullable, parseLoaderFloat[float64](64), column.NewColumnDouble, column.NewNullableColumnDouble), nil
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		return scalarBuilder(nullable, parseLoaderString, column.NewColumnVarChar, column.NewNullableColumnVarChar), nil
+	case entity.FieldTypeJSON:
+		return scalarBuilder(nullable, parseLoaderJSON, column.NewColumnJSONBytes, column.NewNullableColumnJSONBytes), nil
+	case entity.FieldTypeArray:
+		return loaderArrayColumnFor(field, nullable)
+	case entity.FieldTypeFloatVector, entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector:
+		dim, err := field.GetDim()
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", field.Name, err)
+		}
+		dataType := field.DataType
+		return func() loaderColumn { return &vectorColumn{dataType: dataType, dim: int(dim)} }, nil
+	case entity.FieldTypeSparseVector:
+		return func() loaderColumn { return &sparseColumn{} }, nil
+	default:
+		return nil, fmt.Errorf("field %q has unsupported type %s", field.Name, field.DataType.Name())
+	}
+}
+
+func loaderArrayColumnFor(field *entity.Field, nullable bool) (func() loaderColumn, error) {
+	switch field.ElementType {
+	case entity.FieldTypeBool:
+		return scalarBuilder(nullable, parseLoaderArray(parseLoaderBool), column.NewColumnBoolArray, column.NewNullableColumnBoolArray), nil
+	case entity.FieldTypeInt8:
+		return scalarBuilder(nullable, parseLoaderArray(parseLoaderInt[int8](8)), column.NewColumnInt8Array, column.NewNullableColumnInt8Array), nil
+	case entity.FieldTypeInt16:
+		return scalarBuilder(nullable, parseLoaderArray(parseLoaderInt[int16](16)), column.NewColumnInt16Array, column.NewNullableColumnInt16Array), nil
+	case entity.FieldTypeInt32:
+		return scalarBuilder(nullable, parseLoaderArray(parseLoaderInt[int32](32)), column.NewColumnInt32Array, column.NewNullableColumnInt32Array), nil
+	case entity.FieldTypeInt64:
+		return scalarBuilder(nullable, parseLoaderArray(parseLoaderInt[int64](64)), column.NewColumnInt64Array, column.NewNullableColumnInt64Array), nil
+	case entity.FieldTypeFloat:
+		return scalarBuilder(nullable, parseLoaderArray(parseLoaderFloat[float32](32)), column.NewColumnFloatArray, column.NewNullableColumnFloatArray), nil
+	case entity.FieldTypeDouble:
+		return scalarBuilder(nullable, parseLoaderArray(parseLoaderFloat[float64](64)), column.NewColumnDoubleArray, column.NewNullableColumnDoubleArray), nil
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		return scalarBuilder(nullable, parseLoaderArray(parseLoaderString), column.NewColumnVarCharArray, column.NewNullableColumnVarCharArray), nil
+	default:
+		return nil, fmt.Errorf("field %q is an array of unsupported type %s", field.Name, field.ElementType.Name())
+	}
+}
+
+// Next returns the next batch as a map of field name to column, or null once the file is read
+func (l *FileLoader) Next() (map[string]interface{}, error) {
+	if l.file == nil {
+		return nil, fmt.Errorf("file loader %s is closed", l.path)
+	}
+	columns := make([]loaderColumn, len(l.fields))
+	for i, field := range l.fields {
+		columns[i] = field.newBatch()
+	}
+
+	rows := 0
+	for rows < l.config.BatchSize {
+		values, err := l.readRow()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", l.path, l.line, err)
+		}
+		for i, field := range l.fields {
+			if err := appendLoaderValue(columns[i], field, values); err != nil {
+				return nil, fmt.Errorf("%s line %d: %v", l.path, l.line, err)
+			}
+		}
+		rows++
+	}
+	if rows == 0 {
+		return nil, nil
+	}
+
+	batch := make(map[string]interface{}, len(l.fields))
+	for i, field := range l.fields {
+		col, err := columns[i].build(field.field.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: field %q: %v", l.path, field.field.Name, err)
+		}
+		batch[field.field.Name] = col
+	}
+	return batch, nil
+}
+
+// readRow returns the values of the next row by column name. CSV cells are strings.
+func (l *FileLoader) readRow() (map[string]interface{}, error) {
+	if l.format == loaderFormatCSV {
+		record, err := l.records.Read()
+		if err != nil {
+			return nil, err
+		}
+		l.line, _ = l.records.FieldPos(0)
+		values := make(map[string]interface{}, len(l.header))
+		for name, i := range l.header {
+			if i < len(record) {
+				values[name] = record[i]
+			}
+		}
+		return values, nil
+	}
+
+	for {
+		line, err := l.lines.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return nil, err
+		}
+		l.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		var values map[string]interface{}
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		return values, nil
+	}
+}
+
+// appendLoaderValue converts the value of a field's column and appends it to the batch
+func appendLoaderValue(col loaderColumn, field loaderField, values map[string]interface{}) error {
+	value, present := values[field.column]
+	if text, isCell := value.(string); isCell && present {
+		if text == "" && field.nullable {
+			value = nil
+		} else if value, present = csvCellValue(field.field, text); !present {
+			return fmt.Errorf("column %q: invalid JSON %q", field.column, text)
+		}
+	}
+	if !present || value == nil {
+		if !field.nullable {
+			return fmt.Errorf("column %q is missing or null", field.column)
+		}
+		col.appendNull()
+		return nil
+	}
+	if err := col.append(value); err != nil {
+		return fmt.Errorf("column %q: %v", field.column, err)
+	}
+	return nil
+}
+
+// csvCellValue decodes the JSON text of vector, array and JSON cells; other strings are
+// parsed by the field's converter. It reports false for invalid JSON.
+func csvCellValue(field *entity.Field, text string) (interface{}, bool) {
+	switch field.DataType {
+	case entity.FieldTypeJSON, entity.FieldTypeArray, entity.FieldTypeSparseVector,
+		entity.FieldTypeFloatVector, entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector:
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+		return value, true
+	default:
+		return text, true
+	}
+}
+
+// scalarBuilder returns a builder of columns of T, nullable when the field accepts nulls
+func scalarBuilder[T any, C column.Column](
+	nullable bool,
+	parse func(interface{}) (T, error),
+	create func(string, []T) C,
+	createNullable func(string, []T, []bool, ...column.ColumnOption[T]) (C, error),
+) func() loaderColumn {
+	return func() loaderColumn {
+		return &scalarColumn[T, C]{nullable: nullable, parse: parse, create: create, createNullable: createNullable}
+	}
+}
+
+// scalarColumn accumulates scalar or array values. Nullable columns hold only the valid values.
+type scalarColumn[T any, C column.Column] struct {
+	nullable       bool
+	parse          func(interface{}) (T, error)
+	create         func(string, []T) C
+	createNullable func(string, []T, []bool, ...column.ColumnOption[T]) (C, error)
+	values         []T
+	valid          []bool
+}
+
+func (s *scalarColumn[T, C]) append(value interface{}) error {
+	parsed, err := s.parse(value)
+	if err != nil {
+		return err
+	}
+	s.values = append(s.values, parsed)
+	s.valid = append(s.valid, true)
+	return nil
+}
+
+func (s *scalarColumn[T, C]) appendNull() {
+	s.valid = append(s.valid, false)
+}
+
+func (s *scalarColumn[T, C]) build(name string) (column.Column, error) {
+	if !s.nullable {
+		return s.create(name, s.values), nil
+	}
+	if s.values == nil {
+		s.values = []T{}
+	}
+	return s.createNullable(name, s.values, s.valid)
+}
+
+// vectorColumn accumulates dense vectors, converted to half precision for float16 fields
+type vectorColumn struct {
+	dataType entity.FieldType
+	dim      int
+	values   [][]float32
+}
+
+func (v *vectorColumn) append(value interface{}) error {
+	vector, err := parseLoaderArray(parseLoaderFloat[float32](32))(value)
+	if err != nil {
+		return err
+	}
+	if len(vector) != v.dim {
+		return fmt.Errorf("vector has dimension %d, expected %d", len(vector), v.dim)
+	}
+	v.values = append(v.values, vector)
+	return nil
+}
+
+func (v *vectorColumn) appendNull() {}
+
+func (v *vectorColumn) build(name string) (column.Column, error) {
+	switch v.dataType {
+	case entity.FieldTypeFloat16Vector:
+		return column.NewColumnFloat16VectorFromFp32Vector(name, v.dim, v.values), nil
+	case entity.FieldTypeBFloat16Vector:
+		return column.NewColumnBFloat16VectorFromFp32Vector(name, v.dim, v.values), nil
+	default:
+		return column.NewColumnFloatVector(name, v.dim, v.values), nil
+	}
+}
+
+// sparseColumn accumulates sparse vectors given as {"index": value} objects
+type sparseColumn struct {
+	values []entity.SparseEmbedding
+}
+
+func (s *sparseColumn) append(value interface{}) error {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("sparse vector must be an object of index to value, got %T", value)
+	}
+	positions := make([]uint32, 0, len(object))
+	for key := range object {
+		position, err := strconv.ParseUint(key, 10, 32)
+		if err != nil {
+			return fmt.Errorf("sparse vector index %q is not a non-negative integer", key)
+		}
+		positions = append(positions, uint32(position))
+	}
+	slices.Sort(positions)
+	values := make([]float32, len(positions))
+	for i, position := range positions {
+		v, err := parseLoaderFloat[float32](32)(object[strconv.FormatUint(uint64(position), 10)])
+		if err != nil {
+			return err
+		}
+		values[i] = v
+	}
+	embedding, err := entity.NewSliceSparseEmbedding(positions, values)
+	if err != nil {
+		return err
+	}
+	s.values = append(s.values, embedding)
+	return nil
+}
+
+func (s *sparseColumn) appendNull() {}
+
+func (s *sparseColumn) build(name string) (column.Column, error) {
+	return column.NewColumnSparseVectors(name, s.values), nil
+}
+
+// loaderNumber returns the text of a JSON number, or of a CSV cell or JSON string
+func loaderNumber(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return string(v), nil
+	case string:
+		return strings.TrimSpace(v), nil
+	default:
+		return "", fmt.Errorf("expected a number, got %T", value)
+	}
+}
+
+func parseLoaderInt[T int8 | int16 | int32 | int64](bits int) func(interface{}) (T, error) {
+	return func(value interface{}) (T, error) {
+		text, err := loaderNumber(value)
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.ParseInt(text, 10, bits)
+		if err != nil {
+			return 0, fmt.Errorf("invalid int%d %q", bits, text)
+		}
+		return T(n), nil
+	}
+}
+
+func parseLoaderFloat[T float32 | float64](bits int) func(interface{}) (T, error) {
+	return func(value interface{}) (T, error) {
+		text, err := loaderNumber(value)
+		if err != nil {
+			return 0, err
+		}
+		f, err := strconv.ParseFloat(text, bits)
+		if err != nil {
+			return 0, fmt.Errorf("invalid float %q", text)
+		}
+		return T(f), nil
+	}
+}
+
+func parseLoaderBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("invalid bool %q", v)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("expected a bool, got %T", value)
+	}
+}
+
+func parseLoaderString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("expected a string, got %T", value)
+	}
+}
+
+func parseLoaderJSON(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func parseLoaderArray[T any](parse func(interface{}) (T, error)) func(interface{}) ([]T, error) {
+	return func(value interface{}) ([]T, error) {
+		elements, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an array, got %T", value)
+		}
+		out := make([]T, len(elements))
+		for i, element := range elements {
+			v, err := parse(element)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %v", i, err)
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+}
+
+// Reset rewinds the loader to the first row
+func (l *FileLoader) Reset() error {
+	if l.file == nil {
+		return fmt.Errorf("file loader %s is closed", l.path)
+	}
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %v", l.path, err)
+	}
+	l.line = 0
+	if l.format == loaderFormatJSONL {
+		l.lines = bufio.NewReaderSize(l.file, 1<<20)
+		return nil
+	}
+
+	l.records = csv.NewReader(bufio.NewReaderSize(l.file, 1<<20))
+	if l.config.Delimiter != "" {
+		l.records.Comma = []rune(l.config.Delimiter)[0]
+	}
+	l.records.ReuseRecord = true
+	header, err := l.records.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header of %s: %v", l.path, err)
+	}
+	l.header = make(map[string]int, len(header))
+	for i, name := range header {
+		l.header[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, field := range l.fields {
+		if _, ok := l.header[field.column]; !ok && !field.nullable {
+			return fmt.Errorf("CSV file %s has no %q column", l.path, field.column)
+		}
+	}
+	return nil
+}
+
+// Fields returns the field names of each batch
+func (l *FileLoader) Fields() []string {
+	fields := make([]string, len(l.fields))
+	for i, field := range l.fields {
+		fields[i] = field.field.Name
+	}
+	return fields
+}
+
+// Close closes the file
+func (l *FileLoader) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
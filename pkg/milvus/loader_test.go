@@ -0,0 +1,247 @@
+package milvus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loaderSchema has an auto ID key, scalar, nullable, array, JSON and vector fields
+func loaderSchema() *entity.Schema {
+	return entity.NewSchema().WithName("products").
+		WithField(entity.NewField().WithName("pk").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true)).
+		WithField(entity.NewField().WithName("sku").WithDataType(entity.FieldTypeInt64)).
+		WithField(entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar).WithMaxLength(64)).
+		WithField(entity.NewField().WithName("stock").WithDataType(entity.FieldTypeInt16)).
+		WithField(entity.NewField().WithName("price").WithDataType(entity.FieldTypeFloat).WithNullable(true)).
+		WithField(entity.NewField().WithName("tags").WithDataType(entity.FieldTypeArray).WithElementType(entity.FieldTypeVarChar).WithMaxCapacity(4).WithMaxLength(16)).
+		WithField(entity.NewField().WithName("meta").WithDataType(entity.FieldTypeJSON)).
+		WithField(entity.NewField().WithName("embedding").WithDataType(entity.FieldTypeFloatVector).WithDim(3)).
+		WithField(entity.NewField().WithName("keywords").WithDataType(entity.FieldTypeSparseVector))
+}
+
+func writeLoaderFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const loaderJSONL = `{"sku": 1, "title": "lamp", "stock": 3, "price": 9.5, "tags": ["home"], "meta": {"color": "red"}, "embedding": [0.1, 0.2, 0.3], "keywords": {"7": 0.5, "2": 1}}
+{"sku": 9007199254740993, "title": "desk", "stock": "12", "price": null, "tags": [], "meta": [1, 2], "embedding": [1, 2, 3], "keywords": {}}
+
+{"sku": 3, "title": "chair", "stock": 0, "tags": ["office", "home"], "meta": null, "embedding": [0, 0, 1], "keywords": {"1": 0.25}}
+`
+
+// loaderColumnData returns the values of a scalar, array or vector column
+func loaderColumnData(t *testing.T, col interface{}) interface{} {
+	t.Helper()
+	switch c := col.(type) {
+	case *column.ColumnInt64:
+		return c.Data()
+	case *column.ColumnInt16:
+		return c.Data()
+	case *column.ColumnVarChar:
+		return c.Data()
+	case *column.ColumnFloat:
+		return c.Data()
+	case *column.ColumnVarCharArray:
+		return c.Data()
+	case *column.ColumnJSONBytes:
+		data := c.Data()
+		texts := make([]string, len(data))
+		for i, b := range data {
+			texts[i] = string(b)
+		}
+		return texts
+	case *column.ColumnFloatVector:
+		return c.Data()
+	}
+	t.Fatalf("unexpected column %T", col)
+	return nil
+}
+
+func TestFileLoaderJSONL(t *testing.T) {
+	schema := loaderSchema()
+	schema.Fields[6].Nullable = true // meta
+	path := writeLoaderFile(t, "products.jsonl", loaderJSONL)
+	loader, err := openFileLoader(path, schema, FileLoaderConfig{BatchSize: 2})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = loader.Close() })
+	assert.Equal(t, []string{"embedding", "keywords", "meta", "price", "sku", "stock", "tags", "title"}, loader.Fields())
+
+	batch, err := loader.Next()
+	require.NoError(t, err)
+	require.Len(t, batch, 8)
+	assert.Equal(t, []int64{1, 9007199254740993}, loaderColumnData(t, batch["sku"]))
+	assert.Equal(t, []string{"lamp", "desk"}, loaderColumnData(t, batch["title"]))
+	assert.Equal(t, []int16{3, 12}, loaderColumnData(t, batch["stock"]))
+	assert.Equal(t, []float32{9.5}, loaderColumnData(t, batch["price"])) // Only the valid values
+	assert.Equal(t, 2, batch["price"].(column.Column).Len())
+	isNull, err := batch["price"].(column.Column).IsNull(1)
+	require.NoError(t, err)
+	assert.True(t, isNull)
+	assert.Equal(t, [][]string{{"home"}, {}}, loaderColumnData(t, batch["tags"]))
+	assert.Equal(t, []string{`{"color":"red"}`, `[1,2]`}, loaderColumnData(t, batch["meta"]))
+	assert.Equal(t, []entity.FloatVector{{0.1, 0.2, 0.3}, {1, 2, 3}}, loaderColumnData(t, batch["embedding"]))
+	keywords := batch["keywords"].(*column.ColumnSparseFloatVector)
+	require.Equal(t, 2, keywords.Len())
+	first, err := keywords.Value(0)
+	require.NoError(t, err)
+	position, value, ok := first.Get(0)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(2), position)
+	assert.Equal(t, float32(1), value)
+
+	// The blank line is skipped; missing and null values of nullable fields are nulls
+	batch, err = loader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, loaderColumnData(t, batch["sku"]))
+	assert.Equal(t, 1, batch["price"].(column.Column).Len())
+	assert.Equal(t, 1, batch["meta"].(column.Column).Len())
+
+	batch, err = loader.Next()
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+
+	require.NoError(t, loader.Reset())
+	batch, err = loader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 9007199254740993}, loaderColumnData(t, batch["sku"]))
+
+	// Batches pass through the insert conversion unchanged
+	columns, err := (&Client{}).convertDataToColumns(batch)
+	require.NoError(t, err)
+	assert.Len(t, columns, 8)
+}
+
+func TestFileLoaderCSV(t *testing.T) {
+	path := writeLoaderFile(t, "products.csv", "\ufeffsku,name,stock,price,tags,meta,vec,keywords\n"+
+		`1,lamp,3,9.5,"[""home""]","{""color"": ""red""}","[0.1, 0.2, 0.3]","{""7"": 0.5}"`+"\n"+
+		`2,desk,12,,[],{},"[1,2,3]",{}`+"\n")
+	loader, err := openFileLoader(path, loaderSchema(), FileLoaderConfig{Fields: map[string]string{
+		"sku": "sku", "title": "name", "stock": "stock", "price": "price", "tags": "tags", "meta": "meta", "embedding": "vec", "keywords": "keywords",
+	}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = loader.Close() })
+
+	batch, err := loader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, loaderColumnData(t, batch["sku"]))
+	assert.Equal(t, []string{"lamp", "desk"}, loaderColumnData(t, batch["title"]))
+	assert.Equal(t, []float32{9.5}, loaderColumnData(t, batch["price"]))
+	assert.Equal(t, [][]string{{"home"}, {}}, loaderColumnData(t, batch["tags"]))
+	assert.Equal(t, []string{`{"color":"red"}`, `{}`}, loaderColumnData(t, batch["meta"]))
+	assert.Equal(t, []entity.FloatVector{{0.1, 0.2, 0.3}, {1, 2, 3}}, loaderColumnData(t, batch["embedding"]))
+
+	batch, err = loader.Next()
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+
+	// Tab separated files are detected by extension
+	tsv := writeLoaderFile(t, "products.tsv", "sku\ttitle\n7\tvase\n")
+	loader, err = openFileLoader(tsv, loaderSchema(), FileLoaderConfig{Fields: map[string]string{"sku": "sku", "title": "title"}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = loader.Close() })
+	batch, err = loader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vase"}, loaderColumnData(t, batch["title"]))
+}
+
+func TestFileLoaderErrors(t *testing.T) {
+	schema := loaderSchema()
+	jsonl := writeLoaderFile(t, "products.jsonl", loaderJSONL)
+
+	for _, config := range []FileLoaderConfig{
+		{BatchSize: -1},
+		{Format: "parquet"},
+		{Delimiter: ";;"},
+		{Fields: map[string]string{"missing": "missing"}},
+	} {
+		_, err := openFileLoader(jsonl, schema, config)
+		assert.Error(t, err, config)
+	}
+	_, err := openFileLoader(filepath.Join(t.TempDir(), "missing.jsonl"), schema, FileLoaderConfig{})
+	assert.Error(t, err)
+
+	// A CSV file without a required column fails on open
+	csvPath := writeLoaderFile(t, "products.csv", "sku\n1\n")
+	_, err = openFileLoader(csvPath, schema, FileLoaderConfig{Fields: map[string]string{"sku": "sku", "title": "title"}})
+	assert.ErrorContains(t, err, `no "title" column`)
+
+	// Conversion errors report the line
+	for _, content := range []string{
+		`{"sku": "one"}`,
+		`{"sku": 1, "stock": 70000}`,
+		`{"sku": 1, "embedding": [1, 2]}`,
+		`{"sku": 1, "keywords": {"a": 1}}`,
+		`{"sku": 1}`, // Missing embedding
+		`not json`,
+	} {
+		path := writeLoaderFile(t, "bad.jsonl", "\n"+content+"\n")
+		loader, err := openFileLoader(path, schema, FileLoaderConfig{Fields: map[string]string{
+			"sku": "sku", "stock": "stock", "embedding": "embedding", "keywords": "keywords",
+		}})
+		require.NoError(t, err)
+		_, err = loader.Next()
+		assert.ErrorContains(t, err, "line 2", content)
+		require.NoError(t, loader.Close())
+	}
+}
+
+// loaderServer describes a products collection and records the inserted field data
+type loaderServer struct {
+	milvuspb.UnimplementedMilvusServiceServer
+	mu       sync.Mutex
+	inserted []*schemapb.FieldData
+}
+
+func (s *loaderServer) DescribeCollection(context.Context, *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error) {
+	return &milvuspb.DescribeCollectionResponse{
+		Status:         &commonpb.Status{},
+		CollectionName: "products",
+		CollectionID:   1,
+		Schema:         loaderSchema().ProtoMessage(),
+	}, nil
+}
+
+func (s *loaderServer) Insert(_ context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inserted = req.GetFieldsData()
+	return &milvuspb.MutationResult{
+		Status:    &commonpb.Status{},
+		InsertCnt: int64(req.GetNumRows()),
+		IDs:       &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: make([]int64, req.GetNumRows())}}},
+	}, nil
+}
+
+func TestClientFileLoader(t *testing.T) {
+	service := &loaderServer{}
+	client := fakeClient(t, &Milvus{vu: &metricsVU{}}, service, WithCollection("products"))
+
+	loader, err := client.FileLoader(writeLoaderFile(t, "products.jsonl", loaderJSONL), map[string]interface{}{"batchSize": 2})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = loader.Close() })
+	batch, err := loader.Next()
+	require.NoError(t, err)
+
+	result := client.Insert(batch).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	types := make(map[string]schemapb.DataType)
+	for _, data := range service.inserted {
+		types[data.GetFieldName()] = data.GetType()
+	}
+	assert.Equal(t, schemapb.DataType_Int16, types["stock"])
+	assert.Equal(t, schemapb.DataType_Float, types["price"])
+	assert.Equal(t, schemapb.DataType_SparseFloatVector, types["keywords"])
+}
@@ -0,0 +1,126 @@
+package milvus
+
+import (
+	"runtime"
+	runtimemetrics "runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// EnvMemoryMetrics set to "true" emits the milvus_memory Gauge: the memory held by the extension
+// for datasets, prepared batches and typed search results, next to the Go heap of the k6 process
+const EnvMemoryMetrics = "MILVUS_MEMORY_METRICS"
+
+// memoryMetricsInterval is the least time between two milvus_memory updates, across all VUs
+const memoryMetricsInterval = time.Second
+
+// heapObjectsMetric is the runtime metric of the live and unswept objects of the Go heap, which
+// holds the JS heap of every VU as well as the extension's memory
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// memorySizer is implemented by the datasets that report the memory they hold
+type memorySizer interface {
+	memorySize() int64
+}
+
+// memoryUsage counts the memory held by the extension across all VUs, for milvus_memory.
+// Datasets are sized when the gauge is emitted; other memory is counted while it is reachable.
+type memoryUsage struct {
+	batches atomic.Int64 // Columns of prepared batches
+	results atomic.Int64 // Typed arrays returned by typedResults searches
+	emitted atomic.Int64 // Time of the last milvus_memory update, in Unix nanoseconds
+}
+
+// holdMemory adds bytes to a counter until obj is garbage collected
+func holdMemory[T any](counter *atomic.Int64, obj *T, bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+	counter.Add(bytes)
+	runtime.AddCleanup(obj, func(bytes int64) { counter.Add(-bytes) }, bytes)
+}
+
+// holdBuffer adds the bytes of a buffer to a counter until the buffer is garbage collected
+func holdBuffer(counter *atomic.Int64, data []byte) {
+	if len(data) > 0 {
+		holdMemory(counter, &data[0], int64(len(data)))
+	}
+}
+
+// trackedMemory returns the test-wide memory counters, or nil when milvus_memory is not emitted
+func (c *Client) trackedMemory() *memoryUsage {
+	if c.memory == nil || c.metrics == nil || c.metrics.Memory == nil {
+		return nil
+	}
+	return c.memory
+}
+
+// addMemorySamples adds the milvus_memory samples to a batch, at most once per
+// memoryMetricsInterval across all VUs
+func (c *Client) addMemorySamples(samples *sampleBatch) {
+	usage := c.trackedMemory()
+	if usage == nil || samples.state == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := usage.emitted.Load()
+	if now-last < int64(memoryMetricsInterval) || !usage.emitted.CompareAndSwap(last, now) {
+		return
+	}
+	samples.add(c.metrics.Memory, float64(datasetMemory(c.shared)), map[string]string{"kind": "datasets"})
+	samples.add(c.metrics.Memory, float64(usage.batches.Load()), map[string]string{"kind": "prepared_batches"})
+	samples.add(c.metrics.Memory, float64(usage.results.Load()), map[string]string{"kind": "results"})
+	samples.add(c.metrics.Memory, float64(heapMemory()), map[string]string{"kind": "heap"})
+}
+
+// datasetMemory returns the memory held by the loaded datasets
+func datasetMemory(shared *sync.Map) int64 {
+	if shared == nil {
+		return 0
+	}
+	var total int64
+	shared.Range(func(_, value any) bool {
+		entry := value.(*sharedEntry)
+		if !entry.loaded.Load() {
+			return true
+		}
+		if sizer, ok := entry.value.(memorySizer); ok {
+			total += sizer.memorySize()
+		}
+		return true
+	})
+	return total
+}
+
+// heapMemory returns the bytes of the Go heap in use
+func heapMemory() int64 {
+	sample := []runtimemetrics.Sample{{Name: heapObjectsMetric}}
+	runtimemetrics.Read(sample)
+	if sample[0].Value.Kind() != runtimemetrics.KindUint64 {
+		return 0
+	}
+	return int64(sample[0].Value.Uint64())
+}
+
+// matrixBytes returns the bytes of the values of a matrix
+func matrixBytes[T any](matrix [][]T) int64 {
+	var zero T
+	var values int
+	for _, row := range matrix {
+		values += len(row)
+	}
+	return int64(values) * int64(unsafe.Sizeof(zero))
+}
+
+// batchMemory estimates the memory of prepared columns by the size of their data on the wire
+func (b *PreparedBatch) batchMemory() int64 {
+	var total int64
+	for _, col := range b.columns {
+		total += int64(proto.Size(col.FieldData()))
+	}
+	return total
+}
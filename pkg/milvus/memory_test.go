@@ -0,0 +1,78 @@
+package milvus
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasetMemory(t *testing.T) {
+	var shared sync.Map
+	_, err := sharedValue(&shared, "vectors\x00base", func() (*SharedVectors, error) {
+		return &SharedVectors{vectors: newMatrix[float32](10, 4)}, nil
+	})
+	require.NoError(t, err)
+	_, err = zipfTableFor(&shared, 100, 1)
+	require.NoError(t, err)
+	// Entries without a size, and entries still loading, are not counted
+	_, err = sharedValue(&shared, "ids", func() (*atomic.Int64, error) { return &atomic.Int64{}, nil })
+	require.NoError(t, err)
+	shared.Store("loading", &sharedEntry{})
+
+	assert.Equal(t, int64(10*4*4+100*8), datasetMemory(&shared))
+	assert.Zero(t, datasetMemory(nil))
+}
+
+func TestHoldMemory(t *testing.T) {
+	var counter atomic.Int64
+	func() {
+		holdBuffer(&counter, make([]byte, 1024))
+		holdBuffer(&counter, nil)
+		batch, err := newPreparedBatch("bench", []column.Column{column.NewColumnInt64("id", []int64{1, 2, 3})}, nil, 0)
+		require.NoError(t, err)
+		holdMemory(&counter, batch, batch.batchMemory())
+		assert.Greater(t, counter.Load(), int64(1024))
+	}()
+
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return counter.Load() == 0
+	}, 5*time.Second, 10*time.Millisecond, "memory is released once garbage collected")
+}
+
+func TestMemorySamples(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	usage := &memoryUsage{}
+	usage.batches.Store(100)
+
+	// Off by default
+	client := &Client{vu: vu, metrics: registerMetrics(vu), memory: usage}
+	assert.Nil(t, client.trackedMemory())
+	batch := client.sampleBatch()
+	client.addMemorySamples(batch)
+	assert.Empty(t, batch.samples)
+
+	t.Setenv(EnvMemoryMetrics, "true")
+	client.metrics = registerMetrics(vu)
+	batch = client.sampleBatch()
+	client.addMemorySamples(batch)
+	client.addMemorySamples(batch) // Within memoryMetricsInterval
+	batch.push()
+
+	values := make(map[string]float64)
+	for _, sample := range drainSamples(samples) {
+		assert.Equal(t, "milvus_memory", sample.Metric.Name)
+		kind, _ := sample.Tags.Get("kind")
+		values[kind] = sample.Value
+	}
+	require.Len(t, values, 4)
+	assert.Equal(t, float64(100), values["prepared_batches"])
+	assert.Zero(t, values["datasets"])
+	assert.Greater(t, values["heap"], float64(0))
+}
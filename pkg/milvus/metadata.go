@@ -0,0 +1,83 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// WithHeaders returns a client that shares this client's connection and attaches the given
+// gRPC metadata to every call, on top of the headers already set on this client.
+// Use it for per-call headers such as tenant routing keys or trace IDs.
+//
+// Usage in k6:
+//
+//	const client = milvus.clientWithConfig({ address, headers: { 'x-tenant-id': 'acme' } });
+//	client.withHeaders({ 'x-trace-id': `${exec.vu.idInTest}-${exec.vu.iterationInScenario}` })
+//	    .search(vectors, 10, { vectorField: 'embedding' });
+func (c *Client) WithHeaders(headers map[string]string) (*Client, error) {
+	if err := validateHeaders(headers); err != nil {
+		return nil, err
+	}
+
+	scoped := *c
+	scoped.base = c.root()
+	scoped.headers = make(map[string]string, len(c.headers)+len(headers))
+	maps.Copy(scoped.headers, c.headers)
+	maps.Copy(scoped.headers, normalizeHeaders(headers))
+	return &scoped, nil
+}
+
+// root returns the client that owns the connection
+func (c *Client) root() *Client {
+	if c.base != nil {
+		return c.base
+	}
+	return c
+}
+
+// withMetadata attaches the client's headers to the outgoing gRPC metadata
+func (c *Client) withMetadata(ctx context.Context) context.Context {
+	if len(c.headers) == 0 {
+		return ctx
+	}
+	pairs := make([]string, 0, 2*len(c.headers))
+	for key, value := range c.headers {
+		pairs = append(pairs, key, value)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// validateHeaders rejects header names gRPC would refuse or that are reserved for gRPC itself
+func validateHeaders(headers map[string]string) error {
+	for key := range headers {
+		name := strings.ToLower(key)
+		if name == "" {
+			return fmt.Errorf("invalid header: empty name")
+		}
+		if strings.HasPrefix(name, "grpc-") {
+			return fmt.Errorf("invalid header %q: grpc- prefix is reserved", key)
+		}
+		for _, r := range name {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' && r != '.' {
+				return fmt.Errorf("invalid header %q: only letters, digits, '-', '_' and '.' are allowed", key)
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeHeaders lowercases header names, as gRPC metadata keys are case-insensitive
+func normalizeHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	normalized := make(map[string]string, len(headers))
+	for key, value := range headers {
+		normalized[strings.ToLower(key)] = value
+	}
+	return normalized
+}
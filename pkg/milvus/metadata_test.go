@@ -0,0 +1,57 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValidateHeaders(t *testing.T) {
+	assert.NoError(t, validateHeaders(nil))
+	assert.NoError(t, validateHeaders(map[string]string{"X-Tenant-ID": "acme", "trace_id.v1": "abc"}))
+
+	for _, name := range []string{"", "grpc-timeout", "x tenant", "x-tenant:id"} {
+		assert.Error(t, validateHeaders(map[string]string{name: "v"}), name)
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	config := DefaultClientConfig()
+	config.Headers = map[string]string{"X-Tenant-ID": "acme", "x-env": "staging"}
+	client, err := (&Milvus{}).wrapClient(nil, config)
+	require.NoError(t, err)
+
+	scoped, err := client.WithHeaders(map[string]string{"X-Trace-ID": "t1", "x-env": "canary"})
+	require.NoError(t, err)
+	assert.Same(t, client, scoped.root())
+
+	md, ok := metadata.FromOutgoingContext(scoped.context())
+	require.True(t, ok)
+	assert.Equal(t, []string{"acme"}, md.Get("x-tenant-id"))
+	assert.Equal(t, []string{"t1"}, md.Get("x-trace-id"))
+	assert.Equal(t, []string{"canary"}, md.Get("x-env"))
+
+	// The base client keeps its own headers
+	md, ok = metadata.FromOutgoingContext(client.context())
+	require.True(t, ok)
+	assert.Empty(t, md.Get("x-trace-id"))
+	assert.Equal(t, []string{"staging"}, md.Get("x-env"))
+
+	// Derived clients chain to the client owning the connection
+	nested, err := scoped.WithHeaders(map[string]string{"x-request-id": "r1"})
+	require.NoError(t, err)
+	assert.Same(t, client, nested.root())
+	assert.NoError(t, nested.Close())
+
+	_, err = client.WithHeaders(map[string]string{"grpc-status": "0"})
+	assert.Error(t, err)
+}
+
+func TestContextWithoutHeaders(t *testing.T) {
+	client := &Client{ctx: context.Background()}
+	_, ok := metadata.FromOutgoingContext(client.context())
+	assert.False(t, ok)
+}
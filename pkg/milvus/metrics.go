@@ -0,0 +1,304 @@
+package milvus
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// EnvDisableMetrics set to "true" turns off every metric emitted by the extension, for all VUs
+// and clients, leaving only k6's built-in metrics
+const EnvDisableMetrics = "MILVUS_DISABLE_METRICS"
+
+// milvusMetrics holds the custom k6 metrics emitted by the extension
+type milvusMetrics struct {
+	IndexRebuildDuration *metrics.Metric
+	IndexBuildProgress   *metrics.Metric
+	IndexBuildDuration   *metrics.Metric
+	ConnectDuration      *metrics.Metric
+	Connections          *metrics.Metric
+	Reconnects           *metrics.Metric
+	ConnectionState      *metrics.Metric
+	Errors               *metrics.Metric
+	ErrorTypes           *metrics.Metric
+	RateLimited          *metrics.Metric
+	DataSize             *metrics.Metric
+	DataSent             *metrics.Metric
+	DataReceived         *metrics.Metric
+	InflightRequests     *metrics.Metric
+	Rows                 *metrics.Metric
+	Recall               *metrics.Metric
+	Precision            *metrics.Metric
+	NDCG                 *metrics.Metric
+	MRR                  *metrics.Metric
+	ServerMetric         *metrics.Metric
+	LoadDuration         *metrics.Metric
+	ReleaseDuration      *metrics.Metric
+	BatchSize            *metrics.Metric
+	InsertChunkDuration  *metrics.Metric
+	SearchHits           *metrics.Metric
+	Segments             *metrics.Metric
+	SegmentRows          *metrics.Metric
+	SegmentMemory        *metrics.Metric
+	ConsistencyFailures  *metrics.Metric
+	ChurnDeletedRatio    *metrics.Metric
+	TTLExpiryLag         *metrics.Metric
+	AliasFlipErrors      *metrics.Metric
+	Memory               *metrics.Metric // nil unless MILVUS_MEMORY_METRICS is set
+}
+
+// registerMetrics registers the custom metrics with the k6 registry.
+// It returns nil when no init environment is available (e.g. in tests),
+// in which case metric emission is skipped. It also returns nil when MILVUS_DISABLE_METRICS is set.
+func registerMetrics(vu modules.VU) *milvusMetrics {
+	if vu == nil || vu.InitEnv() == nil || vu.InitEnv().Registry == nil {
+		return nil
+	}
+	if disabled, _ := strconv.ParseBool(os.Getenv(EnvDisableMetrics)); disabled {
+		return nil
+	}
+	registry := vu.InitEnv().Registry
+
+	m := &milvusMetrics{
+		IndexRebuildDuration: registry.MustNewMetric("milvus_index_rebuild_duration", metrics.Trend, metrics.Time),
+		IndexBuildProgress:   registry.MustNewMetric("milvus_index_build_progress", metrics.Gauge),
+		IndexBuildDuration:   registry.MustNewMetric("milvus_index_build_duration", metrics.Trend, metrics.Time),
+		ConnectDuration:      registry.MustNewMetric("milvus_connect_duration", metrics.Trend, metrics.Time),
+		Connections:          registry.MustNewMetric("milvus_connections", metrics.Gauge),
+		Reconnects:           registry.MustNewMetric("milvus_reconnects", metrics.Counter),
+		ConnectionState:      registry.MustNewMetric("milvus_connection_state", metrics.Gauge),
+		Errors:               registry.MustNewMetric("milvus_errors", metrics.Rate),
+		ErrorTypes:           registry.MustNewMetric("milvus_error_types", metrics.Counter),
+		RateLimited:          registry.MustNewMetric("milvus_rate_limited", metrics.Counter),
+		DataSize:             registry.MustNewMetric("milvus_data_size", metrics.Counter, metrics.Data),
+		DataSent:             registry.MustNewMetric("milvus_data_sent", metrics.Counter, metrics.Data),
+		DataReceived:         registry.MustNewMetric("milvus_data_received", metrics.Counter, metrics.Data),
+		InflightRequests:     registry.MustNewMetric("milvus_inflight_requests", metrics.Gauge),
+		Rows:                 registry.MustNewMetric("milvus_rows", metrics.Counter),
+		Recall:               registry.MustNewMetric("milvus_recall", metrics.Trend),
+		Precision:            registry.MustNewMetric("milvus_precision", metrics.Trend),
+		NDCG:                 registry.MustNewMetric("milvus_ndcg", metrics.Trend),
+		MRR:                  registry.MustNewMetric("milvus_mrr", metrics.Trend),
+		ServerMetric:         registry.MustNewMetric("milvus_server_metric", metrics.Gauge),
+		LoadDuration:         registry.MustNewMetric("milvus_load_duration", metrics.Trend, metrics.Time),
+		ReleaseDuration:      registry.MustNewMetric("milvus_release_duration", metrics.Trend, metrics.Time),
+		BatchSize:            registry.MustNewMetric("milvus_batch_size", metrics.Trend),
+		InsertChunkDuration:  registry.MustNewMetric("milvus_insert_chunk_duration", metrics.Trend, metrics.Time),
+		SearchHits:           registry.MustNewMetric("milvus_search_hits", metrics.Trend),
+		Segments:             registry.MustNewMetric("milvus_segments", metrics.Gauge),
+		SegmentRows:          registry.MustNewMetric("milvus_segment_rows", metrics.Gauge),
+		SegmentMemory:        registry.MustNewMetric("milvus_segment_memory", metrics.Gauge, metrics.Data),
+		ConsistencyFailures:  registry.MustNewMetric("milvus_consistency_failures", metrics.Counter),
+		ChurnDeletedRatio:    registry.MustNewMetric("milvus_churn_deleted_ratio", metrics.Gauge),
+		TTLExpiryLag:         registry.MustNewMetric("milvus_ttl_expiry_lag", metrics.Trend, metrics.Time),
+		AliasFlipErrors:      registry.MustNewMetric("milvus_alias_flip_errors", metrics.Counter),
+	}
+	if enabled, _ := strconv.ParseBool(os.Getenv(EnvMemoryMetrics)); enabled {
+		m.Memory = registry.MustNewMetric("milvus_memory", metrics.Gauge, metrics.Data)
+	}
+	return m
+}
+
+// qualityMetric returns the Trend for a ranking quality metric selected with qualityMetrics
+func (m *milvusMetrics) qualityMetric(name string) *metrics.Metric {
+	if m == nil {
+		return nil
+	}
+	switch name {
+	case "precision":
+		return m.Precision
+	case "ndcg":
+		return m.NDCG
+	case "mrr":
+		return m.MRR
+	}
+	return nil
+}
+
+// pushMetric emits a sample for the given metric on the client's VU, adding the client's
+// per-call tags. Tags set by the extension itself take precedence over per-call tags.
+func (c *Client) pushMetric(metric *metrics.Metric, value float64, tags map[string]string) {
+	pushMetric(c.vu, metric, value, c.sampleTags(tags))
+}
+
+// sampleTags adds the client's per-call tags to the tags of a sample
+func (c *Client) sampleTags(tags map[string]string) map[string]string {
+	if len(c.tags) == 0 {
+		return tags
+	}
+	merged := maps.Clone(c.tags)
+	maps.Copy(merged, tags)
+	return merged
+}
+
+// sampleBatch collects the samples of one call on the client's VU, to push them together as
+// one ConnectedSamples instead of one send on the VU's samples channel each
+type sampleBatch struct {
+	client  *Client
+	state   *lib.State // nil outside of a running VU, where samples are dropped
+	samples []metrics.Sample
+}
+
+// sampleBatch returns an empty batch of samples for the client's VU
+func (c *Client) sampleBatch() *sampleBatch {
+	b := &sampleBatch{client: c}
+	if c.vu != nil {
+		if state := c.vu.State(); state != nil && state.Samples != nil && state.Tags != nil {
+			b.state = state
+		}
+	}
+	return b
+}
+
+// add buffers a sample for the given metric, tagged like pushMetric() tags it
+func (b *sampleBatch) add(metric *metrics.Metric, value float64, tags map[string]string) {
+	if metric == nil || b.state == nil {
+		return
+	}
+	b.samples = append(b.samples, newSample(b.state, metric, value, b.client.sampleTags(tags)))
+}
+
+// push emits the buffered samples, all stamped with the current time, and empties the batch
+func (b *sampleBatch) push() {
+	if len(b.samples) == 0 {
+		return
+	}
+	now := time.Now()
+	for i := range b.samples {
+		b.samples[i].Time = now
+	}
+	metrics.PushIfNotDone(vuContext(b.client.vu), b.state.Samples, metrics.ConnectedSamples{
+		Samples: b.samples,
+		Tags:    b.state.Tags.GetCurrentValues().Tags,
+		Time:    now,
+	})
+	b.samples = nil
+}
+
+// withTags returns a client that shares this client's connection and adds the given tags to
+// every sample emitted by its calls. It returns c itself when there are no tags.
+func (c *Client) withTags(tags map[string]string) *Client {
+	if len(tags) == 0 {
+		return c
+	}
+	scoped := *c
+	scoped.base = c.root()
+	scoped.tags = maps.Clone(c.tags)
+	if scoped.tags == nil {
+		scoped.tags = make(map[string]string, len(tags))
+	}
+	maps.Copy(scoped.tags, tags)
+	return &scoped
+}
+
+// withTargetTags returns a client whose samples are tagged with the partitions a call targets
+// and the index type of the searched field, when known. Unlike per-call tags, they cannot be
+// overridden by the script.
+func (c *Client) withTargetTags(partitions []string, indexType string) *Client {
+	tags := make(map[string]string, 2)
+	if len(partitions) > 0 {
+		tags["partition"] = strings.Join(partitions, ",")
+	}
+	if indexType != "" {
+		tags["index_type"] = indexType
+	}
+	return c.withTags(tags)
+}
+
+// indexType returns the type of the index on a collection field, for the index_type tag.
+// The index is described on first use and cached per VU, including failures, so only the
+// first search on a field pays for the lookup. The lookup is not instrumented, so it adds no
+// samples. It is empty when metrics are disabled, the field has no index or the index cannot
+// be described.
+func (c *Client) indexType(coll, field string) string {
+	if c.metrics == nil {
+		return ""
+	}
+	root := c.root()
+	key := coll + "/" + field
+	if indexType, cached := root.indexTypes[key]; cached {
+		return indexType
+	}
+
+	var indexType string
+	ctx := c.uninstrumentedContext()
+	names, err := c.milvus().ListIndexes(ctx, milvusclient.NewListIndexOption(coll).WithFieldName(field))
+	if err == nil && len(names) > 0 {
+		desc, describeErr := c.milvus().DescribeIndex(ctx, milvusclient.NewDescribeIndexOption(coll, names[0]))
+		if describeErr == nil && desc.Index != nil {
+			indexType = desc.Params()[index.IndexTypeKey]
+		}
+	}
+	root.rememberIndexType(coll, field, indexType)
+	return indexType
+}
+
+// rememberIndexType caches the index type of a collection field for the index_type tag
+func (c *Client) rememberIndexType(coll, field, indexType string) {
+	root := c.root()
+	if root.indexTypes == nil {
+		root.indexTypes = make(map[string]string)
+	}
+	root.indexTypes[coll+"/"+field] = indexType
+}
+
+// tagsOption reads the per-call metric tags from the "tags" option, e.g. { dataset: 'sift1m' }
+func tagsOption(options map[string]interface{}) (map[string]string, error) {
+	value, ok := options["tags"]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("tags must be an object of name/value pairs, got %T", value)
+	}
+	tags := make(map[string]string, len(raw))
+	for key, val := range raw {
+		if key == "" {
+			return nil, fmt.Errorf("tags must not have an empty name")
+		}
+		tags[key] = fmt.Sprint(val)
+	}
+	return tags, nil
+}
+
+// pushMetric emits a sample for the given metric, tagged with the VU's current tags plus the extra tags.
+// It is a no-op outside of a running VU (init context, tests without state).
+func pushMetric(vu modules.VU, metric *metrics.Metric, value float64, tags map[string]string) {
+	if metric == nil || vu == nil {
+		return
+	}
+	state := vu.State()
+	if state == nil || state.Samples == nil || state.Tags == nil {
+		return
+	}
+	metrics.PushIfNotDone(vuContext(vu), state.Samples, newSample(state, metric, value, tags))
+}
+
+// newSample returns a sample for the given metric, tagged with the VU's current tags plus the extra tags
+func newSample(state *lib.State, metric *metrics.Metric, value float64, tags map[string]string) metrics.Sample {
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	tagSet := tagsAndMeta.Tags
+	for key, val := range tags {
+		tagSet = tagSet.With(key, val)
+	}
+	return metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: metric,
+			Tags:   tagSet,
+		},
+		Time:     time.Now(),
+		Metadata: tagsAndMeta.Metadata,
+		Value:    value,
+	}
+}
@@ -0,0 +1,203 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+func TestRegisterMetricsWithoutInitEnv(t *testing.T) {
+	assert.Nil(t, registerMetrics(nil))
+	assert.Nil(t, registerMetrics(&metricsVU{}))
+}
+
+func TestDisableMetrics(t *testing.T) {
+	vu, _ := newMetricsVU(t)
+	m := &Milvus{vu: vu, metrics: registerMetrics(vu)}
+	require.NotNil(t, m.metrics)
+
+	config := DefaultClientConfig()
+	client, err := m.wrapClient(nil, config)
+	require.NoError(t, err)
+	assert.NotNil(t, client.metrics)
+	config.DisableMetrics = true
+	client, err = m.wrapClient(nil, config)
+	require.NoError(t, err)
+	assert.Nil(t, client.metrics)
+
+	t.Setenv(EnvDisableMetrics, "true")
+	assert.Nil(t, registerMetrics(vu))
+}
+
+func TestPushMetric(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	state := vu.state
+	vu.state = nil
+	m := registerMetrics(vu)
+	require.NotNil(t, m)
+	require.NotNil(t, m.IndexRebuildDuration)
+
+	client := &Client{vu: vu, metrics: m}
+
+	// No VU state yet (init context): emission is skipped
+	client.pushMetric(m.IndexRebuildDuration, 1, nil)
+
+	state.Tags = lib.NewVUStateTags(vu.initEnv.Registry.RootTagSet().With("scenario", "default"))
+	vu.state = state
+
+	client.pushMetric(m.IndexRebuildDuration, metrics.D(1500*time.Millisecond), map[string]string{"collection": "docs"})
+
+	require.Len(t, samples, 1)
+	sample := (<-samples).(metrics.Sample)
+	assert.Equal(t, "milvus_index_rebuild_duration", sample.Metric.Name)
+	assert.Equal(t, 1500.0, sample.Value)
+
+	tags := sample.Tags.Map()
+	assert.Equal(t, "docs", tags["collection"])
+	assert.Equal(t, "default", tags["scenario"])
+}
+
+func TestSampleBatch(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	state := vu.state
+	vu.state = nil
+	client := &Client{vu: vu, metrics: registerMetrics(vu), tags: map[string]string{"phase": "steady"}}
+
+	// No VU state yet (init context): samples are dropped
+	batch := client.sampleBatch()
+	batch.add(client.metrics.Rows, 1, nil)
+	batch.push()
+
+	vu.state = state
+	batch = client.sampleBatch()
+	batch.add(client.metrics.Rows, 10, map[string]string{"operation": "insert"})
+	batch.add(client.metrics.BatchSize, 10, map[string]string{"operation": "insert"})
+	batch.add(nil, 1, nil)
+	assert.Empty(t, samples, "samples wait for push")
+	batch.push()
+	batch.push()
+
+	require.Len(t, samples, 1, "one send per batch")
+	container, ok := (<-samples).(metrics.ConnectedSamples)
+	require.True(t, ok)
+	require.Len(t, container.Samples, 2)
+	assert.Equal(t, "milvus_rows", container.Samples[0].Metric.Name)
+	assert.Equal(t, map[string]string{"operation": "insert", "phase": "steady"}, container.Samples[1].Tags.Map())
+	assert.Equal(t, container.Time, container.Samples[1].Time)
+}
+
+// drainSamples returns the samples buffered in the channel, in order
+func drainSamples(samples chan metrics.SampleContainer) []metrics.Sample {
+	var all []metrics.Sample
+	for len(samples) > 0 {
+		all = append(all, (<-samples).GetSamples()...)
+	}
+	return all
+}
+
+func TestPushMetricWithTags(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := &Client{vu: vu, metrics: registerMetrics(vu)}
+
+	assert.Same(t, client, client.withTags(nil))
+
+	scoped := client.withTags(map[string]string{"dataset": "sift1m", "operation": "ignored"})
+	assert.Same(t, client, scoped.root())
+	assert.Empty(t, client.tags)
+
+	nested := scoped.withTags(map[string]string{"phase": "steady"})
+	assert.Same(t, client, nested.root())
+	assert.Len(t, scoped.tags, 2)
+
+	nested.pushMetric(nested.metrics.Rows, 10, map[string]string{"operation": "insert"})
+	client.pushMetric(client.metrics.Rows, 5, map[string]string{"operation": "insert"})
+
+	require.Len(t, samples, 2)
+	tags := (<-samples).(metrics.Sample).Tags.Map()
+	assert.Equal(t, "sift1m", tags["dataset"])
+	assert.Equal(t, "steady", tags["phase"])
+	assert.Equal(t, "insert", tags["operation"])
+
+	tags = (<-samples).(metrics.Sample).Tags.Map()
+	assert.NotContains(t, tags, "dataset")
+}
+
+func TestTagsOption(t *testing.T) {
+	tags, err := tagsOption(nil)
+	require.NoError(t, err)
+	assert.Nil(t, tags)
+
+	tags, err = tagsOption(map[string]interface{}{"tags": map[string]interface{}{"dataset": "sift1m", "run": int64(3)}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"dataset": "sift1m", "run": "3"}, tags)
+
+	_, err = tagsOption(map[string]interface{}{"tags": "phase=steady"})
+	assert.Error(t, err)
+	_, err = tagsOption(map[string]interface{}{"tags": map[string]interface{}{"": "x"}})
+	assert.Error(t, err)
+}
+
+func TestInsertInvalidTags(t *testing.T) {
+	client := &Client{defaultCollection: "docs"}
+	result := client.Insert(map[string]interface{}{}, map[string]interface{}{"tags": []interface{}{"steady"}}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "invalid tags")
+}
+
+func TestWithTargetTags(t *testing.T) {
+	client := &Client{}
+	assert.Same(t, client, client.withTargetTags(nil, ""))
+
+	scoped := client.withTags(map[string]string{"partition": "mine", "phase": "steady"}).withTargetTags([]string{"2024", "2025"}, "HNSW")
+	assert.Same(t, client, scoped.root())
+	assert.Equal(t, map[string]string{"partition": "2024,2025", "index_type": "HNSW", "phase": "steady"}, scoped.tags)
+}
+
+func TestIndexTypeCache(t *testing.T) {
+	vu, _ := newMetricsVU(t)
+	client := &Client{vu: vu, metrics: registerMetrics(vu)}
+	scoped := client.withTags(map[string]string{"phase": "steady"})
+
+	// Types recorded through a derived client are cached on the client owning the connection
+	scoped.rememberIndexType("docs", "embedding", "IVF_FLAT")
+	assert.Equal(t, "IVF_FLAT", client.indexType("docs", "embedding"))
+	scoped.rememberIndexType("docs", "sparse", "")
+	assert.Empty(t, client.indexType("docs", "sparse"))
+
+	// No lookups without metrics
+	assert.Empty(t, (&Client{}).indexType("docs", "embedding"))
+}
+
+// indexServer describes an HNSW index on the embedding field of every collection
+type indexServer struct {
+	milvuspb.UnimplementedMilvusServiceServer
+}
+
+func (indexServer) DescribeIndex(context.Context, *milvuspb.DescribeIndexRequest) (*milvuspb.DescribeIndexResponse, error) {
+	return &milvuspb.DescribeIndexResponse{
+		Status: &commonpb.Status{},
+		IndexDescriptions: []*milvuspb.IndexDescription{{
+			IndexName: "embedding_idx",
+			FieldName: "embedding",
+			Params:    []*commonpb.KeyValuePair{{Key: "index_type", Value: "HNSW"}},
+		}},
+	}, nil
+}
+
+func TestIndexTypeLookup(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := fakeClient(t, &Milvus{vu: vu, metrics: registerMetrics(vu)}, indexServer{})
+	drainSamples(samples)
+
+	assert.Equal(t, "HNSW", client.indexType("docs", "embedding"))
+	assert.Empty(t, client.indexType("docs", "sparse"))
+	// The lookups are not operations of the script, so they emit nothing
+	assert.Empty(t, drainSamples(samples))
+}
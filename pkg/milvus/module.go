@@ -1,6 +1,9 @@
 package milvus
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"go.k6.io/k6/js/modules"
 )
 
@@ -15,22 +18,49 @@ var (
 )
 
 // RootModule is the global module instance that creates module instances for each VU
-type RootModule struct{}
+type RootModule struct {
+	pool        clientPool       // gRPC connections shared across VUs
+	connections atomic.Int64     // Open gRPC connections across all VUs, for milvus_connections
+	inflight    atomic.Int64     // RPCs in progress across all VUs, for milvus_inflight_requests
+	collectors  sync.Map         // Running server metrics collectors, by URL
+	summary     operationSummary // Per-operation totals for milvus.summary()
+	shared      sync.Map         // Datasets and coordination state of the test, by key, see sharedValue()
+	memory      memoryUsage      // Memory held by the extension, for milvus_memory
+	resources   resourceRegistry // Resources created through clients, for milvus.cleanup()
+}
 
 // Milvus represents the JS module instance for each VU
 type Milvus struct {
 	vu          modules.VU
 	clients     map[string]*Client     // VU-level gRPC client cache
 	restClients map[string]*RestClient // VU-level REST client cache
+	pool        *clientPool            // Test-wide shared connection pool
+	connections *atomic.Int64          // Test-wide open connection count
+	inflight    *atomic.Int64          // Test-wide in-progress RPC count
+	collectors  *sync.Map              // Test-wide server metrics collectors
+	summary     *operationSummary      // Test-wide per-operation totals
+	shared      *sync.Map              // Test-wide datasets and coordination state
+	memory      *memoryUsage           // Test-wide memory held by the extension
+	resources   *resourceRegistry      // Test-wide resources created through clients
+	metrics     *milvusMetrics
 }
 
 // NewModuleInstance implements the modules.Module interface
 // It creates a new instance of the Milvus module for each VU
-func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+func (r *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 	return &Milvus{
 		vu:          vu,
 		clients:     make(map[string]*Client),
 		restClients: make(map[string]*RestClient),
+		pool:        &r.pool,
+		connections: &r.connections,
+		inflight:    &r.inflight,
+		collectors:  &r.collectors,
+		summary:     &r.summary,
+		shared:      &r.shared,
+		memory:      &r.memory,
+		resources:   &r.resources,
+		metrics:     registerMetrics(vu),
 	}
 }
 
@@ -42,10 +72,78 @@ func (m *Milvus) Exports() modules.Exports {
 		Named: map[string]interface{}{
 			"client":                   m.Client,
 			"clientWithCollection":     m.ClientWithCollection,
-			"getClient":                m.GetClient,     // VU-level cached gRPC client
+			"clientWithConfig":         m.ClientWithConfig,
+			"getClient":                m.GetClient,          // VU-level cached gRPC client
+			"getSharedClient":          m.GetSharedClient,    // gRPC client from the test-wide pool
+			"closeSharedClients":       m.CloseSharedClients, // Teardown closing the test-wide pool
 			"restClient":               m.RestClient,
 			"restClientWithCollection": m.RestClientWithCollection,
 			"getRestClient":            m.GetRestClient, // VU-level cached REST client
+			"schema":                   m.Schema,
+			"collectServerMetrics":     m.CollectServerMetrics, // Background scrape of Milvus Prometheus metrics
+			"summary":                  m.Summary,              // Per-operation totals for handleSummary
+			"vectorGenerator":          m.VectorGenerator,      // Clustered vector generator
+			"dataFaker":                m.DataFaker,            // Scalar field values with controllable distributions
+			"zipfGenerator":            m.ZipfGenerator,        // Zipfian integers for hot keys
+			"seed":                     m.Seed,                 // Generator seed of a VU and iteration, for regenerating data
+			"pacer":                    m.Pacer,                // Constant-rate waits between operations, per VU or test-wide
+			"mixedWorkload":            m.MixedWorkload,        // Weighted search, insert and delete, one operation per run
+			"selectivitySweep":         m.SelectivitySweep,     // Filters of chosen selectivities over dataFaker fields, with selectivity-tagged searches
+			"queryLog":                 m.QueryLog,             // Replay of recorded queries with their own params, in order or shuffled, at a rate
+			"churnWorkload":            m.ChurnWorkload,        // Upsert and delete cycles keeping a fraction of the keys deleted
+			"collectionSet":            m.CollectionSet,        // N collections of one schema, handed out round robin with metrics tagged by collection
+			"tenantSimulator":          m.TenantSimulator,      // VUs mapped to tenant databases, partition keys or collections, with tenant_bucket tags
+			"ttlProbe":                 m.TTLProbe,             // TTL collections, timestamped inserts and the lag between expected and observed expiry
+			"queryWhileIngest":         m.QueryWhileIngest,     // Ingest progress shared with searching scenarios, with phase-tagged metrics
+			"checkpoint":               m.Checkpoint,           // Persisted ingest progress and primary key ranges, resumed by a restarted run
+			"aliasReindex":             m.AliasReindex,         // Build, fill and load a new collection, flip an alias to it and count failed searches during the flip
+			"runId":                    m.RunID,                // Run ID stored on the collections the test creates
+			"cleanup":                  m.Cleanup,              // Teardown dropping the resources the test created
+			"barrier":                  m.Barrier,              // Named barrier or flag that VUs wait on between test phases
+			"vdbbenchPreset":           m.VDBBenchPreset,       // Dataset, schema, filter and scenarios of a VectorDBBench case
+			"parquetReader":            m.ParquetReader,        // Batched inserts from a Parquet file
+			"arrowReader":              m.ArrowReader,          // Record batches of an Arrow IPC or Parquet file, inserted without conversion
+			"vectorStream":             m.VectorStream,         // Batches of an fvecs, bvecs or npy file with bounded memory
+			"textCorpus":               m.TextCorpus,           // Batched documents of a text or JSONL corpus, for full-text search
+			"sparseReader":             m.SparseReader,         // Batched sparse vectors of a libsvm file
+			"annDataset":               m.AnnDataset,           // ann-benchmarks HDF5 dataset, loaded once per test
+			"sharedVectors":            m.SharedVectors,        // Vectors from a file or function, loaded once per test
+			"groundTruth":              m.GroundTruth,          // Query neighbors from an ivecs, npy or Parquet file, loaded once per test
+			"computeGroundTruth":       m.ComputeGroundTruth,   // Exact neighbors by parallel brute force
+			"insertSample":             m.InsertSample,         // Reservoir sample of inserted vectors, to search for them
+			"embedder":                 m.Embedder,             // Cached text embeddings from an OpenAI-compatible endpoint
+			"normalize":                m.Normalize,            // Unit-length vectors, as for COSINE
+			"cosineSimilarity":         m.CosineSimilarity,     // COSINE score of two vectors
+			"l2Distance":               m.L2Distance,           // L2 score (squared distance) of two vectors
+			"innerProduct":             m.InnerProduct,         // IP score of two vectors
 		},
 	}
 }
+
+// sharedEntry is a value shared by all VUs, loaded once
+type sharedEntry struct {
+	once   sync.Once
+	value  interface{}
+	err    error
+	loaded atomic.Bool // Set once value and err are, for readers not going through once
+}
+
+// sharedValue returns the value stored under key in the test-wide store, loading it on the first
+// call of the test: a dataset, or state that VUs coordinate through such as barriers and
+// counters. Without a store, as in unit tests, it is loaded on every call.
+func sharedValue[T any](shared *sync.Map, key string, load func() (T, error)) (T, error) {
+	if shared == nil {
+		return load()
+	}
+	value, _ := shared.LoadOrStore(key, &sharedEntry{})
+	entry := value.(*sharedEntry)
+	entry.once.Do(func() {
+		entry.value, entry.err = load()
+		entry.loaded.Store(true)
+	})
+	if entry.err != nil {
+		var zero T
+		return zero, entry.err
+	}
+	return entry.value.(T), nil
+}
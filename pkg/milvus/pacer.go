@@ -0,0 +1,109 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// PacerConfig configures milvus.pacer()
+type PacerConfig struct {
+	// Pacers of the same name and rate share one schedule across all VUs, so the rate is for the
+	// whole test; unnamed pacers pace their VU only
+	Name string `json:"name,omitempty"`
+}
+
+// Pacer spaces operations at a constant rate. Each wait() sleeps until the next slot of a fixed
+// schedule on the monotonic clock, so the latency of the calls between waits is absorbed
+// instead of added to the interval. A caller behind schedule does not wait, and the schedule
+// restarts from it rather than bursting to catch up. This generates smoother open-model load
+// than arrival-rate executors when single calls are heavy.
+//
+// Usage in k6:
+//
+//	const pacer = milvus.pacer(50, { name: 'search' }); // 50 searches per second across all VUs
+//	export default function () {
+//	    pacer.wait();
+//	    client.search(gen.next(1), 10, { vectorField: 'embedding' });
+//	}
+type Pacer struct {
+	vu       modules.VU
+	schedule *pacerSchedule
+}
+
+// pacerSchedule is the slots of a pacer, shared by the VUs of a named pacer
+type pacerSchedule struct {
+	interval time.Duration
+	start    time.Time    // Monotonic origin of the slots
+	next     atomic.Int64 // Next free slot, in nanoseconds since start
+}
+
+// Pacer creates a pacer of qps operations per second
+func (m *Milvus) Pacer(qps float64, configInput ...interface{}) (*Pacer, error) {
+	var config PacerConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid pacer config: %v", err)
+		}
+	}
+	if qps <= 0 || math.IsNaN(qps) || math.IsInf(qps, 0) {
+		return nil, fmt.Errorf("pacer qps must be a positive number, got %v", qps)
+	}
+	interval := time.Duration(float64(time.Second) / qps)
+	if interval <= 0 {
+		return nil, fmt.Errorf("pacer qps %v is too high", qps)
+	}
+
+	newSchedule := func() (*pacerSchedule, error) {
+		return &pacerSchedule{interval: interval, start: time.Now()}, nil
+	}
+	if config.Name == "" {
+		schedule, _ := newSchedule()
+		return &Pacer{vu: m.vu, schedule: schedule}, nil
+	}
+	key := "pacer\x00" + config.Name + "\x00" + strconv.FormatFloat(qps, 'g', -1, 64)
+	schedule, err := sharedValue(m.shared, key, newSchedule)
+	if err != nil {
+		return nil, err
+	}
+	return &Pacer{vu: m.vu, schedule: schedule}, nil
+}
+
+// Wait blocks until the next slot of the schedule and returns the time waited, in milliseconds.
+// It returns early when the test ends.
+func (p *Pacer) Wait() float64 {
+	ctx := context.Background()
+	if p.vu != nil && p.vu.Context() != nil {
+		ctx = p.vu.Context()
+	}
+	return float64(p.schedule.wait(ctx)) / float64(time.Millisecond)
+}
+
+// Interval returns the time between two slots, in milliseconds
+func (p *Pacer) Interval() float64 {
+	return float64(p.schedule.interval) / float64(time.Millisecond)
+}
+
+// wait reserves the next slot and sleeps until it. A timer firing late delays that wait only:
+// slots are fixed offsets from the start, so the rate does not drift.
+func (s *pacerSchedule) wait(ctx context.Context) time.Duration {
+	var slot, now time.Duration
+	for {
+		next := s.next.Load()
+		now = time.Since(s.start)
+		slot = max(time.Duration(next), now)
+		if s.next.CompareAndSwap(next, int64(slot+s.interval)) {
+			break
+		}
+	}
+	if slot <= now {
+		return 0
+	}
+	sleepContext(ctx, slot-now)
+	return time.Since(s.start) - now
+}
@@ -0,0 +1,61 @@
+package milvus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacer(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	pacer, err := m.Pacer(200)
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, pacer.Interval())
+
+	start := time.Now()
+	assert.Zero(t, pacer.Wait(), "the first slot is immediate")
+	for i := 0; i < 4; i++ {
+		pacer.Wait()
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	// Call latency is absorbed by the schedule, and a late caller does not wait
+	time.Sleep(3 * time.Millisecond)
+	assert.Less(t, pacer.Wait(), 3.0)
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, pacer.Wait())
+
+	for _, qps := range []float64{0, -1, 1e10} {
+		_, err = m.Pacer(qps)
+		assert.Error(t, err, qps)
+	}
+}
+
+func TestPacerShared(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	first, err := m.Pacer(100, map[string]interface{}{"name": "search"})
+	require.NoError(t, err)
+	second, err := (&Milvus{shared: m.shared}).Pacer(100, map[string]interface{}{"name": "search"})
+	require.NoError(t, err)
+	other, err := m.Pacer(100)
+	require.NoError(t, err)
+	assert.Same(t, first.schedule, second.schedule)
+	assert.NotSame(t, first.schedule, other.schedule)
+
+	// Two VUs draw alternate slots of one schedule
+	assert.Zero(t, first.Wait())
+	assert.Greater(t, second.Wait(), 5.0)
+	assert.Zero(t, other.Wait())
+}
+
+func TestPacerCanceled(t *testing.T) {
+	schedule := &pacerSchedule{interval: time.Hour, start: time.Now()}
+	schedule.wait(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Less(t, schedule.wait(ctx), time.Second)
+}
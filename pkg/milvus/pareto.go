@@ -0,0 +1,250 @@
+package milvus
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultParetoTopK are the topK values of a Pareto sweep without topK
+var defaultParetoTopK = []int{10}
+
+// ParetoSweepConfig configures client.paretoSweep()
+type ParetoSweepConfig struct {
+	CollectionName string                   `json:"collectionName,omitempty"` // Default: the collection of the client
+	SearchParams   []map[string]interface{} `json:"searchParams"`             // Index search params of each configuration, e.g. { ef: 64 }
+	TopK           []int                    `json:"topK,omitempty"`           // topK values crossed with searchParams (default: 10)
+	Params         map[string]interface{}   `json:"params,omitempty"`         // search() params shared by every configuration
+	Rounds         int                      `json:"rounds,omitempty"`         // Passes over the queries per configuration (default: 1)
+}
+
+// paretoPoint is the measurement of one configuration of a Pareto sweep
+type paretoPoint struct {
+	label   string // Tag value, e.g. "ef=64,topK=10"
+	params  map[string]interface{}
+	topK    int
+	pass    *segmentPass
+	recalls []float64 // Recall of each successful search
+	elapsed time.Duration
+}
+
+// ParetoSweep searches the query set with every combination of index search params and topK,
+// measuring the latency and the recall against the ground truth of each combination, so the
+// latency-recall trade-off of an index, and its Pareto frontier, comes from a single script.
+// Each query is searched on its own, so every search is one latency sample. Every metric of
+// the searches, milvus_recall included, is tagged with pareto_config, e.g. "ef=64,topK=10",
+// so thresholds and outputs can split them per configuration. The result holds a point per
+// configuration, in sweep order, with its recall, latency statistics and throughput, and
+// frontier: the labels of the points no other point beats on both mean latency and recall,
+// fastest first.
+//
+// Usage in k6:
+//
+//	export function setup() {
+//	    const ds = milvus.annDataset('sift-128-euclidean.hdf5');
+//	    const client = milvus.client('localhost:19530', 'sift');
+//	    const result = client.paretoSweep({
+//	        queries: ds.test(0, 100),
+//	        groundTruth: ds.neighbors(0, 100),
+//	        searchParams: [{ ef: 16 }, { ef: 64 }, { ef: 256 }],
+//	        topK: [10, 100],
+//	        params: { vectorField: 'embedding', metricType: 'L2' },
+//	    });
+//	    console.log(JSON.stringify(result.result.frontier));
+//	}
+func (c *Client) ParetoSweep(configInput map[string]interface{}) interface{} {
+	start := time.Now()
+	fail := func(err string) interface{} {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err,
+		})
+	}
+
+	var config ParetoSweepConfig
+	if err := convertViaJSON(splitModuleObjects(configInput, "queries", "groundTruth", "queryIds"), &config); err != nil {
+		return fail(fmt.Sprintf("invalid pareto sweep config: %v", err))
+	}
+	coll := c.getCollectionName(config.CollectionName)
+	if coll == "" {
+		return fail(ErrCollectionNameRequired.Error())
+	}
+	queries, err := segmentCompareQueries(configInput["queries"])
+	if err != nil {
+		return fail(err.Error())
+	}
+	if configInput["groundTruth"] == nil {
+		return fail("groundTruth required")
+	}
+	truth, err := groundTruthOption(map[string]interface{}{"groundTruth": configInput["groundTruth"], "queryIds": configInput["queryIds"]}, len(queries))
+	if err != nil {
+		return fail(fmt.Sprintf("invalid groundTruth: %v", err))
+	}
+	if len(config.SearchParams) == 0 {
+		return fail("searchParams must list at least one set of search params")
+	}
+	if len(config.TopK) == 0 {
+		config.TopK = defaultParetoTopK
+	}
+	for _, topK := range config.TopK {
+		if topK <= 0 {
+			return fail(fmt.Sprintf("topK must be positive, got %d", topK))
+		}
+	}
+	rounds := optionalPositive(config.Rounds, 1)
+
+	// Per-query ground truth, passed to search() with each query
+	queryTruths := make([][]interface{}, len(truth))
+	for i, t := range truth {
+		if t.keys != nil {
+			keys := make([]interface{}, len(t.keys))
+			for j, key := range t.keys {
+				keys[j] = key
+			}
+			queryTruths[i] = []interface{}{keys}
+		} else {
+			queryTruths[i] = []interface{}{t.ints}
+		}
+	}
+
+	var points []*paretoPoint
+	for _, searchParams := range config.SearchParams {
+		for _, topK := range config.TopK {
+			point := &paretoPoint{label: paretoLabel(searchParams, topK), params: searchParams, topK: topK}
+			c.searchParetoPoint(point, queries, queryTruths, config.Params, rounds, coll)
+			points = append(points, point)
+		}
+	}
+
+	frontier := paretoFrontier(points)
+	results := make([]map[string]interface{}, len(points))
+	failed := 0
+	for i, point := range points {
+		results[i] = point.toMap(slices.Contains(frontier, point.label))
+		failed += point.pass.errors
+	}
+	errMsg := ""
+	if failed > 0 {
+		errMsg = fmt.Sprintf("%d searches failed", failed)
+	}
+	return toMap(&OperationResult{
+		Success:      failed == 0,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Error:        errMsg,
+		Result: map[string]interface{}{
+			"collection": coll,
+			"points":     results,
+			"frontier":   frontier,
+		},
+	})
+}
+
+// paretoLabel names a configuration by its search params, in key order, and its topK
+func paretoLabel(searchParams map[string]interface{}, topK int) string {
+	parts := make([]string, 0, len(searchParams)+1)
+	for _, key := range slices.Sorted(maps.Keys(searchParams)) {
+		parts = append(parts, key+"="+searchParamValue(searchParams[key]))
+	}
+	return strings.Join(append(parts, "topK="+strconv.Itoa(topK)), ",")
+}
+
+// searchParetoPoint searches each query on its own, rounds times, with the search params of the
+// point merged over the shared ones and metrics tagged with the point's label
+func (c *Client) searchParetoPoint(point *paretoPoint, queries [][]float32, truths [][]interface{}, shared map[string]interface{}, rounds int, coll string) {
+	params := make(map[string]interface{}, len(shared)+2)
+	for key, value := range shared {
+		params[key] = value
+	}
+	nested := make(map[string]interface{})
+	if base, ok := shared["params"].(map[string]interface{}); ok {
+		for key, value := range base {
+			nested[key] = value
+		}
+	}
+	for key, value := range point.params {
+		nested[key] = value
+	}
+	params["params"] = nested
+
+	client := c.withTags(map[string]string{"pareto_config": point.label})
+	point.pass = &segmentPass{}
+	sweepStart := time.Now()
+	for round := 0; round < rounds; round++ {
+		for i, query := range queries {
+			params["groundTruth"] = truths[i]
+			searchStart := time.Now()
+			result := client.Search([][]float32{query}, point.topK, params, coll).(map[string]interface{})
+			point.pass.latencies = append(point.pass.latencies, float64(time.Since(searchStart).Microseconds())/1000)
+			if result["success"] != true {
+				point.pass.errors++
+				continue
+			}
+			recall, _ := result["recall"].(float64)
+			point.recalls = append(point.recalls, recall)
+		}
+	}
+	point.elapsed = time.Since(sweepStart)
+}
+
+// paretoFrontier returns the labels of the points with a successful search that no other such
+// point beats on both mean latency and recall, by mean latency
+func paretoFrontier(points []*paretoPoint) []string {
+	var measured []*paretoPoint
+	for _, point := range points {
+		if len(point.recalls) > 0 {
+			measured = append(measured, point)
+		}
+	}
+	slices.SortStableFunc(measured, func(a, b *paretoPoint) int {
+		if la, lb := mean(a.pass.latencies), mean(b.pass.latencies); la != lb {
+			if la < lb {
+				return -1
+			}
+			return 1
+		}
+		// Equal latency: the best recall first, so it hides the others
+		if ra, rb := mean(a.recalls), mean(b.recalls); ra > rb {
+			return -1
+		} else if ra < rb {
+			return 1
+		}
+		return 0
+	})
+	frontier := []string{}
+	best := -1.0
+	for _, point := range measured {
+		if recall := mean(point.recalls); recall > best {
+			frontier = append(frontier, point.label)
+			best = recall
+		}
+	}
+	return frontier
+}
+
+// toMap returns the result of a point: its configuration, recall, latency statistics in
+// milliseconds, throughput in searches per second and whether it is on the frontier
+func (p *paretoPoint) toMap(onFrontier bool) map[string]interface{} {
+	stats := p.pass.stats()
+	qps := 0.0
+	if p.elapsed > 0 {
+		qps = float64(len(p.pass.latencies)) / p.elapsed.Seconds()
+	}
+	return map[string]interface{}{
+		"config":   p.label,
+		"params":   p.params,
+		"topK":     p.topK,
+		"searches": stats["searches"],
+		"errors":   stats["errors"],
+		"recall":   mean(p.recalls),
+		"mean":     stats["mean"],
+		"p50":      stats["p50"],
+		"p95":      stats["p95"],
+		"p99":      stats["p99"],
+		"qps":      qps,
+		"pareto":   onFrontier,
+	}
+}
@@ -0,0 +1,119 @@
+package milvus
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paretoServer answers searches with the IDs 1, 2 and 3 when ef is at least 64 and 1, 9 and 8
+// otherwise, taking 3ms per 16 ef and hit
+type paretoServer struct {
+	prepareServer
+}
+
+func (s *paretoServer) Search(_ context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	var ef, topK int64
+	for _, param := range req.GetSearchParams() {
+		switch param.GetKey() {
+		case "ef":
+			ef, _ = strconv.ParseInt(param.GetValue(), 10, 64)
+		case "topk":
+			topK, _ = strconv.ParseInt(param.GetValue(), 10, 64)
+		}
+	}
+	time.Sleep(time.Duration(ef/16*topK*3) * time.Millisecond)
+	ids := []int64{1, 9, 8}
+	if ef >= 64 {
+		ids = []int64{1, 2, 3}
+	}
+	data := &schemapb.SearchResultData{NumQueries: 1, TopK: topK, Topks: []int64{topK}, Scores: make([]float32, topK),
+		Ids: &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids[:topK]}}}}
+	return &milvuspb.SearchResults{Status: &commonpb.Status{}, Results: data}, nil
+}
+
+func TestParetoSweep(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := benchClient(t, &paretoServer{prepareServer: prepareServer{exists: true}}, vu)
+
+	result := client.ParetoSweep(map[string]interface{}{
+		"queries":      [][]float32{{1, 0}, {0, 1}},
+		"groundTruth":  [][]int64{{1, 2, 3}, {1, 2, 3}},
+		"searchParams": []interface{}{map[string]interface{}{"ef": 16}, map[string]interface{}{"ef": 64}},
+		"topK":         []interface{}{2, 3},
+		"params":       map[string]interface{}{"vectorField": "embedding"},
+	}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	sweep := result["result"].(map[string]interface{})
+	points := sweep["points"].([]interface{})
+	require.Len(t, points, 4)
+	recalls := map[string]float64{}
+	for _, p := range points {
+		point := p.(map[string]interface{})
+		assert.Equal(t, float64(2), point["searches"])
+		recalls[point["config"].(string)] = point["recall"].(float64)
+	}
+	assert.InDelta(t, 0.5, recalls["ef=16,topK=2"], 1e-6)
+	assert.InDelta(t, 1.0/3, recalls["ef=16,topK=3"], 1e-6)
+	assert.InDelta(t, 1.0, recalls["ef=64,topK=2"], 1e-6)
+	assert.Equal(t, map[string]interface{}{"ef": float64(64)}, points[2].(map[string]interface{})["params"])
+	assert.Equal(t, []interface{}{"ef=16,topK=2", "ef=64,topK=2"}, sweep["frontier"])
+	assert.Equal(t, true, points[0].(map[string]interface{})["pareto"])
+	assert.Equal(t, false, points[1].(map[string]interface{})["pareto"])
+
+	configs := map[string]int{}
+	for _, sample := range drainSamples(samples) {
+		if config, ok := sample.Tags.Get("pareto_config"); ok && sample.Metric.Name == "milvus_recall" {
+			configs[config]++
+		}
+	}
+	assert.Equal(t, map[string]int{"ef=16,topK=2": 2, "ef=16,topK=3": 2, "ef=64,topK=2": 2, "ef=64,topK=3": 2}, configs)
+}
+
+func TestParetoFrontier(t *testing.T) {
+	point := func(label string, latency, recall float64) *paretoPoint {
+		return &paretoPoint{label: label, pass: &segmentPass{latencies: []float64{latency}}, recalls: []float64{recall}}
+	}
+	points := []*paretoPoint{
+		point("slow", 10, 0.99),
+		point("dominated", 6, 0.8),
+		point("fast", 2, 0.7),
+		point("tied", 5, 0.85),
+		point("tied-worse", 5, 0.8),
+		{label: "failed", pass: &segmentPass{latencies: []float64{1}, errors: 1}},
+	}
+	assert.Equal(t, []string{"fast", "tied", "slow"}, paretoFrontier(points))
+	assert.Equal(t, "M=16,ef=64,topK=10", paretoLabel(map[string]interface{}{"ef": 64, "M": "16"}, 10))
+}
+
+func TestParetoSweepErrors(t *testing.T) {
+	client := benchClient(t, &paretoServer{prepareServer: prepareServer{exists: true}}, &metricsVU{})
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"queries":      [][]float32{{1, 0}},
+			"groundTruth":  [][]int64{{1, 2}},
+			"searchParams": []interface{}{map[string]interface{}{"ef": 16}},
+		}
+	}
+	for name, change := range map[string]func(map[string]interface{}){
+		"no queries":        func(c map[string]interface{}) { delete(c, "queries") },
+		"no ground truth":   func(c map[string]interface{}) { delete(c, "groundTruth") },
+		"truth count":       func(c map[string]interface{}) { c["groundTruth"] = [][]int64{{1}, {2}} },
+		"no search params":  func(c map[string]interface{}) { c["searchParams"] = []interface{}{} },
+		"negative topK":     func(c map[string]interface{}) { c["topK"] = []interface{}{-1} },
+		"invalid topK":      func(c map[string]interface{}) { c["topK"] = 10 },
+		"queryIds no truth": func(c map[string]interface{}) { c["queryIds"] = []interface{}{1} },
+	} {
+		config := valid()
+		change(config)
+		result := client.ParetoSweep(config).(map[string]interface{})
+		assert.Equal(t, false, result["success"], name)
+	}
+}
@@ -0,0 +1,374 @@
+package milvus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/parquet-go/parquet-go"
+	"go.k6.io/k6/js/modules"
+)
+
+// defaultParquetBatchSize is the number of rows returned by each next() call
+const defaultParquetBatchSize = 1000
+
+// ParquetReaderConfig configures milvus.parquetReader()
+type ParquetReaderConfig struct {
+	BatchSize int               `json:"batchSize,omitempty"` // Rows per batch (default: 1000)
+	Fields    map[string]string `json:"fields,omitempty"`    // Schema field to Parquet column; default reads every column under its own name
+	Offset    int64             `json:"offset,omitempty"`    // First row to read, e.g. to split a file across VUs
+	Limit     int64             `json:"limit,omitempty"`     // Maximum number of rows to read (default: to the end of the file)
+	// "vu" to read a fixed part of the rows per VU, "scenario" for batches claimed by the VUs of a scenario
+	Shard  string `json:"shard,omitempty"`
+	Shards int    `json:"shards,omitempty"` // Parts with shard "vu" (default: the number of VUs)
+}
+
+// parquetColumn maps a Parquet leaf column to a Milvus field
+type parquetColumn struct {
+	field  string
+	column string
+	index  int          // Leaf column index in the file schema
+	kind   parquet.Kind // Physical type of the leaf values
+	list   bool         // Repeated leaf, read as one vector per row
+}
+
+// ParquetReader reads a Parquet file in insert-ready batches. Scalar columns become typed
+// slices and LIST<float> or LIST<double> columns become float vectors, so a batch can be
+// passed to client.insert() without conversion.
+//
+// Usage in k6:
+//
+//	const reader = milvus.parquetReader('data/embeddings.parquet', { batchSize: 500, fields: { embedding: 'emb' } });
+//	export default function () {
+//	    const batch = reader.next();
+//	    if (batch) client.insert(batch);
+//	}
+type ParquetReader struct {
+	path    string
+	file    *os.File
+	reader  *parquet.Reader
+	columns []parquetColumn
+	config  ParquetReaderConfig
+	shard   datasetShard
+	start   int64 // Row index of the first row to read, before sharding
+	end     int64 // Row index after the last row to read, before sharding
+	started bool  // Positioned at the rows of the VU, on the first next() call
+	next    int64 // Row index of the next batch
+	stop    int64 // Row index after the last row of the VU
+	rows    []parquet.Row
+}
+
+// ParquetReader opens a Parquet file for batched inserts. Relative paths are resolved
+// against the working directory of the k6 process.
+func (m *Milvus) ParquetReader(path string, configInput ...interface{}) (*ParquetReader, error) {
+	var config ParquetReaderConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid parquet reader config: %v", err)
+		}
+	}
+	path, err := localDataset(m.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	return openParquetReader(m.vu, m.shared, path, config)
+}
+
+func openParquetReader(vu modules.VU, shared *sync.Map, path string, config ParquetReaderConfig) (*ParquetReader, error) {
+	if config.BatchSize < 0 || config.Offset < 0 || config.Limit < 0 {
+		return nil, fmt.Errorf("parquet reader batchSize, offset and limit must not be negative")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultParquetBatchSize
+	}
+	shard, err := newDatasetShard(vu, shared, config.Shard, config.Shards, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid parquet reader config: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to open parquet file: %v", err)
+	}
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read parquet file %s: %v", path, err)
+	}
+	columns, err := parquetColumns(pf.Schema(), config.Fields)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("parquet file %s: %v", path, err)
+	}
+
+	end := pf.NumRows()
+	if config.Limit > 0 && config.Offset+config.Limit < end {
+		end = config.Offset + config.Limit
+	}
+	r := &ParquetReader{
+		path:    path,
+		file:    file,
+		reader:  parquet.NewReader(pf),
+		columns: columns,
+		config:  config,
+		shard:   shard,
+		start:   min(config.Offset, end),
+		end:     end,
+	}
+	r.shard.key = shardKey(path, r.start, r.end)
+	return r, nil
+}
+
+// parquetColumns resolves the columns to read. Each top-level Parquet column must hold a single
+// leaf: a scalar, or a list of scalars.
+func parquetColumns(schema *parquet.Schema, fields map[string]string) ([]parquetColumn, error) {
+	leaves := make(map[string]parquetColumn)
+	for _, path := range schema.Columns() {
+		leaf, ok := schema.Lookup(path...)
+		if !ok {
+			continue
+		}
+		name := path[0]
+		if _, seen := leaves[name]; seen {
+			leaves[name] = parquetColumn{column: name, index: -1} // Nested group, rejected if selected
+			continue
+		}
+		leaves[name] = parquetColumn{
+			column: name,
+			index:  leaf.ColumnIndex,
+			kind:   leaf.Node.Type().Kind(),
+			list:   leaf.MaxRepetitionLevel > 0,
+		}
+	}
+
+	if len(fields) == 0 {
+		fields = make(map[string]string, len(leaves))
+		for name := range leaves {
+			fields[name] = name
+		}
+	}
+
+	columns := make([]parquetColumn, 0, len(fields))
+	for field, name := range fields {
+		col, ok := leaves[name]
+		if !ok {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+		if col.index < 0 {
+			return nil, fmt.Errorf("column %q is a nested group, which is not supported", name)
+		}
+		switch col.kind {
+		case parquet.Float, parquet.Double:
+		case parquet.Boolean, parquet.Int32, parquet.Int64, parquet.ByteArray:
+			if col.list {
+				return nil, fmt.Errorf("column %q is a list of %s, only lists of float or double are supported", name, col.kind)
+			}
+		default:
+			return nil, fmt.Errorf("column %q has unsupported type %s", name, col.kind)
+		}
+		col.field = field
+		columns = append(columns, col)
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i].field < columns[j].field })
+	return columns, nil
+}
+
+// Next returns the next batch as a map of field name to column values, or null once all rows
+// have been read
+func (r *ParquetReader) Next() (map[string]interface{}, error) {
+	if r.reader == nil {
+		return nil, fmt.Errorf("parquet reader %s is closed", r.path)
+	}
+	if !r.started {
+		if err := r.begin(); err != nil {
+			return nil, err
+		}
+	}
+	if r.shard.mode == shardScenario {
+		first, stop := r.shard.claim(r.start, r.end, int64(r.config.BatchSize))
+		if first >= stop {
+			return nil, nil
+		}
+		if first != r.next {
+			if err := r.seek(first); err != nil {
+				return nil, err
+			}
+		}
+		r.stop = stop
+	}
+	count := min(int64(r.config.BatchSize), r.stop-r.next)
+	if count <= 0 {
+		return nil, nil
+	}
+
+	if int64(cap(r.rows)) < count {
+		r.rows = make([]parquet.Row, count)
+	}
+	rows := r.rows[:count]
+	n := 0
+	for n < len(rows) {
+		read, err := r.reader.ReadRows(rows[n:])
+		n += read
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parquet file %s: %v", r.path, err)
+		}
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	rows = rows[:n]
+
+	batch := make(map[string]interface{}, len(r.columns))
+	for _, col := range r.columns {
+		values, err := col.read(rows, r.next)
+		if err != nil {
+			return nil, fmt.Errorf("parquet file %s: %v", r.path, err)
+		}
+		batch[col.field] = values
+	}
+	r.next += int64(n)
+	return batch, nil
+}
+
+// read collects the values of the column from rows into a typed slice
+func (col parquetColumn) read(rows []parquet.Row, first int64) (interface{}, error) {
+	if col.list {
+		vectors := make([][]float32, len(rows))
+		for i, row := range rows {
+			vector := make([]float32, 0)
+			for _, v := range row {
+				if v.Column() != col.index || v.IsNull() {
+					continue
+				}
+				if col.kind == parquet.Double {
+					vector = append(vector, float32(v.Double()))
+				} else {
+					vector = append(vector, v.Float())
+				}
+			}
+			vectors[i] = vector
+		}
+		return vectors, nil
+	}
+
+	values := make([]parquet.Value, len(rows))
+	for i, row := range rows {
+		found := false
+		for _, v := range row {
+			if v.Column() == col.index {
+				values[i], found = v, !v.IsNull()
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column %q is null at row %d", col.column, first+int64(i))
+		}
+	}
+
+	switch col.kind {
+	case parquet.Boolean:
+		out := make([]bool, len(values))
+		for i, v := range values {
+			out[i] = v.Boolean()
+		}
+		return out, nil
+	case parquet.Int32:
+		out := make([]int32, len(values))
+		for i, v := range values {
+			out[i] = v.Int32()
+		}
+		return out, nil
+	case parquet.Int64:
+		out := make([]int64, len(values))
+		for i, v := range values {
+			out[i] = v.Int64()
+		}
+		return out, nil
+	case parquet.Float:
+		out := make([]float32, len(values))
+		for i, v := range values {
+			out[i] = v.Float()
+		}
+		return out, nil
+	case parquet.Double:
+		out := make([]float64, len(values))
+		for i, v := range values {
+			out[i] = v.Double()
+		}
+		return out, nil
+	default:
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = string(v.ByteArray())
+		}
+		return out, nil
+	}
+}
+
+// begin positions the reader at the first row of the VU
+func (r *ParquetReader) begin() error {
+	r.shard.prepare()
+	first, stop := r.shard.vuRange(r.start, r.end)
+	if err := r.seek(first); err != nil {
+		return err
+	}
+	r.stop = stop
+	r.started = true
+	return nil
+}
+
+func (r *ParquetReader) seek(row int64) error {
+	if err := r.reader.SeekToRow(row); err != nil {
+		return fmt.Errorf("failed to seek parquet file %s: %v", r.path, err)
+	}
+	r.next = row
+	return nil
+}
+
+// Reset rewinds the reader to the first row of the VU (the configured offset without sharding).
+// Readers with shard "scenario" cannot be rewound, as other VUs have read the rest of the rows.
+func (r *ParquetReader) Reset() error {
+	if r.reader == nil {
+		return fmt.Errorf("parquet reader %s is closed", r.path)
+	}
+	if r.shard.mode == shardScenario {
+		return fmt.Errorf("parquet reader %s with shard %q cannot be reset", r.path, shardScenario)
+	}
+	r.started = false
+	return nil
+}
+
+// NumRows returns the number of rows the reader yields in total, after offset and limit
+func (r *ParquetReader) NumRows() int64 {
+	return r.end - r.start
+}
+
+// Fields returns the field names of each batch
+func (r *ParquetReader) Fields() []string {
+	fields := make([]string, len(r.columns))
+	for i, col := range r.columns {
+		fields[i] = col.field
+	}
+	return fields
+}
+
+// Close closes the underlying file
+func (r *ParquetReader) Close() error {
+	if r.reader == nil {
+		return nil
+	}
+	_ = r.reader.Close()
+	r.reader = nil
+	return r.file.Close()
+}
@@ -0,0 +1,135 @@
+package milvus
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type parquetRow struct {
+	ID       int64     `parquet:"id"`
+	Title    string    `parquet:"title"`
+	Price    float64   `parquet:"price"`
+	InStock  bool      `parquet:"in_stock"`
+	Emb      []float32 `parquet:"emb,list"`
+	Features []float64 `parquet:"features"`
+}
+
+// writeParquet writes n rows to a temporary Parquet file, two rows per row group
+func writeParquet(t *testing.T, n int) string {
+	path := filepath.Join(t.TempDir(), "products.parquet")
+	rows := make([]parquetRow, n)
+	for i := range rows {
+		rows[i] = parquetRow{
+			ID:       int64(i),
+			Title:    "product",
+			Price:    float64(i) / 2,
+			InStock:  i%2 == 0,
+			Emb:      []float32{float32(i), 1, 2},
+			Features: []float64{float64(i), 0.5},
+		}
+	}
+	require.NoError(t, parquet.WriteFile(path, rows, parquet.PageBufferSize(64), parquet.MaxRowsPerRowGroup(2)))
+	return path
+}
+
+func TestParquetReader(t *testing.T) {
+	path := writeParquet(t, 5)
+	reader, err := (&Milvus{}).ParquetReader(path, map[string]interface{}{
+		"batchSize": 2,
+		"fields":    map[string]interface{}{"id": "id", "embedding": "emb", "features": "features", "name": "title"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reader.Close() })
+	assert.Equal(t, int64(5), reader.NumRows())
+	assert.Equal(t, []string{"embedding", "features", "id", "name"}, reader.Fields())
+
+	batch, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":        []int64{0, 1},
+		"name":      []string{"product", "product"},
+		"embedding": [][]float32{{0, 1, 2}, {1, 1, 2}},
+		"features":  [][]float32{{0, 0.5}, {1, 0.5}},
+	}, batch)
+
+	var ids []int64
+	for batch != nil {
+		ids = append(ids, batch["id"].([]int64)...)
+		batch, err = reader.Next()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, ids)
+
+	// Reset starts over
+	require.NoError(t, reader.Reset())
+	batch, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1}, batch["id"])
+
+	require.NoError(t, reader.Close())
+	_, err = reader.Next()
+	assert.Error(t, err)
+}
+
+func TestParquetReaderDefaults(t *testing.T) {
+	path := writeParquet(t, 3)
+	reader, err := (&Milvus{}).ParquetReader(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reader.Close() })
+	assert.Equal(t, []string{"emb", "features", "id", "in_stock", "price", "title"}, reader.Fields())
+
+	batch, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, true}, batch["in_stock"])
+	assert.Equal(t, []float64{0, 0.5, 1}, batch["price"])
+
+	batch, err = reader.Next()
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+}
+
+func TestParquetReaderOffsetLimit(t *testing.T) {
+	path := writeParquet(t, 10)
+	reader, err := openParquetReader(nil, nil, path, ParquetReaderConfig{BatchSize: 4, Offset: 3, Limit: 5, Fields: map[string]string{"id": "id"}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reader.Close() })
+	assert.Equal(t, int64(5), reader.NumRows())
+
+	var ids []int64
+	for {
+		batch, err := reader.Next()
+		require.NoError(t, err)
+		if batch == nil {
+			break
+		}
+		ids = append(ids, batch["id"].([]int64)...)
+	}
+	assert.Equal(t, []int64{3, 4, 5, 6, 7}, ids)
+
+	// An offset past the end yields nothing
+	past, err := openParquetReader(nil, nil, path, ParquetReaderConfig{Offset: 20})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = past.Close() })
+	assert.Equal(t, int64(0), past.NumRows())
+	batch, err := past.Next()
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+}
+
+func TestParquetReaderErrors(t *testing.T) {
+	path := writeParquet(t, 1)
+	m := &Milvus{}
+
+	_, err := m.ParquetReader(filepath.Join(t.TempDir(), "missing.parquet"))
+	assert.Error(t, err)
+	_, err = m.ParquetReader(path, map[string]interface{}{"fields": map[string]interface{}{"id": "pk"}})
+	assert.ErrorContains(t, err, `column "pk" not found`)
+	_, err = m.ParquetReader(path, map[string]interface{}{"batchSize": -1})
+	assert.Error(t, err)
+	_, err = m.ParquetReader(path, map[string]interface{}{"batchSize": "all"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package milvus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// DefaultPoolSize is the number of shared connections per config when poolSize is not set
+const DefaultPoolSize = 1
+
+// clientPool shares a fixed number of gRPC connections across all VUs.
+// It lives on the RootModule, so it outlives any single VU.
+type clientPool struct {
+	mu      sync.Mutex
+	entries map[string]*poolEntry
+}
+
+// poolEntry holds the connections for one address+config
+type poolEntry struct {
+	mu    sync.Mutex
+	conns []pooledConn
+	next  int
+}
+
+// pooledConn is a shared connection and the tracker detecting when it breaks
+type pooledConn struct {
+	client  *milvusclient.Client
+	tracker *connTracker
+}
+
+// acquire returns a connection for the key. The pool grows up to size connections,
+// then hands out the existing ones round-robin. dial is given the tracker to install on
+// a new connection.
+func (p *clientPool) acquire(key string, size int, dial func(tracker *connTracker) (*milvusclient.Client, error)) (pooledConn, error) {
+	p.mu.Lock()
+	if p.entries == nil {
+		p.entries = make(map[string]*poolEntry)
+	}
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &poolEntry{}
+		p.entries[key] = entry
+	}
+	p.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if len(entry.conns) < size {
+		tracker := &connTracker{}
+		c, err := dial(tracker)
+		if err != nil {
+			return pooledConn{}, err
+		}
+		conn := pooledConn{client: c, tracker: tracker}
+		entry.conns = append(entry.conns, conn)
+		return conn, nil
+	}
+
+	conn := entry.conns[entry.next%len(entry.conns)]
+	entry.next++
+	return conn, nil
+}
+
+// evict removes a broken connection from the pool, so that the next acquire for the key dials
+// a replacement, and closes it. It reports whether the connection was still in the pool, i.e.
+// whether this call closed it.
+func (p *clientPool) evict(key string, c *milvusclient.Client) bool {
+	p.mu.Lock()
+	entry, ok := p.entries[key]
+	p.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	for i, conn := range entry.conns {
+		if conn.client == c {
+			entry.conns = slices.Delete(entry.conns, i, i+1)
+			go func() { _ = c.Close(context.Background()) }()
+			return true
+		}
+	}
+	return false
+}
+
+// close closes every pooled connection and empties the pool. It returns the number of
+// connections closed.
+func (p *clientPool) close(ctx context.Context) int {
+	p.mu.Lock()
+	entries := p.entries
+	p.entries = nil
+	p.mu.Unlock()
+
+	closed := 0
+	for _, entry := range entries {
+		entry.mu.Lock()
+		for _, conn := range entry.conns {
+			_ = conn.client.Close(ctx)
+			closed++
+		}
+		entry.conns = nil
+		entry.mu.Unlock()
+	}
+	return closed
+}
+
+// size returns the number of open connections across all entries
+func (p *clientPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := 0
+	for _, entry := range p.entries {
+		entry.mu.Lock()
+		total += len(entry.conns)
+		entry.mu.Unlock()
+	}
+	return total
+}
+
+// poolKey identifies connections that can be shared: same address and connection settings.
+// The bound collection is excluded since it does not affect the connection.
+func poolKey(clientConfig *ClientConfig) (string, error) {
+	keyConfig := *clientConfig
+	keyConfig.DefaultCollection = ""
+	keyConfig.PoolSize = 0
+
+	data, err := json.Marshal(keyConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetSharedClient returns a gRPC client backed by a connection pool shared by all VUs.
+// The first poolSize calls for a given address+config open connections; later calls
+// reuse them round-robin. The result is cached per VU like getClient(). A connection
+// that breaks is evicted from the pool, and the clients using it move to a replacement.
+//
+// Usage in k6:
+//
+//	import milvus from 'k6/x/milvus';
+//	export default function() {
+//	    const client = milvus.getSharedClient({ address: host, collection: 'products', poolSize: 8 });
+//	    client.search(...);
+//	    // client.close() is a no-op; pooled connections live until closeSharedClients()
+//	}
+//
+//	export function teardown() {
+//	    milvus.closeSharedClients();
+//	}
+func (m *Milvus) GetSharedClient(configInput interface{}) (*Client, error) {
+	clientConfig, err := parseClientConfig(configInput)
+	if err != nil {
+		return nil, err
+	}
+	if clientConfig.PoolSize < 0 {
+		return nil, fmt.Errorf("poolSize must not be negative")
+	}
+	size := clientConfig.PoolSize
+	if size == 0 {
+		size = DefaultPoolSize
+	}
+	if err = clientConfig.applyEnvDefaults(); err != nil {
+		return nil, err
+	}
+	if err = clientConfig.applyCloudDefaults(); err != nil {
+		return nil, err
+	}
+	if err = clientConfig.validate(); err != nil {
+		return nil, err
+	}
+
+	key, err := poolKey(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pool key: %v", err)
+	}
+	cacheKey := "shared:" + key + ":" + clientConfig.DefaultCollection
+	if client, ok := m.clients[cacheKey]; ok {
+		return client, nil
+	}
+
+	dial := func(tracker *connTracker) (*milvusclient.Client, error) {
+		pooled, dialErr := m.dial(clientConfig, tracker)
+		if dialErr == nil && m.connections != nil {
+			m.connections.Add(1)
+		}
+		return pooled, dialErr
+	}
+	conn, err := m.pool.acquire(key, size, dial)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := m.wrapClient(conn.client, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	client.pooled = true
+	if !clientConfig.DisableReconnect {
+		// A broken connection is evicted for all VUs, and replaced by the next acquire
+		client.tracker = conn.tracker
+		client.redial = func() (*milvusclient.Client, *connTracker, error) {
+			if m.pool.evict(key, client.client) && m.connections != nil {
+				m.connections.Add(-1)
+			}
+			next, redialErr := m.pool.acquire(key, size, dial)
+			return next.client, next.tracker, redialErr
+		}
+	}
+	if clientConfig.ValidateConnection {
+		if err = client.validate(); err != nil {
+			return nil, err
+		}
+	}
+	m.clients[cacheKey] = client
+
+	if m.metrics != nil && m.connections != nil {
+		pushMetric(m.vu, m.metrics.Connections, float64(m.connections.Load()), nil)
+	}
+	return client, nil
+}
+
+// CloseSharedClients closes the connections of the pool shared by getSharedClient(), e.g. in
+// teardown(), and returns how many it closed. A later getSharedClient() call dials again.
+func (m *Milvus) CloseSharedClients() int {
+	closed := m.pool.close(vuContext(m.vu))
+	for key, client := range m.clients {
+		if client.pooled {
+			delete(m.clients, key)
+		}
+	}
+	if m.connections != nil {
+		open := m.connections.Add(-int64(closed))
+		if m.metrics != nil {
+			pushMetric(m.vu, m.metrics.Connections, float64(open), nil)
+		}
+	}
+	return closed
+}
@@ -0,0 +1,196 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPoolAcquire(t *testing.T) {
+	var pool clientPool
+	dials := 0
+	dial := func(*connTracker) (*milvusclient.Client, error) {
+		dials++
+		return &milvusclient.Client{}, nil
+	}
+
+	first, err := pool.acquire("a", 2, dial)
+	require.NoError(t, err)
+	second, err := pool.acquire("a", 2, dial)
+	require.NoError(t, err)
+	assert.NotSame(t, first.client, second.client)
+	assert.NotSame(t, first.tracker, second.tracker)
+
+	// Pool is full: connections are handed out round-robin
+	third, err := pool.acquire("a", 2, dial)
+	require.NoError(t, err)
+	fourth, err := pool.acquire("a", 2, dial)
+	require.NoError(t, err)
+	assert.Same(t, first.client, third.client)
+	assert.Same(t, first.tracker, third.tracker)
+	assert.Same(t, second.client, fourth.client)
+	assert.Equal(t, 2, dials)
+
+	_, err = pool.acquire("b", 1, dial)
+	require.NoError(t, err)
+	assert.Equal(t, 3, pool.size())
+}
+
+func TestClientPoolDialError(t *testing.T) {
+	var pool clientPool
+
+	_, err := pool.acquire("a", 1, func(*connTracker) (*milvusclient.Client, error) {
+		return nil, errors.New("connection refused")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 0, pool.size())
+
+	// A later acquire retries the dial
+	c, err := pool.acquire("a", 1, func(*connTracker) (*milvusclient.Client, error) {
+		return &milvusclient.Client{}, nil
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, c.client)
+}
+
+func TestClientPoolEvict(t *testing.T) {
+	var pool clientPool
+	dial := func(*connTracker) (*milvusclient.Client, error) {
+		return &milvusclient.Client{}, nil
+	}
+	first, err := pool.acquire("a", 2, dial)
+	require.NoError(t, err)
+	second, err := pool.acquire("a", 2, dial)
+	require.NoError(t, err)
+
+	assert.True(t, pool.evict("a", first.client))
+	assert.False(t, pool.evict("a", first.client), "evicted twice")
+	assert.False(t, pool.evict("b", second.client))
+	assert.Equal(t, 1, pool.size())
+
+	// The next acquire dials a replacement instead of handing out the evicted connection
+	replacement, err := pool.acquire("a", 2, dial)
+	require.NoError(t, err)
+	assert.NotSame(t, first.client, replacement.client)
+	assert.NotSame(t, second.client, replacement.client)
+	assert.Equal(t, 2, pool.size())
+}
+
+func TestClientPoolClose(t *testing.T) {
+	var pool clientPool
+	dial := func(*connTracker) (*milvusclient.Client, error) {
+		return &milvusclient.Client{}, nil
+	}
+	for _, key := range []string{"a", "a", "b"} {
+		_, err := pool.acquire(key, 2, dial)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, pool.close(context.Background()))
+	assert.Equal(t, 0, pool.size())
+	assert.Equal(t, 0, pool.close(context.Background()))
+}
+
+func TestClientPoolConcurrentAcquire(t *testing.T) {
+	var pool clientPool
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pool.acquire("a", 4, func(*connTracker) (*milvusclient.Client, error) {
+				return &milvusclient.Client{}, nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 4, pool.size())
+}
+
+func TestPoolKey(t *testing.T) {
+	base := &ClientConfig{Address: "localhost:19530", DefaultCollection: "products", PoolSize: 4}
+
+	key, err := poolKey(base)
+	require.NoError(t, err)
+
+	otherCollection := *base
+	otherCollection.DefaultCollection = "users"
+	otherCollection.PoolSize = 8
+	sameKey, err := poolKey(&otherCollection)
+	require.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	otherDB := *base
+	otherDB.DBName = "tenant_1"
+	otherKey, err := poolKey(&otherDB)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+}
+
+func TestGetSharedClientValidation(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{}, clients: make(map[string]*Client), pool: &clientPool{}}
+
+	_, err := m.GetSharedClient(map[string]interface{}{"address": "localhost:19530", "poolSize": -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poolSize")
+
+	_, err = m.GetSharedClient(map[string]interface{}{"poolSize": 2})
+	assert.ErrorIs(t, err, ErrAddressRequired)
+
+	// Rejected before dialing, as by clientWithConfig()
+	_, err = m.GetSharedClient(map[string]interface{}{"address": "localhost:19530", "workers": -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "workers")
+	_, err = m.GetSharedClient(map[string]interface{}{"address": "localhost:19530", "headers": map[string]interface{}{"grpc-timeout": "1s"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "grpc-timeout")
+	assert.Empty(t, m.clients)
+}
+
+func TestSharedClientCloseIsNoop(t *testing.T) {
+	client := &Client{pooled: true}
+	assert.NoError(t, client.Close())
+}
+
+func TestSharedClientRedial(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{}, clients: make(map[string]*Client), pool: &clientPool{}, connections: &atomic.Int64{}}
+	config := map[string]interface{}{"address": newFakeMilvus(t, nil), "collection": "products", "dialTimeout": "1s"}
+	client, err := m.GetSharedClient(config)
+	require.NoError(t, err)
+	stale := client.client
+
+	// A broken connection is evicted, and the client moves to a replacement
+	client.tracker.broken.Store(true)
+	assert.NotSame(t, stale, client.milvus())
+	assert.False(t, client.tracker.broken.Load())
+	assert.Equal(t, 1, m.pool.size())
+	assert.Equal(t, int64(1), m.connections.Load())
+
+	other := &Milvus{vu: &metricsVU{}, clients: make(map[string]*Client), pool: m.pool, connections: m.connections}
+	otherClient, err := other.GetSharedClient(config)
+	require.NoError(t, err)
+	assert.Same(t, client.client, otherClient.client)
+}
+
+func TestCloseSharedClients(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{}, clients: make(map[string]*Client), pool: &clientPool{}, connections: &atomic.Int64{}}
+	config := map[string]interface{}{"address": newFakeMilvus(t, nil), "poolSize": 2, "dialTimeout": "1s"}
+	_, err := m.GetSharedClient(config)
+	require.NoError(t, err)
+	_, err = m.GetSharedClient(map[string]interface{}{"address": config["address"], "collection": "products", "poolSize": 2, "dialTimeout": "1s"})
+	require.NoError(t, err)
+	require.Equal(t, 2, m.pool.size())
+
+	assert.Equal(t, 2, m.CloseSharedClients())
+	assert.Equal(t, 0, m.pool.size())
+	assert.Equal(t, int64(0), m.connections.Load())
+	assert.Empty(t, m.clients)
+}
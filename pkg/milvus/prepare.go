@@ -0,0 +1,258 @@
+package milvus
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultPrepareBatchSize is the rows per insert of client.prepare()
+const defaultPrepareBatchSize = 1000
+
+// PrepareConfig configures client.prepare()
+type PrepareConfig struct {
+	Schema      Schema                 `json:"schema"`                // Collection to create, named by schema.name
+	Recreate    bool                   `json:"recreate,omitempty"`    // Drop the collection first if it exists; otherwise an existing collection is an error
+	Rows        int                    `json:"rows,omitempty"`        // Vectors to insert (default: every vector of a dataset source)
+	VectorField string                 `json:"vectorField,omitempty"` // Field of the source vectors (default: the only FloatVector field)
+	BatchSize   int                    `json:"batchSize,omitempty"`   // Rows per Insert RPC (default: 1000)
+	Concurrency int                    `json:"concurrency,omitempty"` // Insert RPCs in flight at once (default: 1)
+	Index       map[string]interface{} `json:"index,omitempty"`       // Index params of the vector field, as for createIndex()
+	SkipLoad    bool                   `json:"skipLoad,omitempty"`    // Leave the collection released
+}
+
+// Prepare creates a collection, inserts vectors from a vector generator or dataset, flushes,
+// builds the vector index, waits for it and loads the collection: the fixture of most benchmark
+// scripts, usually run in setup(). The result holds the time of each phase in milliseconds.
+//
+// Usage in k6:
+//
+//	export function setup() {
+//	    const client = milvus.client('localhost:19530');
+//	    const result = client.prepare({
+//	        schema: milvus.schema('bench').addPkInt64('id', false).addFloatVector('embedding', 128),
+//	        source: ds, index: { indexType: 'HNSW' }, recreate: true,
+//	    });
+//	    console.log(JSON.stringify(result.result.timings));
+//	}
+func (c *Client) Prepare(configInput map[string]interface{}) interface{} {
+	start := time.Now()
+	fail := func(err string) interface{} {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err,
+		})
+	}
+
+	source, scalars, config, err := prepareOptions(configInput)
+	if err != nil {
+		return fail(err.Error())
+	}
+	coll := config.Schema.Name
+	vectorField, pkField, err := prepareFields(config.Schema, config.VectorField)
+	if err != nil {
+		return fail(err.Error())
+	}
+	rows := config.Rows
+	if size, ok := prepareSourceSize(source); ok && (rows == 0 || rows > size) {
+		rows = size
+	}
+	if rows <= 0 {
+		return fail("prepare requires rows with a vector generator source")
+	}
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPrepareBatchSize
+	}
+	concurrency := max(1, config.Concurrency)
+	indexParams := make(map[string]interface{}, len(config.Index)+1)
+	for key, value := range config.Index {
+		indexParams[key] = value
+	}
+	if ds, ok := source.(*AnnDataset); ok && ds.MetricType() != "" {
+		// Index with the metric the dataset's neighbors were computed with, unless set
+		if params := flattenIndexParams(indexParams); params["metricType"] == nil && params["metric_type"] == nil {
+			indexParams["metricType"] = ds.MetricType()
+		}
+	}
+
+	timings := make(map[string]float64)
+	// phase runs one step, recording its time; a failed step fails prepare with its result
+	phase := func(name string, run func() map[string]interface{}) map[string]interface{} {
+		phaseStart := time.Now()
+		result := run()
+		timings[name] = float64(time.Since(phaseStart).Milliseconds())
+		if result["success"] != true {
+			result["error"] = fmt.Sprintf("prepare %s: %v", name, result["error"])
+			result["responseTime"] = float64(time.Since(start).Milliseconds())
+			result["result"] = map[string]interface{}{"collection": coll, "timings": timings}
+			return result
+		}
+		return nil
+	}
+
+	exists := c.HasCollection(coll).(map[string]interface{})
+	if exists["success"] != true {
+		return fail(fmt.Sprintf("prepare: %v", exists["error"]))
+	}
+	if exists["result"] == true {
+		if !config.Recreate {
+			return fail(fmt.Sprintf("collection %s already exists, set recreate to drop it", coll))
+		}
+		if failed := phase("drop", func() map[string]interface{} { return c.DropCollection(coll).(map[string]interface{}) }); failed != nil {
+			return failed
+		}
+	}
+	if failed := phase("create", func() map[string]interface{} { return c.CreateCollection(config.Schema).(map[string]interface{}) }); failed != nil {
+		return failed
+	}
+
+	var inserted int64
+	failed := phase("insert", func() map[string]interface{} {
+		step := batchSize * concurrency
+		for offset := 0; offset < rows; offset += step {
+			count := min(step, rows-offset)
+			data, err := prepareData(source, scalars, offset, count, vectorField, pkField)
+			if err != nil {
+				return map[string]interface{}{"success": false, "error": err.Error()}
+			}
+			result := c.Insert(data, map[string]interface{}{
+				"collectionName": coll,
+				"batchSize":      batchSize,
+				"concurrency":    concurrency,
+			}).(map[string]interface{})
+			if result["success"] != true {
+				return result
+			}
+			count64, _ := result["result"].(map[string]interface{})["insert_count"].(float64)
+			inserted += int64(count64)
+		}
+		return map[string]interface{}{"success": true}
+	})
+	if failed != nil {
+		return failed
+	}
+	if failed := phase("flush", func() map[string]interface{} { return c.Flush(coll).(map[string]interface{}) }); failed != nil {
+		return failed
+	}
+	if failed := phase("index", func() map[string]interface{} {
+		return c.CreateIndex(vectorField, indexParams, coll).(map[string]interface{})
+	}); failed != nil {
+		return failed
+	}
+	if !config.SkipLoad {
+		if failed := phase("load", func() map[string]interface{} { return c.LoadCollection(coll).(map[string]interface{}) }); failed != nil {
+			return failed
+		}
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result: map[string]interface{}{
+			"collection":   coll,
+			"insert_count": inserted,
+			"timings":      timings,
+		},
+	})
+}
+
+// prepareOptions reads the config of client.prepare(): the vector source, the optional
+// dataFaker of the scalar fields, and the rest of the config
+func prepareOptions(configInput map[string]interface{}) (interface{}, *DataFaker, PrepareConfig, error) {
+	var config PrepareConfig
+	if err := convertViaJSON(splitModuleObjects(configInput, "source", "scalars"), &config); err != nil {
+		return nil, nil, config, fmt.Errorf("invalid prepare config: %v", err)
+	}
+	if config.Schema.Name == "" {
+		return nil, nil, config, fmt.Errorf("prepare requires a schema with a name")
+	}
+
+	source := configInput["source"]
+	if !isVectorSource(source) {
+		return nil, nil, config, fmt.Errorf("prepare source must be a vectorGenerator, annDataset or sharedVectors, got %T", source)
+	}
+	var scalars *DataFaker
+	if value, ok := configInput["scalars"]; ok && value != nil {
+		if scalars, ok = value.(*DataFaker); !ok {
+			return nil, nil, config, fmt.Errorf("prepare scalars must be a milvus.dataFaker() object, got %T", value)
+		}
+	}
+	return source, scalars, config, nil
+}
+
+// prepareFields returns the vector field of the source and the Int64 primary key to fill with
+// the row indices of the source, or "" when Milvus assigns the keys or the key is not Int64
+func prepareFields(schema Schema, vectorField string) (string, string, error) {
+	var vectorFields []string
+	var pkField string
+	for _, field := range schema.Fields {
+		if field.DataType == "FloatVector" {
+			vectorFields = append(vectorFields, field.Name)
+		}
+		if field.IsPrimaryKey && !field.IsAutoID && field.DataType == "Int64" {
+			pkField = field.Name
+		}
+	}
+	if vectorField == "" {
+		if len(vectorFields) != 1 {
+			return "", "", fmt.Errorf("prepare requires vectorField with %d FloatVector fields in the schema", len(vectorFields))
+		}
+		vectorField = vectorFields[0]
+	}
+	return vectorField, pkField, nil
+}
+
+// isVectorSource reports whether a module object can be a vector source of prepare() and
+// mixedWorkload()
+func isVectorSource(source interface{}) bool {
+	switch source.(type) {
+	case *VectorGenerator, *AnnDataset, *SharedVectors:
+		return true
+	}
+	return false
+}
+
+// prepareSourceSize returns the number of vectors of a dataset source; generators are unbounded
+func prepareSourceSize(source interface{}) (int, bool) {
+	switch s := source.(type) {
+	case *AnnDataset:
+		return s.TrainSize(), true
+	case *SharedVectors:
+		return s.Size(), true
+	}
+	return 0, false
+}
+
+// prepareData returns count rows of the source from offset, as insert data. The primary key,
+// when set, is the row index, which the ground truth of dataset sources refers to.
+func prepareData(source interface{}, scalars *DataFaker, offset, count int, vectorField, pkField string) (map[string]interface{}, error) {
+	var vectors [][]float32
+	var err error
+	switch s := source.(type) {
+	case *VectorGenerator:
+		vectors, err = s.Next(count)
+	case *AnnDataset:
+		vectors, err = s.Train(offset, count)
+	case *SharedVectors:
+		vectors, err = s.Vectors(offset, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{vectorField: vectors}
+	if scalars != nil {
+		values, err := scalars.Next(count)
+		if err != nil {
+			return nil, err
+		}
+		for field, column := range values {
+			data[field] = column
+		}
+	}
+	if _, ok := data[pkField]; pkField != "" && !ok {
+		data[pkField] = rowIndices(offset, offset+count)
+	}
+	return data, nil
+}
@@ -0,0 +1,154 @@
+package milvus
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareServer serves the RPCs of client.prepare(), recording the collection calls in order
+type prepareServer struct {
+	chunkServer
+	callsMu sync.Mutex
+	calls   []string
+	exists  bool
+	index   *milvuspb.CreateIndexRequest
+}
+
+func (s *prepareServer) record(call string) {
+	s.callsMu.Lock()
+	defer s.callsMu.Unlock()
+	s.calls = append(s.calls, call)
+}
+
+func (s *prepareServer) DescribeCollection(ctx context.Context, req *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error) {
+	if !s.exists {
+		return &milvuspb.DescribeCollectionResponse{Status: merr.Status(merr.ErrCollectionNotFound)}, nil
+	}
+	return s.chunkServer.DescribeCollection(ctx, req)
+}
+
+func (s *prepareServer) DropCollection(context.Context, *milvuspb.DropCollectionRequest) (*commonpb.Status, error) {
+	s.record("drop")
+	s.exists = false
+	return &commonpb.Status{}, nil
+}
+
+func (s *prepareServer) CreateCollection(context.Context, *milvuspb.CreateCollectionRequest) (*commonpb.Status, error) {
+	s.record("create")
+	s.exists = true
+	return &commonpb.Status{}, nil
+}
+
+func (s *prepareServer) Flush(context.Context, *milvuspb.FlushRequest) (*milvuspb.FlushResponse, error) {
+	s.record("flush")
+	return &milvuspb.FlushResponse{Status: &commonpb.Status{}}, nil
+}
+
+func (s *prepareServer) GetFlushState(context.Context, *milvuspb.GetFlushStateRequest) (*milvuspb.GetFlushStateResponse, error) {
+	return &milvuspb.GetFlushStateResponse{Status: &commonpb.Status{}, Flushed: true}, nil
+}
+
+func (s *prepareServer) CreateIndex(_ context.Context, req *milvuspb.CreateIndexRequest) (*commonpb.Status, error) {
+	s.record("index")
+	s.index = req
+	return &commonpb.Status{}, nil
+}
+
+func (s *prepareServer) DescribeIndex(context.Context, *milvuspb.DescribeIndexRequest) (*milvuspb.DescribeIndexResponse, error) {
+	return &milvuspb.DescribeIndexResponse{Status: &commonpb.Status{}, IndexDescriptions: []*milvuspb.IndexDescription{
+		{FieldName: "embedding", IndexName: "embedding", State: commonpb.IndexState_Finished},
+	}}, nil
+}
+
+func (s *prepareServer) LoadCollection(context.Context, *milvuspb.LoadCollectionRequest) (*commonpb.Status, error) {
+	s.record("load")
+	return &commonpb.Status{}, nil
+}
+
+func (s *prepareServer) GetLoadingProgress(context.Context, *milvuspb.GetLoadingProgressRequest) (*milvuspb.GetLoadingProgressResponse, error) {
+	return &milvuspb.GetLoadingProgressResponse{Status: &commonpb.Status{}, Progress: 100, RefreshProgress: 100}, nil
+}
+
+// prepareSchema is the schema of the chunkServer collection
+var prepareSchema = map[string]interface{}{
+	"name": "bench",
+	"fields": []interface{}{
+		map[string]interface{}{"name": "id", "dataType": "Int64", "isPrimaryKey": true},
+		map[string]interface{}{"name": "embedding", "dataType": "FloatVector", "dimension": 2},
+	},
+}
+
+func prepareClient(t *testing.T, service *prepareServer) *Client {
+	return fakeClient(t, &Milvus{}, service)
+}
+
+func TestPrepareSharedVectors(t *testing.T) {
+	service := &prepareServer{exists: true}
+	client := prepareClient(t, service)
+	vectors := &SharedVectors{vectors: [][]float32{{0, 1}, {1, 1}, {2, 1}, {3, 1}, {4, 1}}}
+
+	// An existing collection is kept unless recreate is set
+	result := client.Prepare(map[string]interface{}{"schema": prepareSchema, "source": vectors}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "already exists")
+	assert.Empty(t, service.calls)
+
+	result = client.Prepare(map[string]interface{}{
+		"schema":      prepareSchema,
+		"source":      vectors,
+		"recreate":    true,
+		"batchSize":   2,
+		"concurrency": 2,
+		"index":       map[string]interface{}{"indexType": "HNSW", "metricType": "COSINE"},
+	}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, []string{"drop", "create", "flush", "index", "load"}, service.calls)
+	assert.ElementsMatch(t, [][]int64{{0, 1}, {2, 3}, {4}}, service.batches, "row indices are the primary keys")
+	details := result["result"].(map[string]interface{})
+	assert.Equal(t, float64(5), details["insert_count"])
+	assert.Equal(t, "embedding", service.index.GetFieldName())
+	timings := details["timings"].(map[string]interface{})
+	for _, phase := range []string{"drop", "create", "insert", "flush", "index", "load"} {
+		assert.Contains(t, timings, phase)
+	}
+}
+
+func TestPrepareGenerator(t *testing.T) {
+	service := &prepareServer{}
+	client := prepareClient(t, service)
+	gen, err := (&Milvus{}).VectorGenerator(map[string]interface{}{"dim": 2})
+	require.NoError(t, err)
+
+	// A generator is unbounded, so rows is required
+	result := client.Prepare(map[string]interface{}{"schema": prepareSchema, "source": gen}).(map[string]interface{})
+	assert.Contains(t, result["error"], "requires rows")
+
+	result = client.Prepare(map[string]interface{}{"schema": prepareSchema, "source": gen, "rows": 3, "skipLoad": true}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, []string{"create", "flush", "index"}, service.calls)
+	assert.Equal(t, [][]int64{{0, 1, 2}}, service.batches)
+	assert.NotContains(t, result["result"].(map[string]interface{})["timings"], "drop")
+}
+
+func TestPrepareOptions(t *testing.T) {
+	_, _, _, err := prepareOptions(map[string]interface{}{"source": &SharedVectors{}})
+	assert.ErrorContains(t, err, "schema with a name")
+	_, _, _, err = prepareOptions(map[string]interface{}{"schema": prepareSchema, "source": "vectors"})
+	assert.ErrorContains(t, err, "source must be")
+	_, _, _, err = prepareOptions(map[string]interface{}{"schema": prepareSchema, "source": &SharedVectors{}, "scalars": 1})
+	assert.ErrorContains(t, err, "scalars must be")
+
+	_, _, err = prepareFields(Schema{Fields: []Field{{Name: "a", DataType: "FloatVector"}, {Name: "b", DataType: "FloatVector"}}}, "")
+	assert.ErrorContains(t, err, "requires vectorField")
+	field, pk, err := prepareFields(Schema{Fields: []Field{{Name: "pk", DataType: "Int64", IsPrimaryKey: true, IsAutoID: true}, {Name: "v", DataType: "FloatVector"}}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "v", field)
+	assert.Empty(t, pk, "Milvus assigns auto IDs")
+}
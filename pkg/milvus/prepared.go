@@ -0,0 +1,176 @@
+package milvus
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// PrepareBatchOptions configures client.prepareBatch()
+type PrepareBatchOptions struct {
+	CollectionName string `json:"collectionName,omitempty"` // Collection the batch is inserted into (default: the client's collection)
+	NewIDs         bool   `json:"newIds,omitempty"`         // Send fresh primary keys on every insert
+	IDStart        int64  `json:"idStart,omitempty"`        // First fresh primary key (default: 0)
+}
+
+// PreparedBatch is insert data converted to Milvus columns once, so client.insert() can send
+// it repeatedly without converting JavaScript values again. With newIds, each insert replaces
+// the primary key column with keys drawn from a counter shared by all VUs of the test, so
+// repeated inserts add distinct entities.
+//
+// Usage in k6:
+//
+//	const client = milvus.client('localhost:19530');
+//	const batch = client.prepareBatch({ embedding: gen.next(1000) }, { collectionName: 'bench', newIds: true });
+//	export default function () {
+//	    client.insert(batch);
+//	}
+type PreparedBatch struct {
+	collection string
+	columns    []column.Column
+	rows       int
+	vectors    map[string]interface{} // Float vector rows, for insert samples
+
+	// Fresh primary keys, with newIds
+	pk      *entity.Field
+	pkIndex int // Position of the primary key column in columns, -1 to append it
+	ids     *atomic.Int64
+}
+
+// PrepareBatch converts insert data to columns for repeated inserts. Options are a collection
+// name or PrepareBatchOptions; the collection schema is read only for newIds, to find the
+// primary key field.
+func (c *Client) PrepareBatch(data map[string]interface{}, args ...interface{}) (*PreparedBatch, error) {
+	coll, optionsInput := c.parseQueryArgs(args...)
+	var options PrepareBatchOptions
+	if err := convertViaJSON(optionsInput, &options); err != nil {
+		return nil, fmt.Errorf("invalid prepare batch options: %v", err)
+	}
+	if coll == "" {
+		return nil, fmt.Errorf("collection name required")
+	}
+	columns, err := c.convertDataToColumns(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert data: %v", err)
+	}
+	var schema *entity.Schema
+	if options.NewIDs {
+		collection, err := c.milvus().DescribeCollection(c.context(), milvusclient.NewDescribeCollectionOption(coll))
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe collection %s: %v", coll, err)
+		}
+		schema = collection.Schema
+	}
+	batch, err := newPreparedBatch(coll, columns, schema, options.IDStart)
+	if err != nil {
+		return nil, err
+	}
+	if memory := c.trackedMemory(); memory != nil {
+		holdMemory(&memory.batches, batch, batch.batchMemory())
+	}
+	if batch.pk != nil {
+		// All batches of a collection draw from one counter, so VUs never send the same keys
+		if batch.ids, err = idCounter(c.shared, coll, batch.pk.Name, options.IDStart); err != nil {
+			return nil, err
+		}
+	}
+	return batch, nil
+}
+
+// idCounter returns the test-wide counter of fresh primary keys of a collection field from
+// idStart, shared by prepared batches, mixed workloads and capacity tests
+func idCounter(shared *sync.Map, coll, pkField string, idStart int64) (*atomic.Int64, error) {
+	key := fmt.Sprintf("ids\x00%s\x00%s\x00%d", coll, pkField, idStart)
+	return sharedValue(shared, key, func() (*atomic.Int64, error) {
+		ids := &atomic.Int64{}
+		ids.Store(idStart)
+		return ids, nil
+	})
+}
+
+// newPreparedBatch checks the columns of a batch; with a schema, its primary key is replaced by
+// fresh keys counted from idStart
+func newPreparedBatch(coll string, columns []column.Column, schema *entity.Schema, idStart int64) (*PreparedBatch, error) {
+	b := &PreparedBatch{collection: coll, columns: columns, rows: columns[0].Len(), vectors: make(map[string]interface{}), pkIndex: -1}
+	for i, col := range columns {
+		if col.Len() != b.rows {
+			return nil, fmt.Errorf("field %s has %d rows, expected %d", col.Name(), col.Len(), b.rows)
+		}
+		if vectors, ok := col.(*column.ColumnFloatVector); ok {
+			// Copy the rows into one block, as Float32Array rows are views the script may refill
+			rows := newMatrix[float32](vectors.Len(), vectors.Dim())
+			for j, vector := range vectors.Data() {
+				copy(rows[j], vector)
+			}
+			columns[i] = column.NewColumnFloatVector(col.Name(), vectors.Dim(), rows)
+			b.vectors[col.Name()] = rows
+		}
+	}
+	if schema == nil {
+		return b, nil
+	}
+	for _, field := range schema.Fields {
+		if field.PrimaryKey {
+			b.pk = field
+		}
+	}
+	switch {
+	case b.pk == nil:
+		return nil, fmt.Errorf("collection %s has no primary key field", coll)
+	case b.pk.AutoID:
+		return nil, fmt.Errorf("newIds is not needed: the primary key %s of collection %s is auto generated", b.pk.Name, coll)
+	case b.pk.DataType != entity.FieldTypeInt64 && b.pk.DataType != entity.FieldTypeVarChar:
+		return nil, fmt.Errorf("unsupported primary key type %s", b.pk.DataType.String())
+	}
+	for i, col := range columns {
+		if col.Name() == b.pk.Name {
+			b.pkIndex = i
+		}
+	}
+	b.ids = &atomic.Int64{}
+	b.ids.Store(idStart)
+	return b, nil
+}
+
+// insertColumns returns the columns of one insert, with fresh primary keys for newIds
+func (b *PreparedBatch) insertColumns() []column.Column {
+	if b.pk == nil {
+		return b.columns
+	}
+	first := b.ids.Add(int64(b.rows)) - int64(b.rows)
+	var pk column.Column
+	if b.pk.DataType == entity.FieldTypeVarChar {
+		keys := make([]string, b.rows)
+		for i := range keys {
+			keys[i] = strconv.FormatInt(first+int64(i), 10)
+		}
+		pk = column.NewColumnVarChar(b.pk.Name, keys)
+	} else {
+		keys := make([]int64, b.rows)
+		for i := range keys {
+			keys[i] = first + int64(i)
+		}
+		pk = column.NewColumnInt64(b.pk.Name, keys)
+	}
+	if b.pkIndex < 0 {
+		return append(append(make([]column.Column, 0, len(b.columns)+1), b.columns...), pk)
+	}
+	columns := append([]column.Column(nil), b.columns...)
+	columns[b.pkIndex] = pk
+	return columns
+}
+
+// Rows returns the number of rows sent by each insert
+func (b *PreparedBatch) Rows() int {
+	return b.rows
+}
+
+// Collection returns the collection the batch is inserted into
+func (b *PreparedBatch) Collection() string {
+	return b.collection
+}
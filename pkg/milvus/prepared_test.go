@@ -0,0 +1,85 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func preparedColumns(t *testing.T, data map[string]interface{}) []column.Column {
+	columns, err := (&Client{}).convertDataToColumns(data)
+	require.NoError(t, err)
+	return columns
+}
+
+func columnByName(columns []column.Column, name string) column.Column {
+	for _, col := range columns {
+		if col.Name() == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func TestPreparedBatchReusesColumns(t *testing.T) {
+	embedding := []float32{1, 2}
+	columns := preparedColumns(t, map[string]interface{}{
+		"id":        []int64{7, 8},
+		"embedding": []interface{}{embedding, []float32{3, 4}},
+	})
+	batch, err := newPreparedBatch("bench", columns, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, batch.Rows())
+	assert.Equal(t, "bench", batch.Collection())
+
+	embedding[0] = 9 // A refilled Float32Array does not change the batch
+	first := batch.insertColumns()
+	assert.Equal(t, first, batch.insertColumns(), "inserts send the same columns")
+	assert.Equal(t, []entity.FloatVector{{1, 2}, {3, 4}}, columnByName(first, "embedding").(*column.ColumnFloatVector).Data())
+	assert.Equal(t, [][]float32{{1, 2}, {3, 4}}, batch.vectors["embedding"])
+
+	_, err = newPreparedBatch("bench", preparedColumns(t, map[string]interface{}{
+		"id": []int64{1, 2, 3}, "embedding": [][]float32{{1, 2}},
+	}), nil, 0)
+	assert.ErrorContains(t, err, "rows, expected")
+}
+
+func TestPreparedBatchNewIDs(t *testing.T) {
+	schema := entity.NewSchema().WithName("bench").
+		WithField(entity.NewField().WithName("pk").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("embedding").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+	columns := preparedColumns(t, map[string]interface{}{
+		"pk":        []int64{1, 1},
+		"embedding": [][]float32{{1, 2}, {3, 4}},
+	})
+	batch, err := newPreparedBatch("bench", columns, schema, 100)
+	require.NoError(t, err)
+
+	first, second := batch.insertColumns(), batch.insertColumns()
+	require.Len(t, second, 2, "the primary key column is replaced")
+	assert.Equal(t, []int64{100, 101}, columnByName(first, "pk").(*column.ColumnInt64).Data())
+	assert.Equal(t, []int64{102, 103}, columnByName(second, "pk").(*column.ColumnInt64).Data())
+	assert.Equal(t, []int64{1, 1}, columnByName(batch.columns, "pk").(*column.ColumnInt64).Data(), "the prepared batch is unchanged")
+
+	// Without a primary key column, and with VarChar keys, fresh keys are added
+	schema.Fields[0].DataType = entity.FieldTypeVarChar
+	batch, err = newPreparedBatch("bench", preparedColumns(t, map[string]interface{}{"embedding": [][]float32{{1, 2}}}), schema, 0)
+	require.NoError(t, err)
+	inserted := batch.insertColumns()
+	require.Len(t, inserted, 2)
+	assert.Equal(t, []string{"0"}, columnByName(inserted, "pk").(*column.ColumnVarChar).Data())
+
+	schema.Fields[0].AutoID = true
+	_, err = newPreparedBatch("bench", columns, schema, 0)
+	assert.ErrorContains(t, err, "auto generated")
+}
+
+func TestInsertDataType(t *testing.T) {
+	c := &Client{defaultCollection: "bench"}
+	result := c.Insert([]interface{}{1, 2}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "an object of columns or a prepared batch")
+}
@@ -0,0 +1,106 @@
+package milvus
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// contextDialer matches grpc.WithContextDialer
+type contextDialer func(ctx context.Context, address string) (net.Conn, error)
+
+// proxyDialer returns a dialer that reaches the Milvus address through the given proxy.
+// Supported schemes: socks5 / socks5h (optionally with user:password) and http (HTTP CONNECT).
+func proxyDialer(rawURL string) (contextDialer, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %v", err)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", rawURL)
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		dialer, dialerErr := proxy.FromURL(proxyURL, proxy.Direct)
+		if dialerErr != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %v", dialerErr)
+		}
+		socks, ok := dialer.(proxy.ContextDialer)
+		if !ok {
+			return nil, fmt.Errorf("proxy %q does not support context dialing", proxyURL.Scheme)
+		}
+		return func(ctx context.Context, address string) (net.Conn, error) {
+			return socks.DialContext(ctx, "tcp", address)
+		}, nil
+	case "http":
+		return func(ctx context.Context, address string) (net.Conn, error) {
+			return dialHTTPConnect(ctx, proxyURL, address)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use socks5, socks5h or http)", proxyURL.Scheme)
+	}
+}
+
+// dialHTTPConnect opens a tunnel to address through an HTTP proxy using the CONNECT method
+func dialHTTPConnect(ctx context.Context, proxyURL *url.URL, address string) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial proxy %s: %v", proxyURL.Host, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err = req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT to proxy: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to read CONNECT response from proxy: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy refused CONNECT to %s: %s", address, resp.Status)
+	}
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn replays bytes the proxy sent right after its CONNECT response
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
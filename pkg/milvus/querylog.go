@@ -0,0 +1,310 @@
+package milvus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// defaultQueryLogTopK is the topK of query log entries without one
+const defaultQueryLogTopK = 10
+
+// queryLogKeys are the keys of a query log entry that are not search params
+var queryLogKeys = map[string]struct{}{
+	"vector":         {},
+	"vectors":        {},
+	"topK":           {},
+	"limit":          {},
+	"collectionName": {},
+}
+
+// QueryLogConfig configures milvus.queryLog()
+type QueryLogConfig struct {
+	Shuffle        bool                   `json:"shuffle,omitempty"`        // Replay in a random order drawn once from seed, instead of file order
+	Seed           int64                  `json:"seed,omitempty"`           // Seed of the shuffled order
+	Rate           float64                `json:"rate,omitempty"`           // Replays per second across all VUs (default: as fast as replay() is called)
+	Loop           bool                   `json:"loop,omitempty"`           // Start over at the end of the log instead of returning null
+	TopK           int                    `json:"topK,omitempty"`           // TopK of entries without one (default: 10)
+	Defaults       map[string]interface{} `json:"defaults,omitempty"`       // Search params of every entry, overridden by those of the entry
+	CollectionName string                 `json:"collectionName,omitempty"` // Collection of entries without one (default: the collection of the client)
+}
+
+// queryLogEntry is a recorded query, ready to search
+type queryLogEntry struct {
+	line           int         // Line of the entry in the file
+	vectors        interface{} // Query vectors in a form client.search() accepts
+	topK           int
+	params         map[string]interface{}
+	collectionName string
+}
+
+// queryLogReplay is the replay state shared by the query logs of the same file and order
+type queryLogReplay struct {
+	entries  []queryLogEntry
+	order    []int        // Entry of each position when shuffled, nil in file order
+	cursor   atomic.Int64 // Next position, across passes
+	schedule *pacerSchedule
+}
+
+// QueryLog replays recorded production queries, so tests reflect real traffic mixes. Each line of
+// the JSONL file is a query with its vector, topK and search params such as filter, and replay()
+// runs the next one with its own params. The log is replayed once in file order or shuffled,
+// across all VUs, and optionally at a rate shared by all VUs.
+//
+// Usage in k6:
+//
+//	const log = milvus.queryLog('data/queries.jsonl', { rate: 200, shuffle: true, loop: true, defaults: { vectorField: 'embedding' } });
+//	export default function () {
+//	    log.replay(client);
+//	}
+type QueryLog struct {
+	vu     modules.VU
+	config QueryLogConfig
+	replay *queryLogReplay
+}
+
+// QueryLog opens a JSONL file of recorded queries for replay. Relative paths are resolved
+// against the working directory of the k6 process.
+func (m *Milvus) QueryLog(path string, configInput ...interface{}) (*QueryLog, error) {
+	var config QueryLogConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid query log config: %v", err)
+		}
+	}
+	if config.Rate < 0 || math.IsNaN(config.Rate) || math.IsInf(config.Rate, 0) {
+		return nil, fmt.Errorf("query log rate must be a positive number, got %v", config.Rate)
+	}
+	if config.TopK < 0 {
+		return nil, fmt.Errorf("query log topK must not be negative, got %d", config.TopK)
+	}
+	config.TopK = optionalPositive(config.TopK, defaultQueryLogTopK)
+	var interval time.Duration
+	if config.Rate > 0 {
+		if interval = time.Duration(float64(time.Second) / config.Rate); interval <= 0 {
+			return nil, fmt.Errorf("query log rate %v is too high", config.Rate)
+		}
+	}
+
+	path, err := localDataset(m.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := sharedValue(m.shared, "querylog\x00"+path, func() ([]queryLogEntry, error) {
+		return readQueryLog(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("querylog\x00%s\x00%t\x00%d\x00%v", path, config.Shuffle, config.Seed, config.Rate)
+	replay, err := sharedValue(m.shared, key, func() (*queryLogReplay, error) {
+		replay := &queryLogReplay{entries: entries}
+		if config.Shuffle {
+			replay.order = rand.New(rand.NewSource(config.Seed)).Perm(len(entries))
+		}
+		if interval > 0 {
+			replay.schedule = &pacerSchedule{interval: interval, start: time.Now()}
+		}
+		return replay, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &QueryLog{vu: m.vu, config: config, replay: replay}, nil
+}
+
+// readQueryLog reads the entries of a JSONL query log, skipping blank lines
+func readQueryLog(path string) ([]queryLogEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open query log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []queryLogEntry
+	lines := bufio.NewReaderSize(file, 1<<20)
+	for line := 1; ; line++ {
+		text, err := lines.ReadString('\n')
+		if text == "" && err == io.EOF {
+			break
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read query log %s: %v", path, err)
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		entry, err := parseQueryLogEntry(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", path, line, err)
+		}
+		entry.line = line
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("query log %s has no entries", path)
+	}
+	return entries, nil
+}
+
+// parseQueryLogEntry parses one line of a query log: "vector" or "vectors", with "topK" or
+// "limit", "collectionName", and search params
+func parseQueryLogEntry(text string) (queryLogEntry, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return queryLogEntry{}, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	var entry queryLogEntry
+	switch {
+	case raw["vector"] != nil && raw["vectors"] != nil:
+		return entry, fmt.Errorf("entry has both vector and vectors")
+	case raw["vector"] != nil:
+		entry.vectors = []interface{}{raw["vector"]}
+	case raw["vectors"] != nil:
+		entry.vectors = raw["vectors"]
+	default:
+		return entry, fmt.Errorf("entry has no vector")
+	}
+	// Dense vectors are converted once here rather than on every replay
+	var dense [][]float32
+	if data, err := json.Marshal(entry.vectors); err == nil && json.Unmarshal(data, &dense) == nil && len(dense) > 0 && len(dense[0]) > 0 {
+		entry.vectors = dense
+	}
+	if _, err := convertToSearchVectors(entry.vectors); err != nil {
+		return entry, fmt.Errorf("invalid vector: %v", err)
+	}
+
+	for _, key := range []string{"topK", "limit"} {
+		if value, ok := raw[key]; ok {
+			topK, ok := value.(float64)
+			if !ok || topK < 1 || topK != math.Trunc(topK) {
+				return entry, fmt.Errorf("%s must be a positive integer, got %v", key, value)
+			}
+			entry.topK = int(topK)
+		}
+	}
+	if value, ok := raw["collectionName"]; ok {
+		if entry.collectionName, ok = value.(string); !ok {
+			return entry, fmt.Errorf("collectionName must be a string, got %v", value)
+		}
+	}
+	for key, value := range raw {
+		if _, ok := queryLogKeys[key]; !ok {
+			if entry.params == nil {
+				entry.params = make(map[string]interface{})
+			}
+			entry.params[key] = value
+		}
+	}
+	return entry, nil
+}
+
+// next returns the next entry to replay, or nil once the log is replayed without loop
+func (q *QueryLog) next() *queryLogEntry {
+	position := q.replay.cursor.Add(1) - 1
+	size := int64(len(q.replay.entries))
+	if position >= size && !q.config.Loop {
+		return nil
+	}
+	index := int(position % size)
+	if q.replay.order != nil {
+		index = q.replay.order[index]
+	}
+	return &q.replay.entries[index]
+}
+
+// searchArgs returns the topK, params and collection of an entry, with the defaults of the log
+func (q *QueryLog) searchArgs(entry *queryLogEntry) (int, map[string]interface{}, string) {
+	topK := entry.topK
+	if topK == 0 {
+		topK = q.config.TopK
+	}
+	params := maps.Clone(q.config.Defaults)
+	if params == nil {
+		params = make(map[string]interface{}, len(entry.params))
+	}
+	maps.Copy(params, entry.params)
+	coll := entry.collectionName
+	if coll == "" {
+		coll = q.config.CollectionName
+	}
+	return topK, params, coll
+}
+
+// Replay waits for the next slot of the rate, if any, and searches with the client for the next
+// entry of the log, with its own topK and params. The result is that of client.search(), with
+// the line of the entry in "line" and the time waited for the rate, in milliseconds, in
+// "waited_ms". It returns null once every entry is replayed, unless loop is set.
+func (q *QueryLog) Replay(client *Client) interface{} {
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "replay requires a client"})
+	}
+	entry := q.next()
+	if entry == nil {
+		return nil
+	}
+	var waited time.Duration
+	if q.replay.schedule != nil {
+		ctx := context.Background()
+		if q.vu != nil && q.vu.Context() != nil {
+			ctx = q.vu.Context()
+		}
+		waited = q.replay.schedule.wait(ctx)
+	}
+	topK, params, coll := q.searchArgs(entry)
+	var collectionName []string
+	if coll != "" {
+		collectionName = []string{coll}
+	}
+	result := client.Search(entry.vectors, topK, params, collectionName...).(map[string]interface{})
+	result["line"] = entry.line
+	result["waited_ms"] = float64(waited) / float64(time.Millisecond)
+	return result
+}
+
+// Next returns the next entry of the log as { vectors, topK, params, collectionName, line }
+// without searching, e.g. to search with other options, or null once every entry is replayed,
+// unless loop is set. Entries taken by next() are not replayed.
+func (q *QueryLog) Next() map[string]interface{} {
+	entry := q.next()
+	if entry == nil {
+		return nil
+	}
+	topK, params, coll := q.searchArgs(entry)
+	next := map[string]interface{}{"vectors": entry.vectors, "topK": topK, "params": params, "line": entry.line}
+	if coll != "" {
+		next["collectionName"] = coll
+	}
+	return next
+}
+
+// Len returns the entries of the log
+func (q *QueryLog) Len() int {
+	return len(q.replay.entries)
+}
+
+// Replayed returns the entries taken so far by all VUs, across passes with loop
+func (q *QueryLog) Replayed() int64 {
+	replayed := q.replay.cursor.Load()
+	if !q.config.Loop {
+		replayed = min(replayed, int64(len(q.replay.entries)))
+	}
+	return replayed
+}
+
+// Reset starts the replay over at the first entry, for all VUs
+func (q *QueryLog) Reset() {
+	q.replay.cursor.Store(0)
+}
@@ -0,0 +1,147 @@
+package milvus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryLogServer serves searches, recording the filter and topK of each
+type queryLogServer struct {
+	searchServer
+	filters []string
+	topKs   []string
+}
+
+func (s *queryLogServer) Search(ctx context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	s.mu.Lock()
+	s.filters = append(s.filters, req.GetDsl())
+	for _, param := range req.GetSearchParams() {
+		if param.GetKey() == "topk" {
+			s.topKs = append(s.topKs, param.GetValue())
+		}
+	}
+	s.mu.Unlock()
+	return s.searchServer.Search(ctx, req)
+}
+
+func writeQueryLog(t *testing.T, lines ...string) string {
+	path := filepath.Join(t.TempDir(), "queries.jsonl")
+	content := ""
+	for _, line := range lines {
+		content += line + "\n"
+	}
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestQueryLogReplay(t *testing.T) {
+	service := &queryLogServer{}
+	client := benchClient(t, service, &metricsVU{})
+	path := writeQueryLog(t,
+		`{"vector": [1, 0], "filter": "id > 1", "topK": 3}`,
+		``,
+		`{"vectors": [[0, 1], [1, 1]], "limit": 5, "vectorField": "other"}`,
+	)
+
+	log, err := (&Milvus{shared: &sync.Map{}}).QueryLog(path, map[string]interface{}{"defaults": map[string]interface{}{"vectorField": "embedding"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, log.Len())
+
+	result := log.Replay(client).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, 1, result["line"])
+	result = log.Replay(client).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, 3, result["line"])
+	assert.Nil(t, log.Replay(client), "log replayed without loop")
+	assert.Equal(t, int64(2), log.Replayed())
+
+	assert.Equal(t, []string{"id > 1", ""}, service.filters)
+	assert.Equal(t, []string{"3", "5"}, service.topKs)
+	assert.Equal(t, []int64{1, 2}, service.nqs)
+
+	log.Reset()
+	next := log.Next()
+	assert.Equal(t, [][]float32{{1, 0}}, next["vectors"])
+	assert.Equal(t, 3, next["topK"])
+	assert.Equal(t, map[string]interface{}{"vectorField": "embedding", "filter": "id > 1"}, next["params"])
+	assert.Equal(t, map[string]interface{}{"vectorField": "other"}, log.Next()["params"], "entry params override the defaults")
+}
+
+func TestQueryLogOrder(t *testing.T) {
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, `{"vector": [1, 0]}`)
+	}
+	path := writeQueryLog(t, lines...)
+	shared := &sync.Map{}
+	config := map[string]interface{}{"shuffle": true, "seed": 7, "loop": true}
+
+	first, err := (&Milvus{shared: shared}).QueryLog(path, config)
+	require.NoError(t, err)
+	second, err := (&Milvus{shared: shared}).QueryLog(path, config)
+	require.NoError(t, err)
+
+	// Logs of the same file and order share the replay, so each pass takes every entry once
+	seen := make(map[interface{}]bool)
+	for i := 0; i < 10; i++ {
+		seen[first.Next()["line"]] = true
+		seen[second.Next()["line"]] = true
+	}
+	assert.Len(t, seen, 20)
+	assert.Equal(t, int64(20), first.Replayed())
+	assert.NotNil(t, first.Next(), "loop starts over")
+
+	inOrder, err := (&Milvus{shared: shared}).QueryLog(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, inOrder.Next()["line"])
+	assert.Equal(t, 2, inOrder.Next()["line"])
+	assert.Equal(t, 10, inOrder.Next()["topK"], "default topK")
+}
+
+func TestQueryLogRate(t *testing.T) {
+	client := benchClient(t, &queryLogServer{}, &metricsVU{})
+	path := writeQueryLog(t, `{"vector": [1, 0], "vectorField": "embedding"}`)
+	log, err := (&Milvus{shared: &sync.Map{}}).QueryLog(path, map[string]interface{}{"rate": 20, "loop": true})
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		result := log.Replay(client).(map[string]interface{})
+		require.Equal(t, true, result["success"], result["error"])
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond, "3 replays at 20 per second")
+}
+
+func TestQueryLogErrors(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	for name, line := range map[string]string{
+		"invalid JSON":    `{"vector": [1, 0]`,
+		"no vector":       `{"filter": "id > 1"}`,
+		"both vectors":    `{"vector": [1], "vectors": [[1]]}`,
+		"invalid topK":    `{"vector": [1, 0], "topK": 1.5}`,
+		"invalid vector":  `{"vector": [true]}`,
+		"collection type": `{"vector": [1, 0], "collectionName": 1}`,
+	} {
+		_, err := m.QueryLog(writeQueryLog(t, `{"vector": [0, 1]}`, line))
+		assert.ErrorContains(t, err, "line 2", name)
+	}
+	_, err := m.QueryLog(writeQueryLog(t, ""))
+	assert.ErrorContains(t, err, "no entries")
+	_, err = m.QueryLog(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.Error(t, err)
+	_, err = m.QueryLog(writeQueryLog(t, `{"vector": [1, 0]}`), map[string]interface{}{"rate": -1})
+	assert.Error(t, err)
+
+	log, err := m.QueryLog(writeQueryLog(t, `{"vector": [1, 0]}`))
+	require.NoError(t, err)
+	assert.Equal(t, false, log.Replay(nil).(map[string]interface{})["success"])
+}
@@ -0,0 +1,359 @@
+package milvus
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// serverRecallParam is the search param asking Zilliz Cloud to estimate the recall of each query
+const serverRecallParam = "enable_recall_calculation"
+
+// groundTruthOption reads the "groundTruth" search param: for each query vector, the IDs of its
+// true nearest neighbors, best first. A groundTruth or annDataset object is looked up with the
+// "queryIds" search param instead.
+func groundTruthOption(params map[string]interface{}, nq int) ([]queryTruth, error) {
+	value, ok := params["groundTruth"]
+	if !ok || value == nil {
+		if params["queryIds"] != nil {
+			return nil, fmt.Errorf("queryIds requires a groundTruth dataset")
+		}
+		return nil, nil
+	}
+	var queries []interface{}
+	switch v := value.(type) {
+	case groundTruthSource:
+		ids, err := queryIDsOption(params, nq)
+		if err != nil {
+			return nil, err
+		}
+		queries = make([]interface{}, nq)
+		for i, id := range ids {
+			neighbors, err := v.truthFor(id)
+			if err != nil {
+				return nil, err
+			}
+			queries[i] = neighbors
+		}
+	case []interface{}:
+		queries = v
+	case [][]int64: // annDataset neighbors
+		queries = make([]interface{}, len(v))
+		for i, ids := range v {
+			queries[i] = ids
+		}
+	default:
+		return nil, fmt.Errorf("groundTruth must be an array of ID arrays, got %T", value)
+	}
+	if _, isSource := value.(groundTruthSource); !isSource && params["queryIds"] != nil {
+		return nil, fmt.Errorf("queryIds requires a groundTruth dataset")
+	}
+	if len(queries) != nq {
+		return nil, fmt.Errorf("groundTruth has %d entries for %d query vectors", len(queries), nq)
+	}
+
+	truth := make([]queryTruth, len(queries))
+	for i, query := range queries {
+		switch ids := query.(type) {
+		case []interface{}:
+			truth[i] = truthOf(ids)
+		case []int64:
+			truth[i] = queryTruth{ints: ids}
+		default:
+			return nil, fmt.Errorf("groundTruth[%d] must be an array of IDs, got %T", i, query)
+		}
+		if truth[i].len() == 0 {
+			return nil, fmt.Errorf("groundTruth[%d] is empty", i)
+		}
+	}
+	return truth, nil
+}
+
+// queryTruth is the ground truth of one query, best first: integer IDs, shared with the dataset
+// they were loaded from, or formatted IDs when some ID is not an integer
+type queryTruth struct {
+	ints []int64
+	keys []string
+}
+
+// truthOf reads the ground truth IDs of one query passed from JavaScript
+func truthOf(ids []interface{}) queryTruth {
+	ints := make([]int64, len(ids))
+	for i, id := range ids {
+		switch v := id.(type) {
+		case int64:
+			ints[i] = v
+			continue
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+				ints[i] = int64(v)
+				continue
+			}
+		}
+		keys := make([]string, len(ids))
+		for j, id := range ids {
+			keys[j] = idKey(id)
+		}
+		return queryTruth{keys: keys}
+	}
+	return queryTruth{ints: ints}
+}
+
+func (t queryTruth) len() int {
+	if t.keys != nil {
+		return len(t.keys)
+	}
+	return len(t.ints)
+}
+
+// recallParallelWork is the number of result IDs (nq * topK) from which queries are matched
+// against their ground truth in parallel
+const recallParallelWork = 1 << 15
+
+// queryMatch is how the top-k results of one query match its top-k ground truth. Recall and
+// the quality metrics are all computed from it, so each query is matched once.
+type queryMatch struct {
+	k        int
+	expected int     // Ground truth IDs considered: min(k, len(truth))
+	found    int     // Distinct top-k ground truth IDs in the top-k results
+	dcg      float64 // Discounted cumulative gain of those hits
+	first    int     // Rank of the true nearest neighbor from 1, or 0 when it is missing
+}
+
+// matchQueries matches the results of each query against its ground truth. A query without a
+// result set matches as if nothing was returned. Large searches are matched in parallel.
+func matchQueries(resultSets []milvusclient.ResultSet, truth []queryTruth, topK int) []queryMatch {
+	if truth == nil {
+		return nil
+	}
+	matches := make([]queryMatch, len(truth))
+	workers := 1
+	if len(truth)*topK >= recallParallelWork {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	runParallel(len(truth), workers, func(i int) {
+		var resultSet milvusclient.ResultSet
+		if i < len(resultSets) {
+			resultSet = resultSets[i]
+		}
+		scratch := matchScratchPool.Get().(*matchScratch)
+		matches[i] = scratch.match(resultSet, truth[i], topK)
+		matchScratchPool.Put(scratch)
+	})
+	return matches
+}
+
+// matchScratch holds the buffers of one query match, reused across queries and searches
+type matchScratch struct {
+	ints relevance[int64]
+	keys relevance[string]
+	// Formatted integer IDs, for string keys matched against integer IDs
+	formatted []string
+}
+
+var matchScratchPool = sync.Pool{New: func() any { return &matchScratch{} }}
+
+// match matches the results of one query against its ground truth. Integer keys, the common
+// case, are compared as integers; the results are read from the ID column without a copy.
+func (s *matchScratch) match(resultSet milvusclient.ResultSet, truth queryTruth, k int) queryMatch {
+	var ints []int64
+	var keys []string
+	switch ids := resultSet.IDs.(type) {
+	case nil:
+	case *column.ColumnInt64:
+		ints = ids.Data()[:min(resultSet.ResultCount, ids.Len())]
+	case *column.ColumnVarChar:
+		keys = ids.Data()[:min(resultSet.ResultCount, ids.Len())]
+	default:
+		for i := 0; i < resultSet.ResultCount; i++ {
+			if id, err := ids.Get(i); err == nil {
+				keys = append(keys, idKey(id))
+			}
+		}
+	}
+
+	switch {
+	case truth.keys == nil && keys == nil:
+		return matchIDs(ints, truth.ints, k, &s.ints)
+	case truth.keys == nil:
+		s.formatted = formatIDs(s.formatted[:0], truth.ints)
+		return matchIDs(keys, s.formatted, k, &s.keys)
+	default:
+		if keys == nil {
+			s.formatted = formatIDs(s.formatted[:0], ints)
+			keys = s.formatted
+		}
+		return matchIDs(keys, truth.keys, k, &s.keys)
+	}
+}
+
+// formatIDs appends integer IDs formatted as keys
+func formatIDs(keys []string, ids []int64) []string {
+	for _, id := range ids {
+		keys = append(keys, strconv.FormatInt(id, 10))
+	}
+	return keys
+}
+
+// relevance is a reusable set of the top-k ground truth IDs of a query
+type relevance[K comparable] struct {
+	set map[K]struct{}
+}
+
+// matchIDs matches ranked result IDs against the top-k ground truth IDs
+func matchIDs[K comparable](ids, truth []K, k int, r *relevance[K]) queryMatch {
+	m := queryMatch{k: k, expected: max(0, min(k, len(truth)))}
+	if m.expected == 0 {
+		return m
+	}
+	if r.set == nil {
+		r.set = make(map[K]struct{}, m.expected)
+	}
+	clear(r.set)
+	for _, id := range truth[:m.expected] {
+		r.set[id] = struct{}{}
+	}
+	for i, id := range ids[:min(k, len(ids))] {
+		if m.first == 0 && id == truth[0] {
+			m.first = i + 1
+		}
+		if _, ok := r.set[id]; ok {
+			m.found++
+			m.dcg += 1 / math.Log2(float64(i+2))
+			delete(r.set, id) // Duplicates count once
+		}
+	}
+	return m
+}
+
+// queryRecalls returns the recall of each query: recall@topK against the ground truth when
+// matched, otherwise the recall estimated by the server
+func queryRecalls(resultSets []milvusclient.ResultSet, matches []queryMatch) []float64 {
+	if matches == nil {
+		recalls := make([]float64, len(resultSets))
+		for i, resultSet := range resultSets {
+			recalls[i] = float64(resultSet.Recall)
+		}
+		return recalls
+	}
+	return scoreQueries(matches, recallAtK)
+}
+
+// scoreQueries applies a quality score to the match of each query
+func scoreQueries(matches []queryMatch, score qualityScorer) []float64 {
+	scores := make([]float64, len(matches))
+	for i, m := range matches {
+		scores[i] = score(m)
+	}
+	return scores
+}
+
+// qualityScorer computes a search quality score for one query from its match
+type qualityScorer func(m queryMatch) float64
+
+// qualityScorers are the ranking quality metrics selectable with the "qualityMetrics" search param
+var qualityScorers = map[string]qualityScorer{
+	"precision": precisionAtK,
+	"ndcg":      ndcgAtK,
+	"mrr":       reciprocalRank,
+}
+
+// qualityMetricsOption reads the "qualityMetrics" search param, e.g. ['precision', 'ndcg', 'mrr'].
+// The metrics are computed against the ground truth, so they require groundTruth.
+func qualityMetricsOption(params map[string]interface{}, hasTruth bool) ([]string, error) {
+	value, ok := params["qualityMetrics"]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("qualityMetrics must be an array of metric names, got %T", value)
+	}
+	if len(list) > 0 && !hasTruth {
+		return nil, fmt.Errorf("qualityMetrics requires groundTruth")
+	}
+
+	names := make([]string, 0, len(list))
+	for _, item := range list {
+		name, isString := item.(string)
+		if _, known := qualityScorers[name]; !isString || !known {
+			return nil, fmt.Errorf("unknown quality metric %v: expected precision, ndcg or mrr", item)
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// recallAtK returns the fraction of the top-k ground truth IDs found in the top-k results
+func recallAtK(m queryMatch) float64 {
+	if m.expected <= 0 {
+		return 0
+	}
+	return float64(m.found) / float64(m.expected)
+}
+
+// precisionAtK returns the fraction of the top-k results that are among the top-k ground truth IDs
+func precisionAtK(m queryMatch) float64 {
+	if m.k <= 0 {
+		return 0
+	}
+	return float64(m.found) / float64(m.k)
+}
+
+// ndcgAtK returns the normalized discounted cumulative gain of the top-k results, with the top-k
+// ground truth IDs as relevant. Unlike recall, it drops when relevant hits are ranked lower.
+func ndcgAtK(m queryMatch) float64 {
+	ideal := 0.0
+	for i := 0; i < m.expected; i++ {
+		ideal += 1 / math.Log2(float64(i+2))
+	}
+	if ideal == 0 {
+		return 0
+	}
+	return m.dcg / ideal
+}
+
+// reciprocalRank returns 1/rank of the true nearest neighbor (the first ground truth ID) in the
+// top-k results, or 0 when it is missing. Averaged over queries, this is the MRR.
+func reciprocalRank(m queryMatch) float64 {
+	if m.first == 0 {
+		return 0
+	}
+	return 1 / float64(m.first)
+}
+
+// idKey formats a primary key for comparison. JavaScript numbers arrive as float64,
+// while Milvus returns int64 keys.
+func idKey(id interface{}) string {
+	switch v := id.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+	case float32:
+		if v == float32(math.Trunc(float64(v))) {
+			return strconv.FormatInt(int64(v), 10)
+		}
+	}
+	return fmt.Sprint(id)
+}
+
+// mean returns the average of values, or 0 when there are none
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,155 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroundTruthOption(t *testing.T) {
+	truth, err := groundTruthOption(map[string]interface{}{}, 2)
+	require.NoError(t, err)
+	assert.Nil(t, truth)
+
+	truth, err = groundTruthOption(map[string]interface{}{
+		"groundTruth": []interface{}{
+			[]interface{}{float64(3), float64(1)},
+			[]interface{}{int64(7), "doc-9"},
+		},
+	}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []queryTruth{{ints: []int64{3, 1}}, {keys: []string{"7", "doc-9"}}}, truth)
+
+	// Typed neighbors from annDataset, whole or sliced in JavaScript
+	truth, err = groundTruthOption(map[string]interface{}{"groundTruth": [][]int64{{3, 1}, {7}}}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []queryTruth{{ints: []int64{3, 1}}, {ints: []int64{7}}}, truth)
+	truth, err = groundTruthOption(map[string]interface{}{"groundTruth": []interface{}{[]int64{3, 1}, []int64{7}}}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []queryTruth{{ints: []int64{3, 1}}, {ints: []int64{7}}}, truth)
+
+	for _, value := range []interface{}{
+		"1,2",
+		[]interface{}{[]interface{}{float64(1)}}, // One entry for two queries
+		[]interface{}{[]interface{}{float64(1)}, float64(2)},      // Not an ID array
+		[]interface{}{[]interface{}{float64(1)}, []interface{}{}}, // Empty
+	} {
+		_, err = groundTruthOption(map[string]interface{}{"groundTruth": value}, 2)
+		assert.Error(t, err, value)
+	}
+}
+
+// matchOf matches ranked result IDs against a query's ground truth
+func matchOf(ids, truth []string, k int) queryMatch {
+	return matchIDs(ids, truth, k, &relevance[string]{})
+}
+
+func TestRecallAtK(t *testing.T) {
+	truth := []string{"1", "2", "3", "4"}
+	assert.Equal(t, 1.0, recallAtK(matchOf([]string{"2", "1"}, truth, 2)))
+	assert.Equal(t, 0.5, recallAtK(matchOf([]string{"1", "9"}, truth, 2)))
+	assert.Equal(t, 0.75, recallAtK(matchOf([]string{"1", "9", "2", "3"}, truth, 4)))
+	assert.Equal(t, 0.0, recallAtK(matchOf(nil, truth, 4)))
+	assert.Equal(t, 0.5, recallAtK(matchOf([]string{"1", "1"}, truth, 2))) // Duplicates count once
+	assert.Equal(t, 1.0, recallAtK(matchOf([]string{"1", "2"}, []string{"1", "2"}, 10)))
+}
+
+func TestQueryRecalls(t *testing.T) {
+	resultSets := []milvusclient.ResultSet{
+		{ResultCount: 2, IDs: column.NewColumnInt64("id", []int64{1, 2}), Recall: 0.9},
+		{ResultCount: 2, IDs: column.NewColumnInt64("id", []int64{5, 8}), Recall: 0.7},
+	}
+
+	recalls := queryRecalls(resultSets, matchQueries(resultSets, []queryTruth{{ints: []int64{1, 2}}, {ints: []int64{5, 6}}}, 2))
+	assert.Equal(t, []float64{1, 0.5}, recalls)
+	assert.Equal(t, 0.75, mean(recalls))
+
+	// Without ground truth, the server estimate is used
+	recalls = queryRecalls(resultSets, matchQueries(resultSets, nil, 2))
+	assert.InDeltaSlice(t, []float64{0.9, 0.7}, recalls, 1e-6)
+
+	// A missing result set scores 0
+	assert.Equal(t, []float64{1, 0}, queryRecalls(resultSets[:1], matchQueries(resultSets[:1], []queryTruth{{ints: []int64{1}}, {ints: []int64{5}}}, 2)))
+
+	// String keys match integer IDs by their formatted value
+	assert.Equal(t, []float64{0.5}, queryRecalls(resultSets, matchQueries(resultSets, []queryTruth{{keys: []string{"2", "doc-1"}}}, 2)))
+	varChars := []milvusclient.ResultSet{{ResultCount: 2, IDs: column.NewColumnVarChar("id", []string{"7", "doc-1"})}}
+	assert.Equal(t, []float64{1}, queryRecalls(varChars, matchQueries(varChars, []queryTruth{{ints: []int64{7}}}, 2)))
+}
+
+func TestMatchQueriesParallel(t *testing.T) {
+	nq, topK := 100, 1000
+	resultSets := make([]milvusclient.ResultSet, nq)
+	truth := make([]queryTruth, nq)
+	for q := range resultSets {
+		ids := make([]int64, topK)
+		for i := range ids {
+			ids[i] = int64(q*topK + i)
+		}
+		resultSets[q] = milvusclient.ResultSet{ResultCount: topK, IDs: column.NewColumnInt64("id", ids)}
+		// The first half of the results are true neighbors
+		truth[q] = queryTruth{ints: append(ids[:topK/2:topK/2], make([]int64, topK/2)...)}
+	}
+	require.GreaterOrEqual(t, nq*topK, recallParallelWork)
+
+	matches := matchQueries(resultSets, truth, topK)
+	for _, recall := range queryRecalls(resultSets, matches) {
+		assert.Equal(t, 0.5, recall)
+	}
+	assert.Equal(t, []float64{1}, scoreQueries(matches[:1], reciprocalRank))
+
+	// Matching a query reuses the buffers of the previous one
+	scratch := &matchScratch{}
+	allocs := testing.AllocsPerRun(10, func() {
+		scratch.match(resultSets[1], truth[1], topK)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestIDKey(t *testing.T) {
+	assert.Equal(t, "42", idKey(float64(42)))
+	assert.Equal(t, "42", idKey(int64(42)))
+	assert.Equal(t, "1.5", idKey(1.5))
+	assert.Equal(t, "doc-1", idKey("doc-1"))
+	assert.Equal(t, 0.0, mean(nil))
+}
+
+func TestQualityScores(t *testing.T) {
+	truth := []string{"1", "2", "3", "4"}
+
+	assert.Equal(t, 0.5, precisionAtK(matchOf([]string{"1", "9", "2", "8"}, truth, 4)))
+	assert.Equal(t, 0.0, precisionAtK(matchOf([]string{"1"}, truth, 0)))
+
+	assert.Equal(t, 1.0, ndcgAtK(matchOf([]string{"2", "1"}, truth, 2)))
+	// The same hits ranked lower score less, while recall is unchanged
+	top := ndcgAtK(matchOf([]string{"1", "2", "8", "9"}, truth, 4))
+	bottom := ndcgAtK(matchOf([]string{"8", "9", "1", "2"}, truth, 4))
+	assert.Greater(t, top, bottom)
+	assert.Equal(t, recallAtK(matchOf([]string{"1", "2", "8", "9"}, truth, 4)), recallAtK(matchOf([]string{"8", "9", "1", "2"}, truth, 4)))
+	assert.Equal(t, 0.0, ndcgAtK(matchOf(nil, nil, 4)))
+
+	assert.Equal(t, 1.0, reciprocalRank(matchOf([]string{"1", "2"}, truth, 4)))
+	assert.Equal(t, 1.0/3, reciprocalRank(matchOf([]string{"9", "2", "1"}, truth, 4)))
+	assert.Equal(t, 0.0, reciprocalRank(matchOf([]string{"9", "2", "1"}, truth, 2)))
+	assert.Equal(t, 0.0, reciprocalRank(matchOf([]string{"1"}, nil, 4)))
+}
+
+func TestQualityMetricsOption(t *testing.T) {
+	names, err := qualityMetricsOption(map[string]interface{}{}, false)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	names, err = qualityMetricsOption(map[string]interface{}{"qualityMetrics": []interface{}{"ndcg", "mrr", "ndcg"}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ndcg", "mrr"}, names)
+
+	_, err = qualityMetricsOption(map[string]interface{}{"qualityMetrics": []interface{}{"mrr"}}, false)
+	assert.ErrorContains(t, err, "requires groundTruth")
+	_, err = qualityMetricsOption(map[string]interface{}{"qualityMetrics": []interface{}{"map"}}, true)
+	assert.ErrorContains(t, err, "unknown quality metric")
+	_, err = qualityMetricsOption(map[string]interface{}{"qualityMetrics": "mrr"}, true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package milvus
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"go.k6.io/k6/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reconnectInterval throttles re-dial attempts while the connection stays broken
+const reconnectInterval = time.Second
+
+// connTracker observes RPC results on a connection to detect when it is broken.
+// A connection counts as broken once an RPC fails with codes.Unavailable (after the SDK's
+// own retries), and healthy again after any successful RPC.
+type connTracker struct {
+	broken atomic.Bool
+}
+
+func (t *connTracker) interceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		switch {
+		case err == nil:
+			t.broken.Store(false)
+		case status.Code(err) == codes.Unavailable:
+			t.broken.Store(true)
+		}
+		return err
+	}
+}
+
+// milvus returns the underlying SDK client, re-dialing first when the connection is broken.
+// Re-dial attempts are emitted as milvus_reconnects and the outcome as milvus_connection_state
+// (1 connected, 0 broken). If the re-dial fails, the old connection is kept and retried later.
+func (c *Client) milvus() *milvusclient.Client {
+	if c.base != nil {
+		return c.base.milvus()
+	}
+	if c.tracker == nil || c.redial == nil || !c.tracker.broken.Load() {
+		return c.client
+	}
+	if time.Since(c.lastRedial) < reconnectInterval {
+		return c.client
+	}
+	c.lastRedial = time.Now()
+	c.pushMetric(c.connectionStateMetric(), 0, nil)
+
+	redialed, tracker, err := c.redial()
+	if c.metrics != nil {
+		tags := map[string]string{"status": "ok"}
+		if err != nil {
+			tags = errorTags(map[string]string{"status": "error"}, err)
+		}
+		c.pushMetric(c.metrics.Reconnects, 1, tags)
+	}
+	if err != nil {
+		return c.client
+	}
+
+	stale := c.client
+	c.client = redialed
+	c.tracker = tracker
+	c.version = "" // The server may have been upgraded while the connection was down
+	c.pushMetric(c.connectionStateMetric(), 1, nil)
+	if !c.pooled { // The pool closes the connections it evicts
+		go func() { _ = stale.Close(context.Background()) }()
+	}
+	return c.client
+}
+
+// connectionStateMetric returns the connection state gauge, or nil when metrics are unavailable
+func (c *Client) connectionStateMetric() *metrics.Metric {
+	if c.metrics == nil {
+		return nil
+	}
+	return c.metrics.ConnectionState
+}
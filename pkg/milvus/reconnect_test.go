@@ -0,0 +1,118 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConnTrackerInterceptor(t *testing.T) {
+	tracker := &connTracker{}
+	interceptor := tracker.interceptor()
+	call := func(err error) {
+		invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return err }
+		_ = interceptor(context.Background(), "/milvus.proto.milvus.MilvusService/Search", nil, nil, nil, invoker)
+	}
+
+	call(status.Error(codes.Unavailable, "connection refused"))
+	assert.True(t, tracker.broken.Load())
+
+	// Other errors do not change the state
+	call(status.Error(codes.InvalidArgument, "bad request"))
+	assert.True(t, tracker.broken.Load())
+
+	call(nil)
+	assert.False(t, tracker.broken.Load())
+}
+
+// reconnectClient returns a client with a broken connection and a VU collecting samples
+func reconnectClient(t *testing.T, redialErr error) (*Client, chan metrics.SampleContainer) {
+	t.Helper()
+	vu, samples := newMetricsVU(t)
+	m := registerMetrics(vu)
+
+	client := &Client{
+		client:  &milvusclient.Client{},
+		vu:      vu,
+		metrics: m,
+		tracker: &connTracker{},
+		redial: func() (*milvusclient.Client, *connTracker, error) {
+			if redialErr != nil {
+				return nil, nil, redialErr
+			}
+			return &milvusclient.Client{}, &connTracker{}, nil
+		},
+	}
+	client.tracker.broken.Store(true)
+	return client, samples
+}
+
+// sampleValues drains the samples and returns the last value per metric name, plus reconnect statuses
+func sampleValues(samples chan metrics.SampleContainer) (map[string]float64, []string) {
+	values := make(map[string]float64)
+	var statuses []string
+	for len(samples) > 0 {
+		sample := (<-samples).(metrics.Sample)
+		values[sample.Metric.Name] = sample.Value
+		if sample.Metric.Name == "milvus_reconnects" {
+			outcome, _ := sample.Tags.Get("status")
+			statuses = append(statuses, outcome)
+		}
+	}
+	return values, statuses
+}
+
+func TestClientReconnect(t *testing.T) {
+	client, samples := reconnectClient(t, nil)
+	stale := client.client
+
+	got := client.milvus()
+
+	assert.NotSame(t, stale, got)
+	assert.Same(t, got, client.client)
+	assert.False(t, client.tracker.broken.Load())
+
+	values, statuses := sampleValues(samples)
+	assert.Equal(t, []string{"ok"}, statuses)
+	assert.Equal(t, 1.0, values["milvus_connection_state"])
+}
+
+func TestClientReconnectFailure(t *testing.T) {
+	client, samples := reconnectClient(t, errors.New("connection refused"))
+	stale := client.client
+
+	assert.Same(t, stale, client.milvus())
+	assert.True(t, client.tracker.broken.Load())
+
+	values, statuses := sampleValues(samples)
+	assert.Equal(t, []string{"error"}, statuses)
+	assert.Equal(t, 0.0, values["milvus_connection_state"])
+
+	// Attempts are throttled while the connection stays broken
+	client.milvus()
+	_, statuses = sampleValues(samples)
+	assert.Empty(t, statuses)
+
+	client.lastRedial = time.Now().Add(-reconnectInterval)
+	client.milvus()
+	_, statuses = sampleValues(samples)
+	assert.Equal(t, []string{"error"}, statuses)
+}
+
+func TestClientReconnectDisabled(t *testing.T) {
+	client, samples := reconnectClient(t, nil)
+	client.redial = nil
+	stale := client.client
+
+	require.Same(t, stale, client.milvus())
+	assert.Empty(t, samples)
+}
@@ -0,0 +1,268 @@
+package milvus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// Alias reindex defaults
+const (
+	defaultReindexBlip  = time.Second
+	defaultReindexGrace = 5 * time.Second
+)
+
+// Values of the reindex_phase tag
+const (
+	reindexIdle   = "idle"
+	reindexBuild  = "build"
+	reindexFlip   = "flip"
+	reindexDrop   = "drop"
+	reindexSettle = "settle"
+	reindexDone   = "done"
+)
+
+// AliasReindexConfig configures milvus.aliasReindex()
+type AliasReindexConfig struct {
+	Alias        string `json:"alias"`                  // Alias the searches target and run() flips
+	Blip         string `json:"blip,omitempty"`         // Searches slower than this during the flip are blips (default: "1s")
+	Grace        string `json:"grace,omitempty"`        // Time after the drop still counted as part of the flip (default: "5s")
+	KeepPrevious bool   `json:"keepPrevious,omitempty"` // Keep the collection the alias pointed to instead of dropping it
+}
+
+// aliasReindexState is the progress of a reindex, shared by the VU running it and the VUs
+// searching the alias
+type aliasReindexState struct {
+	mu       sync.Mutex
+	phase    string
+	searches int64 // Searches overlapping the flip
+	errors   int64 // Of those, the failed ones
+	blips    int64 // Of those, the successful ones slower than the blip threshold
+}
+
+// inFlip reports whether the alias may be changing under the searches
+func (s *aliasReindexState) inFlip() bool {
+	return s.phase == reindexFlip || s.phase == reindexDrop || s.phase == reindexSettle
+}
+
+func (s *aliasReindexState) setPhase(phase string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.phase = phase
+}
+
+func (s *aliasReindexState) currentPhase() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.phase, s.inFlip()
+}
+
+// AliasReindex orchestrates the zero-downtime reindex pattern behind a collection alias while
+// other VUs search the alias: run() builds, fills, indexes and loads a new collection as
+// client.prepare(), flips the alias to it, drops the collection the alias pointed to and waits
+// for the grace period. search() searches the alias, with every metric tagged with
+// reindex_phase: idle, build, flip, drop, settle or done. Searches overlapping the flip, from
+// the alias change until the grace period after the drop, that fail or take longer than the
+// blip threshold are counted in milvus_alias_flip_errors, tagged with alias and kind, error or
+// blip. Reindexes of the same alias share their phase across all VUs.
+//
+// Usage in k6:
+//
+//	const reindex = milvus.aliasReindex({ alias: 'products', blip: '500ms' });
+//	export const options = {
+//	    scenarios: {
+//	        search: { executor: 'constant-vus', vus: 8, duration: '10m', exec: 'search' },
+//	        reindex: { executor: 'shared-iterations', iterations: 1, startTime: '1m', exec: 'flip' },
+//	    },
+//	    thresholds: { milvus_alias_flip_errors: ['count==0'] },
+//	};
+//	export function search() { reindex.search(client, gen.next(1), 10, { vectorField: 'embedding' }); }
+//	export function flip() { reindex.run(client, { schema: schemaV2, source: ds, index: { indexType: 'HNSW' } }); }
+type AliasReindex struct {
+	alias        string
+	blip         time.Duration
+	grace        time.Duration
+	keepPrevious bool
+	state        *aliasReindexState
+}
+
+// AliasReindex creates the reindex helper of an alias
+func (m *Milvus) AliasReindex(configInput map[string]interface{}) (*AliasReindex, error) {
+	var config AliasReindexConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid alias reindex config: %v", err)
+	}
+	if config.Alias == "" {
+		return nil, fmt.Errorf("alias reindex alias required")
+	}
+	r := &AliasReindex{alias: config.Alias, blip: defaultReindexBlip, grace: defaultReindexGrace, keepPrevious: config.KeepPrevious}
+	for _, option := range []struct {
+		name  string
+		value string
+		into  *time.Duration
+	}{
+		{"blip", config.Blip, &r.blip},
+		{"grace", config.Grace, &r.grace},
+	} {
+		if option.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(option.value)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("alias reindex %s must be a duration, got %q", option.name, option.value)
+		}
+		*option.into = d
+	}
+	state, err := sharedValue(m.shared, "aliasReindex\x00"+config.Alias, func() (*aliasReindexState, error) {
+		return &aliasReindexState{phase: reindexIdle}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.state = state
+	return r, nil
+}
+
+// Run reindexes behind the alias: it creates and fills the collection of prepareConfig, named
+// by its schema, as client.prepare(), points the alias at it, creating the alias if it does not
+// resolve, drops the collection the alias pointed to unless keepPrevious is set, and waits for
+// the grace period so that the searches still in flight are counted. The result holds the new
+// and previous collections, the time of each phase in milliseconds, and flip: the searches that
+// overlapped the flip, the failed ones and the blips.
+func (r *AliasReindex) Run(client *Client, prepareConfig map[string]interface{}) interface{} {
+	start := time.Now()
+	fail := func(err string, result map[string]interface{}) interface{} {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err,
+			Result:       result,
+		})
+	}
+	if client == nil {
+		return fail("alias reindex requires a client", nil)
+	}
+	_, _, config, err := prepareOptions(prepareConfig)
+	if err != nil {
+		return fail(err.Error(), nil)
+	}
+	coll := config.Schema.Name
+
+	r.state.mu.Lock()
+	if r.state.phase != reindexIdle && r.state.phase != reindexDone {
+		r.state.mu.Unlock()
+		return fail(fmt.Sprintf("a reindex of alias %s is already running", r.alias), nil)
+	}
+	r.state.phase = reindexBuild
+	r.state.searches, r.state.errors, r.state.blips = 0, 0, 0
+	r.state.mu.Unlock()
+	defer r.state.setPhase(reindexDone)
+
+	// The alias does not resolve before the first reindex, so it is created instead of altered
+	previous := ""
+	if alias, err := client.milvus().DescribeAlias(client.context(), milvusclient.NewDescribeAliasOption(r.alias)); err == nil {
+		previous = alias.CollectionName
+	}
+	if previous == coll {
+		return fail(fmt.Sprintf("alias %s already points to collection %s, name the new collection differently", r.alias, coll), nil)
+	}
+	details := map[string]interface{}{"alias": r.alias, "collection": coll, "previous": previous}
+
+	prepared := client.Prepare(prepareConfig).(map[string]interface{})
+	timings := make(map[string]interface{})
+	if result, ok := prepared["result"].(map[string]interface{}); ok {
+		if phases, ok := result["timings"].(map[string]interface{}); ok {
+			for name, ms := range phases {
+				timings[name] = ms
+			}
+		}
+	}
+	details["timings"] = timings
+	if prepared["success"] != true {
+		return fail(fmt.Sprintf("alias reindex: %v", prepared["error"]), details)
+	}
+
+	// phase runs one step of the flip, recording its time
+	phase := func(name string, run func() map[string]interface{}) map[string]interface{} {
+		r.state.setPhase(name)
+		phaseStart := time.Now()
+		result := run()
+		timings[name] = float64(time.Since(phaseStart).Milliseconds())
+		return result
+	}
+	flipped := phase(reindexFlip, func() map[string]interface{} {
+		if previous == "" {
+			return client.CreateAlias(r.alias, coll).(map[string]interface{})
+		}
+		return client.AlterAlias(r.alias, coll).(map[string]interface{})
+	})
+	if flipped["success"] != true {
+		return fail(fmt.Sprintf("alias reindex flip: %v", flipped["error"]), details)
+	}
+	if previous != "" && !r.keepPrevious {
+		if dropped := phase(reindexDrop, func() map[string]interface{} {
+			return client.DropCollection(previous).(map[string]interface{})
+		}); dropped["success"] != true {
+			return fail(fmt.Sprintf("alias reindex drop of %s: %v", previous, dropped["error"]), details)
+		}
+	}
+	r.state.setPhase(reindexSettle)
+	sleepContext(client.context(), r.grace)
+
+	details["flip"] = r.Stats()
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       details,
+	})
+}
+
+// Search searches the alias with the client, as client.search(), with every metric tagged with
+// the reindex phase, which the result holds in "reindex_phase". A search overlapping the flip
+// that fails or is slower than the blip threshold is counted in milvus_alias_flip_errors.
+func (r *AliasReindex) Search(client *Client, vectors interface{}, topK int, params map[string]interface{}) interface{} {
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "search requires a client"})
+	}
+	phase, startInFlip := r.state.currentPhase()
+	start := time.Now()
+	result := client.withTags(map[string]string{"reindex_phase": phase}).Search(vectors, topK, params, r.alias).(map[string]interface{})
+	elapsed := time.Since(start)
+	result["reindex_phase"] = phase
+
+	_, endInFlip := r.state.currentPhase()
+	if !startInFlip && !endInFlip {
+		return result
+	}
+	kind := ""
+	r.state.mu.Lock()
+	r.state.searches++
+	switch {
+	case result["success"] != true:
+		r.state.errors++
+		kind = "error"
+	case elapsed > r.blip:
+		r.state.blips++
+		kind = "blip"
+	}
+	r.state.mu.Unlock()
+	if kind != "" && client.metrics != nil {
+		client.pushMetric(client.metrics.AliasFlipErrors, 1, map[string]string{"alias": r.alias, "kind": kind})
+	}
+	return result
+}
+
+// Stats returns the reindex phase and the searches that overlapped the flip so far, as
+// { phase, searches, errors, blips }
+func (r *AliasReindex) Stats() map[string]interface{} {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return map[string]interface{}{
+		"phase":    r.state.phase,
+		"searches": r.state.searches,
+		"errors":   r.state.errors,
+		"blips":    r.state.blips,
+	}
+}
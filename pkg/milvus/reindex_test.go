@@ -0,0 +1,188 @@
+package milvus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reindexServer serves client.prepare() of the collection bench as prepareServer and resolves
+// one alias, failing the first search after the alias changes. Other collections exist.
+type reindexServer struct {
+	prepareServer
+	created    bool
+	target     string // Collection of the alias, "" while it does not exist
+	failNext   bool
+	searchedAt []string // Alias target of each search
+}
+
+func (s *reindexServer) DescribeCollection(ctx context.Context, req *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error) {
+	s.mu.Lock()
+	created := s.created
+	s.mu.Unlock()
+	if req.GetCollectionName() == "bench" && !created {
+		return &milvuspb.DescribeCollectionResponse{Status: merr.Status(merr.ErrCollectionNotFound)}, nil
+	}
+	return s.chunkServer.DescribeCollection(ctx, req)
+}
+
+func (s *reindexServer) CreateCollection(context.Context, *milvuspb.CreateCollectionRequest) (*commonpb.Status, error) {
+	s.record("create")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.created = true
+	return &commonpb.Status{}, nil
+}
+
+func (s *reindexServer) DescribeAlias(_ context.Context, req *milvuspb.DescribeAliasRequest) (*milvuspb.DescribeAliasResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.target == "" {
+		return &milvuspb.DescribeAliasResponse{Status: merr.Status(merr.ErrAliasNotFound)}, nil
+	}
+	return &milvuspb.DescribeAliasResponse{Status: &commonpb.Status{}, Alias: req.GetAlias(), Collection: s.target}, nil
+}
+
+func (s *reindexServer) CreateAlias(_ context.Context, req *milvuspb.CreateAliasRequest) (*commonpb.Status, error) {
+	s.record("create alias " + req.GetCollectionName())
+	s.flip(req.GetCollectionName())
+	return &commonpb.Status{}, nil
+}
+
+func (s *reindexServer) AlterAlias(_ context.Context, req *milvuspb.AlterAliasRequest) (*commonpb.Status, error) {
+	s.record("alter alias " + req.GetCollectionName())
+	s.flip(req.GetCollectionName())
+	return &commonpb.Status{}, nil
+}
+
+func (s *reindexServer) flip(coll string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.target = coll
+	s.failNext = true
+}
+
+func (s *reindexServer) DropCollection(_ context.Context, req *milvuspb.DropCollectionRequest) (*commonpb.Status, error) {
+	s.record("drop " + req.GetCollectionName())
+	return &commonpb.Status{}, nil
+}
+
+func (s *reindexServer) Search(context.Context, *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searchedAt = append(s.searchedAt, s.target)
+	if s.failNext {
+		s.failNext = false
+		return &milvuspb.SearchResults{Status: merr.Status(merr.ErrCollectionNotFound)}, nil
+	}
+	data := &schemapb.SearchResultData{NumQueries: 1, TopK: 1, Topks: []int64{1}, Scores: []float32{0.1},
+		Ids: &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}}}
+	return &milvuspb.SearchResults{Status: &commonpb.Status{}, Results: data}, nil
+}
+
+func TestAliasReindex(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	service := &reindexServer{target: "bench_v1"}
+	searcher := benchClient(t, service, vu)
+	runner := benchClient(t, service, &metricsVU{})
+
+	m := &Milvus{shared: &sync.Map{}}
+	reindex, err := m.AliasReindex(map[string]interface{}{"alias": "products"})
+	require.NoError(t, err)
+	shared, err := m.AliasReindex(map[string]interface{}{"alias": "products", "grace": "100ms"})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	phases := make(chan string, 10000)
+	go func() {
+		defer close(phases)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			phases <- reindex.Search(searcher, [][]float32{{1, 0}}, 1, map[string]interface{}{"vectorField": "embedding"}).(map[string]interface{})["reindex_phase"].(string)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	result := shared.Run(runner, map[string]interface{}{"schema": prepareSchema, "source": &SharedVectors{vectors: [][]float32{{0, 1}, {1, 1}}}}).(map[string]interface{})
+	close(done)
+	require.Equal(t, true, result["success"], result["error"])
+	seen := map[string]bool{}
+	for phase := range phases {
+		seen[phase] = true
+	}
+
+	details := result["result"].(map[string]interface{})
+	assert.Equal(t, "bench", details["collection"])
+	assert.Equal(t, "bench_v1", details["previous"])
+	assert.Contains(t, details["timings"], "insert")
+	assert.Contains(t, details["timings"], "flip")
+	assert.Equal(t, []string{"create", "flush", "index", "load", "alter alias bench", "drop bench_v1"}, service.calls)
+	flip := details["flip"].(map[string]interface{})
+	assert.Equal(t, float64(1), flip["errors"], "the first search after the flip fails")
+	assert.GreaterOrEqual(t, flip["searches"], float64(1))
+	assert.True(t, seen[reindexSettle], "searches run during the grace period")
+	assert.Equal(t, reindexDone, reindex.Stats()["phase"], "the phase is shared by the helpers of the alias")
+	assert.Contains(t, service.searchedAt, "bench")
+
+	kinds := map[string]int{}
+	for _, sample := range drainSamples(samples) {
+		if sample.Metric.Name == "milvus_alias_flip_errors" {
+			alias, _ := sample.Tags.Get("alias")
+			assert.Equal(t, "products", alias)
+			kind, _ := sample.Tags.Get("kind")
+			kinds[kind]++
+		}
+	}
+	assert.Equal(t, 1, kinds["error"])
+}
+
+func TestAliasReindexCreatesAlias(t *testing.T) {
+	service := &reindexServer{}
+	client := benchClient(t, service, &metricsVU{})
+	reindex, err := (&Milvus{}).AliasReindex(map[string]interface{}{"alias": "products", "grace": "0s"})
+	require.NoError(t, err)
+
+	result := reindex.Run(client, map[string]interface{}{"schema": prepareSchema, "source": &SharedVectors{vectors: [][]float32{{0, 1}}}}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, "", result["result"].(map[string]interface{})["previous"])
+	assert.Equal(t, []string{"create", "flush", "index", "load", "create alias bench"}, service.calls)
+
+	// The alias now points to the collection of the schema
+	result = reindex.Run(client, map[string]interface{}{"schema": prepareSchema, "source": &SharedVectors{vectors: [][]float32{{0, 1}}}, "recreate": true}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "already points to collection bench")
+	assert.Len(t, service.calls, 5, "the serving collection is not recreated")
+}
+
+func TestAliasReindexErrors(t *testing.T) {
+	m := &Milvus{}
+	for _, config := range []map[string]interface{}{
+		{},
+		{"alias": "products", "blip": "slow"},
+		{"alias": "products", "grace": "-1s"},
+	} {
+		_, err := m.AliasReindex(config)
+		assert.Error(t, err, config)
+	}
+
+	reindex, err := m.AliasReindex(map[string]interface{}{"alias": "products"})
+	require.NoError(t, err)
+	assert.Equal(t, false, reindex.Run(nil, nil).(map[string]interface{})["success"])
+	assert.Equal(t, false, reindex.Search(nil, nil, 1, nil).(map[string]interface{})["success"])
+
+	client := benchClient(t, &reindexServer{}, &metricsVU{})
+	reindex.state.setPhase(reindexBuild)
+	result := reindex.Run(client, map[string]interface{}{"schema": prepareSchema, "source": &SharedVectors{vectors: [][]float32{{0, 1}}}}).(map[string]interface{})
+	assert.Contains(t, result["error"], "already running")
+}
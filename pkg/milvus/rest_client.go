@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 )
@@ -55,6 +56,8 @@ func (m *Milvus) createRestClient(address, collectionName string, token ...strin
 
 	if len(token) > 0 && token[0] != "" {
 		rc.token = token[0]
+	} else {
+		rc.token = os.Getenv(EnvToken)
 	}
 
 	return rc
@@ -333,3 +333,19 @@ func TestErrorResultAndSuccessResult(t *testing.T) {
 		assert.Equal(t, float64(5), r["count"])
 	})
 }
+
+func TestCreateRestClientToken(t *testing.T) {
+	m := &Milvus{}
+
+	t.Run("explicit token", func(t *testing.T) {
+		t.Setenv(EnvToken, "env-token")
+		rc := m.createRestClient("localhost:19530", "", "api-key")
+		assert.Equal(t, "api-key", rc.token)
+	})
+
+	t.Run("env fallback", func(t *testing.T) {
+		t.Setenv(EnvToken, "env-token")
+		rc := m.createRestClient("localhost:19530", "")
+		assert.Equal(t, "env-token", rc.token)
+	})
+}
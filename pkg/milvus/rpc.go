@@ -0,0 +1,228 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// clientContextKey carries the calling Client in RPC contexts, so interceptors shared by
+// pooled connections can emit samples on the VU that made the call
+type clientContextKey struct{}
+
+// dataSizeOperation names the payload counted in milvus_data_size for an RPC:
+// the request for writes, the response for reads
+type dataSizeOperation struct {
+	operation string
+	request   bool
+}
+
+// dataSizeOperations are the RPCs whose payload is emitted as milvus_data_size
+var dataSizeOperations = map[string]dataSizeOperation{
+	"Insert":       {operation: "insert", request: true},
+	"Upsert":       {operation: "upsert", request: true},
+	"Search":       {operation: "search"},
+	"HybridSearch": {operation: "hybrid_search"},
+	"Query":        {operation: "query"},
+}
+
+// observeRPC is the unary interceptor emitting the per-RPC metrics (errors, payload sizes, rows,
+// in-flight calls) of the client carried by the context and adding the call to milvus.summary().
+// The samples of each RPC are pushed to the VU together, in one ConnectedSamples.
+func observeRPC(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	c, ok := ctx.Value(clientContextKey{}).(*Client)
+	if !ok || c.metrics == nil {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	// The samples of the call are pushed together, except the in-flight count at its start
+	samples := c.sampleBatch()
+	c.trackInflight(samples, 1)
+	samples.push()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	c.trackInflight(samples, -1)
+	defer samples.push()
+
+	name := path.Base(method)
+	call := operationStats{ops: 1}
+	defer func() { c.summary.add(operationName(name), call) }()
+
+	c.trackTransfer(samples, name, req, reply, err)
+	c.addMemorySamples(samples)
+	if op, rows, isWrite := batchRows(req); isWrite {
+		samples.add(c.metrics.BatchSize, float64(rows), map[string]string{"operation": op})
+	}
+	methodTags := map[string]string{"method": name}
+	if rpcErr := merr.CheckRPCCall(reply, err); rpcErr != nil {
+		samples.add(c.metrics.Errors, 1, methodTags)
+		tags := errorTags(map[string]string{"method": name}, rpcErr)
+		samples.add(c.metrics.ErrorTypes, 1, tags)
+		if isBackpressure(tags["error_type"]) {
+			samples.add(c.metrics.RateLimited, 1, map[string]string{"method": name, "error_type": tags["error_type"]})
+		}
+		call.errors = 1
+		return err
+	}
+	samples.add(c.metrics.Errors, 0, methodTags)
+	if op, counted := dataSizeOperations[name]; counted {
+		payload := reply
+		if op.request {
+			payload = req
+		}
+		if msg, isProto := payload.(proto.Message); isProto {
+			call.bytes = int64(proto.Size(msg))
+			samples.add(c.metrics.DataSize, float64(call.bytes), map[string]string{"operation": op.operation})
+		}
+	}
+	if op, rows := mutatedRows(name, reply); rows > 0 {
+		call.rows = rows
+		samples.add(c.metrics.Rows, float64(rows), map[string]string{"operation": op})
+	}
+	if results, isSearch := reply.(*milvuspb.SearchResults); isSearch {
+		tags := map[string]string{"operation": dataSizeOperations[name].operation}
+		for _, hits := range results.GetResults().GetTopks() {
+			samples.add(c.metrics.SearchHits, float64(hits), tags)
+		}
+	}
+	return err
+}
+
+// trackTransfer emits the serialized request and response sizes of an RPC. The response is counted
+// whenever the server answered, including answers carrying a Milvus error status.
+func (c *Client) trackTransfer(samples *sampleBatch, method string, req, reply any, err error) {
+	tags := map[string]string{"operation": operationName(method)}
+	if msg, isProto := req.(proto.Message); isProto {
+		samples.add(c.metrics.DataSent, float64(proto.Size(msg)), tags)
+	}
+	if msg, isProto := reply.(proto.Message); isProto && err == nil {
+		samples.add(c.metrics.DataReceived, float64(proto.Size(msg)), tags)
+	}
+}
+
+// batchRows returns the operation and the number of rows sent by an insert or upsert request
+func batchRows(req any) (string, uint32, bool) {
+	switch r := req.(type) {
+	case *milvuspb.InsertRequest:
+		return "insert", r.GetNumRows(), true
+	case *milvuspb.UpsertRequest:
+		return "upsert", r.GetNumRows(), true
+	}
+	return "", 0, false
+}
+
+// mutatedRows returns the operation and the number of entities written by an Insert, Upsert or
+// Delete RPC, as reported by the server. Each entity counts once, whatever its number of vector fields.
+func mutatedRows(method string, reply any) (string, int64) {
+	result, ok := reply.(*milvuspb.MutationResult)
+	if !ok {
+		return "", 0
+	}
+	switch method {
+	case "Insert":
+		return "insert", result.GetInsertCnt()
+	case "Upsert":
+		return "upsert", result.GetUpsertCnt()
+	case "Delete":
+		return "delete", result.GetDeleteCnt()
+	}
+	return "", 0
+}
+
+// trackInflight adjusts the test-wide in-progress RPC count and emits it as milvus_inflight_requests
+func (c *Client) trackInflight(samples *sampleBatch, delta int64) {
+	if c.inflight == nil {
+		return
+	}
+	samples.add(c.metrics.InflightRequests, float64(c.inflight.Add(delta)), nil)
+}
+
+// isBackpressure reports whether an error type means the cluster shed load rather than failed
+func isBackpressure(errType string) bool {
+	return errType == "rate_limited" || errType == "quota_exceeded"
+}
+
+// errorTags adds the error_type and error_code tags for err to tags
+func errorTags(tags map[string]string, err error) map[string]string {
+	tags["error_type"] = errorType(err)
+	if code := errorCode(err); code != "" {
+		tags["error_code"] = code
+	}
+	return tags
+}
+
+// errorCode returns the Milvus error code of err, or the gRPC status code name for
+// transport errors. It is empty when err carries no code.
+func errorCode(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return s.Code().String()
+	}
+	if merr.IsMilvusError(err) {
+		return strconv.Itoa(int(merr.Code(err)))
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded.String()
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled.String()
+	}
+	return ""
+}
+
+// errorType classifies an RPC error for metric tags. Rate limiting and quota errors get
+// their own types, including the forms returned by Zilliz Cloud.
+func errorType(err error) string {
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Canceled:
+			return "canceled"
+		case codes.DeadlineExceeded:
+			return "deadline_exceeded"
+		case codes.Unavailable:
+			return "unavailable"
+		case codes.ResourceExhausted:
+			return "rate_limited"
+		case codes.Unauthenticated:
+			return "unauthorized"
+		case codes.PermissionDenied:
+			return "permission_denied"
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, merr.ErrServiceRateLimit), errors.Is(err, merr.ErrServiceTooManyRequests):
+		return "rate_limited"
+	case errors.Is(err, merr.ErrServiceQuotaExceeded):
+		return "quota_exceeded"
+	case errors.Is(err, merr.ErrPrivilegeNotAuthenticated), errors.Is(err, merr.ErrNeedAuthenticate):
+		return "unauthorized"
+	case errors.Is(err, merr.ErrPrivilegeNotPermitted):
+		return "permission_denied"
+	case errors.Is(err, merr.ErrCollectionNotFound):
+		return "collection_not_found"
+	case errors.Is(err, merr.ErrServiceNotReady), errors.Is(err, merr.ErrServiceUnavailable):
+		return "unavailable"
+	}
+
+	// Zilliz Cloud proxies reject over-limit requests and calls to suspended clusters with plain messages
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "ratelimiter"), strings.Contains(message, "rate limit"):
+		return "rate_limited"
+	case strings.Contains(message, "suspended"):
+		return "cluster_suspended"
+	}
+	return "other"
+}
@@ -0,0 +1,267 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{status.Error(codes.Unavailable, "connection refused"), "unavailable"},
+		{status.Error(codes.DeadlineExceeded, "timeout"), "deadline_exceeded"},
+		{status.Error(codes.ResourceExhausted, "too many requests"), "rate_limited"},
+		{status.Error(codes.Unauthenticated, "bad token"), "unauthorized"},
+		{merr.ErrServiceRateLimit, "rate_limited"},
+		{merr.ErrServiceQuotaExceeded, "quota_exceeded"},
+		{merr.ErrCollectionNotFound, "collection_not_found"},
+		{merr.ErrPrivilegeNotPermitted, "permission_denied"},
+		{errors.New("request is rejected by grpc RateLimiter middleware, please retry later"), "rate_limited"},
+		{errors.New("cluster in01-abc is suspended"), "cluster_suspended"},
+		{errors.New("boom"), "other"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, errorType(tt.err), tt.err.Error())
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "Unavailable", errorCode(status.Error(codes.Unavailable, "connection refused")))
+	assert.Equal(t, "8", errorCode(merr.ErrServiceRateLimit))
+	assert.Equal(t, "100", errorCode(fmt.Errorf("failed to search: %w", merr.ErrCollectionNotFound)))
+	assert.Equal(t, "DeadlineExceeded", errorCode(fmt.Errorf("dial: %w", context.DeadlineExceeded)))
+	assert.Empty(t, errorCode(errors.New("boom")))
+}
+
+func TestToMapErrorCause(t *testing.T) {
+	result := toMap(&OperationResult{
+		Error: "failed to insert: rate limit exceeded",
+		Cause: merr.ErrServiceRateLimit,
+	})
+	assert.Equal(t, "8", result["error_code"])
+	assert.Equal(t, "rate_limited", result["error_type"])
+
+	result = toMap(&OperationResult{Error: "collection name required"})
+	assert.NotContains(t, result, "error_code")
+	assert.NotContains(t, result, "error_type")
+}
+
+func TestObserveRPC(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := &Client{client: &milvusclient.Client{}, vu: vu, metrics: registerMetrics(vu)}
+
+	call := func(ctx context.Context, rpcErr error, reply *commonpb.Status) {
+		invoker := func(_ context.Context, _ string, _, out any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			if rpcErr == nil {
+				out.(*commonpb.Status).Code = reply.GetCode()
+				out.(*commonpb.Status).Reason = reply.GetReason()
+			}
+			return rpcErr
+		}
+		_ = observeRPC(ctx, "/milvus.proto.milvus.MilvusService/Insert", nil, &commonpb.Status{}, nil, invoker)
+	}
+
+	call(client.context(), nil, &commonpb.Status{})
+	call(client.context(), nil, merr.Status(merr.ErrServiceRateLimit))
+	call(client.context(), nil, merr.Status(merr.ErrServiceQuotaExceeded))
+	call(client.context(), status.Error(codes.Unavailable, "down"), nil)
+	call(context.Background(), status.Error(codes.Unavailable, "no client in context"), nil)
+
+	var rates, types, limited []string
+	for _, sample := range drainSamples(samples) {
+		method, _ := sample.Tags.Get("method")
+		switch sample.Metric.Name {
+		case "milvus_errors":
+			// Successes and failures share one time series per method
+			assert.Equal(t, map[string]string{"method": "Insert"}, sample.Tags.Map())
+			rates = append(rates, fmt.Sprintf("%s:%v", method, sample.Value))
+		case "milvus_error_types":
+			errType, _ := sample.Tags.Get("error_type")
+			code, _ := sample.Tags.Get("error_code")
+			types = append(types, fmt.Sprintf("%s:%s:%s", method, errType, code))
+		case "milvus_rate_limited":
+			errType, _ := sample.Tags.Get("error_type")
+			limited = append(limited, fmt.Sprintf("%s:%s", method, errType))
+		}
+	}
+	quotaCode := merr.Code(merr.ErrServiceQuotaExceeded)
+	assert.Equal(t, []string{"Insert:0", "Insert:1", "Insert:1", "Insert:1"}, rates)
+	assert.Equal(t, []string{"Insert:rate_limited:8", fmt.Sprintf("Insert:quota_exceeded:%d", quotaCode), "Insert:unavailable:Unavailable"}, types)
+	// Backpressure is counted apart from the unavailable server
+	assert.Equal(t, []string{"Insert:rate_limited", "Insert:quota_exceeded"}, limited)
+}
+
+func TestObserveRPCDataSize(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := &Client{client: &milvusclient.Client{}, vu: vu, metrics: registerMetrics(vu)}
+	ok := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return nil }
+
+	insert := &milvuspb.InsertRequest{
+		CollectionName: "products",
+		FieldsData: []*schemapb.FieldData{{
+			FieldName: "vector",
+			Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+				Dim:  4,
+				Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: make([]float32, 400)}},
+			}},
+		}},
+		NumRows: 100,
+	}
+	require.NoError(t, observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Insert", insert, &milvuspb.MutationResult{}, nil, ok))
+	require.NoError(t, observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Search", &milvuspb.SearchRequest{}, &milvuspb.SearchResults{CollectionName: "products"}, nil, ok))
+	require.NoError(t, observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/HasCollection", &milvuspb.HasCollectionRequest{}, &milvuspb.BoolResponse{}, nil, ok))
+
+	var dataSize []metrics.Sample
+	for _, sample := range drainSamples(samples) {
+		if sample.Metric.Name == "milvus_data_size" {
+			dataSize = append(dataSize, sample)
+		}
+	}
+	require.Len(t, dataSize, 2)
+	sample := dataSize[0]
+	operation, _ := sample.Tags.Get("operation")
+	assert.Equal(t, "insert", operation)
+	assert.Equal(t, float64(proto.Size(insert)), sample.Value)
+	assert.Greater(t, sample.Value, float64(400*4)) // Vector bytes plus field metadata
+
+	sample = dataSize[1]
+	operation, _ = sample.Tags.Get("operation")
+	assert.Equal(t, "search", operation)
+	assert.Equal(t, float64(proto.Size(&milvuspb.SearchResults{CollectionName: "products"})), sample.Value)
+}
+
+func TestObserveRPCInflight(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	var inflight atomic.Int64
+	inflight.Store(2) // Calls already in progress on other VUs
+	client := &Client{client: &milvusclient.Client{}, vu: vu, metrics: registerMetrics(vu), inflight: &inflight}
+
+	var during int64
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		during = inflight.Load()
+		return status.Error(codes.Unavailable, "down")
+	}
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/DescribeCollection", nil, &milvuspb.DescribeCollectionResponse{}, nil, invoker)
+
+	assert.EqualValues(t, 3, during)
+	assert.EqualValues(t, 2, inflight.Load())
+
+	var got []float64
+	for _, sample := range drainSamples(samples) {
+		if sample.Metric.Name == "milvus_inflight_requests" {
+			got = append(got, sample.Value)
+		}
+	}
+	assert.Equal(t, []float64{3, 2}, got)
+}
+
+func TestObserveRPCRows(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := &Client{client: &milvusclient.Client{}, vu: vu, metrics: registerMetrics(vu)}
+	ok := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return nil }
+
+	calls := []struct {
+		method string
+		reply  *milvuspb.MutationResult
+	}{
+		{"Insert", &milvuspb.MutationResult{InsertCnt: 100}},
+		{"Upsert", &milvuspb.MutationResult{UpsertCnt: 20, InsertCnt: 20}},
+		{"Delete", &milvuspb.MutationResult{DeleteCnt: 5}},
+		{"Delete", &milvuspb.MutationResult{}}, // Filter matched nothing
+		{"Insert", &milvuspb.MutationResult{Status: merr.Status(merr.ErrCollectionNotFound), InsertCnt: 7}},
+	}
+	for _, call := range calls {
+		_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/"+call.method, nil, call.reply, nil, ok)
+	}
+
+	var got []string
+	for _, sample := range drainSamples(samples) {
+		if sample.Metric.Name == "milvus_rows" {
+			operation, _ := sample.Tags.Get("operation")
+			got = append(got, fmt.Sprintf("%s:%v", operation, sample.Value))
+		}
+	}
+	assert.Equal(t, []string{"insert:100", "upsert:20", "delete:5"}, got)
+}
+
+func TestObserveRPCBatchSizeAndHits(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := &Client{client: &milvusclient.Client{}, vu: vu, metrics: registerMetrics(vu)}
+	ok := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return nil }
+	down := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "down")
+	}
+
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Insert", &milvuspb.InsertRequest{NumRows: 500}, &milvuspb.MutationResult{}, nil, ok)
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Upsert", &milvuspb.UpsertRequest{NumRows: 20}, &milvuspb.MutationResult{}, nil, down)
+	search := &milvuspb.SearchResults{Results: &schemapb.SearchResultData{NumQueries: 3, Topks: []int64{10, 0, 4}}}
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Search", &milvuspb.SearchRequest{}, search, nil, ok)
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/HybridSearch", &milvuspb.HybridSearchRequest{}, search, nil, down)
+
+	var got []string
+	for _, sample := range drainSamples(samples) {
+		switch sample.Metric.Name {
+		case "milvus_batch_size", "milvus_search_hits":
+			operation, _ := sample.Tags.Get("operation")
+			got = append(got, fmt.Sprintf("%s:%s:%v", sample.Metric.Name, operation, sample.Value))
+		}
+	}
+	// Batch sizes are recorded for failed writes too; hits only for successful searches
+	assert.Equal(t, []string{
+		"milvus_batch_size:insert:500",
+		"milvus_batch_size:upsert:20",
+		"milvus_search_hits:search:10",
+		"milvus_search_hits:search:0",
+		"milvus_search_hits:search:4",
+	}, got)
+}
+
+func TestObserveRPCDataSentReceived(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := &Client{client: &milvusclient.Client{}, vu: vu, metrics: registerMetrics(vu)}
+
+	query := &milvuspb.QueryRequest{CollectionName: "products", Expr: "id > 0"}
+	results := &milvuspb.QueryResults{CollectionName: "products", OutputFields: []string{"id", "title", "embedding"}}
+	ok := func(_ context.Context, _ string, _, out any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		proto.Merge(out.(proto.Message), results)
+		return nil
+	}
+	down := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "down")
+	}
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Query", query, &milvuspb.QueryResults{}, nil, ok)
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/GetLoadingProgress", &milvuspb.GetLoadingProgressRequest{CollectionName: "products"}, &milvuspb.GetLoadingProgressResponse{}, nil, down)
+
+	var got []string
+	for _, sample := range drainSamples(samples) {
+		switch sample.Metric.Name {
+		case "milvus_data_sent", "milvus_data_received":
+			operation, _ := sample.Tags.Get("operation")
+			got = append(got, fmt.Sprintf("%s:%s:%v", sample.Metric.Name, operation, sample.Value))
+		}
+	}
+	// Nothing is received from a server that could not be reached
+	assert.Equal(t, []string{
+		fmt.Sprintf("milvus_data_sent:query:%d", proto.Size(query)),
+		fmt.Sprintf("milvus_data_received:query:%d", proto.Size(results)),
+		fmt.Sprintf("milvus_data_sent:get_loading_progress:%d", proto.Size(&milvuspb.GetLoadingProgressRequest{CollectionName: "products"})),
+	}, got)
+}
@@ -0,0 +1,233 @@
+package milvus
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/grafana/sobek"
+	"github.com/milvus-io/milvus/client/v2/column"
+)
+
+// defaultInsertSampleSize is the number of rows an insert sample keeps by default
+const defaultInsertSampleSize = 1000
+
+// InsertSampleConfig configures milvus.insertSample()
+type InsertSampleConfig struct {
+	Size  int    `json:"size,omitempty"`  // Rows kept (default: 1000)
+	Field string `json:"field,omitempty"` // Vector field to record (default: the only vector column of each insert)
+	Seed  int64  `json:"seed,omitempty"`  // Seed of the reservoir and of draw()
+}
+
+// InsertSample keeps a uniform random sample of the rows inserted with it, as primary keys with
+// their vectors, shared by all VUs of the test. Searching for sampled vectors must return their
+// own primary keys first, which checks correctness on data the test inserted itself.
+//
+// Usage in k6:
+//
+//	const sample = milvus.insertSample('inserted', { size: 1000, field: 'embedding' });
+//	export default function () {
+//	    client.insert(gen.batch(100), { sample });
+//	    const q = sample.draw(10);
+//	    if (q) client.search(q.vectors, 10, { vectorField: 'embedding', groundTruth: q.groundTruth });
+//	}
+type InsertSample struct {
+	mu      sync.Mutex
+	config  InsertSampleConfig
+	rng     *rand.Rand
+	ids     []interface{}
+	vectors [][]float32
+	seen    int64 // Rows offered to the reservoir
+}
+
+// InsertSample returns the insert sample stored under name, creating it on the first call of
+// the test. Later calls with the same name return the same sample and ignore config.
+func (m *Milvus) InsertSample(name string, configInput ...interface{}) (*InsertSample, error) {
+	if name == "" {
+		return nil, fmt.Errorf("insert sample name must not be empty")
+	}
+	var config InsertSampleConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid insert sample config: %v", err)
+		}
+	}
+	if config.Size < 0 {
+		return nil, fmt.Errorf("invalid insert sample config: size must not be negative, got %d", config.Size)
+	}
+	if config.Size == 0 {
+		config.Size = defaultInsertSampleSize
+	}
+	return sharedValue(m.shared, "sample\x00"+name, func() (*InsertSample, error) {
+		return newInsertSample(config), nil
+	})
+}
+
+func newInsertSample(config InsertSampleConfig) *InsertSample {
+	return &InsertSample{config: config, rng: rand.New(rand.NewSource(config.Seed))}
+}
+
+// vectorColumn returns the rows of the vector field the sample records from insert data,
+// checked before the insert is sent
+func (s *InsertSample) vectorColumn(data map[string]interface{}) (interface{}, error) {
+	if s.config.Field != "" {
+		rows, ok := data[s.config.Field]
+		if !ok || !isVectorColumn(rows) {
+			return nil, fmt.Errorf("insert sample field %s is not a vector column of the data", s.config.Field)
+		}
+		return rows, nil
+	}
+	var found string
+	for name, rows := range data {
+		if !isVectorColumn(rows) {
+			continue
+		}
+		if found != "" {
+			return nil, fmt.Errorf("insert sample needs a field, the data has vector columns %s and %s", found, name)
+		}
+		found = name
+	}
+	if found == "" {
+		return nil, fmt.Errorf("insert sample found no vector column in the data")
+	}
+	return data[found], nil
+}
+
+// isVectorColumn reports whether insert data rows are dense vectors
+func isVectorColumn(rows interface{}) bool {
+	switch v := rows.(type) {
+	case [][]float32:
+		return true
+	case []interface{}:
+		if len(v) == 0 {
+			return false
+		}
+		switch first := v[0].(type) {
+		case []float32, []float64, sobek.ArrayBuffer:
+			return true
+		case []interface{}:
+			if len(first) == 0 {
+				return false
+			}
+			switch first[0].(type) {
+			case float64, int64, int:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// record offers the inserted rows to the reservoir: ids are the primary keys returned by the
+// server, including auto IDs, and rows the vectors of the insert data
+func (s *InsertSample) record(ids column.Column, rows interface{}) {
+	if ids == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < ids.Len(); i++ {
+		s.seen++
+		slot := len(s.ids)
+		if slot >= s.config.Size {
+			if slot = int(s.rng.Int63n(s.seen)); slot >= s.config.Size {
+				continue
+			}
+		}
+		id, err := ids.Get(i)
+		if err != nil {
+			continue
+		}
+		vector, err := vectorRow(rows, i)
+		if err != nil {
+			continue
+		}
+		if slot == len(s.ids) {
+			s.ids = append(s.ids, id)
+			s.vectors = append(s.vectors, vector)
+		} else {
+			s.ids[slot], s.vectors[slot] = id, vector
+		}
+	}
+}
+
+// vectorRow copies row i of a vector column, so the sample does not keep insert batches alive
+func vectorRow(rows interface{}, i int) ([]float32, error) {
+	if v, ok := rows.([][]float32); ok {
+		if i >= len(v) {
+			return nil, fmt.Errorf("row %d out of range", i)
+		}
+		return append([]float32(nil), v[i]...), nil
+	}
+	v, ok := rows.([]interface{})
+	if !ok || i >= len(v) {
+		return nil, fmt.Errorf("row %d out of range", i)
+	}
+	vectors, err := denseVectors([]interface{}{v[i]})
+	if err != nil {
+		return nil, err
+	}
+	// A Float32Array row is a view of JavaScript memory the script may reuse
+	return append([]float32(nil), vectors[0]...), nil
+}
+
+// Size returns the number of rows in the sample
+func (s *InsertSample) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.ids)
+}
+
+// memorySize returns the bytes of the sampled vectors, and roughly those of their primary keys
+func (s *InsertSample) memorySize() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return matrixBytes(s.vectors) + 16*int64(len(s.ids))
+}
+
+// Seen returns the number of inserted rows the sample was drawn from
+func (s *InsertSample) Seen() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen
+}
+
+// Draw returns up to count distinct sampled rows, picked at random, as { ids, vectors,
+// groundTruth } where groundTruth lists each vector's own primary key, to pass as the
+// groundTruth search param; null while the sample is empty
+func (s *InsertSample) Draw(count int) (map[string]interface{}, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", count)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.ids) == 0 {
+		return nil, nil
+	}
+	picks := distinctIndices(s.rng, len(s.ids), min(count, len(s.ids)))
+	ids := make([]interface{}, len(picks))
+	vectors := make([][]float32, len(picks))
+	truth := make([]interface{}, len(picks))
+	for i, pick := range picks {
+		// Vectors are never modified once sampled, so they are shared rather than copied
+		ids[i], vectors[i] = s.ids[pick], s.vectors[pick]
+		truth[i] = []interface{}{s.ids[pick]}
+	}
+	return map[string]interface{}{"ids": ids, "vectors": vectors, "groundTruth": truth}, nil
+}
+
+// distinctIndices picks count distinct indices of [0, n) at random, with Floyd's algorithm so
+// the cost does not grow with n
+func distinctIndices(rng *rand.Rand, n, count int) []int {
+	picked := make(map[int]bool, count)
+	picks := make([]int, 0, count)
+	for j := n - count; j < n; j++ {
+		pick := rng.Intn(j + 1)
+		if picked[pick] {
+			pick = j
+		}
+		picked[pick] = true
+		picks = append(picks, pick)
+	}
+	return picks
+}
@@ -0,0 +1,143 @@
+package milvus
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertSampleShared(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	first, err := m.InsertSample("inserted", map[string]interface{}{"size": 10})
+	require.NoError(t, err)
+	second, err := m.InsertSample("inserted")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 10, second.config.Size)
+
+	_, err = m.InsertSample("")
+	assert.Error(t, err)
+	_, err = m.InsertSample("negative", map[string]interface{}{"size": -1})
+	assert.Error(t, err)
+}
+
+func TestInsertSampleVectorColumn(t *testing.T) {
+	sample := newInsertSample(InsertSampleConfig{Size: 10})
+	data := map[string]interface{}{
+		"id":        []interface{}{int64(1), int64(2)},
+		"tags":      []interface{}{"a", "b"},
+		"embedding": []interface{}{[]interface{}{0.5, 1.0}, []interface{}{1.5, 2.0}},
+	}
+	rows, err := sample.vectorColumn(data)
+	require.NoError(t, err)
+	assert.Equal(t, data["embedding"], rows)
+
+	data["image"] = [][]float32{{1}, {2}}
+	_, err = sample.vectorColumn(data)
+	assert.ErrorContains(t, err, "needs a field")
+
+	sample.config.Field = "image"
+	rows, err = sample.vectorColumn(data)
+	require.NoError(t, err)
+	assert.Equal(t, data["image"], rows)
+
+	sample.config.Field = "tags"
+	_, err = sample.vectorColumn(data)
+	assert.Error(t, err)
+}
+
+func TestInsertSampleReservoir(t *testing.T) {
+	sample := newInsertSample(InsertSampleConfig{Size: 3})
+	sample.record(column.NewColumnVarChar("pk", []string{"a", "b"}), []interface{}{[]interface{}{1.0}, []interface{}{2.0}})
+	assert.Equal(t, 2, sample.Size())
+	assert.Equal(t, []interface{}{"a", "b"}, sample.ids)
+	assert.Equal(t, [][]float32{{1}, {2}}, sample.vectors)
+
+	// Once full, later rows replace sampled ones, keeping each PK with its own vector
+	const rows = 1000
+	ids := make([]int64, rows)
+	vectors := newMatrix[float32](rows, 1)
+	for i := range ids {
+		ids[i] = int64(100 + i)
+		vectors[i][0] = float32(100 + i)
+	}
+	sample.record(column.NewColumnInt64("pk", ids), vectors)
+	assert.Equal(t, 3, sample.Size())
+	assert.EqualValues(t, rows+2, sample.Seen())
+	replaced := 0
+	for i, id := range sample.ids {
+		if pk, ok := id.(int64); ok {
+			replaced++
+			assert.Equal(t, []float32{float32(pk)}, sample.vectors[i])
+		}
+	}
+	assert.Positive(t, replaced)
+
+	// Sampled vectors are copies, not rows of the insert batch
+	vectors[0][0], vectors[rows-1][0] = -1, -1
+	for _, vector := range sample.vectors {
+		assert.NotEqual(t, float32(-1), vector[0])
+	}
+}
+
+func TestInsertSampleDraw(t *testing.T) {
+	sample := newInsertSample(InsertSampleConfig{Size: 5})
+	batch, err := sample.Draw(2)
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+	_, err = sample.Draw(0)
+	assert.Error(t, err)
+
+	sample.record(column.NewColumnInt64("pk", []int64{10, 11, 12}), [][]float32{{0}, {1}, {2}})
+	batch, err = sample.Draw(10)
+	require.NoError(t, err)
+	ids := batch["ids"].([]interface{})
+	vectors := batch["vectors"].([][]float32)
+	truth := batch["groundTruth"].([]interface{})
+	require.Len(t, ids, 3)
+	assert.ElementsMatch(t, []interface{}{int64(10), int64(11), int64(12)}, ids)
+	for i, id := range ids {
+		assert.Equal(t, []float32{float32(id.(int64) - 10)}, vectors[i])
+		assert.Equal(t, []interface{}{id}, truth[i])
+	}
+
+	// The drawn ground truth is accepted by searches
+	expected, err := groundTruthOption(map[string]interface{}{"groundTruth": truth}, 3)
+	require.NoError(t, err)
+	assert.Len(t, expected, 3)
+}
+
+func TestDistinctIndices(t *testing.T) {
+	sample := newInsertSample(InsertSampleConfig{})
+	for count := 0; count <= 20; count++ {
+		picks := distinctIndices(sample.rng, 20, count)
+		seen := map[int]bool{}
+		for _, pick := range picks {
+			assert.True(t, pick >= 0 && pick < 20)
+			assert.False(t, seen[pick], "index %d picked twice", pick)
+			seen[pick] = true
+		}
+		assert.Len(t, picks, count)
+	}
+}
+
+func TestInsertSampleConcurrent(t *testing.T) {
+	sample := newInsertSample(InsertSampleConfig{Size: 50})
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				sample.record(column.NewColumnInt64("pk", []int64{1, 2, 3, 4}), [][]float32{{1}, {2}, {3}, {4}})
+				_, _ = sample.Draw(5)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 8*100*4, sample.Seen())
+	assert.Equal(t, 50, sample.Size())
+}
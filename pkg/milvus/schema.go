@@ -0,0 +1,216 @@
+package milvus
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// SchemaBuilder composes a collection Schema through chained calls.
+// It can be passed directly to createCollection() or serialized with json(). A field or function
+// that cannot be converted is reported by build(), json() and createCollection().
+//
+// Usage in k6:
+//
+//	const schema = milvus.schema('docs')
+//	    .addPkInt64('id', true)
+//	    .addFloatVector('emb', 768)
+//	    .addVarChar('title', 512);
+//	client.createCollection(schema);
+type SchemaBuilder struct {
+	schema Schema
+	err    error // First addField() or addFunction() conversion failure
+}
+
+// Schema creates a new SchemaBuilder for the given collection name
+func (m *Milvus) Schema(name string) *SchemaBuilder {
+	return NewSchemaBuilder(name)
+}
+
+// NewSchemaBuilder creates a new SchemaBuilder for the given collection name
+func NewSchemaBuilder(name string) *SchemaBuilder {
+	return &SchemaBuilder{
+		schema: Schema{Name: name},
+	}
+}
+
+// Description sets the collection description
+func (b *SchemaBuilder) Description(description string) *SchemaBuilder {
+	b.schema.Description = description
+	return b
+}
+
+// NumShards sets the number of shards
+func (b *SchemaBuilder) NumShards(numShards int32) *SchemaBuilder {
+	b.schema.NumShards = numShards
+	return b
+}
+
+// AddPkInt64 adds an Int64 primary key field
+func (b *SchemaBuilder) AddPkInt64(name string, autoID bool) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Int64", IsPrimaryKey: true, IsAutoID: autoID})
+}
+
+// AddPkVarChar adds a VarChar primary key field
+func (b *SchemaBuilder) AddPkVarChar(name string, maxLength int64, autoID bool) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "VarChar", MaxLength: maxLength, IsPrimaryKey: true, IsAutoID: autoID})
+}
+
+// AddBool adds a Bool field
+func (b *SchemaBuilder) AddBool(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Bool"})
+}
+
+// AddInt8 adds an Int8 field
+func (b *SchemaBuilder) AddInt8(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Int8"})
+}
+
+// AddInt16 adds an Int16 field
+func (b *SchemaBuilder) AddInt16(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Int16"})
+}
+
+// AddInt32 adds an Int32 field
+func (b *SchemaBuilder) AddInt32(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Int32"})
+}
+
+// AddInt64 adds an Int64 field
+func (b *SchemaBuilder) AddInt64(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Int64"})
+}
+
+// AddFloat adds a Float field
+func (b *SchemaBuilder) AddFloat(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Float"})
+}
+
+// AddDouble adds a Double field
+func (b *SchemaBuilder) AddDouble(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Double"})
+}
+
+// AddVarChar adds a VarChar field with the given max length
+func (b *SchemaBuilder) AddVarChar(name string, maxLength int64) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "VarChar", MaxLength: maxLength})
+}
+
+// AddJSON adds a JSON field
+func (b *SchemaBuilder) AddJSON(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "JSON"})
+}
+
+// AddArray adds an Array field with the given element type and max capacity
+func (b *SchemaBuilder) AddArray(name, elementType string, maxCapacity int64) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Array", ElementType: elementType, MaxCapacity: maxCapacity})
+}
+
+// AddFloatVector adds a FloatVector field with the given dimension
+func (b *SchemaBuilder) AddFloatVector(name string, dim int64) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "FloatVector", Dimension: dim})
+}
+
+// AddBinaryVector adds a BinaryVector field with the given dimension
+func (b *SchemaBuilder) AddBinaryVector(name string, dim int64) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "BinaryVector", Dimension: dim})
+}
+
+// AddFloat16Vector adds a Float16Vector field with the given dimension
+func (b *SchemaBuilder) AddFloat16Vector(name string, dim int64) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "Float16Vector", Dimension: dim})
+}
+
+// AddBFloat16Vector adds a BFloat16Vector field with the given dimension
+func (b *SchemaBuilder) AddBFloat16Vector(name string, dim int64) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "BFloat16Vector", Dimension: dim})
+}
+
+// AddSparseFloatVector adds a SparseFloatVector field
+func (b *SchemaBuilder) AddSparseFloatVector(name string) *SchemaBuilder {
+	return b.addField(Field{Name: name, DataType: "SparseFloatVector"})
+}
+
+// AddField adds a field from a FieldSchema-shaped object, for options not covered by the typed helpers
+// (analyzers, nullable, struct sub-fields, etc.)
+func (b *SchemaBuilder) AddField(fieldInput interface{}) *SchemaBuilder {
+	var field Field
+	if err := convertViaJSON(fieldInput, &field); err != nil {
+		b.fail(fmt.Errorf("invalid schema field: %v", err))
+		return b
+	}
+	return b.addField(field)
+}
+
+// AddFunction adds a function (BM25, TextEmbedding) from a FunctionSchema-shaped object
+func (b *SchemaBuilder) AddFunction(functionInput interface{}) *SchemaBuilder {
+	var fn Function
+	if err := convertViaJSON(functionInput, &fn); err != nil {
+		b.fail(fmt.Errorf("invalid schema function: %v", err))
+		return b
+	}
+	b.schema.Functions = append(b.schema.Functions, fn)
+	return b
+}
+
+// Build returns the schema as a plain object accepted by createCollection()
+func (b *SchemaBuilder) Build() (map[string]interface{}, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	var m map[string]interface{}
+	if err := convertViaJSON(b.schema, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// JSON returns the schema as a JSON string accepted by createCollectionFromJSON()
+func (b *SchemaBuilder) JSON() (string, error) {
+	data, err := b.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// MarshalJSON implements json.Marshaler so a builder can be passed wherever a schema object is expected
+func (b *SchemaBuilder) MarshalJSON() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return json.Marshal(b.schema)
+}
+
+// fail records the first error of the chained calls
+func (b *SchemaBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *SchemaBuilder) addField(field Field) *SchemaBuilder {
+	b.schema.Fields = append(b.schema.Fields, field)
+	return b
+}
+
+// convertViaJSON converts a JS value into a typed Go struct using JSON marshal/unmarshal
+func convertViaJSON(input interface{}, out interface{}) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// splitModuleObjects returns input without the given keys, whose values are module objects or
+// typed arrays that the caller reads as they are instead of converting them
+func splitModuleObjects(input map[string]interface{}, keys ...string) map[string]interface{} {
+	rest := make(map[string]interface{}, len(input))
+	for key, value := range input {
+		if !slices.Contains(keys, key) {
+			rest[key] = value
+		}
+	}
+	return rest
+}
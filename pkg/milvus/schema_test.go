@@ -0,0 +1,82 @@
+package milvus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaBuilder(t *testing.T) {
+	builder := NewSchemaBuilder("docs").
+		Description("test docs").
+		AddPkInt64("id", true).
+		AddFloatVector("emb", 768).
+		AddVarChar("title", 512).
+		AddArray("tags", "VarChar", 16)
+
+	data, err := builder.JSON()
+	require.NoError(t, err)
+	var schema Schema
+	require.NoError(t, json.Unmarshal([]byte(data), &schema))
+
+	assert.Equal(t, "docs", schema.Name)
+	assert.Equal(t, "test docs", schema.Description)
+	require.Len(t, schema.Fields, 4)
+
+	assert.Equal(t, Field{Name: "id", DataType: "Int64", IsPrimaryKey: true, IsAutoID: true}, schema.Fields[0])
+	assert.Equal(t, Field{Name: "emb", DataType: "FloatVector", Dimension: 768}, schema.Fields[1])
+	assert.Equal(t, Field{Name: "title", DataType: "VarChar", MaxLength: 512}, schema.Fields[2])
+	assert.Equal(t, Field{Name: "tags", DataType: "Array", ElementType: "VarChar", MaxCapacity: 16}, schema.Fields[3])
+}
+
+func TestSchemaBuilderAddFieldAndFunction(t *testing.T) {
+	builder := NewSchemaBuilder("fts").
+		AddPkVarChar("pk", 64, false).
+		AddField(map[string]interface{}{
+			"name":           "text",
+			"dataType":       "VarChar",
+			"maxLength":      float64(1024),
+			"enableAnalyzer": true,
+			"nullable":       true,
+		}).
+		AddSparseFloatVector("sparse").
+		AddFunction(map[string]interface{}{
+			"name":             "bm25",
+			"functionType":     "BM25",
+			"inputFieldNames":  []interface{}{"text"},
+			"outputFieldNames": []interface{}{"sparse"},
+		})
+
+	built, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "fts", built["name"])
+
+	var schema Schema
+	require.NoError(t, convertViaJSON(builder, &schema))
+	require.Len(t, schema.Fields, 3)
+	assert.True(t, schema.Fields[1].EnableAnalyzer)
+	require.NotNil(t, schema.Fields[1].Nullable)
+	assert.True(t, *schema.Fields[1].Nullable)
+	require.Len(t, schema.Functions, 1)
+	assert.Equal(t, "BM25", schema.Functions[0].FunctionType)
+	assert.Equal(t, []string{"sparse"}, schema.Functions[0].OutputFieldNames)
+}
+
+func TestSchemaBuilderErrors(t *testing.T) {
+	builder := NewSchemaBuilder("docs").
+		AddPkInt64("id", true).
+		AddField(map[string]interface{}{"name": "title", "maxLength": "long"}).
+		AddFunction(map[string]interface{}{"name": 1})
+
+	_, err := builder.Build()
+	assert.ErrorContains(t, err, "invalid schema field")
+	_, err = builder.JSON()
+	assert.ErrorContains(t, err, "invalid schema field", "the first failure is kept")
+	var schema Schema
+	assert.Error(t, convertViaJSON(builder, &schema), "createCollection() reports it")
+
+	_, err = NewSchemaBuilder("docs").AddFunction(map[string]interface{}{"name": 1}).Build()
+	assert.ErrorContains(t, err, "invalid schema function")
+}
@@ -6,13 +6,17 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/milvus-io/milvus/client/v2/entity"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/sirupsen/logrus"
 )
 
 // Search performs vector similarity search with Recall support.
 // The vectorsInput parameter accepts dense vectors ([][]float32), text queries ([]string for BM25),
 // or sparse vectors. Type detection is automatic.
 func (c *Client) Search(vectorsInput interface{}, topK int, params map[string]interface{}, collectionName ...string) interface{} {
+	// Text queries are embedded first, so the embedding request is not timed as part of the search
+	vectorsInput, embedErr := embedderOption(c.context(), params, vectorsInput)
 	start := time.Now()
 
 	coll := c.getCollectionName(collectionName...)
@@ -24,6 +28,14 @@ func (c *Client) Search(vectorsInput interface{}, topK int, params map[string]in
 		})
 	}
 
+	if embedErr != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to embed queries: %v", embedErr),
+		})
+	}
+
 	// Convert input to entity.Vector — supports dense, sparse, and text (BM25)
 	searchVectors, err := convertToSearchVectors(vectorsInput)
 	if err != nil {
@@ -34,82 +46,142 @@ func (c *Client) Search(vectorsInput interface{}, topK int, params map[string]in
 		})
 	}
 
-	// Get vector field name (default to "vector")
-	vectorField := "vector"
-	if field, ok := params["vectorField"].(string); ok {
-		vectorField = field
-	}
-
-	// Get output fields
-	var outputFields []string
-	if fields, ok := params["outputFields"].([]interface{}); ok {
-		outputFields = make([]string, len(fields))
-		for i, field := range fields {
-			if fieldStr, ok := field.(string); ok {
-				outputFields[i] = fieldStr
-			}
-		}
-	} else if fields, ok := params["outputFields"].([]string); ok {
-		outputFields = fields
+	spec, err := c.searchSpec(coll, params)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err.Error(),
+		})
 	}
 
-	if len(outputFields) == 0 {
-		outputFields = []string{"id"}
+	truth, err := groundTruthOption(params, len(searchVectors))
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("invalid groundTruth: %v", err),
+		})
 	}
-
-	// Create search option
-	searchOption := milvusclient.NewSearchOption(coll, topK, searchVectors).
-		WithANNSField(vectorField).
-		WithOutputFields(outputFields...)
-
-	// Set filter expression
-	if expr, ok := stringOption(params, "expr"); ok && expr != "" {
-		searchOption = searchOption.WithFilter(expr)
-	} else if expr, ok := stringOption(params, "filter"); ok && expr != "" {
-		searchOption = searchOption.WithFilter(expr)
+	qualityMetrics, err := qualityMetricsOption(params, truth != nil)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("invalid qualityMetrics: %v", err),
+		})
 	}
 
-	// Set metric type through search param
-	if metricType, ok := stringOption(params, "metricType"); ok {
-		searchOption = searchOption.WithSearchParam("metric_type", metricType)
-	}
-	if metricType, ok := stringOption(params, "metric_type"); ok {
-		searchOption = searchOption.WithSearchParam("metric_type", metricType)
-	}
-	if offset, ok := intOption(params, "offset"); ok {
-		searchOption = searchOption.WithOffset(offset)
-	}
-	if groupBy, ok := stringOption(params, "groupByField"); ok && groupBy != "" {
-		searchOption = searchOption.WithGroupByField(groupBy)
-	} else if groupBy, ok := stringOption(params, "groupingField"); ok && groupBy != "" {
-		searchOption = searchOption.WithGroupByField(groupBy)
-	}
-	if groupSize, ok := intOption(params, "groupSize"); ok {
-		searchOption = searchOption.WithGroupSize(groupSize)
-	}
-	if strict, ok := boolOption(params, "strictGroupSize"); ok {
-		searchOption = searchOption.WithStrictGroupSize(strict)
-	}
-	if ignoreGrowing, ok := boolOption(params, "ignoreGrowing"); ok {
-		searchOption = searchOption.WithIgnoreGrowing(ignoreGrowing)
-	}
-	for key, val := range searchParamMap(params) {
-		searchOption = searchOption.WithSearchParam(key, searchParamValue(val))
-	}
+	client := c.withTags(spec.tags).withTargetTags(spec.partitions, c.indexType(coll, spec.vectorField))
 
 	// Execute search
-	resultSets, err := c.client.Search(c.context(), searchOption)
+	resultSets, err := client.searchSplit(spec, coll, topK, searchVectors)
+	client.logSlow("search", start, logrus.Fields{"collection": coll, "expr": spec.filter, "nq": len(searchVectors), "topK": topK})
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to search: %v", err),
+			Cause:        err,
 		})
 	}
 
-	// Convert results with pre-allocated capacity
+	// Convert results with pre-allocated capacity, or as typed arrays with typedResults
+	var results interface{}
+	isEmpty := true
+	if spec.typedResults {
+		hits, err := searchTypedHits(resultSets)
+		if err != nil {
+			return toMap(&OperationResult{
+				Success:      false,
+				ResponseTime: float64(time.Since(start).Milliseconds()),
+				Error:        err.Error(),
+			})
+		}
+		for _, h := range hits {
+			if len(h.IDs) > 0 {
+				isEmpty = false
+			}
+		}
+		results = hits
+	} else {
+		results, isEmpty = searchResults(resultSets, spec.outputFields)
+	}
+
+	// Per-query recall, from the ground truth or as estimated by the server. Each query is
+	// one milvus_recall sample, so recall percentiles can be thresholded, not just the mean.
+	// The samples of all queries are pushed together.
+	samples := client.sampleBatch()
+	defer samples.push()
+	matches := matchQueries(resultSets, truth, topK)
+	recalls := queryRecalls(resultSets, matches)
+	var recallPerQuery []float32
+	if truth != nil || spec.serverRecall {
+		recallPerQuery = make([]float32, len(recalls))
+		for i, recall := range recalls {
+			recallPerQuery[i] = float32(recall)
+			if client.metrics != nil {
+				samples.add(client.metrics.Recall, recall, map[string]string{"collection": coll})
+			}
+		}
+		if client.metrics != nil {
+			client.summary.addRecalls("search", recalls)
+		}
+	}
+
+	// Ranking quality, one sample per query like recall, with the mean in the result
+	var quality map[string]float32
+	if len(qualityMetrics) > 0 {
+		quality = make(map[string]float32, len(qualityMetrics))
+		for _, name := range qualityMetrics {
+			scores := scoreQueries(matches, qualityScorers[name])
+			for _, score := range scores {
+				samples.add(client.metrics.qualityMetric(name), score, map[string]string{"collection": coll})
+			}
+			quality[name] = float32(mean(scores))
+		}
+	}
+
+	result := &OperationResult{
+		Success:        true,
+		ResponseTime:   float64(time.Since(start).Milliseconds()),
+		Empty:          isEmpty,
+		Recall:         float32(mean(recalls)),
+		RecallPerQuery: recallPerQuery,
+		Quality:        quality,
+	}
+	if hits, ok := results.([]typedHits); ok {
+		// Typed hits skip the JSON round trip of toMap
+		m := toMap(result)
+		m["result"] = hits
+		return client.jsResult(m)
+	}
+	result.Result = results
+	return toMap(result)
+}
+
+// searchSplit runs a search as consecutive Search RPCs of at most maxNq query vectors each, and
+// returns the result sets of all queries in order. Each RPC emits its own metrics.
+func (c *Client) searchSplit(spec *searchSpec, coll string, topK int, vectors []entity.Vector) ([]milvusclient.ResultSet, error) {
+	if spec.maxNq == 0 || len(vectors) <= spec.maxNq {
+		return c.milvus().Search(c.context(), spec.option(coll, topK, vectors))
+	}
+	resultSets := make([]milvusclient.ResultSet, 0, len(vectors))
+	for start := 0; start < len(vectors); start += spec.maxNq {
+		end := min(start+spec.maxNq, len(vectors))
+		part, err := c.milvus().Search(c.context(), spec.option(coll, topK, vectors[start:end]))
+		if err != nil {
+			return nil, fmt.Errorf("query vectors %d-%d: %w", start, end-1, err)
+		}
+		resultSets = append(resultSets, part...)
+	}
+	return resultSets, nil
+}
+
+// searchResults returns a result object per hit, with its output fields, and whether there
+// were no hits
+func searchResults(resultSets []milvusclient.ResultSet, outputFields []string) ([]SearchResult, bool) {
 	var results []SearchResult
-	var recall float32
 	isEmpty := true
 
 	// Pre-allocate with estimated capacity
@@ -125,8 +197,6 @@ func (c *Client) Search(vectorsInput interface{}, topK int, params map[string]in
 		if resultSet.ResultCount > 0 {
 			isEmpty = false
 		}
-		recall = resultSet.Recall // Capture recall from SDK
-
 		for i := 0; i < resultSet.ResultCount; i++ {
 			result := SearchResult{
 				Score:  resultSet.Scores[i],
@@ -159,14 +229,7 @@ func (c *Client) Search(vectorsInput interface{}, topK int, params map[string]in
 			results = append(results, result)
 		}
 	}
-
-	return toMap(&OperationResult{
-		Success:      true,
-		ResponseTime: float64(time.Since(start).Milliseconds()),
-		Result:       results,
-		Empty:        isEmpty,
-		Recall:       recall, // NEW: Expose recall metric
-	})
+	return results, isEmpty
 }
 
 // HybridSearch performs multi-vector hybrid search with reranking (NEW - from Locust)
@@ -230,6 +293,7 @@ func (c *Client) HybridSearch(requestsInput interface{}, rerankerInput interface
 
 	// Build ANN requests
 	var annRequests []*milvusclient.AnnRequest
+	nq := 0
 	for _, req := range requests {
 		// Use the shared convertToSearchVectors for dense, sparse, and text (BM25)
 		searchVectors, err := convertToSearchVectors(req.Vectors)
@@ -245,6 +309,7 @@ func (c *Client) HybridSearch(requestsInput interface{}, rerankerInput interface
 			})
 		}
 
+		nq = len(searchVectors)
 		annReq := milvusclient.NewAnnRequest(req.VectorField, req.Limit, searchVectors...)
 
 		// Apply params if provided
@@ -328,12 +393,14 @@ func (c *Client) HybridSearch(requestsInput interface{}, rerankerInput interface
 	}
 
 	// Execute hybrid search
-	resultSets, err := c.client.HybridSearch(c.context(), hybridOption)
+	resultSets, err := c.milvus().HybridSearch(c.context(), hybridOption)
+	c.logSlow("hybrid_search", start, logrus.Fields{"collection": coll, "requests": len(requests), "nq": nq, "topK": limit})
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to hybrid search: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -412,6 +479,24 @@ func (c *Client) Query(filter string, outputFields []interface{}, args ...interf
 		})
 	}
 
+	tags, err := tagsOption(options)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("invalid tags: %v", err),
+		})
+	}
+	partitions, err := partitionsOption(options)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("invalid partitionNames: %v", err),
+		})
+	}
+	client := c.withTags(tags).withTargetTags(partitions, "")
+
 	// Convert outputFields
 	fields := make([]string, len(outputFields))
 	for i, field := range outputFields {
@@ -427,6 +512,9 @@ func (c *Client) Query(filter string, outputFields []interface{}, args ...interf
 	option := milvusclient.NewQueryOption(coll).
 		WithFilter(filter).
 		WithOutputFields(fields...)
+	if len(partitions) > 0 {
+		option = option.WithPartitions(partitions...)
+	}
 	if limit, ok := intOption(options, "limit"); ok {
 		option = option.WithLimit(limit)
 	}
@@ -434,12 +522,14 @@ func (c *Client) Query(filter string, outputFields []interface{}, args ...interf
 		option = option.WithOffset(offset)
 	}
 
-	resultSet, err := c.client.Query(c.context(), option)
+	resultSet, err := client.milvus().Query(client.context(), option)
+	client.logSlow("query", start, logrus.Fields{"collection": coll, "expr": filter})
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to query: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -516,6 +606,13 @@ func searchParamMap(params map[string]interface{}) map[string]interface{} {
 		"collectionName":   {},
 		"partitionNames":   {},
 		"consistencyLevel": {},
+		"tags":             {},
+		"groundTruth":      {},
+		"queryIds":         {},
+		"qualityMetrics":   {},
+		"embedder":         {},
+		typedResultsParam:  {},
+		"maxNq":            {},
 	}
 	for key, val := range params {
 		if _, ok := reserved[key]; ok {
@@ -548,6 +645,33 @@ func searchParamValue(value interface{}) string {
 	}
 }
 
+// partitionsOption reads the "partitionNames" option, given as a list of names or a single name
+func partitionsOption(options map[string]interface{}) ([]string, error) {
+	switch v := options["partitionNames"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("partitionNames must be partition names, got %v", item)
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	default:
+		return nil, fmt.Errorf("partitionNames must be an array of partition names, got %T", v)
+	}
+}
+
 func stringOption(options map[string]interface{}, key string) (string, bool) {
 	value, ok := options[key]
 	if !ok || value == nil {
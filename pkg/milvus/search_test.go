@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSearchParamMap(t *testing.T) {
@@ -12,6 +13,9 @@ func TestSearchParamMap(t *testing.T) {
 		"metricType":   "COSINE",
 		"groupByField": "id",
 		"radius":       0.5,
+		"tags":         map[string]interface{}{"phase": "steady"},
+		"typedResults": true,
+		"maxNq":        1000,
 		"params": map[string]interface{}{
 			"ef":    float64(64),
 			"range": "strict",
@@ -27,6 +31,9 @@ func TestSearchParamMap(t *testing.T) {
 	assert.NotContains(t, got, "metricType")
 	assert.NotContains(t, got, "groupByField")
 	assert.NotContains(t, got, "params")
+	assert.NotContains(t, got, "tags")
+	assert.NotContains(t, got, "typedResults")
+	assert.NotContains(t, got, "maxNq")
 }
 
 func TestSearchParamValue(t *testing.T) {
@@ -88,3 +95,52 @@ func TestParseQueryArgs(t *testing.T) {
 	assert.Equal(t, "string_collection", coll)
 	assert.Empty(t, options)
 }
+
+func TestPartitionsOption(t *testing.T) {
+	partitions, err := partitionsOption(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Nil(t, partitions)
+
+	partitions, err = partitionsOption(map[string]interface{}{"partitionNames": "2024"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024"}, partitions)
+
+	partitions, err = partitionsOption(map[string]interface{}{"partitionNames": []interface{}{"2024", "2025"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024", "2025"}, partitions)
+
+	_, err = partitionsOption(map[string]interface{}{"partitionNames": []interface{}{"2024", 7}})
+	assert.Error(t, err)
+	_, err = partitionsOption(map[string]interface{}{"partitionNames": 2024})
+	assert.Error(t, err)
+}
+
+func TestSearchMaxNq(t *testing.T) {
+	service := &searchServer{}
+	client := searchManyClient(t, service)
+	vectors := [][]float32{{1, 0}, {0, 1}, {1, 1}, {2, 1}, {1, 2}}
+	truth := []interface{}{
+		[]interface{}{int64(1)}, []interface{}{int64(3)}, []interface{}{int64(1)}, []interface{}{int64(3)}, []interface{}{int64(2)},
+	}
+
+	result := client.Search(vectors, 2, map[string]interface{}{"vectorField": "embedding", "maxNq": 2, "groundTruth": truth}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, []int64{2, 2, 1}, service.nqs, "one RPC per maxNq query vectors")
+	assert.Len(t, result["result"], 10)
+	assert.Equal(t, []interface{}{1.0, 0.0, 1.0, 0.0, 1.0}, result["recall_per_query"], "results merged in query order")
+
+	// maxNq: true is Milvus' default limit, and leaves small searches whole
+	service.nqs = nil
+	result = client.Search(vectors, 2, map[string]interface{}{"vectorField": "embedding", "maxNq": true}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, []int64{5}, service.nqs)
+
+	result = client.Search(vectors, 2, map[string]interface{}{"vectorField": "embedding", "maxNq": 0}).(map[string]interface{})
+	assert.Contains(t, result["error"], "invalid maxNq")
+
+	failing := searchManyClient(t, &searchServer{failNq: 2})
+	result = failing.Search(vectors, 2, map[string]interface{}{"vectorField": "embedding", "maxNq": 3}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "query vectors 0-2")
+	assert.Contains(t, result["error"], "too many queries")
+}
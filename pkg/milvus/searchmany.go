@@ -0,0 +1,152 @@
+package milvus
+
+import (
+	"fmt"
+	"time"
+)
+
+// SearchMany runs one search per batch of query vectors, with up to concurrency Search RPCs in
+// flight at once, so a single VU can drive high query rates. Params are the search() params of
+// every batch, or an array with the params of each batch, e.g. to pass per-batch queryIds.
+// Options are a collection name or { collectionName, concurrency }; concurrency defaults to
+// the number of batches.
+//
+// Each search emits its own metrics, as search() does. The result holds the result object of
+// each batch in order; success is true only when every search succeeded, recall is the mean
+// over all queries, and responseTime covers the whole call.
+//
+// Usage in k6:
+//
+//	const res = client.searchMany([gen.next(10), gen.next(10), gen.next(10)], 10, { vectorField: 'embedding' }, { concurrency: 3 });
+func (c *Client) SearchMany(batchesInput interface{}, topK int, paramsInput interface{}, args ...interface{}) interface{} {
+	start := time.Now()
+
+	coll, options := c.parseQueryArgs(args...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+	batches, ok := batchesInput.([]interface{})
+	if !ok || len(batches) == 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("batches must be a non-empty array of query vector batches, got %T", batchesInput),
+		})
+	}
+	params, err := searchManyParams(paramsInput, len(batches))
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err.Error(),
+		})
+	}
+	concurrency, ok := intOption(options, "concurrency")
+	if !ok {
+		concurrency = len(batches)
+	}
+	if concurrency < 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "concurrency must not be negative",
+		})
+	}
+
+	// Detached clients are made in the VU goroutine, one per batch so that searches share no
+	// client state. The index type of the searched field is described first, so each search
+	// finds it cached instead of describing it again.
+	vectorField := "vector"
+	if field, ok := params[0]["vectorField"].(string); ok {
+		vectorField = field
+	}
+	c.indexType(coll, vectorField)
+	clients := make([]*Client, len(batches))
+	for i := range clients {
+		clients[i] = c.detached()
+	}
+	results := make([]map[string]interface{}, len(batches))
+	runParallel(len(batches), concurrency, func(i int) {
+		results[i], _ = clients[i].Search(batches[i], topK, params[i], coll).(map[string]interface{})
+	})
+	// The batch results are already maps, and are not converted again
+	m := toMap(searchManyResult(results, start))
+	batches = make([]interface{}, len(results))
+	for i, result := range results {
+		batches[i] = c.jsResult(result)
+	}
+	m["result"] = batches
+	return m
+}
+
+// searchManyParams returns the search params of each batch: one object shared by every batch,
+// or an array with one object per batch
+func searchManyParams(input interface{}, batches int) ([]map[string]interface{}, error) {
+	switch v := input.(type) {
+	case map[string]interface{}:
+		params := make([]map[string]interface{}, batches)
+		for i := range params {
+			params[i] = v
+		}
+		return params, nil
+	case []interface{}:
+		if len(v) != batches {
+			return nil, fmt.Errorf("params has %d entries for %d batches", len(v), batches)
+		}
+		params := make([]map[string]interface{}, batches)
+		for i, item := range v {
+			p, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("params[%d] must be an object, got %T", i, item)
+			}
+			params[i] = p
+		}
+		return params, nil
+	}
+	return nil, fmt.Errorf("params must be an object or an array of objects, got %T", input)
+}
+
+// searchManyResult aggregates the search results of each batch, except the results themselves
+func searchManyResult(results []map[string]interface{}, start time.Time) *OperationResult {
+	aggregate := &OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Empty:        true,
+	}
+	var recallSum float64
+	var recallQueries int
+	var failed int
+	var firstError string
+	for _, result := range results {
+		if success, _ := result["success"].(bool); !success {
+			failed++
+			if firstError == "" {
+				firstError, _ = result["error"].(string)
+			}
+			continue
+		}
+		if empty, _ := result["empty"].(bool); !empty {
+			aggregate.Empty = false
+		}
+		if perQuery, ok := result["recall_per_query"].([]interface{}); ok {
+			for _, recall := range perQuery {
+				if value, ok := recall.(float64); ok {
+					recallSum += value
+					recallQueries++
+				}
+			}
+		}
+	}
+	if recallQueries > 0 {
+		aggregate.Recall = float32(recallSum / float64(recallQueries))
+	}
+	if failed > 0 {
+		aggregate.Success = false
+		aggregate.Error = fmt.Sprintf("%d of %d searches failed: %s", failed, len(results), firstError)
+	}
+	return aggregate
+}
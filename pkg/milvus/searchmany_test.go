@@ -0,0 +1,101 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchServer answers each query with the IDs 1 and 2, and records the number of queries of
+// each search and the most searches in flight at once. Searches of more than failNq queries
+// are rejected.
+type searchServer struct {
+	chunkServer
+	failNq int64
+	nqs    []int64
+}
+
+func (s *searchServer) Search(_ context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	current := s.inflight.Add(1)
+	defer s.inflight.Add(-1)
+	for peak := s.peak.Load(); current > peak && !s.peak.CompareAndSwap(peak, current); peak = s.peak.Load() {
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	nq := req.GetNq()
+	s.mu.Lock()
+	s.nqs = append(s.nqs, nq)
+	s.mu.Unlock()
+	if s.failNq > 0 && nq > s.failNq {
+		return &milvuspb.SearchResults{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError, Code: 65535, Reason: "too many queries"}}, nil
+	}
+	data := &schemapb.SearchResultData{NumQueries: nq, TopK: 2, Ids: &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{}}}}
+	for q := int64(0); q < nq; q++ {
+		data.Topks = append(data.Topks, 2)
+		data.Scores = append(data.Scores, 0.1, 0.2)
+		data.Ids.GetIntId().Data = append(data.Ids.GetIntId().Data, 1, 2)
+	}
+	return &milvuspb.SearchResults{Status: &commonpb.Status{}, Results: data}, nil
+}
+
+func searchManyClient(t *testing.T, service *searchServer) *Client {
+	return fakeClient(t, &Milvus{}, service, WithCollection("bench"))
+}
+
+func TestSearchMany(t *testing.T) {
+	service := &searchServer{}
+	client := searchManyClient(t, service)
+	batches := []interface{}{
+		[][]float32{{1, 0}},
+		[][]float32{{0, 1}, {1, 1}},
+		[][]float32{{1, 1}},
+	}
+	params := []interface{}{
+		map[string]interface{}{"vectorField": "embedding", "groundTruth": []interface{}{[]interface{}{int64(1), int64(2)}}},
+		map[string]interface{}{"vectorField": "embedding", "groundTruth": []interface{}{[]interface{}{int64(1), int64(3)}, []interface{}{int64(4), int64(5)}}},
+		map[string]interface{}{"vectorField": "embedding"},
+	}
+
+	result := client.SearchMany(batches, 2, params, map[string]interface{}{"concurrency": 2}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, int32(2), service.peak.Load(), "at most concurrency searches in flight")
+	perBatch := result["result"].([]interface{})
+	require.Len(t, perBatch, 3)
+	assert.Len(t, perBatch[1].(map[string]interface{})["result"], 4, "results stay in batch order")
+	// Recall over the queries with ground truth: 1, 0.5 and 0
+	assert.InDelta(t, 0.5, result["recall"], 1e-6)
+	assert.Equal(t, false, result["empty"])
+
+	// Shared params, and all batches at once by default
+	service.peak.Store(0)
+	result = client.SearchMany(batches, 2, map[string]interface{}{"vectorField": "embedding"}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, int32(3), service.peak.Load())
+}
+
+func TestSearchManyFailures(t *testing.T) {
+	client := searchManyClient(t, &searchServer{failNq: 1})
+	batches := []interface{}{[][]float32{{1, 0}}, [][]float32{{0, 1}, {1, 1}}}
+
+	result := client.SearchMany(batches, 2, map[string]interface{}{"vectorField": "embedding"}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "1 of 2 searches failed")
+	assert.Contains(t, result["error"], "too many queries")
+	assert.Equal(t, true, result["result"].([]interface{})[0].(map[string]interface{})["success"])
+
+	for name, call := range map[string]func() interface{}{
+		"batches": func() interface{} { return client.SearchMany([][]float32{{1, 0}}, 2, map[string]interface{}{}) },
+		"params":  func() interface{} { return client.SearchMany(batches, 2, []interface{}{map[string]interface{}{}}) },
+		"concurrency": func() interface{} {
+			return client.SearchMany(batches, 2, map[string]interface{}{}, map[string]interface{}{"concurrency": -1})
+		},
+	} {
+		assert.Equal(t, false, call().(map[string]interface{})["success"], name)
+	}
+}
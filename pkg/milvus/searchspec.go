@@ -0,0 +1,260 @@
+package milvus
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// defaultMaxNq is Milvus' default limit on the query vectors of one search
+// (quotaAndLimits.limits.maxNq), used by maxNq: true
+const defaultMaxNq = 16384
+
+// maxSearchSpecs bounds the search specs cached per VU, for scripts building a new filter
+// expression on every call
+const maxSearchSpecs = 256
+
+// searchCallParams are the search params that change from call to call. They are read on every
+// call and are not part of a search spec.
+var searchCallParams = map[string]struct{}{
+	"groundTruth":    {},
+	"queryIds":       {},
+	"qualityMetrics": {},
+	"embedder":       {},
+}
+
+// searchSpec is the parsed form of the search params a script repeats on every call
+type searchSpec struct {
+	tags          map[string]string
+	partitions    []string
+	vectorField   string
+	outputFields  []string
+	filter        string
+	offset        *int
+	groupBy       string
+	groupSize     *int
+	strict        *bool
+	ignoreGrowing *bool
+	searchParams  map[string]string // Index-specific params, with the metric type
+	serverRecall  bool
+	typedResults  bool
+	maxNq         int // Query vectors per Search RPC, 0 to send all at once
+}
+
+// searchSpec returns the parsed search params of a collection, cached per VU by the params'
+// content, so a script passing the same params on every iteration parses them once
+func (c *Client) searchSpec(coll string, params map[string]interface{}) (*searchSpec, error) {
+	root := c.root()
+	key, cacheable := searchSpecKey(coll, params)
+	if cacheable {
+		if spec, cached := root.searchSpecs[key]; cached {
+			return spec, nil
+		}
+	}
+	spec, err := newSearchSpec(params)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable && len(root.searchSpecs) < maxSearchSpecs {
+		if root.searchSpecs == nil {
+			root.searchSpecs = make(map[string]*searchSpec)
+		}
+		root.searchSpecs[key] = spec
+	}
+	return spec, nil
+}
+
+// newSearchSpec parses the search params
+func newSearchSpec(params map[string]interface{}) (*searchSpec, error) {
+	tags, err := tagsOption(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tags: %v", err)
+	}
+	partitions, err := partitionsOption(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid partitionNames: %v", err)
+	}
+	spec := &searchSpec{tags: tags, partitions: partitions, vectorField: "vector"}
+
+	// Get vector field name (default to "vector")
+	if field, ok := params["vectorField"].(string); ok {
+		spec.vectorField = field
+	}
+
+	// Get output fields
+	if fields, ok := params["outputFields"].([]interface{}); ok {
+		spec.outputFields = make([]string, len(fields))
+		for i, field := range fields {
+			if fieldStr, ok := field.(string); ok {
+				spec.outputFields[i] = fieldStr
+			}
+		}
+	} else if fields, ok := params["outputFields"].([]string); ok {
+		spec.outputFields = fields
+	}
+	if len(spec.outputFields) == 0 {
+		spec.outputFields = []string{"id"}
+	}
+
+	spec.filter, _ = stringOption(params, "expr")
+	if spec.filter == "" {
+		spec.filter, _ = stringOption(params, "filter")
+	}
+	if offset, ok := intOption(params, "offset"); ok {
+		spec.offset = &offset
+	}
+	if groupBy, ok := stringOption(params, "groupByField"); ok && groupBy != "" {
+		spec.groupBy = groupBy
+	} else if groupBy, ok := stringOption(params, "groupingField"); ok && groupBy != "" {
+		spec.groupBy = groupBy
+	}
+	if groupSize, ok := intOption(params, "groupSize"); ok {
+		spec.groupSize = &groupSize
+	}
+	if strict, ok := boolOption(params, "strictGroupSize"); ok {
+		spec.strict = &strict
+	}
+	if ignoreGrowing, ok := boolOption(params, "ignoreGrowing"); ok {
+		spec.ignoreGrowing = &ignoreGrowing
+	}
+
+	// The metric type is a search param, overridden by an explicit one in params
+	spec.searchParams = make(map[string]string)
+	if metricType, ok := stringOption(params, "metricType"); ok {
+		spec.searchParams["metric_type"] = metricType
+	}
+	if metricType, ok := stringOption(params, "metric_type"); ok {
+		spec.searchParams["metric_type"] = metricType
+	}
+	extra := searchParamMap(params)
+	for key, val := range extra {
+		spec.searchParams[key] = searchParamValue(val)
+	}
+	spec.serverRecall, _ = boolOption(extra, serverRecallParam)
+	spec.typedResults, _ = boolOption(params, typedResultsParam)
+	if spec.maxNq, err = maxNqOption(params); err != nil {
+		return nil, fmt.Errorf("invalid maxNq: %v", err)
+	}
+	return spec, nil
+}
+
+// maxNqOption reads the "maxNq" search param: a number of query vectors, or true for Milvus'
+// default limit
+func maxNqOption(params map[string]interface{}) (int, error) {
+	switch v := params["maxNq"].(type) {
+	case nil:
+		return 0, nil
+	case bool:
+		if v {
+			return defaultMaxNq, nil
+		}
+		return 0, nil
+	}
+	maxNq, ok := intOption(params, "maxNq")
+	if !ok || maxNq <= 0 {
+		return 0, fmt.Errorf("maxNq must be a positive number or true, got %v", params["maxNq"])
+	}
+	return maxNq, nil
+}
+
+// option returns the search option of a search with this spec
+func (s *searchSpec) option(coll string, topK int, vectors []entity.Vector) milvusclient.SearchOption {
+	option := milvusclient.NewSearchOption(coll, topK, vectors).
+		WithANNSField(s.vectorField).
+		WithOutputFields(s.outputFields...)
+	if len(s.partitions) > 0 {
+		option = option.WithPartitions(s.partitions...)
+	}
+	if s.filter != "" {
+		option = option.WithFilter(s.filter)
+	}
+	if s.offset != nil {
+		option = option.WithOffset(*s.offset)
+	}
+	if s.groupBy != "" {
+		option = option.WithGroupByField(s.groupBy)
+	}
+	if s.groupSize != nil {
+		option = option.WithGroupSize(*s.groupSize)
+	}
+	if s.strict != nil {
+		option = option.WithStrictGroupSize(*s.strict)
+	}
+	if s.ignoreGrowing != nil {
+		option = option.WithIgnoreGrowing(*s.ignoreGrowing)
+	}
+	for key, val := range s.searchParams {
+		option = option.WithSearchParam(key, val)
+	}
+	return option
+}
+
+// searchSpecKey returns the cache key of a collection's search params, made of their content
+// except the per-call params. Params holding values other than JSON-like data are not cached.
+func searchSpecKey(coll string, params map[string]interface{}) (string, bool) {
+	var b strings.Builder
+	b.WriteString(strconv.Quote(coll))
+	keys := slices.Sorted(maps.Keys(params))
+	for _, key := range keys {
+		if _, perCall := searchCallParams[key]; perCall {
+			continue
+		}
+		b.WriteString(strconv.Quote(key))
+		b.WriteByte(':')
+		if !writeSpecValue(&b, params[key]) {
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+// writeSpecValue writes a param value to a cache key, keeping values of different types apart
+func writeSpecValue(b *strings.Builder, value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case string:
+		b.WriteString(strconv.Quote(v))
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int:
+		b.WriteString("i" + strconv.Itoa(v))
+	case int64:
+		b.WriteString("i" + strconv.FormatInt(v, 10))
+	case float64:
+		b.WriteString("f" + strconv.FormatFloat(v, 'g', -1, 64))
+	case []string:
+		b.WriteByte('[')
+		for _, item := range v {
+			b.WriteString(strconv.Quote(item) + ",")
+		}
+		b.WriteByte(']')
+	case []interface{}:
+		b.WriteByte('[')
+		for _, item := range v {
+			if !writeSpecValue(b, item) {
+				return false
+			}
+			b.WriteByte(',')
+		}
+		b.WriteByte(']')
+	case map[string]interface{}:
+		b.WriteByte('{')
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			b.WriteString(strconv.Quote(key) + ":")
+			if !writeSpecValue(b, v[key]) {
+				return false
+			}
+			b.WriteByte(',')
+		}
+		b.WriteByte('}')
+	default:
+		return false
+	}
+	return true
+}
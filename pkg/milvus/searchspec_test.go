@@ -0,0 +1,58 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchSpecCache(t *testing.T) {
+	client := &Client{}
+	params := func() map[string]interface{} {
+		return map[string]interface{}{
+			"vectorField":  "embedding",
+			"outputFields": []interface{}{"title"},
+			"metricType":   "COSINE",
+			"params":       map[string]interface{}{"ef": int64(64), "metric_type": "IP"},
+			"tags":         map[string]interface{}{"phase": "steady"},
+		}
+	}
+
+	spec, err := client.searchSpec("bench", params())
+	require.NoError(t, err)
+	assert.Equal(t, "embedding", spec.vectorField)
+	assert.Equal(t, []string{"title"}, spec.outputFields)
+	assert.Equal(t, map[string]string{"ef": "64", "metric_type": "IP"}, spec.searchParams, "params override metricType")
+	assert.Equal(t, map[string]string{"phase": "steady"}, spec.tags)
+
+	// Equal params are parsed once, whatever their per-call params
+	again := params()
+	again["queryIds"] = []interface{}{int64(7)}
+	cached, err := client.searchSpec("bench", again)
+	require.NoError(t, err)
+	assert.Same(t, spec, cached)
+
+	// Another collection or another value is another spec
+	other, err := client.searchSpec("other", params())
+	require.NoError(t, err)
+	assert.NotSame(t, spec, other)
+	changed := params()
+	changed["params"] = map[string]interface{}{"ef": "64", "metric_type": "IP"}
+	other, err = client.searchSpec("bench", changed)
+	require.NoError(t, err)
+	assert.NotSame(t, spec, other, "a string is not a number")
+
+	_, err = client.searchSpec("bench", map[string]interface{}{"tags": "phase"})
+	assert.ErrorContains(t, err, "invalid tags")
+	assert.Len(t, client.searchSpecs, 3, "failures are not cached")
+
+	// Values the key cannot hold and new specs beyond the bound are parsed on every call
+	_, cacheable := searchSpecKey("bench", map[string]interface{}{"vectorField": struct{}{}})
+	assert.False(t, cacheable)
+	for i := 0; i < 2*maxSearchSpecs; i++ {
+		_, err := client.searchSpec("bench", map[string]interface{}{"offset": int64(i)})
+		require.NoError(t, err)
+	}
+	assert.Len(t, client.searchSpecs, maxSearchSpecs)
+}
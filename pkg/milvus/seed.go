@@ -0,0 +1,98 @@
+package milvus
+
+import (
+	"fmt"
+	"math/rand"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// seedStream is the random stream of a generator. By default each VU draws one stream for the
+// whole test. In per-iteration mode, the stream restarts at every iteration from a seed derived
+// from the VU ID and the iteration, so the data of any iteration can be regenerated with replay.
+type seedStream struct {
+	vu           modules.VU
+	seed         int64
+	perIteration bool
+	rng          *rand.Rand // Seeded on the first draw, as the VU ID is not known in the init context
+	iteration    int64      // Iteration the stream was seeded for, in per-iteration mode
+	replaying    bool       // Positioned by replay, so it no longer follows the VU
+}
+
+func newSeedStream(vu modules.VU, seed int64, perIteration bool) seedStream {
+	return seedStream{vu: vu, seed: seed, perIteration: perIteration}
+}
+
+// rand returns the stream to draw from, reseeded when a new iteration starts
+func (s *seedStream) rand() *rand.Rand {
+	if s.replaying {
+		return s.rng
+	}
+	var vuID uint64
+	iteration := int64(-1)
+	if s.vu != nil && s.vu.State() != nil {
+		vuID = s.vu.State().VUID
+		if s.perIteration {
+			iteration = s.vu.State().Iteration
+		}
+	}
+	if s.rng == nil || iteration != s.iteration {
+		s.rng = rand.New(rand.NewSource(streamSeed(s.seed, vuID, iteration)))
+		s.iteration = iteration
+	}
+	return s.rng
+}
+
+// replay positions the stream at the start of the stream of a VU, or of one of its iterations
+// in per-iteration mode. Later draws continue from there, whatever VU and iteration run them.
+func (s *seedStream) replay(vuID int64, iteration []int64) error {
+	if vuID < 0 {
+		return fmt.Errorf("vuId must not be negative, got %d", vuID)
+	}
+	it := int64(-1)
+	if s.perIteration {
+		if len(iteration) == 0 || iteration[0] < 0 {
+			return fmt.Errorf("replay of a per-iteration generator requires a non-negative iteration")
+		}
+		it = iteration[0]
+	}
+	s.rng = rand.New(rand.NewSource(streamSeed(s.seed, uint64(vuID), it)))
+	s.iteration = it
+	s.replaying = true
+	return nil
+}
+
+// streamSeed derives the seed of the stream of a VU, and of one of its iterations when
+// iteration is not negative
+func streamSeed(seed int64, vuID uint64, iteration int64) int64 {
+	// Spread VU streams far apart in seed space
+	derived := seed ^ int64(vuID*0x9E3779B97F4A7C15>>1)
+	if iteration >= 0 {
+		derived ^= int64(splitmix64(uint64(iteration)) >> 1)
+	}
+	return derived
+}
+
+// splitmix64 scrambles consecutive integers into unrelated ones
+func splitmix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
+
+// Seed returns the seed that generators derive for a VU, or for one of its iterations, from a
+// base seed. Use it to seed data generated in JavaScript the same way.
+func (m *Milvus) Seed(seed int64, vuID int64, iteration ...int64) (int64, error) {
+	if vuID < 0 {
+		return 0, fmt.Errorf("vuId must not be negative, got %d", vuID)
+	}
+	it := int64(-1)
+	if len(iteration) > 0 {
+		if iteration[0] < 0 {
+			return 0, fmt.Errorf("iteration must not be negative, got %d", iteration[0])
+		}
+		it = iteration[0]
+	}
+	return streamSeed(seed, uint64(vuID), it), nil
+}
@@ -0,0 +1,98 @@
+package milvus
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+)
+
+func TestSeedPerIteration(t *testing.T) {
+	state := &lib.State{VUID: 3, Iteration: 0}
+	m := &Milvus{vu: &metricsVU{state: state}, shared: &sync.Map{}}
+	config := map[string]interface{}{"dim": 8, "seed": 11, "perIteration": true}
+	gen, err := m.VectorGenerator(config)
+	require.NoError(t, err)
+
+	first, err := gen.Next(4)
+	require.NoError(t, err)
+	state.Iteration = 1
+	second, err := gen.Next(4)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	// Another generator, e.g. in a later scenario, regenerates the data of any VU and iteration
+	replay, err := (&Milvus{vu: &metricsVU{state: &lib.State{VUID: 9, Iteration: 5}}}).VectorGenerator(config)
+	require.NoError(t, err)
+	require.NoError(t, replay.Replay(3, 0))
+	vectors, err := replay.Next(4)
+	require.NoError(t, err)
+	assert.Equal(t, first, vectors)
+	require.NoError(t, replay.Replay(3, 1))
+	vectors, err = replay.Next(2)
+	require.NoError(t, err)
+	assert.Equal(t, second[:2], vectors)
+	// Replaying does not follow the VU iteration
+	vectors, err = replay.Next(2)
+	require.NoError(t, err)
+	assert.Equal(t, second[2:], vectors)
+	assert.ErrorContains(t, replay.Replay(3), "requires a non-negative iteration")
+
+	faker, err := newDataFaker(&metricsVU{state: &lib.State{VUID: 3, Iteration: 2}}, nil, DataFakerConfig{Seed: 4, PerIteration: true, Fields: map[string]FakerFieldConfig{"age": {Type: "int64"}}})
+	require.NoError(t, err)
+	rows, err := faker.Next(5)
+	require.NoError(t, err)
+	require.NoError(t, faker.Replay(3, 2))
+	again, err := faker.Next(5)
+	require.NoError(t, err)
+	assert.Equal(t, rows, again)
+
+	zipf, err := m.ZipfGenerator(map[string]interface{}{"n": 50, "seed": 2, "perIteration": true})
+	require.NoError(t, err)
+	values, err := zipf.Next(10)
+	require.NoError(t, err)
+	require.NoError(t, zipf.Replay(3, 1))
+	replayed, err := zipf.Next(10)
+	require.NoError(t, err)
+	assert.Equal(t, values, replayed)
+}
+
+func TestSeedPerVU(t *testing.T) {
+	state := &lib.State{VUID: 2}
+	gen, err := (&Milvus{vu: &metricsVU{state: state}}).VectorGenerator(map[string]interface{}{"dim": 8, "seed": 11})
+	require.NoError(t, err)
+	first, err := gen.Next(3)
+	require.NoError(t, err)
+	state.Iteration = 1
+	second, err := gen.Next(3)
+	require.NoError(t, err)
+
+	// Without perIteration, the stream spans the iterations of the VU
+	replay, err := (&Milvus{}).VectorGenerator(map[string]interface{}{"dim": 8, "seed": 11})
+	require.NoError(t, err)
+	require.NoError(t, replay.Replay(2, 7))
+	vectors, err := replay.Next(6)
+	require.NoError(t, err)
+	assert.Equal(t, append(first, second...), vectors)
+	assert.ErrorContains(t, replay.Replay(-1), "vuId must not be negative")
+}
+
+func TestSeed(t *testing.T) {
+	m := &Milvus{}
+	vuSeed, err := m.Seed(42, 1)
+	require.NoError(t, err)
+	assert.Equal(t, streamSeed(42, 1, -1), vuSeed)
+	first, err := m.Seed(42, 1, 0)
+	require.NoError(t, err)
+	second, err := m.Seed(42, 1, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, vuSeed, first)
+	assert.NotEqual(t, first, second)
+
+	_, err = m.Seed(42, -1)
+	assert.Error(t, err)
+	_, err = m.Seed(42, 1, -1)
+	assert.Error(t, err)
+}
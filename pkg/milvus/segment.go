@@ -0,0 +1,203 @@
+package milvus
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+)
+
+// segmentStates are always emitted in milvus_segments and milvus_segment_rows, with 0 when no
+// segment is in that state, so a series drops to 0 instead of going stale once segments move on
+var segmentStates = []commonpb.SegmentState{
+	commonpb.SegmentState_Growing,
+	commonpb.SegmentState_Sealed,
+	commonpb.SegmentState_Flushing,
+	commonpb.SegmentState_Flushed,
+}
+
+// segmentTotals aggregates segments by state
+type segmentTotals struct {
+	counts map[commonpb.SegmentState]int64
+	rows   map[commonpb.SegmentState]int64
+}
+
+func newSegmentTotals() *segmentTotals {
+	return &segmentTotals{
+		counts: make(map[commonpb.SegmentState]int64),
+		rows:   make(map[commonpb.SegmentState]int64),
+	}
+}
+
+func (t *segmentTotals) add(state commonpb.SegmentState, rows int64) {
+	t.counts[state]++
+	t.rows[state] += rows
+}
+
+// result returns the per-state segment counts and the total row count
+func (t *segmentTotals) result() (map[string]int64, int64) {
+	states := make(map[string]int64, len(t.counts))
+	var rows int64
+	for state, count := range t.counts {
+		states[state.String()] = count
+		rows += t.rows[state]
+	}
+	return states, rows
+}
+
+// emit pushes milvus_segments and milvus_segment_rows for each state, tagged with source
+// (persistent or query) so the data and query node views can be told apart
+func (t *segmentTotals) emit(c *Client, coll, source string) {
+	if c.metrics == nil {
+		return
+	}
+	states := append([]commonpb.SegmentState(nil), segmentStates...)
+	for state := range t.counts {
+		if !slices.Contains(states, state) {
+			states = append(states, state)
+		}
+	}
+	for _, state := range states {
+		tags := map[string]string{"collection": coll, "source": source, "state": state.String()}
+		c.pushMetric(c.metrics.Segments, float64(t.counts[state]), tags)
+		c.pushMetric(c.metrics.SegmentRows, float64(t.rows[state]), tags)
+	}
+}
+
+// GetPersistentSegmentInfo returns the segments of a collection as tracked by the data coordinator,
+// including growing segments, and emits their counts and rows per state as the milvus_segments and
+// milvus_segment_rows Gauges tagged with source=persistent. Call it periodically during long
+// ingest tests to watch segments grow, seal and flush.
+func (c *Client) GetPersistentSegmentInfo(collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+	service := c.milvus().GetService()
+	if service == nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "failed to get persistent segment info: client is not connected",
+		})
+	}
+
+	resp, err := service.GetPersistentSegmentInfo(c.context(), &milvuspb.GetPersistentSegmentInfoRequest{CollectionName: coll})
+	if err = merr.CheckRPCCall(resp, err); err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to get persistent segment info: %v", err),
+			Cause:        err,
+		})
+	}
+
+	totals := newSegmentTotals()
+	segments := make([]map[string]interface{}, 0, len(resp.GetInfos()))
+	for _, info := range resp.GetInfos() {
+		totals.add(info.GetState(), info.GetNumRows())
+		segments = append(segments, map[string]interface{}{
+			"id":           info.GetSegmentID(),
+			"partition_id": info.GetPartitionID(),
+			"num_rows":     info.GetNumRows(),
+			"state":        info.GetState().String(),
+			"level":        info.GetLevel().String(),
+			"is_sorted":    info.GetIsSorted(),
+		})
+	}
+	totals.emit(c, coll, "persistent")
+
+	states, rows := totals.result()
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result: map[string]interface{}{
+			"segments": segments,
+			"count":    len(segments),
+			"num_rows": rows,
+			"states":   states,
+		},
+	})
+}
+
+// GetQuerySegmentInfo returns the segments of a loaded collection as served by the query nodes,
+// and emits their counts and rows per state as the milvus_segments and milvus_segment_rows Gauges
+// tagged with source=query, and their total memory as the milvus_segment_memory Gauge.
+func (c *Client) GetQuerySegmentInfo(collectionName ...string) interface{} {
+	start := time.Now()
+
+	coll := c.getCollectionName(collectionName...)
+	if coll == "" {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "collection name required",
+		})
+	}
+	service := c.milvus().GetService()
+	if service == nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "failed to get query segment info: client is not connected",
+		})
+	}
+
+	resp, err := service.GetQuerySegmentInfo(c.context(), &milvuspb.GetQuerySegmentInfoRequest{CollectionName: coll})
+	if err = merr.CheckRPCCall(resp, err); err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to get query segment info: %v", err),
+			Cause:        err,
+		})
+	}
+
+	totals := newSegmentTotals()
+	var memSize int64
+	segments := make([]map[string]interface{}, 0, len(resp.GetInfos()))
+	for _, info := range resp.GetInfos() {
+		totals.add(info.GetState(), info.GetNumRows())
+		memSize += info.GetMemSize()
+		nodeIDs := info.GetNodeIds()
+		if nodeIDs == nil {
+			nodeIDs = []int64{}
+		}
+		segments = append(segments, map[string]interface{}{
+			"id":           info.GetSegmentID(),
+			"partition_id": info.GetPartitionID(),
+			"num_rows":     info.GetNumRows(),
+			"mem_size":     info.GetMemSize(),
+			"state":        info.GetState().String(),
+			"level":        info.GetLevel().String(),
+			"index_name":   info.GetIndexName(),
+			"node_ids":     nodeIDs,
+		})
+	}
+	totals.emit(c, coll, "query")
+	if c.metrics != nil {
+		c.pushMetric(c.metrics.SegmentMemory, float64(memSize), map[string]string{"collection": coll})
+	}
+
+	states, rows := totals.result()
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result: map[string]interface{}{
+			"segments": segments,
+			"count":    len(segments),
+			"num_rows": rows,
+			"mem_size": memSize,
+			"states":   states,
+		},
+	})
+}
@@ -0,0 +1,87 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segmentServer is a Milvus service reporting a growing and two flushed segments
+type segmentServer struct {
+	milvuspb.UnimplementedMilvusServiceServer
+}
+
+func (segmentServer) GetPersistentSegmentInfo(context.Context, *milvuspb.GetPersistentSegmentInfoRequest) (*milvuspb.GetPersistentSegmentInfoResponse, error) {
+	return &milvuspb.GetPersistentSegmentInfoResponse{
+		Status: &commonpb.Status{},
+		Infos: []*milvuspb.PersistentSegmentInfo{
+			{SegmentID: 1, NumRows: 1000, State: commonpb.SegmentState_Growing},
+			{SegmentID: 2, NumRows: 50000, State: commonpb.SegmentState_Flushed},
+			{SegmentID: 3, NumRows: 40000, State: commonpb.SegmentState_Flushed},
+		},
+	}, nil
+}
+
+func (segmentServer) GetQuerySegmentInfo(context.Context, *milvuspb.GetQuerySegmentInfoRequest) (*milvuspb.GetQuerySegmentInfoResponse, error) {
+	return &milvuspb.GetQuerySegmentInfoResponse{
+		Status: &commonpb.Status{},
+		Infos: []*milvuspb.QuerySegmentInfo{
+			{SegmentID: 2, NumRows: 50000, MemSize: 4 << 20, State: commonpb.SegmentState_Sealed, NodeIds: []int64{7}},
+		},
+	}, nil
+}
+
+func TestSegmentInfo(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	client := fakeClient(t, &Milvus{vu: vu, metrics: registerMetrics(vu)}, segmentServer{}, WithCollection("docs"))
+
+	persistent := client.GetPersistentSegmentInfo().(map[string]interface{})
+	require.Equal(t, true, persistent["success"], persistent["error"])
+	result := persistent["result"].(map[string]interface{})
+	assert.Equal(t, float64(3), result["count"])
+	assert.Equal(t, float64(91000), result["num_rows"])
+	assert.Equal(t, map[string]interface{}{"Growing": float64(1), "Flushed": float64(2)}, result["states"])
+
+	query := client.GetQuerySegmentInfo().(map[string]interface{})
+	require.Equal(t, true, query["success"], query["error"])
+	result = query["result"].(map[string]interface{})
+	assert.Equal(t, float64(4<<20), result["mem_size"])
+
+	got := map[string]float64{}
+	close(samples)
+	for container := range samples {
+		for _, sample := range container.GetSamples() {
+			tags := sample.Tags.Map()
+			switch sample.Metric.Name {
+			case "milvus_segments", "milvus_segment_rows":
+				assert.Equal(t, "docs", tags["collection"])
+				got[sample.Metric.Name+":"+tags["source"]+":"+tags["state"]] = sample.Value
+			case "milvus_segment_memory":
+				got[sample.Metric.Name] = sample.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"milvus_segments:persistent:Growing":      1,
+		"milvus_segments:persistent:Sealed":       0,
+		"milvus_segments:persistent:Flushing":     0,
+		"milvus_segments:persistent:Flushed":      2,
+		"milvus_segment_rows:persistent:Growing":  1000,
+		"milvus_segment_rows:persistent:Sealed":   0,
+		"milvus_segment_rows:persistent:Flushing": 0,
+		"milvus_segment_rows:persistent:Flushed":  90000,
+		"milvus_segments:query:Growing":           0,
+		"milvus_segments:query:Sealed":            1,
+		"milvus_segments:query:Flushing":          0,
+		"milvus_segments:query:Flushed":           0,
+		"milvus_segment_rows:query:Growing":       0,
+		"milvus_segment_rows:query:Sealed":        50000,
+		"milvus_segment_rows:query:Flushing":      0,
+		"milvus_segment_rows:query:Flushed":       0,
+		"milvus_segment_memory":                   4 << 20,
+	}, got)
+}
@@ -0,0 +1,286 @@
+package milvus
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+)
+
+// Segment state comparison defaults
+const (
+	defaultSegmentCompareTimeout = 10 * time.Minute
+	// segmentComparePollInterval is the wait between reads of the index and segment states
+	segmentComparePollInterval = time.Second
+)
+
+// SegmentCompareConfig configures client.compareSegmentStates()
+type SegmentCompareConfig struct {
+	CollectionName string                 `json:"collectionName,omitempty"` // Default: the collection of the client
+	TopK           int                    `json:"topK,omitempty"`           // Hits per query (default: 10)
+	Params         map[string]interface{} `json:"params,omitempty"`         // search() params of every query
+	Rounds         int                    `json:"rounds,omitempty"`         // Passes over the queries in each state (default: 1)
+	Index          map[string]interface{} `json:"index,omitempty"`          // Index created after the flush, as for createIndex() (default: wait for the existing index)
+	Timeout        string                 `json:"timeout,omitempty"`        // Longest wait for the index and the sealed segments (default: "10m")
+}
+
+// segmentPass holds the searches of the query set in one segment state
+type segmentPass struct {
+	latencies []float64 // Milliseconds of each search
+	errors    int
+	hits      [][]int64 // Hit IDs of each query of the first round
+}
+
+// CompareSegmentStates runs the same query set against a loaded collection whose rows are still
+// in growing segments, then flushes, builds or waits for the vector index and for the segments
+// to be sealed, and runs the query set again, quantifying how much faster indexed sealed
+// segments are than growing ones searched by brute force. Every metric of the searches is
+// tagged with segment_state, growing or sealed. The result holds the latency statistics of each
+// state, the speedup of the mean latency, and overlap: the mean fraction of the hits of each
+// query in growing segments also returned by the index, which shows the recall given up.
+//
+// Insert the rows just before the call, without flushing, so they are still growing.
+//
+// Usage in k6:
+//
+//	export function setup() {
+//	    const client = milvus.client('localhost:19530', 'bench');
+//	    client.insert({ embedding: gen.next(10000) });
+//	    const result = client.compareSegmentStates({ queries: gen.next(100), params: { vectorField: 'embedding' } });
+//	    console.log(`sealed segments ${result.result.speedup}x faster`);
+//	}
+func (c *Client) CompareSegmentStates(configInput map[string]interface{}) interface{} {
+	start := time.Now()
+	timings := make(map[string]float64)
+	fail := func(err string, cause error) interface{} {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err,
+			Cause:        cause,
+			Result:       map[string]interface{}{"timings": timings},
+		})
+	}
+
+	var config SegmentCompareConfig
+	if err := convertViaJSON(splitModuleObjects(configInput, "queries"), &config); err != nil {
+		return fail(fmt.Sprintf("invalid segment comparison config: %v", err), nil)
+	}
+	coll := c.getCollectionName(config.CollectionName)
+	if coll == "" {
+		return fail(ErrCollectionNameRequired.Error(), nil)
+	}
+	queries, err := segmentCompareQueries(configInput["queries"])
+	if err != nil {
+		return fail(err.Error(), nil)
+	}
+	timeout := defaultSegmentCompareTimeout
+	if config.Timeout != "" {
+		if timeout, err = time.ParseDuration(config.Timeout); err != nil || timeout <= 0 {
+			return fail(fmt.Sprintf("timeout must be a positive duration, got %q", config.Timeout), nil)
+		}
+	}
+	topK := optionalPositive(config.TopK, defaultVerifyTopK)
+	rounds := optionalPositive(config.Rounds, 1)
+	vectorField, _ := stringOption(config.Params, "vectorField")
+	if vectorField == "" {
+		vectorField = "vector"
+	}
+
+	// phase runs one step, recording its time
+	phase := func(name string, run func() error) error {
+		phaseStart := time.Now()
+		err := run()
+		timings[name] = float64(time.Since(phaseStart).Milliseconds())
+		return err
+	}
+	var growing, sealed *segmentPass
+	search := func(state string) *segmentPass {
+		var pass *segmentPass
+		_ = phase(state, func() error {
+			pass = c.searchSegmentPass(state, queries, topK, config.Params, rounds, coll)
+			return nil
+		})
+		return pass
+	}
+	if growing = search(segmentStateGrowing); growing.errors == len(growing.latencies) {
+		return fail("every search of the growing segments failed", nil)
+	}
+
+	if err := phase("flush", func() error {
+		if flushed := c.Flush(coll).(map[string]interface{}); flushed["success"] != true {
+			return fmt.Errorf("failed to flush: %v", flushed["error"])
+		}
+		return nil
+	}); err != nil {
+		return fail(err.Error(), nil)
+	}
+	deadline := time.Now().Add(timeout)
+	if err := phase("index", func() error { return c.awaitSealedIndex(coll, vectorField, config.Index, deadline) }); err != nil {
+		return fail(err.Error(), nil)
+	}
+	sealed = search(segmentStateSealed)
+
+	growingStats, sealedStats := growing.stats(), sealed.stats()
+	speedup := 0.0
+	if sealedStats["mean"] > 0 {
+		speedup = growingStats["mean"] / sealedStats["mean"]
+	}
+	return toMap(&OperationResult{
+		Success:      sealed.errors == 0 && growing.errors == 0,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Error:        segmentPassErrors(growing, sealed),
+		Result: map[string]interface{}{
+			"collection": coll,
+			"growing":    growingStats,
+			"sealed":     sealedStats,
+			"speedup":    speedup,
+			"overlap":    hitOverlap(growing.hits, sealed.hits),
+			"timings":    timings,
+		},
+	})
+}
+
+// Values of the segment_state tag
+const (
+	segmentStateGrowing = "growing"
+	segmentStateSealed  = "sealed"
+)
+
+// segmentCompareQueries returns the query vectors of a segment state comparison
+func segmentCompareQueries(input interface{}) ([][]float32, error) {
+	if input == nil {
+		return nil, fmt.Errorf("queries required")
+	}
+	vectors, err := convertToSearchVectors(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid queries: %v", err)
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("queries must not be empty")
+	}
+	queries := make([][]float32, len(vectors))
+	for i, vector := range vectors {
+		dense, ok := vector.(entity.FloatVector)
+		if !ok {
+			return nil, fmt.Errorf("queries must be dense float vectors, got %T", vector)
+		}
+		queries[i] = dense
+	}
+	return queries, nil
+}
+
+// searchSegmentPass searches each query on its own, rounds times, with metrics tagged with the
+// segment state
+func (c *Client) searchSegmentPass(state string, queries [][]float32, topK int, params map[string]interface{}, rounds int, coll string) *segmentPass {
+	client := c.withTags(map[string]string{"segment_state": state})
+	pass := &segmentPass{hits: make([][]int64, len(queries))}
+	for round := 0; round < rounds; round++ {
+		for i, query := range queries {
+			searchStart := time.Now()
+			result := client.Search([][]float32{query}, topK, params, coll).(map[string]interface{})
+			pass.latencies = append(pass.latencies, float64(time.Since(searchStart).Microseconds())/1000)
+			if result["success"] != true {
+				pass.errors++
+				continue
+			}
+			if round == 0 {
+				hits, _ := result["result"].([]interface{})
+				for _, hit := range hits {
+					if id, ok := hit.(map[string]interface{})["id"].(float64); ok {
+						pass.hits[i] = append(pass.hits[i], int64(id))
+					}
+				}
+			}
+		}
+	}
+	return pass
+}
+
+// stats returns the number of searches and failures and the mean and percentile latencies in
+// milliseconds
+func (p *segmentPass) stats() map[string]float64 {
+	sorted := slices.Clone(p.latencies)
+	slices.Sort(sorted)
+	percentile := func(q float64) float64 {
+		if len(sorted) == 0 {
+			return 0
+		}
+		return sorted[max(0, int(math.Ceil(q*float64(len(sorted))))-1)]
+	}
+	return map[string]float64{
+		"searches": float64(len(p.latencies)),
+		"errors":   float64(p.errors),
+		"mean":     mean(p.latencies),
+		"p50":      percentile(0.5),
+		"p95":      percentile(0.95),
+		"p99":      percentile(0.99),
+	}
+}
+
+// segmentPassErrors describes the failed searches of the passes, if any
+func segmentPassErrors(growing, sealed *segmentPass) string {
+	if growing.errors == 0 && sealed.errors == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d searches of growing segments and %d of sealed segments failed", growing.errors, sealed.errors)
+}
+
+// hitOverlap returns the mean fraction of the hits of each query in want also in got, over the
+// queries with hits in want
+func hitOverlap(want, got [][]int64) float64 {
+	var fractions []float64
+	for i, ids := range want {
+		if len(ids) == 0 {
+			continue
+		}
+		found := 0
+		for _, id := range ids {
+			if slices.Contains(got[i], id) {
+				found++
+			}
+		}
+		fractions = append(fractions, float64(found)/float64(len(ids)))
+	}
+	return mean(fractions)
+}
+
+// awaitSealedIndex creates the index, if given, or waits for the existing index of the field to
+// cover every flushed row, then waits until the query nodes serve all of them from sealed
+// segments, which they load once the flushed segments are handed off
+func (c *Client) awaitSealedIndex(coll, vectorField string, indexParams map[string]interface{}, deadline time.Time) error {
+	if indexParams != nil {
+		if created := c.CreateIndex(vectorField, indexParams, coll).(map[string]interface{}); created["success"] != true {
+			return fmt.Errorf("failed to create index: %v", created["error"])
+		}
+	}
+	ctx := c.context()
+	var flushedRows float64
+	for {
+		progress := c.IndexBuildProgress(vectorField, coll).(map[string]interface{})
+		if progress["success"] != true {
+			return fmt.Errorf("failed to read index progress: %v", progress["error"])
+		}
+		if result, _ := progress["result"].(map[string]interface{}); result["state"] == "Finished" && result["pending_rows"] == float64(0) {
+			flushedRows, _ = result["total_rows"].(float64)
+			break
+		}
+		if time.Now().After(deadline) || !sleepContext(ctx, segmentComparePollInterval) {
+			return fmt.Errorf("index of field %s not built in time", vectorField)
+		}
+	}
+	for {
+		segments := c.GetQuerySegmentInfo(coll).(map[string]interface{})
+		if segments["success"] != true {
+			return fmt.Errorf("failed to read query segments: %v", segments["error"])
+		}
+		if rows, _ := segments["result"].(map[string]interface{})["num_rows"].(float64); rows >= flushedRows {
+			return nil
+		}
+		if time.Now().After(deadline) || !sleepContext(ctx, segmentComparePollInterval) {
+			return fmt.Errorf("sealed segments of collection %s not loaded in time", coll)
+		}
+	}
+}
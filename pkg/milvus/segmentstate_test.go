@@ -0,0 +1,132 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segmentStateServer answers searches with the IDs 1 and 2 before the flush and 1 and 3 after
+// it, reports the index as pending on the first progress read after the flush, which describes
+// the index twice, and serves the flushed rows from sealed segments
+type segmentStateServer struct {
+	prepareServer
+	flushed      bool
+	indexReads   int
+	sealedRows   int64
+	searchStates []bool
+}
+
+func (s *segmentStateServer) Flush(ctx context.Context, req *milvuspb.FlushRequest) (*milvuspb.FlushResponse, error) {
+	s.mu.Lock()
+	s.flushed = true
+	s.mu.Unlock()
+	return s.prepareServer.Flush(ctx, req)
+}
+
+func (s *segmentStateServer) DescribeIndex(context.Context, *milvuspb.DescribeIndexRequest) (*milvuspb.DescribeIndexResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.flushed {
+		s.indexReads++
+	}
+	pending := int64(0)
+	if s.indexReads <= 2 {
+		pending = 100
+	}
+	return &milvuspb.DescribeIndexResponse{Status: &commonpb.Status{}, IndexDescriptions: []*milvuspb.IndexDescription{
+		{FieldName: "embedding", IndexName: "embedding", State: commonpb.IndexState_Finished, TotalRows: 100, IndexedRows: 100 - pending, PendingIndexRows: pending},
+	}}, nil
+}
+
+func (s *segmentStateServer) GetQuerySegmentInfo(context.Context, *milvuspb.GetQuerySegmentInfoRequest) (*milvuspb.GetQuerySegmentInfoResponse, error) {
+	return &milvuspb.GetQuerySegmentInfoResponse{Status: &commonpb.Status{}, Infos: []*milvuspb.QuerySegmentInfo{
+		{SegmentID: 1, NumRows: s.sealedRows, State: commonpb.SegmentState_Sealed},
+	}}, nil
+}
+
+func (s *segmentStateServer) Search(_ context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	s.mu.Lock()
+	flushed := s.flushed
+	s.searchStates = append(s.searchStates, flushed)
+	s.mu.Unlock()
+	ids := []int64{1, 2}
+	if flushed {
+		ids = []int64{1, 3}
+	}
+	data := &schemapb.SearchResultData{NumQueries: req.GetNq(), TopK: 2, Topks: []int64{2}, Scores: []float32{0.1, 0.2},
+		Ids: &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}}}
+	return &milvuspb.SearchResults{Status: &commonpb.Status{}, Results: data}, nil
+}
+
+func TestCompareSegmentStates(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	service := &segmentStateServer{prepareServer: prepareServer{exists: true}, sealedRows: 100}
+	client := benchClient(t, service, vu)
+
+	result := client.CompareSegmentStates(map[string]interface{}{
+		"queries": [][]float32{{1, 0}, {0, 1}},
+		"topK":    2,
+		"rounds":  2,
+		"params":  map[string]interface{}{"vectorField": "embedding"},
+	}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	compared := result["result"].(map[string]interface{})
+	assert.Equal(t, float64(4), compared["growing"].(map[string]interface{})["searches"])
+	assert.Equal(t, float64(4), compared["sealed"].(map[string]interface{})["searches"])
+	assert.Equal(t, 0.5, compared["overlap"], "one of the two growing hits is found after the flush")
+	assert.Greater(t, compared["speedup"], 0.0)
+	assert.Contains(t, compared["timings"], "index")
+	assert.Equal(t, []bool{false, false, false, false, true, true, true, true}, service.searchStates)
+	assert.Equal(t, 4, service.indexReads, "waits for the pending index rows")
+
+	states := map[string]int{}
+	for _, sample := range drainSamples(samples) {
+		method, _ := sample.Tags.Get("method")
+		if state, ok := sample.Tags.Get("segment_state"); ok && method == "Search" && sample.Metric.Name == "milvus_errors" {
+			states[state]++
+		}
+	}
+	assert.Equal(t, map[string]int{"growing": 4, "sealed": 4}, states)
+}
+
+func TestCompareSegmentStatesCreateIndex(t *testing.T) {
+	service := &segmentStateServer{prepareServer: prepareServer{exists: true}, sealedRows: 100, indexReads: 2}
+	client := benchClient(t, service, &metricsVU{})
+
+	result := client.CompareSegmentStates(map[string]interface{}{
+		"queries": [][]float32{{1, 0}},
+		"params":  map[string]interface{}{"vectorField": "embedding"},
+		"index":   map[string]interface{}{"indexType": "HNSW", "metricType": "L2"},
+	}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, "embedding", service.index.GetFieldName())
+	assert.Equal(t, []string{"flush", "index"}, service.calls)
+}
+
+func TestCompareSegmentStatesErrors(t *testing.T) {
+	service := &segmentStateServer{prepareServer: prepareServer{exists: true}, indexReads: 2}
+	client := benchClient(t, service, &metricsVU{})
+	for name, config := range map[string]map[string]interface{}{
+		"no queries":     {"params": map[string]interface{}{"vectorField": "embedding"}},
+		"sparse queries": {"queries": []interface{}{map[string]interface{}{"1": 0.5}}},
+		"timeout":        {"queries": [][]float32{{1, 0}}, "timeout": "soon"},
+	} {
+		result := client.CompareSegmentStates(config).(map[string]interface{})
+		assert.Equal(t, false, result["success"], name)
+	}
+
+	// The query nodes never serve the flushed rows from sealed segments
+	result := client.CompareSegmentStates(map[string]interface{}{
+		"queries": [][]float32{{1, 0}},
+		"params":  map[string]interface{}{"vectorField": "embedding"},
+		"timeout": "10ms",
+	}).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "not loaded in time")
+}
@@ -0,0 +1,234 @@
+package milvus
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSelectivities are the fractions of rows matched by the filters of a selectivity sweep
+var defaultSelectivities = []float64{0.001, 0.01, 0.1, 0.5}
+
+// SelectivitySweepConfig configures milvus.selectivitySweep()
+type SelectivitySweepConfig struct {
+	Field         string    `json:"field"`                   // Field of the data faker the filters apply to
+	Selectivities []float64 `json:"selectivities,omitempty"` // Fractions of rows matched, in (0, 1] (default: 0.001, 0.01, 0.1 and 0.5)
+}
+
+// selectivityFilter is the filter of one target selectivity
+type selectivityFilter struct {
+	bucket string  // Tag value: the target in percent, e.g. "0.1%"
+	target float64 // Fraction of rows the filter should match
+	actual float64 // Fraction of rows the filter matches in expectation
+	filter string
+}
+
+// SelectivitySweep generates filters matching chosen fractions of the rows generated by a data
+// faker, so that filtered search performance at several selectivities comes from a single run.
+// The filters are derived from the distribution of one faker field: a range cut of numbers and
+// timestamps, or the most frequent categorical values. search() cycles through the
+// selectivities, and every metric of a search is tagged with its selectivity bucket.
+//
+// Usage in k6:
+//
+//	const faker = milvus.dataFaker({ fields: { price: { type: 'double', min: 0, max: 1000 } } });
+//	const sweep = milvus.selectivitySweep({ scalars: faker, field: 'price' });
+//	export default function () {
+//	    sweep.search(client, gen.next(1), 10, { vectorField: 'embedding' });
+//	}
+type SelectivitySweep struct {
+	filters []selectivityFilter
+	turn    int // Next filter of the VU
+}
+
+// SelectivitySweep creates the filters of a selectivity sweep over a data faker field
+func (m *Milvus) SelectivitySweep(configInput map[string]interface{}) (*SelectivitySweep, error) {
+	var config SelectivitySweepConfig
+	if err := convertViaJSON(splitModuleObjects(configInput, "scalars"), &config); err != nil {
+		return nil, fmt.Errorf("invalid selectivity sweep config: %v", err)
+	}
+	faker, ok := configInput["scalars"].(*DataFaker)
+	if !ok {
+		return nil, fmt.Errorf("selectivity sweep scalars must be a milvus.dataFaker() object, got %T", configInput["scalars"])
+	}
+	var field *fakerField
+	for _, candidate := range faker.fields {
+		if candidate.name == config.Field {
+			field = candidate
+		}
+	}
+	if field == nil {
+		return nil, fmt.Errorf("selectivity sweep field %q is not a field of the data faker", config.Field)
+	}
+	if len(config.Selectivities) == 0 {
+		config.Selectivities = defaultSelectivities
+	}
+
+	s := &SelectivitySweep{}
+	for _, target := range config.Selectivities {
+		if !(target > 0 && target <= 1) {
+			return nil, fmt.Errorf("selectivity sweep selectivities must be above 0 and at most 1, got %v", target)
+		}
+		filter, actual, err := field.selectivityFilter(target)
+		if err != nil {
+			return nil, fmt.Errorf("selectivity sweep field %q: %v", field.name, err)
+		}
+		s.filters = append(s.filters, selectivityFilter{
+			bucket: strconv.FormatFloat(target*100, 'f', -1, 64) + "%",
+			target: target,
+			actual: actual,
+			filter: filter,
+		})
+	}
+	return s, nil
+}
+
+// selectivityFilter returns the filter expression matching the fraction target of the values of
+// the field, and the fraction it matches in expectation, which differs from target when the
+// values are discrete
+func (f *fakerField) selectivityFilter(target float64) (string, float64, error) {
+	if f.values != nil {
+		k, actual := f.cumulativePrefix(len(f.values), target)
+		literals := make([]string, k)
+		for i := range literals {
+			literal, err := f.literal(f.values[i])
+			if err != nil {
+				return "", 0, err
+			}
+			literals[i] = literal
+		}
+		if k == 1 {
+			return fmt.Sprintf("%s == %s", f.name, literals[0]), actual, nil
+		}
+		return fmt.Sprintf("%s in [%s]", f.name, strings.Join(literals, ", ")), actual, nil
+	}
+
+	switch f.kind {
+	case "int8", "int16", "int32", "int64":
+		// Values below min+k, the k most frequent with the zipf distribution
+		n := f.max - f.min + 1
+		k, actual := math.Max(1, math.Round(target*n)), 0.0
+		if f.zipf != nil {
+			prefix, probability := f.cumulativePrefix(int(n), target)
+			k, actual = float64(prefix), probability
+		} else {
+			actual = k / n
+		}
+		return fmt.Sprintf("%s < %s", f.name, strconv.FormatFloat(f.min+k, 'f', -1, 64)), actual, nil
+	case "float", "double":
+		threshold := f.min + target*(f.max-f.min)
+		return fmt.Sprintf("%s < %s", f.name, strconv.FormatFloat(threshold, 'f', -1, 64)), target, nil
+	case "timestamp":
+		if f.unit == "rfc3339" {
+			return "", 0, fmt.Errorf("timestamps of unit rfc3339 are not supported, use ms or s")
+		}
+		threshold, _ := f.literal(f.start.Add(time.Duration(target * float64(f.end.Sub(f.start)))))
+		return fmt.Sprintf("%s < %s", f.name, threshold), target, nil
+	default:
+		return "", 0, fmt.Errorf("%s fields without values are not supported", f.kind)
+	}
+}
+
+// cumulativePrefix returns the number k of leading values, in 1 to n, whose total probability
+// is closest to target, and that probability
+func (f *fakerField) cumulativePrefix(n int, target float64) (int, float64) {
+	probability := func(k int) float64 {
+		switch {
+		case f.zipf != nil:
+			return f.zipf.cumulative[k-1] / f.zipf.cumulative[n-1]
+		case f.cumulative != nil:
+			return f.cumulative[k-1] / f.cumulative[n-1]
+		default:
+			return float64(k) / float64(n)
+		}
+	}
+	best, bestProbability := 1, probability(1)
+	for k := 2; k <= n; k++ {
+		p := probability(k)
+		if math.Abs(p-target) < math.Abs(bestProbability-target) {
+			best, bestProbability = k, p
+		}
+		if p >= target {
+			break
+		}
+	}
+	return best, bestProbability
+}
+
+// literal formats a value of the field as an expression literal
+func (f *fakerField) literal(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		switch f.unit {
+		case "s":
+			return strconv.FormatInt(v.Unix(), 10), nil
+		case "rfc3339":
+			return strconv.Quote(v.Format(time.RFC3339)), nil
+		default:
+			return strconv.FormatInt(v.UnixMilli(), 10), nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported value %v of type %T", value, value)
+	}
+}
+
+// Next returns the filter of the next selectivity, cycling through them, as { filter, bucket,
+// selectivity, actual }: selectivity is the target and actual the fraction of rows matched in
+// expectation
+func (s *SelectivitySweep) Next() map[string]interface{} {
+	filter := s.filters[s.turn%len(s.filters)]
+	s.turn++
+	return filter.toMap()
+}
+
+// Filters returns the filter of every selectivity, as next() does
+func (s *SelectivitySweep) Filters() []map[string]interface{} {
+	filters := make([]map[string]interface{}, len(s.filters))
+	for i := range s.filters {
+		filters[i] = s.filters[i].toMap()
+	}
+	return filters
+}
+
+func (f *selectivityFilter) toMap() map[string]interface{} {
+	return map[string]interface{}{"filter": f.filter, "bucket": f.bucket, "selectivity": f.target, "actual": f.actual}
+}
+
+// Search searches with the client, as client.search(), with the filter of the next selectivity
+// joined to the filter of params, if any. Every metric of the search is tagged with the
+// selectivity bucket, e.g. "1%". The result holds the bucket in "selectivity" and the filter in
+// "filter".
+func (s *SelectivitySweep) Search(client *Client, vectors interface{}, topK int, params map[string]interface{}, collectionName ...string) interface{} {
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "search requires a client"})
+	}
+	filter := s.filters[s.turn%len(s.filters)]
+	s.turn++
+	scoped := make(map[string]interface{}, len(params)+1)
+	for key, value := range params {
+		scoped[key] = value
+	}
+	// expr takes precedence over filter, as in client.search()
+	base, _ := stringOption(params, "expr")
+	if base == "" {
+		base, _ = stringOption(params, "filter")
+	}
+	expr := filter.filter
+	if base != "" {
+		expr = fmt.Sprintf("(%s) and %s", base, filter.filter)
+	}
+	delete(scoped, "expr")
+	scoped["filter"] = expr
+	result := client.withTags(map[string]string{"selectivity": filter.bucket}).Search(vectors, topK, scoped, collectionName...).(map[string]interface{})
+	result["selectivity"] = filter.bucket
+	result["filter"] = expr
+	return result
+}
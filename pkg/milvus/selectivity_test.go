@@ -0,0 +1,116 @@
+package milvus
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selectivityFaker(t *testing.T, fields map[string]interface{}) *DataFaker {
+	faker, err := (&Milvus{shared: &sync.Map{}}).DataFaker(map[string]interface{}{"fields": fields})
+	require.NoError(t, err)
+	return faker
+}
+
+func TestSelectivitySweepFilters(t *testing.T) {
+	faker := selectivityFaker(t, map[string]interface{}{
+		"price":    map[string]interface{}{"type": "double", "min": 0, "max": 1000},
+		"stock":    map[string]interface{}{"type": "int64", "min": 1, "max": 1000},
+		"category": map[string]interface{}{"type": "varchar", "values": []interface{}{"a", "b", "c", "d"}, "weights": []interface{}{1, 9, 40, 50}},
+		"created":  map[string]interface{}{"type": "timestamp", "start": "2024-01-01T00:00:00Z", "end": "2024-01-11T00:00:00Z", "unit": "s"},
+	})
+	m := &Milvus{shared: &sync.Map{}}
+
+	sweep, err := m.SelectivitySweep(map[string]interface{}{"scalars": faker, "field": "price"})
+	require.NoError(t, err)
+	var buckets, filters []interface{}
+	for _, filter := range sweep.Filters() {
+		buckets = append(buckets, filter["bucket"])
+		filters = append(filters, filter["filter"])
+	}
+	assert.Equal(t, []interface{}{"0.1%", "1%", "10%", "50%"}, buckets)
+	assert.Equal(t, []interface{}{"price < 1", "price < 10", "price < 100", "price < 500"}, filters)
+
+	for field, want := range map[string][]interface{}{
+		"stock":    {"stock < 2", "stock < 11", "stock < 101", "stock < 501"},
+		"category": {`category == "a"`, `category == "a"`, `category in ["a", "b"]`, `category in ["a", "b", "c"]`},
+		"created":  {"created < 1704067286", "created < 1704153600", "created < 1704931200"},
+	} {
+		selectivities := []interface{}{0.001, 0.01, 0.1, 0.5}
+		if field == "created" {
+			selectivities = []interface{}{0.0001, 0.1, 1}
+		}
+		sweep, err := m.SelectivitySweep(map[string]interface{}{"scalars": faker, "field": field, "selectivities": selectivities})
+		require.NoError(t, err, field)
+		var got []interface{}
+		for _, filter := range sweep.Filters() {
+			got = append(got, filter["filter"])
+		}
+		assert.Equal(t, want, got, field)
+	}
+
+	sweep, err = m.SelectivitySweep(map[string]interface{}{"scalars": faker, "field": "category", "selectivities": []interface{}{0.5}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"filter": `category in ["a", "b", "c"]`, "bucket": "50%", "selectivity": 0.5, "actual": 0.5}, sweep.Next())
+}
+
+func TestSelectivitySweepZipf(t *testing.T) {
+	faker := selectivityFaker(t, map[string]interface{}{
+		"tenant": map[string]interface{}{"type": "int32", "min": 0, "max": 999, "distribution": "zipf"},
+	})
+	sweep, err := (&Milvus{shared: &sync.Map{}}).SelectivitySweep(map[string]interface{}{"scalars": faker, "field": "tenant", "selectivities": []interface{}{0.5}})
+	require.NoError(t, err)
+	filter := sweep.Next()
+	assert.InDelta(t, 0.5, filter["actual"], 0.01)
+	assert.NotEqual(t, "tenant < 500", filter["filter"], "hot values match more rows")
+}
+
+func TestSelectivitySweepSearch(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	service := &queryLogServer{}
+	client := benchClient(t, service, vu)
+
+	faker := selectivityFaker(t, map[string]interface{}{"price": map[string]interface{}{"type": "double", "min": 0, "max": 100}})
+	sweep, err := (&Milvus{shared: &sync.Map{}}).SelectivitySweep(map[string]interface{}{"scalars": faker, "field": "price", "selectivities": []interface{}{0.01, 0.5}})
+	require.NoError(t, err)
+
+	params := map[string]interface{}{"vectorField": "embedding", "filter": "stock > 0"}
+	for _, bucket := range []string{"1%", "50%", "1%"} {
+		result := sweep.Search(client, [][]float32{{1, 0}}, 2, params).(map[string]interface{})
+		require.Equal(t, true, result["success"], result["error"])
+		assert.Equal(t, bucket, result["selectivity"])
+	}
+	assert.Equal(t, []string{"(stock > 0) and price < 1", "(stock > 0) and price < 50", "(stock > 0) and price < 1"}, service.filters)
+	assert.Equal(t, "stock > 0", params["filter"], "params are not modified")
+
+	buckets := map[string]bool{}
+	for _, sample := range drainSamples(samples) {
+		if bucket, ok := sample.Tags.Get("selectivity"); ok {
+			buckets[bucket] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"1%": true, "50%": true}, buckets)
+	assert.Equal(t, false, sweep.Search(nil, nil, 1, nil).(map[string]interface{})["success"])
+}
+
+func TestSelectivitySweepConfig(t *testing.T) {
+	faker := selectivityFaker(t, map[string]interface{}{
+		"price": map[string]interface{}{"type": "double"},
+		"name":  map[string]interface{}{"type": "varchar"},
+		"at":    map[string]interface{}{"type": "timestamp", "unit": "rfc3339"},
+	})
+	m := &Milvus{shared: &sync.Map{}}
+	for _, config := range []map[string]interface{}{
+		{"field": "price"},
+		{"scalars": faker, "field": "missing"},
+		{"scalars": faker, "field": "price", "selectivities": []interface{}{0}},
+		{"scalars": faker, "field": "price", "selectivities": []interface{}{1.5}},
+		{"scalars": faker, "field": "name"},
+		{"scalars": faker, "field": "at"},
+	} {
+		_, err := m.SelectivitySweep(config)
+		assert.Error(t, err, config)
+	}
+}
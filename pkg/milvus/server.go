@@ -0,0 +1,175 @@
+package milvus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+)
+
+// CheckHealth reports whether the cluster is healthy.
+// The result contains "healthy", the "reasons" reported for an unhealthy cluster and
+// the active "quota_states" (e.g. DenyToWrite when the cluster is rate-limiting writes).
+func (c *Client) CheckHealth() interface{} {
+	start := time.Now()
+
+	service := c.milvus().GetService()
+	if service == nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        "failed to check health: client is not connected",
+		})
+	}
+
+	resp, err := service.CheckHealth(c.context(), &milvuspb.CheckHealthRequest{})
+	if err = merr.CheckRPCCall(resp, err); err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to check health: %v", err),
+			Cause:        err,
+		})
+	}
+
+	quotaStates := make([]string, 0, len(resp.GetQuotaStates()))
+	for _, state := range resp.GetQuotaStates() {
+		quotaStates = append(quotaStates, state.String())
+	}
+	reasons := resp.GetReasons()
+	if reasons == nil {
+		reasons = []string{}
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result: map[string]interface{}{
+			"healthy":      resp.GetIsHealthy(),
+			"reasons":      reasons,
+			"quota_states": quotaStates,
+		},
+	})
+}
+
+// GetServerVersion returns the Milvus server version (e.g. "v2.6.0").
+// The version is cached on the client for ServerVersionAtLeast.
+func (c *Client) GetServerVersion() interface{} {
+	start := time.Now()
+
+	version, err := c.serverVersion()
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to get server version: %v", err),
+			Cause:        err,
+		})
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"version": version},
+	})
+}
+
+// ServerVersionAtLeast reports whether the server version is at least minVersion (e.g. "2.5.0"),
+// so scripts can skip features the cluster does not support.
+func (c *Client) ServerVersionAtLeast(minVersion string) interface{} {
+	start := time.Now()
+
+	version, err := c.serverVersion()
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to get server version: %v", err),
+			Cause:        err,
+		})
+	}
+
+	cmp, err := compareVersions(version, minVersion)
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err.Error(),
+		})
+	}
+
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       cmp >= 0,
+	})
+}
+
+// validate checks the connection with a GetServerVersion call, so a wrong address,
+// credentials or database fail at client creation instead of on the first operation
+func (c *Client) validate() error {
+	if _, err := c.serverVersion(); err != nil {
+		return fmt.Errorf("connection validation failed for %s: %v", c.config.Address, err)
+	}
+	return nil
+}
+
+// serverVersion returns the cached server version, fetching it on first use
+func (c *Client) serverVersion() (string, error) {
+	if c.version != "" {
+		return c.version, nil
+	}
+	version, err := c.milvus().GetServerVersion(c.context(), milvusclient.NewGetServerVersionOption())
+	if err != nil {
+		return "", err
+	}
+	c.version = version
+	return version, nil
+}
+
+// compareVersions compares two "vX.Y.Z" versions by their numeric parts.
+// Missing parts count as zero; pre-release and build suffixes ("-beta", "+dev") are ignored.
+func compareVersions(a, b string) (int, error) {
+	partsA, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	partsB, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range partsA {
+		if partsA[i] != partsB[i] {
+			if partsA[i] < partsB[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// parseVersion extracts the major, minor and patch numbers from a version string
+func parseVersion(version string) ([3]int, error) {
+	var parts [3]int
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+	fields := strings.Split(trimmed, ".")
+	if trimmed == "" || len(fields) > 3 {
+		return parts, fmt.Errorf("invalid version %q", version)
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, fmt.Errorf("invalid version %q", version)
+		}
+		parts[i] = n
+	}
+	return parts, nil
+}
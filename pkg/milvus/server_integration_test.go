@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package milvus
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerInfo_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	milvusHost := os.Getenv("MILVUS_HOST")
+	if milvusHost == "" {
+		milvusHost = "localhost:19530"
+	}
+
+	milvusModule := &Milvus{
+		vu: &mockVU{ctx: context.Background()},
+	}
+
+	client, err := milvusModule.Client(milvusHost)
+	require.NoError(t, err)
+	defer client.Close()
+
+	t.Run("check_health", func(t *testing.T) {
+		resultMap := client.CheckHealth().(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+		result := resultMap["result"].(map[string]interface{})
+		assert.Equal(t, true, result["healthy"], result["reasons"])
+	})
+
+	t.Run("get_server_version", func(t *testing.T) {
+		resultMap := client.GetServerVersion().(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+		result := resultMap["result"].(map[string]interface{})
+		assert.NotEmpty(t, result["version"])
+	})
+
+	t.Run("server_version_at_least", func(t *testing.T) {
+		resultMap := client.ServerVersionAtLeast("2.0.0").(map[string]interface{})
+		require.Equal(t, true, resultMap["success"], resultMap["error"])
+		assert.Equal(t, true, resultMap["result"])
+	})
+}
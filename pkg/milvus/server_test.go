@@ -0,0 +1,52 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "v2.6.0", b: "2.5.0", want: 1},
+		{a: "v2.5.4", b: "2.5.4", want: 0},
+		{a: "2.4.15", b: "2.5", want: -1},
+		{a: "v2.6.0-beta", b: "2.6.0", want: 0},
+		{a: "v3.0.0+dev", b: "v2.6.9", want: 1},
+		{a: "2.10.0", b: "2.9.0", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, err := compareVersions(tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompareVersionsInvalid(t *testing.T) {
+	for _, version := range []string{"", "latest", "2.x.0", "1.2.3.4"} {
+		_, err := compareVersions("2.5.0", version)
+		assert.Error(t, err, version)
+	}
+}
+
+func TestServerVersionAtLeastCached(t *testing.T) {
+	client := &Client{version: "v2.5.4"}
+
+	result := client.ServerVersionAtLeast("2.5.0").(map[string]interface{})
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, true, result["result"])
+
+	result = client.ServerVersionAtLeast("2.6").(map[string]interface{})
+	assert.Equal(t, false, result["result"])
+
+	result = client.ServerVersionAtLeast("next").(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "invalid version")
+}
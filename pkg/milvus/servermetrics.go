@@ -0,0 +1,188 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+)
+
+// DefaultServerMetrics are collected when collectServerMetrics() selects no metrics:
+// query node task queue length, loaded segment count and process memory
+var DefaultServerMetrics = []string{
+	"milvus_querynode_read_task_unsolved_len",
+	"milvus_querynode_segment_num",
+	"process_resident_memory_bytes",
+}
+
+// DefaultServerMetricsInterval is the scrape interval when collectServerMetrics() sets none
+const DefaultServerMetricsInterval = 10 * time.Second
+
+// ServerMetricsConfig is the config object accepted by collectServerMetrics()
+type ServerMetricsConfig struct {
+	URL      string   `json:"url"`                // Prometheus endpoint of a Milvus component, e.g. http://milvus:9091/metrics
+	Interval string   `json:"interval,omitempty"` // Scrape interval, e.g. "5s"
+	Metrics  []string `json:"metrics,omitempty"`  // Prometheus metric names to emit, DefaultServerMetrics when empty
+}
+
+// serverMetricsCollector scrapes a Milvus metrics endpoint on an interval
+type serverMetricsCollector struct {
+	url      string
+	interval time.Duration
+	metrics  []string
+	client   *http.Client
+}
+
+// CollectServerMetrics starts a background collector that scrapes the Prometheus metrics of a
+// Milvus component and emits the selected series as the milvus_server_metric Gauge, tagged with
+// the series name (metric) and its labels. One collector runs per URL across all VUs; it stops
+// when the VU that started it finishes, and a later call from another VU starts it again.
+//
+// Usage in k6:
+//
+//	export default function() {
+//	    milvus.collectServerMetrics({ url: 'http://localhost:9091/metrics', interval: '5s' });
+//	    ...
+//	}
+func (m *Milvus) CollectServerMetrics(configInput interface{}) error {
+	collector, err := newServerMetricsCollector(configInput)
+	if err != nil {
+		return err
+	}
+	if m.vu == nil || m.vu.State() == nil {
+		return fmt.Errorf("collectServerMetrics() must be called from a VU, not the init context")
+	}
+	if m.collectors == nil || m.metrics == nil {
+		return nil // Nothing to emit when metrics are disabled
+	}
+	if _, running := m.collectors.LoadOrStore(collector.url, struct{}{}); running {
+		return nil
+	}
+
+	go func() {
+		defer m.collectors.Delete(collector.url)
+		collector.run(vuContext(m.vu), m.emitServerMetric)
+	}()
+	return nil
+}
+
+// newServerMetricsCollector validates the collectServerMetrics() config
+func newServerMetricsCollector(configInput interface{}) (*serverMetricsCollector, error) {
+	var config ServerMetricsConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid server metrics config: %v", err)
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("server metrics url is required")
+	}
+
+	interval := DefaultServerMetricsInterval
+	if config.Interval != "" {
+		parsed, err := time.ParseDuration(config.Interval)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid server metrics interval %q", config.Interval)
+		}
+		interval = parsed
+	}
+	names := config.Metrics
+	if len(names) == 0 {
+		names = DefaultServerMetrics
+	}
+
+	return &serverMetricsCollector{
+		url:      config.URL,
+		interval: interval,
+		metrics:  names,
+		client:   &http.Client{Timeout: interval},
+	}, nil
+}
+
+// run scrapes immediately, then on every interval until ctx is done
+func (s *serverMetricsCollector) run(ctx context.Context, emit func(value float64, tags map[string]string, err error)) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		err := s.scrape(ctx, emit)
+		if err != nil && ctx.Err() == nil {
+			emit(0, nil, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scrape fetches the endpoint once and emits the selected series
+func (s *serverMetricsCollector) scrape(ctx context.Context, emit func(value float64, tags map[string]string, err error)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", s.url, resp.Status)
+	}
+
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to parse metrics from %s: %v", s.url, err)
+	}
+	for _, name := range s.metrics {
+		family, ok := families[name]
+		if !ok {
+			continue
+		}
+		for _, series := range family.GetMetric() {
+			value, isGauge := seriesValue(series)
+			if !isGauge {
+				continue
+			}
+			tags := map[string]string{"metric": name}
+			for _, label := range series.GetLabel() {
+				if label.GetName() != "metric" {
+					tags[label.GetName()] = label.GetValue()
+				}
+			}
+			emit(value, tags, nil)
+		}
+	}
+	return nil
+}
+
+// seriesValue returns the value of a gauge, counter or untyped series.
+// Histograms and summaries are skipped.
+func seriesValue(series *dto.Metric) (float64, bool) {
+	switch {
+	case series.Gauge != nil:
+		return series.GetGauge().GetValue(), true
+	case series.Counter != nil:
+		return series.GetCounter().GetValue(), true
+	case series.Untyped != nil:
+		return series.GetUntyped().GetValue(), true
+	}
+	return 0, false
+}
+
+// emitServerMetric pushes a scraped series on this VU, or logs a failed scrape
+func (m *Milvus) emitServerMetric(value float64, tags map[string]string, err error) {
+	if err != nil {
+		if state := m.vu.State(); state != nil && state.Logger != nil {
+			state.Logger.WithError(err).Warn("Failed to collect Milvus server metrics")
+		}
+		return
+	}
+	if m.metrics != nil {
+		pushMetric(m.vu, m.metrics.ServerMetric, value, tags)
+	}
+}
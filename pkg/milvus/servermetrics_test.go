@@ -0,0 +1,120 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serverMetricsText = `# TYPE milvus_querynode_read_task_unsolved_len gauge
+milvus_querynode_read_task_unsolved_len{node_id="7"} 12
+# TYPE milvus_querynode_segment_num gauge
+milvus_querynode_segment_num{node_id="7",segment_state="Sealed"} 40
+milvus_querynode_segment_num{node_id="7",segment_state="Growing"} 2
+# TYPE milvus_proxy_req_latency histogram
+milvus_proxy_req_latency_bucket{le="+Inf"} 5
+milvus_proxy_req_latency_sum 1.5
+milvus_proxy_req_latency_count 5
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes 1.048576e+08
+`
+
+func TestNewServerMetricsCollector(t *testing.T) {
+	collector, err := newServerMetricsCollector(map[string]interface{}{"url": "http://milvus:9091/metrics"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultServerMetricsInterval, collector.interval)
+	assert.Equal(t, DefaultServerMetrics, collector.metrics)
+
+	collector, err = newServerMetricsCollector(map[string]interface{}{
+		"url":      "http://milvus:9091/metrics",
+		"interval": "2s",
+		"metrics":  []interface{}{"milvus_proxy_req_count"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, collector.interval)
+	assert.Equal(t, []string{"milvus_proxy_req_count"}, collector.metrics)
+
+	_, err = newServerMetricsCollector(map[string]interface{}{})
+	assert.Error(t, err)
+	_, err = newServerMetricsCollector(map[string]interface{}{"url": "http://milvus:9091/metrics", "interval": "-1s"})
+	assert.Error(t, err)
+}
+
+func TestServerMetricsScrape(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, serverMetricsText)
+	}))
+	defer server.Close()
+
+	collector, err := newServerMetricsCollector(map[string]interface{}{
+		"url":     server.URL,
+		"metrics": append([]interface{}{"milvus_proxy_req_latency", "missing_metric"}, toInterfaces(DefaultServerMetrics)...),
+	})
+	require.NoError(t, err)
+
+	var got []string
+	err = collector.scrape(context.Background(), func(value float64, tags map[string]string, emitErr error) {
+		require.NoError(t, emitErr)
+		got = append(got, fmt.Sprintf("%s{%s}=%v", tags["metric"], tags["segment_state"], value))
+	})
+	require.NoError(t, err)
+	sort.Strings(got)
+	assert.Equal(t, []string{
+		"milvus_querynode_read_task_unsolved_len{}=12",
+		"milvus_querynode_segment_num{Growing}=2",
+		"milvus_querynode_segment_num{Sealed}=40",
+		"process_resident_memory_bytes{}=1.048576e+08",
+	}, got)
+}
+
+func TestServerMetricsRunReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	collector, err := newServerMetricsCollector(map[string]interface{}{"url": server.URL, "interval": "10ms"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 10)
+	done := make(chan struct{})
+	go func() {
+		collector.run(ctx, func(_ float64, _ map[string]string, emitErr error) {
+			select {
+			case errs <- emitErr:
+			default:
+			}
+		})
+		close(done)
+	}()
+
+	assert.ErrorContains(t, <-errs, "503")
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("collector did not stop when its context was canceled")
+	}
+}
+
+func TestCollectServerMetricsInitContext(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{}}
+	err := m.CollectServerMetrics(map[string]interface{}{"url": "http://milvus:9091/metrics"})
+	assert.ErrorContains(t, err, "init context")
+}
+
+func toInterfaces(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package milvus
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib"
+)
+
+// Dataset sharding modes
+const (
+	shardVU       = "vu"       // Each VU reads a fixed range of rows
+	shardScenario = "scenario" // The VUs of a scenario claim batches from a shared cursor
+)
+
+// datasetShard splits the rows of a dataset iterator between the VUs that read it, so
+// concurrent VUs ingest disjoint rows without coordinating offsets in JavaScript
+type datasetShard struct {
+	vu     modules.VU
+	shared *sync.Map
+	mode   string
+	shards int
+	key    string        // Dataset and row range, keying the scenario cursor
+	cursor *atomic.Int64 // Rows claimed in the scenario, from the start of the range
+}
+
+func newDatasetShard(vu modules.VU, shared *sync.Map, mode string, shards int, key string) (datasetShard, error) {
+	switch mode {
+	case "", shardVU, shardScenario:
+	default:
+		return datasetShard{}, fmt.Errorf("shard must be %q or %q, got %q", shardVU, shardScenario, mode)
+	}
+	if shards < 0 {
+		return datasetShard{}, fmt.Errorf("shards must not be negative, got %d", shards)
+	}
+	if shards > 0 && mode != shardVU {
+		return datasetShard{}, fmt.Errorf("shards requires shard %q", shardVU)
+	}
+	return datasetShard{vu: vu, shared: shared, mode: mode, shards: shards, key: key}, nil
+}
+
+// vuRange returns the part of the rows [start, end) of the VU: with shard "vu", part VU ID - 1
+// of shards equal parts, by default one per initialized VU. Other modes read all the rows.
+func (d *datasetShard) vuRange(start, end int64) (int64, int64) {
+	if d.mode != shardVU {
+		return start, end
+	}
+	var vuID int64 = 1
+	shards := int64(d.shards)
+	if d.vu != nil {
+		if state := d.vu.State(); state != nil && state.VUID > 0 {
+			vuID = int64(state.VUID)
+		}
+		if ctx := d.vu.Context(); shards == 0 && ctx != nil {
+			if es := lib.GetExecutionState(ctx); es != nil {
+				shards = es.GetInitializedVUsCount()
+			}
+		}
+	}
+	if shards <= 0 {
+		shards = 1
+	}
+	if vuID > shards {
+		return end, end
+	}
+	n := end - start
+	return start + n*(vuID-1)/shards, start + n*vuID/shards
+}
+
+// prepare looks up the cursor of the scenario the VU runs, with shard "scenario". It must be
+// called on the VU goroutine, outside the init context, before claim().
+func (d *datasetShard) prepare() {
+	if d.mode != shardScenario || d.cursor != nil {
+		return
+	}
+	var scenario string
+	if d.vu != nil && d.vu.Context() != nil {
+		if state := lib.GetScenarioState(d.vu.Context()); state != nil {
+			scenario = state.Name
+		}
+	}
+	d.cursor, _ = sharedValue(d.shared, "cursor\x00"+scenario+"\x00"+d.key, func() (*atomic.Int64, error) {
+		return &atomic.Int64{}, nil
+	})
+}
+
+// claim returns the next count rows of [start, end) that no other VU of the scenario has read,
+// or an empty range once all are claimed. It is safe to call from any goroutine.
+func (d *datasetShard) claim(start, end, count int64) (int64, int64) {
+	first := min(start+d.cursor.Add(count)-count, end)
+	return first, min(first+count, end)
+}
+
+// shardKey identifies the rows of a dataset shared by a scenario
+func shardKey(path string, start, end int64) string {
+	return filepath.Clean(path) + "\x00" + strconv.FormatInt(start, 10) + "\x00" + strconv.FormatInt(end, 10)
+}
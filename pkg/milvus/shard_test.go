@@ -0,0 +1,110 @@
+package milvus
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+)
+
+func TestDatasetShardVURange(t *testing.T) {
+	var ranges [][2]int64
+	for vuID := uint64(1); vuID <= 4; vuID++ {
+		shard, err := newDatasetShard(&metricsVU{state: &lib.State{VUID: vuID}}, nil, shardVU, 3, "")
+		require.NoError(t, err)
+		start, end := shard.vuRange(10, 20)
+		ranges = append(ranges, [2]int64{start, end})
+	}
+	// VUs beyond the shard count read nothing
+	assert.Equal(t, [][2]int64{{10, 13}, {13, 16}, {16, 20}, {20, 20}}, ranges)
+
+	for _, config := range []struct {
+		mode   string
+		shards int
+	}{{"tenant", 0}, {shardVU, -1}, {shardScenario, 2}} {
+		_, err := newDatasetShard(nil, nil, config.mode, config.shards, "")
+		assert.Error(t, err, config)
+	}
+}
+
+func TestVectorStreamShard(t *testing.T) {
+	rows := make([][]float32, 10)
+	for i := range rows {
+		rows[i] = []float32{float32(i)}
+	}
+	path := writeFvecs(t, rows)
+
+	stream, err := (&Milvus{vu: &metricsVU{state: &lib.State{VUID: 2}}}).VectorStream(path, map[string]interface{}{"shard": "vu", "shards": 2})
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+	batch, err := stream.NextBatch(100)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 6, 7, 8, 9}, batch["ids"])
+
+	// VUs of a scenario claim disjoint chunks until all rows are read
+	shared := &sync.Map{}
+	var ids []int64
+	var streams []*VectorStream
+	for vuID := uint64(1); vuID <= 3; vuID++ {
+		m := &Milvus{vu: &metricsVU{state: &lib.State{VUID: vuID}}, shared: shared}
+		stream, err := m.VectorStream(path, map[string]interface{}{"shard": "scenario", "readAhead": 2, "offset": 1})
+		require.NoError(t, err)
+		defer func() { _ = stream.Close() }()
+		streams = append(streams, stream)
+	}
+	for done := 0; done < len(streams); {
+		done = 0
+		for _, stream := range streams {
+			batch, err := stream.NextBatch(3)
+			require.NoError(t, err)
+			if batch == nil {
+				done++
+				continue
+			}
+			ids = append(ids, batch["ids"].([]int64)...)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	assert.Equal(t, rowIndices(1, 10), ids)
+	assert.ErrorContains(t, streams[0].Reset(), "cannot be reset")
+}
+
+func TestParquetReaderShard(t *testing.T) {
+	path := writeParquet(t, 7)
+	config := map[string]interface{}{"batchSize": 2, "fields": map[string]interface{}{"id": "id"}, "shard": "scenario"}
+	shared := &sync.Map{}
+	first, err := (&Milvus{shared: shared}).ParquetReader(path, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = first.Close() })
+	second, err := (&Milvus{shared: shared}).ParquetReader(path, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = second.Close() })
+
+	var ids []int64
+	for _, reader := range []*ParquetReader{first, second, second, first, first, second} {
+		batch, err := reader.Next()
+		require.NoError(t, err)
+		if batch != nil {
+			ids = append(ids, batch["id"].([]int64)...)
+		}
+	}
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6}, ids)
+	assert.ErrorContains(t, first.Reset(), "cannot be reset")
+
+	// With shard "vu", reset rewinds to the first row of the VU
+	reader, err := (&Milvus{vu: &metricsVU{state: &lib.State{VUID: 3}}}).ParquetReader(path, map[string]interface{}{
+		"fields": map[string]interface{}{"id": "id"}, "shard": "vu", "shards": 3,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reader.Close() })
+	batch, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 5, 6}, batch["id"])
+	require.NoError(t, reader.Reset())
+	batch, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 5, 6}, batch["id"])
+}
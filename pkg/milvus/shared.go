@@ -0,0 +1,111 @@
+package milvus
+
+import (
+	"fmt"
+
+	"github.com/grafana/sobek"
+)
+
+// SharedVectors is a read-only set of float vectors loaded once per test process and shared by
+// all VUs, like k6's SharedArray but without copying: vectors are stored once as float32, and
+// VUs borrow row slices of the same memory instead of holding their own JavaScript arrays.
+//
+// Usage in k6:
+//
+//	const base = milvus.sharedVectors('base', 'data/sift_base.fvecs');
+//	export default function () {
+//	    const offset = exec.scenario.iterationInTest * 1000;
+//	    client.insert({ id: base.ids(offset, 1000), embedding: base.vectors(offset, 1000) });
+//	}
+type SharedVectors struct {
+	vectors [][]float32
+}
+
+// SharedVectors returns the vectors stored under name, loading them on the first call of the
+// test. Source is a path to an .fvecs, .bvecs or .npy file, or a function returning an array of
+// vectors; it is only read or called once, so later VUs do not repeat the work.
+func (m *Milvus) SharedVectors(name string, source sobek.Value) (*SharedVectors, error) {
+	if name == "" {
+		return nil, fmt.Errorf("shared vectors name must not be empty")
+	}
+	return sharedValue(m.shared, "vectors\x00"+name, func() (*SharedVectors, error) {
+		if fn, ok := sobek.AssertFunction(source); ok {
+			value, err := fn(sobek.Undefined())
+			if err != nil {
+				return nil, fmt.Errorf("shared vectors %s: %v", name, err)
+			}
+			vectors, err := denseVectors(value.Export())
+			if err != nil {
+				return nil, fmt.Errorf("shared vectors %s: %v", name, err)
+			}
+			// Copy the rows into one block, dropping the per-row allocations of the conversion
+			packed := newMatrix[float32](len(vectors), len(vectors[0]))
+			for i, vector := range vectors {
+				copy(packed[i], vector)
+			}
+			return &SharedVectors{vectors: packed}, nil
+		}
+		if source == nil || sobek.IsUndefined(source) || sobek.IsNull(source) {
+			return nil, fmt.Errorf("shared vectors %s: source must be a file path or a function", name)
+		}
+		path, err := localDataset(m.shared, source.String())
+		if err != nil {
+			return nil, fmt.Errorf("shared vectors %s: %v", name, err)
+		}
+		vectors, err := loadVectorFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("shared vectors %s: %v", name, err)
+		}
+		return &SharedVectors{vectors: vectors}, nil
+	})
+}
+
+// loadVectorFile reads all the vectors of an .fvecs, .bvecs or .npy file
+func loadVectorFile(path string) ([][]float32, error) {
+	f, err := openVectorFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.close() }()
+	vectors := newMatrix[float32](int(f.rows), f.dim)
+	for _, vector := range vectors {
+		if err := f.read(vector); err != nil {
+			return nil, err
+		}
+	}
+	return vectors, nil
+}
+
+// Size returns the number of vectors
+func (s *SharedVectors) Size() int {
+	return len(s.vectors)
+}
+
+// memorySize returns the bytes of the vectors
+func (s *SharedVectors) memorySize() int64 {
+	return matrixBytes(s.vectors)
+}
+
+// Dimension returns the vector dimension
+func (s *SharedVectors) Dimension() int {
+	return len(firstRow(s.vectors))
+}
+
+// Vectors returns count vectors from offset. The rows are borrowed from the shared store and
+// must not be modified.
+func (s *SharedVectors) Vectors(offset, count int) ([][]float32, error) {
+	start, end, err := rowRange(len(s.vectors), offset, count)
+	if err != nil {
+		return nil, err
+	}
+	return s.vectors[start:end], nil
+}
+
+// IDs returns the row indices of count vectors from offset, to insert them as primary keys
+func (s *SharedVectors) IDs(offset, count int) ([]int64, error) {
+	start, end, err := rowRange(len(s.vectors), offset, count)
+	if err != nil {
+		return nil, err
+	}
+	return rowIndices(start, end), nil
+}
@@ -0,0 +1,114 @@
+package milvus
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFvecs writes float32 rows, each preceded by its length
+func writeFvecs(t *testing.T, rows [][]float32) string {
+	path := filepath.Join(t.TempDir(), "base.fvecs")
+	var data []byte
+	for _, row := range rows {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(row)))
+		for _, v := range row {
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+		}
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestSharedVectorsFiles(t *testing.T) {
+	rt := sobek.New()
+	m := &Milvus{}
+
+	vectors, err := m.SharedVectors("base", rt.ToValue(writeFvecs(t, [][]float32{{1, 2}, {3, 4}, {5, 6}})))
+	require.NoError(t, err)
+	assert.Equal(t, 3, vectors.Size())
+	assert.Equal(t, 2, vectors.Dimension())
+	batch, err := vectors.Vectors(1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{3, 4}, {5, 6}}, batch)
+	ids, err := vectors.IDs(1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids)
+
+	bvecs := filepath.Join(t.TempDir(), "base.bvecs")
+	require.NoError(t, os.WriteFile(bvecs, []byte{2, 0, 0, 0, 7, 255}, 0o600))
+	vectors, err = m.SharedVectors("bytes", rt.ToValue(bvecs))
+	require.NoError(t, err)
+	batch, err = vectors.Vectors(0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{7, 255}}, batch)
+
+	npy := filepath.Join(t.TempDir(), "base.npy")
+	header := "{'descr': '<f8', 'fortran_order': False, 'shape': (1, 2), }\n"
+	data := append([]byte("\x93NUMPY\x01\x00"), byte(len(header)), 0)
+	data = append(data, header...)
+	data = binary.LittleEndian.AppendUint64(data, math.Float64bits(0.5))
+	data = binary.LittleEndian.AppendUint64(data, math.Float64bits(-1))
+	require.NoError(t, os.WriteFile(npy, data, 0o600))
+	vectors, err = m.SharedVectors("npy", rt.ToValue(npy))
+	require.NoError(t, err)
+	batch, err = vectors.Vectors(0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0.5, -1}}, batch)
+}
+
+func TestSharedVectorsLoadedOnce(t *testing.T) {
+	rt := sobek.New()
+	require.NoError(t, rt.Set("calls", 0))
+	source, err := rt.RunString("(() => { calls++; return [[1, 2], [3, 4]]; })")
+	require.NoError(t, err)
+
+	m := &Milvus{shared: &sync.Map{}}
+	first, err := m.SharedVectors("queries", source)
+	require.NoError(t, err)
+	second, err := m.SharedVectors("queries", source)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, int64(1), rt.Get("calls").ToInteger())
+
+	// Borrowed rows share the memory of the store
+	a, err := first.Vectors(0, 2)
+	require.NoError(t, err)
+	b, err := second.Vectors(1, 1)
+	require.NoError(t, err)
+	assert.Same(t, &a[1][0], &b[0][0])
+}
+
+func TestSharedVectorsErrors(t *testing.T) {
+	rt := sobek.New()
+	m := &Milvus{}
+	dir := t.TempDir()
+
+	_, err := m.SharedVectors("", rt.ToValue("base.fvecs"))
+	assert.ErrorContains(t, err, "name must not be empty")
+	_, err = m.SharedVectors("base", sobek.Undefined())
+	assert.ErrorContains(t, err, "source must be a file path or a function")
+	_, err = m.SharedVectors("base", rt.ToValue(filepath.Join(dir, "base.csv")))
+	assert.ErrorContains(t, err, "must be .fvecs, .bvecs or .npy")
+
+	ragged := filepath.Join(dir, "ragged.fvecs")
+	require.NoError(t, os.WriteFile(ragged, []byte{1, 0, 0, 0, 0, 0, 128, 63, 2, 0, 0, 0, 0, 0, 128, 63}, 0o600))
+	_, err = m.SharedVectors("ragged", rt.ToValue(ragged))
+	assert.ErrorContains(t, err, "row 1 has dimension 2, expected 1")
+
+	throws, err := rt.RunString("(() => { throw new Error('boom'); })")
+	require.NoError(t, err)
+	_, err = m.SharedVectors("throws", throws)
+	assert.ErrorContains(t, err, "boom")
+	empty, err := rt.RunString("(() => [])")
+	require.NoError(t, err)
+	_, err = m.SharedVectors("empty", empty)
+	assert.ErrorContains(t, err, "no vectors")
+}
@@ -0,0 +1,29 @@
+package milvus
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logSlow logs an operation that took at least the client's slowQueryThreshold through the
+// k6 logger, with the details needed to investigate it. Durations are measured from start,
+// like response_time_ms.
+func (c *Client) logSlow(operation string, start time.Time, fields logrus.Fields) {
+	if c.slowQuery <= 0 || c.vu == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	if elapsed < c.slowQuery {
+		return
+	}
+	state := c.vu.State()
+	if state == nil || state.Logger == nil {
+		return
+	}
+
+	fields["operation"] = operation
+	fields["duration"] = elapsed.String()
+	fields["threshold"] = c.slowQuery.String()
+	state.Logger.WithFields(fields).Warn("Slow Milvus operation")
+}
@@ -0,0 +1,52 @@
+package milvus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+)
+
+func TestLogSlow(t *testing.T) {
+	logger, hook := logtest.NewNullLogger()
+	vu := &metricsVU{state: &lib.State{Logger: logger}}
+	client := &Client{vu: vu, slowQuery: 50 * time.Millisecond}
+
+	client.logSlow("search", time.Now(), logrus.Fields{"collection": "docs"})
+	assert.Empty(t, hook.AllEntries())
+
+	client.logSlow("search", time.Now().Add(-time.Second), logrus.Fields{"collection": "docs", "expr": "price > 10", "nq": 5, "topK": 10})
+	require.Len(t, hook.AllEntries(), 1)
+	entry := hook.LastEntry()
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, "search", entry.Data["operation"])
+	assert.Equal(t, "docs", entry.Data["collection"])
+	assert.Equal(t, "price > 10", entry.Data["expr"])
+	assert.Equal(t, 5, entry.Data["nq"])
+	assert.Equal(t, "50ms", entry.Data["threshold"])
+
+	// Disabled by default
+	hook.Reset()
+	(&Client{vu: vu}).logSlow("query", time.Now().Add(-time.Hour), logrus.Fields{})
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestSlowQueryThreshold(t *testing.T) {
+	config := DefaultClientConfig()
+	threshold, err := config.slowQueryThreshold()
+	require.NoError(t, err)
+	assert.Zero(t, threshold)
+
+	WithSlowQueryThreshold(250 * time.Millisecond)(config)
+	threshold, err = config.slowQueryThreshold()
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, threshold)
+
+	config.SlowQueryThreshold = "fast"
+	_, err = config.slowQueryThreshold()
+	assert.Error(t, err)
+}
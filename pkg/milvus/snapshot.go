@@ -37,12 +37,13 @@ func (c *Client) CreateSnapshot(name string, collectionName interface{}, options
 		}
 	}
 
-	err := c.client.CreateSnapshot(c.context(), opt)
+	err := c.milvus().CreateSnapshot(c.context(), opt)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to create snapshot: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -85,12 +86,13 @@ func (c *Client) DropSnapshot(name string, options ...map[string]interface{}) in
 		opt = opt.WithDbName(dbName)
 	}
 
-	err := c.client.DropSnapshot(c.context(), opt)
+	err := c.milvus().DropSnapshot(c.context(), opt)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to drop snapshot: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -129,12 +131,13 @@ func (c *Client) ListSnapshots(options ...map[string]interface{}) interface{} {
 		opt = opt.WithDbName(dbName)
 	}
 
-	snapshots, err := c.client.ListSnapshots(c.context(), opt)
+	snapshots, err := c.milvus().ListSnapshots(c.context(), opt)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to list snapshots: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -176,12 +179,13 @@ func (c *Client) DescribeSnapshot(name string, options ...map[string]interface{}
 		opt = opt.WithDbName(dbName)
 	}
 
-	resp, err := c.client.DescribeSnapshot(c.context(), opt)
+	resp, err := c.milvus().DescribeSnapshot(c.context(), opt)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to describe snapshot: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -247,12 +251,13 @@ func (c *Client) RestoreSnapshot(name string, collectionName string, options ...
 		opt = opt.WithTargetDbName(targetDbName)
 	}
 
-	jobID, err := c.client.RestoreSnapshot(c.context(), opt)
+	jobID, err := c.milvus().RestoreSnapshot(c.context(), opt)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to restore snapshot: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -271,12 +276,13 @@ func (c *Client) GetRestoreSnapshotState(jobID int64) interface{} {
 
 	opt := milvusclient.NewGetRestoreSnapshotStateOption(jobID)
 
-	info, err := c.client.GetRestoreSnapshotState(c.context(), opt)
+	info, err := c.milvus().GetRestoreSnapshotState(c.context(), opt)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to get restore snapshot state: %v", err),
+			Cause:        err,
 		})
 	}
 
@@ -312,12 +318,13 @@ func (c *Client) ListRestoreSnapshotJobs(options ...map[string]interface{}) inte
 		}
 	}
 
-	jobs, err := c.client.ListRestoreSnapshotJobs(c.context(), opt)
+	jobs, err := c.milvus().ListRestoreSnapshotJobs(c.context(), opt)
 	if err != nil {
 		return toMap(&OperationResult{
 			Success:      false,
 			ResponseTime: float64(time.Since(start).Milliseconds()),
 			Error:        fmt.Sprintf("failed to list restore snapshot jobs: %v", err),
+			Cause:        err,
 		})
 	}
 
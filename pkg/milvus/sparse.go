@@ -0,0 +1,222 @@
+package milvus
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+)
+
+// SparseReaderConfig configures milvus.sparseReader()
+type SparseReaderConfig struct {
+	BatchSize int    `json:"batchSize,omitempty"` // Rows per batch (default: 1000)
+	Field     string `json:"field,omitempty"`     // Batch field of the sparse vectors (default: "sparse")
+	IDField   string `json:"idField,omitempty"`   // Batch field of row IDs (default: no IDs)
+	LabelIDs  bool   `json:"labelIds,omitempty"`  // IDs are the integer labels leading each row instead of row indices
+	Loop      bool   `json:"loop,omitempty"`      // Starts over at the end of the file, e.g. for query vectors
+}
+
+// SparseReader reads sparse vectors from a libsvm file in insert-ready batches, to ingest
+// SPLADE and BM25 sparse retrieval datasets. Each line is one vector of index:value pairs,
+// optionally led by a label and an SVMlight qid:N pair, and followed by a # comment.
+//
+// Usage in k6:
+//
+//	const reader = milvus.sparseReader('data/splade_base.svm', { field: 'sparse', idField: 'id' });
+//	export default function () {
+//	    const batch = reader.next();
+//	    if (batch) client.insert(batch);
+//	}
+type SparseReader struct {
+	path   string
+	config SparseReaderConfig
+	file   *os.File
+	lines  *bufio.Reader
+	line   int   // Line of the last row read
+	index  int64 // Index of the next row, its ID without labelIds
+}
+
+// SparseReader opens a libsvm file of sparse vectors for batched inserts. Relative paths are
+// resolved against the working directory of the k6 process.
+func (m *Milvus) SparseReader(path string, configInput ...interface{}) (*SparseReader, error) {
+	var config SparseReaderConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid sparse reader config: %v", err)
+		}
+	}
+	path, err := localDataset(m.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	return openSparseReader(path, config)
+}
+
+func openSparseReader(path string, config SparseReaderConfig) (*SparseReader, error) {
+	if config.BatchSize < 0 {
+		return nil, fmt.Errorf("sparse reader batchSize must not be negative")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultLoaderBatchSize
+	}
+	if config.Field == "" {
+		config.Field = "sparse"
+	}
+	if config.IDField == config.Field {
+		return nil, fmt.Errorf("sparse reader idField and field must differ, both are %q", config.Field)
+	}
+	if config.LabelIDs && config.IDField == "" {
+		return nil, fmt.Errorf("sparse reader labelIds requires idField")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sparse vector file: %v", err)
+	}
+	return &SparseReader{path: path, config: config, file: file, lines: bufio.NewReaderSize(file, 1<<20)}, nil
+}
+
+// Next returns the next batch as { [field]: vectors, [idField]: ids }, or null once the file is
+// read. With loop, reading starts over at the end of the file instead. The vectors can be passed
+// to client.insert() and client.search() as they are.
+func (r *SparseReader) Next() (map[string]interface{}, error) {
+	if r.file == nil {
+		return nil, fmt.Errorf("sparse reader %s is closed", r.path)
+	}
+	vectors := make([]entity.SparseEmbedding, 0, r.config.BatchSize)
+	var ids []int64
+	restarted := false
+	for len(vectors) < r.config.BatchSize {
+		vector, id, err := r.readRow()
+		if errors.Is(err, io.EOF) {
+			// Start over once, so a file without rows does not loop forever
+			if !r.config.Loop || restarted {
+				break
+			}
+			if err := r.Reset(); err != nil {
+				return nil, err
+			}
+			restarted = true
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", r.path, r.line, err)
+		}
+		restarted = false
+		vectors = append(vectors, vector)
+		ids = append(ids, id)
+	}
+	if len(vectors) == 0 {
+		return nil, nil
+	}
+
+	batch := map[string]interface{}{r.config.Field: vectors}
+	if r.config.IDField != "" {
+		batch[r.config.IDField] = ids
+	}
+	return batch, nil
+}
+
+// readRow parses the next row, skipping blank and comment lines. The ID is the row index, or
+// the label with labelIds.
+func (r *SparseReader) readRow() (entity.SparseEmbedding, int64, error) {
+	for {
+		line, err := r.lines.ReadString('\n')
+		if line == "" && err != nil {
+			return nil, 0, err
+		}
+		r.line++
+		if comment := strings.IndexByte(line, '#'); comment >= 0 {
+			line = line[:comment]
+		}
+		tokens := strings.Fields(line)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		id := r.index
+		if !strings.Contains(tokens[0], ":") {
+			if r.config.LabelIDs {
+				if id, err = strconv.ParseInt(tokens[0], 10, 64); err != nil {
+					return nil, 0, fmt.Errorf("label %q is not an integer ID", tokens[0])
+				}
+			}
+			tokens = tokens[1:]
+		} else if r.config.LabelIDs {
+			return nil, 0, fmt.Errorf("missing label")
+		}
+		if len(tokens) > 0 && strings.HasPrefix(tokens[0], "qid:") {
+			tokens = tokens[1:]
+		}
+
+		vector, err := parseSparsePairs(tokens)
+		if err != nil {
+			return nil, 0, err
+		}
+		r.index++
+		return vector, id, nil
+	}
+}
+
+// parseSparsePairs parses index:value pairs into a sparse vector. Indices are kept as written.
+func parseSparsePairs(tokens []string) (entity.SparseEmbedding, error) {
+	positions := make([]uint32, len(tokens))
+	values := make([]float32, len(tokens))
+	seen := make(map[uint32]bool, len(tokens))
+	for i, token := range tokens {
+		indexText, valueText, ok := strings.Cut(token, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not an index:value pair", token)
+		}
+		index, err := strconv.ParseUint(indexText, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index in %q", token)
+		}
+		value, err := strconv.ParseFloat(valueText, 32)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("invalid value in %q", token)
+		}
+		if seen[uint32(index)] {
+			return nil, fmt.Errorf("duplicate index %d", index)
+		}
+		seen[uint32(index)] = true
+		positions[i], values[i] = uint32(index), float32(value)
+	}
+	return entity.NewSliceSparseEmbedding(positions, values)
+}
+
+// Reset starts over at the first row
+func (r *SparseReader) Reset() error {
+	if r.file == nil {
+		return fmt.Errorf("sparse reader %s is closed", r.path)
+	}
+	if _, err := r.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %v", r.path, err)
+	}
+	r.lines.Reset(r.file)
+	r.line, r.index = 0, 0
+	return nil
+}
+
+// Fields returns the field names of each batch
+func (r *SparseReader) Fields() []string {
+	if r.config.IDField == "" {
+		return []string{r.config.Field}
+	}
+	return []string{r.config.IDField, r.config.Field}
+}
+
+// Close closes the underlying file
+func (r *SparseReader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
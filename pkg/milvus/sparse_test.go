@@ -0,0 +1,123 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sparseOf(t *testing.T, positions []uint32, values []float32) entity.SparseEmbedding {
+	vector, err := entity.NewSliceSparseEmbedding(positions, values)
+	require.NoError(t, err)
+	return vector
+}
+
+func TestSparseReaderLibsvm(t *testing.T) {
+	path := writeLoaderFile(t, "base.svm", `# SPLADE document vectors
+3 7:0.5 2:1.25
+1 qid:4 10:2 # doc-b
+
+0:1e-3
+`)
+	reader, err := openSparseReader(path, SparseReaderConfig{BatchSize: 2, IDField: "id"})
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	assert.Equal(t, []string{"id", "sparse"}, reader.Fields())
+
+	batch, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"sparse": []entity.SparseEmbedding{
+			sparseOf(t, []uint32{2, 7}, []float32{1.25, 0.5}),
+			sparseOf(t, []uint32{10}, []float32{2}),
+		},
+		"id": []int64{0, 1},
+	}, batch)
+	batch, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []entity.SparseEmbedding{sparseOf(t, []uint32{0}, []float32{1e-3})}, batch["sparse"])
+	batch, err = reader.Next()
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+}
+
+func TestSparseReaderLabelIDs(t *testing.T) {
+	path := writeLoaderFile(t, "base.svm", "101 1:1\n205 2:1\n")
+	reader, err := openSparseReader(path, SparseReaderConfig{Field: "emb", IDField: "pk", LabelIDs: true})
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	batch, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{101, 205}, batch["pk"])
+	assert.Len(t, batch["emb"], 2)
+}
+
+func TestSparseReaderLoop(t *testing.T) {
+	reader, err := openSparseReader(writeLoaderFile(t, "queries.svm", "1:1\n2:1\n"), SparseReaderConfig{BatchSize: 3, Loop: true})
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	batch, err := reader.Next()
+	require.NoError(t, err)
+	vectors := batch["sparse"].([]entity.SparseEmbedding)
+	require.Len(t, vectors, 3)
+	assert.Equal(t, vectors[0], vectors[2])
+
+	empty, err := openSparseReader(writeLoaderFile(t, "empty.svm", "# nothing\n"), SparseReaderConfig{Loop: true})
+	require.NoError(t, err)
+	defer func() { _ = empty.Close() }()
+	batch, err = empty.Next()
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+}
+
+func TestSparseReaderErrors(t *testing.T) {
+	path := writeLoaderFile(t, "base.svm", "1:1\n")
+	for name, config := range map[string]SparseReaderConfig{
+		"batch size":  {BatchSize: -1},
+		"same fields": {IDField: "sparse"},
+		"label ids":   {LabelIDs: true},
+	} {
+		_, err := openSparseReader(path, config)
+		assert.Error(t, err, name)
+	}
+
+	for content, message := range map[string]string{
+		"1 x\n":          `"x" is not an index:value pair`,
+		"1 -1:2\n":       "invalid index",
+		"1 1:abc\n":      "invalid value",
+		"1 1:1 1:2\n":    "duplicate index 1",
+		"a 1:1\n":        `label "a" is not an integer ID`,
+		"1:1\n":          "line 1: missing label",
+		"1 1:1\n2 4:nan": "line 2: invalid value",
+	} {
+		reader, err := openSparseReader(writeLoaderFile(t, "bad.svm", content), SparseReaderConfig{IDField: "id", LabelIDs: true})
+		require.NoError(t, err)
+		_, err = reader.Next()
+		assert.ErrorContains(t, err, message)
+		_ = reader.Close()
+	}
+}
+
+func TestSparseEmbeddingsConversion(t *testing.T) {
+	vectors := []entity.SparseEmbedding{sparseOf(t, []uint32{1}, []float32{0.5})}
+	c := &Client{}
+
+	col, err := c.convertFieldToColumn("sparse", vectors)
+	require.NoError(t, err)
+	assert.IsType(t, &column.ColumnSparseFloatVector{}, col)
+	assert.Equal(t, 1, col.Len())
+	// Vectors picked into a JavaScript array
+	col, err = c.convertFieldToColumn("sparse", []interface{}{vectors[0]})
+	require.NoError(t, err)
+	assert.IsType(t, &column.ColumnSparseFloatVector{}, col)
+
+	search, err := convertToSearchVectors(vectors)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Vector{vectors[0]}, search)
+	search, err = convertToSearchVectors([]interface{}{vectors[0]})
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Vector{vectors[0]}, search)
+}
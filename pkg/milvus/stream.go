@@ -0,0 +1,244 @@
+package milvus
+
+import (
+	"fmt"
+	"sync"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// Vector stream read-ahead defaults
+const (
+	defaultStreamReadAhead = 10000
+	maxStreamChunkRows     = 1024 // Rows read by the background reader at a time
+)
+
+// VectorStreamConfig configures milvus.vectorStream()
+type VectorStreamConfig struct {
+	Offset    int64 `json:"offset,omitempty"`    // First row to read
+	Limit     int64 `json:"limit,omitempty"`     // Maximum number of rows to read (default: to the end of the file)
+	ReadAhead int   `json:"readAhead,omitempty"` // Rows read ahead of nextBatch() in the background (default: 10000)
+	// "vu" to read a fixed part of the rows per VU, "scenario" for rows claimed by the VUs of a scenario
+	Shard  string `json:"shard,omitempty"`
+	Shards int    `json:"shards,omitempty"` // Parts with shard "vu" (default: the number of VUs)
+}
+
+// VectorStream reads an .fvecs, .bvecs or .npy file in batches without loading it: a background
+// reader keeps a fixed number of rows ready ahead of nextBatch(), so memory stays bounded
+// however large the file is.
+//
+// Usage in k6:
+//
+//	const stream = milvus.vectorStream('data/bigann_base.bvecs', { readAhead: 50000 });
+//	export default function () {
+//	    const batch = stream.nextBatch(1000);
+//	    if (batch) client.insert({ id: batch.ids, embedding: batch.vectors });
+//	}
+type VectorStream struct {
+	path       string
+	dim        int
+	file       *vectorFile
+	config     VectorStreamConfig
+	shard      datasetShard
+	start, end int64            // Rows to read, before sharding
+	chunks     chan vectorChunk // Rows read ahead, closed after the last row
+	done       chan struct{}    // Closed to stop the background reader
+	stopped    chan struct{}    // Closed when the background reader has returned
+	pending    vectorChunk      // Rest of the chunk being returned
+	err        error            // Read error, returned after the rows read before it
+}
+
+// vectorChunk holds consecutive rows read by the background reader
+type vectorChunk struct {
+	first   int64 // Row index of the first vector
+	vectors [][]float32
+	err     error // Error reading the row after the vectors
+}
+
+// VectorStream opens a vector file for streamed batches. Relative paths are resolved against
+// the working directory of the k6 process. Reading starts on the first nextBatch() call.
+func (m *Milvus) VectorStream(path string, configInput ...interface{}) (*VectorStream, error) {
+	var config VectorStreamConfig
+	if len(configInput) > 0 && configInput[0] != nil {
+		if err := convertViaJSON(configInput[0], &config); err != nil {
+			return nil, fmt.Errorf("invalid vector stream config: %v", err)
+		}
+	}
+	path, err := localDataset(m.shared, path)
+	if err != nil {
+		return nil, err
+	}
+	return openVectorStream(m.vu, m.shared, path, config)
+}
+
+func openVectorStream(vu modules.VU, shared *sync.Map, path string, config VectorStreamConfig) (*VectorStream, error) {
+	if config.Offset < 0 || config.Limit < 0 || config.ReadAhead < 0 {
+		return nil, fmt.Errorf("vector stream offset, limit and readAhead must not be negative")
+	}
+	if config.ReadAhead == 0 {
+		config.ReadAhead = defaultStreamReadAhead
+	}
+	shard, err := newDatasetShard(vu, shared, config.Shard, config.Shards, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid vector stream config: %v", err)
+	}
+	file, err := openVectorFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &VectorStream{path: path, dim: file.dim, file: file, config: config, shard: shard}
+	s.start = min(config.Offset, file.rows)
+	s.end = file.rows
+	if config.Limit > 0 {
+		s.end = min(s.start+config.Limit, file.rows)
+	}
+	s.shard.key = shardKey(path, s.start, s.end)
+	return s, nil
+}
+
+// readAhead reads the chunks of rows returned by next until it returns an empty range,
+// blocking while the read-ahead buffer is full
+func (s *VectorStream) readAhead(next func() (int64, int64), chunks chan<- vectorChunk, done <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+	defer close(chunks)
+	positioned := false
+	for {
+		first, end := next()
+		if first >= end {
+			return
+		}
+		chunk := vectorChunk{first: first}
+		if !positioned || first != s.file.next {
+			chunk.err = s.file.seek(first)
+			positioned = true
+		}
+		if chunk.err == nil {
+			chunk.vectors = newMatrix[float32](int(end-first), s.file.dim)
+			for i, vector := range chunk.vectors {
+				if chunk.err = s.file.read(vector); chunk.err != nil {
+					chunk.vectors = chunk.vectors[:i]
+					break
+				}
+			}
+		}
+		select {
+		case chunks <- chunk:
+		case <-done:
+			return
+		}
+		if chunk.err != nil {
+			return
+		}
+	}
+}
+
+// NextBatch returns up to count vectors with their row indices, to insert as primary keys, as
+// { ids, vectors }, or null once all rows have been read
+func (s *VectorStream) NextBatch(count int) (map[string]interface{}, error) {
+	if s.file == nil {
+		return nil, fmt.Errorf("vector stream %s is closed", s.path)
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", count)
+	}
+	if s.chunks == nil {
+		s.startReader()
+	}
+
+	var ids []int64
+	var vectors [][]float32
+	for len(vectors) < count {
+		if len(s.pending.vectors) == 0 {
+			if s.err != nil {
+				// Return the rows before the error first
+				if len(vectors) > 0 {
+					break
+				}
+				return nil, s.err
+			}
+			chunk, ok := <-s.chunks
+			if !ok {
+				break
+			}
+			s.pending, s.err = chunk, chunk.err
+			continue
+		}
+		// Batches borrow the rows of the chunk, so no vector is copied
+		take := min(count-len(vectors), len(s.pending.vectors))
+		ids = append(ids, rowIndices(int(s.pending.first), int(s.pending.first)+take)...)
+		vectors = append(vectors, s.pending.vectors[:take]...)
+		s.pending.vectors = s.pending.vectors[take:]
+		s.pending.first += int64(take)
+	}
+	if len(vectors) == 0 {
+		return nil, nil
+	}
+	return map[string]interface{}{"ids": ids, "vectors": vectors}, nil
+}
+
+// startReader starts the background reader at the first row of the VU
+func (s *VectorStream) startReader() {
+	chunkRows := min(s.config.ReadAhead, maxStreamChunkRows)
+	var next func() (int64, int64)
+	if s.shard.mode == shardScenario {
+		s.shard.prepare()
+		next = func() (int64, int64) { return s.shard.claim(s.start, s.end, int64(chunkRows)) }
+	} else {
+		position, end := s.shard.vuRange(s.start, s.end)
+		next = func() (int64, int64) {
+			first := position
+			position = min(position+int64(chunkRows), end)
+			return first, position
+		}
+	}
+	s.chunks = make(chan vectorChunk, max(s.config.ReadAhead/chunkRows-1, 0))
+	s.done = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go s.readAhead(next, s.chunks, s.done, s.stopped)
+}
+
+// stopReader stops the background reader and drops the rows it read ahead
+func (s *VectorStream) stopReader() {
+	if s.chunks == nil {
+		return
+	}
+	close(s.done)
+	<-s.stopped
+	s.chunks, s.done, s.stopped = nil, nil, nil
+	s.pending = vectorChunk{}
+}
+
+// Reset rewinds the stream to the first row of the VU. Streams with shard "scenario" cannot be
+// rewound, as other VUs have read the rest of the rows.
+func (s *VectorStream) Reset() error {
+	if s.file == nil {
+		return fmt.Errorf("vector stream %s is closed", s.path)
+	}
+	if s.shard.mode == shardScenario {
+		return fmt.Errorf("vector stream %s with shard %q cannot be reset", s.path, shardScenario)
+	}
+	s.stopReader()
+	s.err = nil
+	return nil
+}
+
+// NumRows returns the number of rows the stream yields in total, after offset and limit
+func (s *VectorStream) NumRows() int64 {
+	return s.end - s.start
+}
+
+// Dimension returns the vector dimension
+func (s *VectorStream) Dimension() int {
+	return s.dim
+}
+
+// Close stops reading ahead and closes the underlying file
+func (s *VectorStream) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	s.stopReader()
+	err := s.file.close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,98 @@
+package milvus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorStream(t *testing.T) {
+	rows := make([][]float32, 25)
+	for i := range rows {
+		rows[i] = []float32{float32(i), float32(-i)}
+	}
+	path := writeFvecs(t, rows)
+
+	// A read-ahead smaller than a batch still returns full batches
+	stream, err := (&Milvus{}).VectorStream(path, map[string]interface{}{"readAhead": 4})
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+	assert.Equal(t, int64(25), stream.NumRows())
+	assert.Equal(t, 2, stream.Dimension())
+
+	var ids []int64
+	var vectors [][]float32
+	for {
+		batch, err := stream.NextBatch(10)
+		require.NoError(t, err)
+		if batch == nil {
+			break
+		}
+		assert.LessOrEqual(t, len(batch["vectors"].([][]float32)), 10)
+		ids = append(ids, batch["ids"].([]int64)...)
+		vectors = append(vectors, batch["vectors"].([][]float32)...)
+	}
+	assert.Equal(t, rows, vectors)
+	assert.Equal(t, rowIndices(0, 25), ids)
+
+	// Reset rewinds, also in the middle of the stream
+	require.NoError(t, stream.Reset())
+	_, err = stream.NextBatch(3)
+	require.NoError(t, err)
+	require.NoError(t, stream.Reset())
+	batch, err := stream.NextBatch(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1}, batch["ids"])
+
+	require.NoError(t, stream.Close())
+	_, err = stream.NextBatch(1)
+	assert.ErrorContains(t, err, "is closed")
+}
+
+func TestVectorStreamRange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "base.bvecs")
+	var data []byte
+	for i := 0; i < 6; i++ {
+		data = append(data, 1, 0, 0, 0, byte(i))
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	stream, err := openVectorStream(nil, nil, path, VectorStreamConfig{Offset: 2, Limit: 3})
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+	assert.Equal(t, int64(3), stream.NumRows())
+	batch, err := stream.NextBatch(10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3, 4}, batch["ids"])
+	assert.Equal(t, [][]float32{{2}, {3}, {4}}, batch["vectors"])
+	batch, err = stream.NextBatch(10)
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+}
+
+func TestVectorStreamErrors(t *testing.T) {
+	m := &Milvus{}
+	_, err := m.VectorStream(filepath.Join(t.TempDir(), "missing.fvecs"))
+	assert.Error(t, err)
+	_, err = m.VectorStream("base.fvecs", map[string]interface{}{"readAhead": -1})
+	assert.ErrorContains(t, err, "must not be negative")
+
+	// A bad row fails the batch that reaches it, and every later one
+	path := filepath.Join(t.TempDir(), "ragged.fvecs")
+	require.NoError(t, os.WriteFile(path, []byte{1, 0, 0, 0, 0, 0, 128, 63, 2, 0, 0, 0, 0, 0, 128, 63}, 0o600))
+	stream, err := m.VectorStream(path)
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+	batch, err := stream.NextBatch(5)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1}}, batch["vectors"])
+	_, err = stream.NextBatch(1)
+	assert.ErrorContains(t, err, "row 1 has dimension 2")
+	_, err = stream.NextBatch(1)
+	assert.ErrorContains(t, err, "row 1 has dimension 2")
+	_, err = stream.NextBatch(0)
+	assert.ErrorContains(t, err, "count must be positive")
+}
@@ -0,0 +1,121 @@
+package milvus
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// operationStats holds the counters of one operation in milvus.summary()
+type operationStats struct {
+	ops         int64
+	errors      int64
+	rows        int64
+	bytes       int64
+	recallSum   float64
+	recallCount int64
+}
+
+// operationSummary accumulates per-operation counters across all VUs, so handleSummary can
+// report them after the test. A nil summary records nothing.
+type operationSummary struct {
+	mu         sync.Mutex
+	operations map[string]*operationStats
+}
+
+// add merges the counters of one call into the operation's totals
+func (s *operationSummary) add(operation string, call operationStats) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats(operation)
+	stats.ops += call.ops
+	stats.errors += call.errors
+	stats.rows += call.rows
+	stats.bytes += call.bytes
+}
+
+// addRecalls adds per-query recall values to the operation's mean recall
+func (s *operationSummary) addRecalls(operation string, recalls []float64) {
+	if s == nil || len(recalls) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats(operation)
+	for _, recall := range recalls {
+		stats.recallSum += recall
+	}
+	stats.recallCount += int64(len(recalls))
+}
+
+// stats returns the counters of an operation, creating them on first use. s.mu must be held.
+func (s *operationSummary) stats(operation string) *operationStats {
+	if s.operations == nil {
+		s.operations = make(map[string]*operationStats)
+	}
+	stats, ok := s.operations[operation]
+	if !ok {
+		stats = &operationStats{}
+		s.operations[operation] = stats
+	}
+	return stats
+}
+
+// snapshot returns the totals by operation, as returned to JavaScript by milvus.summary()
+func (s *operationSummary) snapshot() map[string]interface{} {
+	out := make(map[string]interface{})
+	if s == nil {
+		return out
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for operation, stats := range s.operations {
+		entry := map[string]interface{}{
+			"ops":    stats.ops,
+			"errors": stats.errors,
+			"rows":   stats.rows,
+			"bytes":  stats.bytes,
+		}
+		if stats.ops > 0 {
+			entry["error_rate"] = float64(stats.errors) / float64(stats.ops)
+		}
+		if stats.recallCount > 0 {
+			entry["recall"] = stats.recallSum / float64(stats.recallCount)
+		}
+		out[operation] = entry
+	}
+	return out
+}
+
+// Summary returns the calls made by gRPC clients of all VUs so far, by operation: ops, errors,
+// error_rate, rows written, payload bytes and, for searches with recall, the mean recall.
+// Operations are named after the RPC in snake_case, as in the operation metric tag.
+// Calls made while metrics are disabled are not counted.
+//
+// Usage in k6:
+//
+//	export function handleSummary(data) {
+//	    return { 'milvus-summary.json': JSON.stringify(milvus.summary(), null, 2) };
+//	}
+func (m *Milvus) Summary() map[string]interface{} {
+	return m.summary.snapshot()
+}
+
+// operationName converts an RPC method name to the snake_case operation name, e.g.
+// HybridSearch to hybrid_search
+func operationName(method string) string {
+	var b strings.Builder
+	for i, r := range method {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOperationName(t *testing.T) {
+	assert.Equal(t, "insert", operationName("Insert"))
+	assert.Equal(t, "hybrid_search", operationName("HybridSearch"))
+	assert.Equal(t, "describe_collection", operationName("DescribeCollection"))
+}
+
+func TestSummary(t *testing.T) {
+	vu, _ := newMetricsVU(t)
+	m := &Milvus{vu: vu, metrics: registerMetrics(vu), summary: &operationSummary{}}
+	client, err := m.wrapClient(&milvusclient.Client{}, DefaultClientConfig())
+	require.NoError(t, err)
+
+	ok := func(_ context.Context, _ string, _, out any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		if result, isMutation := out.(*milvuspb.MutationResult); isMutation {
+			result.InsertCnt = 100
+		}
+		return nil
+	}
+	down := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "down")
+	}
+	insert := &milvuspb.InsertRequest{CollectionName: "products", NumRows: 100}
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Insert", insert, &milvuspb.MutationResult{}, nil, ok)
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Insert", insert, &milvuspb.MutationResult{}, nil, ok)
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/Insert", insert, &milvuspb.MutationResult{}, nil, down)
+	_ = observeRPC(client.context(), "/milvus.proto.milvus.MilvusService/HybridSearch", &milvuspb.HybridSearchRequest{}, &milvuspb.SearchResults{}, nil, ok)
+	client.summary.addRecalls("search", []float64{1, 0.5})
+
+	summary := m.Summary()
+	assert.Len(t, summary, 3)
+
+	inserts := summary["insert"].(map[string]interface{})
+	assert.Equal(t, int64(3), inserts["ops"])
+	assert.Equal(t, int64(1), inserts["errors"])
+	assert.InDelta(t, 1.0/3, inserts["error_rate"], 1e-9)
+	assert.Equal(t, int64(200), inserts["rows"])
+	assert.Positive(t, inserts["bytes"])
+	assert.NotContains(t, inserts, "recall")
+
+	hybrid := summary["hybrid_search"].(map[string]interface{})
+	assert.Equal(t, int64(1), hybrid["ops"])
+	assert.Equal(t, 0.0, hybrid["error_rate"])
+
+	search := summary["search"].(map[string]interface{})
+	assert.Equal(t, 0.75, search["recall"])
+
+	// Clients with metrics disabled are not counted
+	config := DefaultClientConfig()
+	config.DisableMetrics = true
+	quiet, err := m.wrapClient(&milvusclient.Client{}, config)
+	require.NoError(t, err)
+	_ = observeRPC(quiet.context(), "/milvus.proto.milvus.MilvusService/Insert", insert, &milvuspb.MutationResult{}, nil, ok)
+	assert.Equal(t, int64(3), m.Summary()["insert"].(map[string]interface{})["ops"])
+}
+
+func TestSummaryEmpty(t *testing.T) {
+	assert.Empty(t, (&Milvus{}).Summary())
+	assert.Empty(t, (&Milvus{summary: &operationSummary{}}).Summary())
+}
@@ -0,0 +1,258 @@
+package milvus
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// Tenant simulator defaults
+const (
+	defaultTenantPrefix  = "tenant"
+	defaultTenantField   = "tenant_id"
+	defaultTenantBuckets = 10
+)
+
+// Tenant isolation strategies of the tenant simulator
+const (
+	tenantStrategyDatabase     = "database"
+	tenantStrategyPartitionKey = "partitionKey"
+	tenantStrategyCollection   = "collection"
+)
+
+// TenantSimulatorConfig configures milvus.tenantSimulator()
+type TenantSimulatorConfig struct {
+	Strategy string   `json:"strategy"`          // Tenant isolation: "database", "partitionKey" or "collection"
+	Count    int      `json:"count"`             // Tenants, named prefix_0 to prefix_<count-1>
+	Prefix   string   `json:"prefix,omitempty"`  // Prefix of the tenant names (default: "tenant")
+	Field    string   `json:"field,omitempty"`   // Partition key field of the partitionKey strategy (default: "tenant_id")
+	IntKeys  bool     `json:"intKeys,omitempty"` // The partition key holds tenant indices instead of tenant names
+	Assign   string   `json:"assign,omitempty"`  // Tenant of each call: "vu" (fixed per VU, default), "uniform" or "zipf"
+	Skew     *float64 `json:"skew,omitempty"`    // Zipf exponent of assign "zipf" (default: 1)
+	Buckets  int      `json:"buckets,omitempty"` // Tenant buckets of the tenant_bucket tag (default: 10, at most count)
+	Seed     int64    `json:"seed,omitempty"`    // Seed of the tenant draws, together with the VU ID
+}
+
+// TenantSimulator maps VUs to the tenants of a multi-tenant deployment, where each tenant is a
+// database, a partition key value or a collection. bind() returns the client scoped to the
+// tenant of the call, with every metric tagged with tenant_bucket: tenants are grouped into
+// buckets of consecutive indices so that thousands of tenants do not explode the series of the
+// metrics. filter() scopes filter expressions to the tenant, as the partitionKey strategy
+// requires.
+//
+// Usage in k6:
+//
+//	const tenants = milvus.tenantSimulator({ strategy: 'partitionKey', count: 1000, assign: 'zipf' });
+//	export default function () {
+//	    const client = tenants.bind(milvus.getClient('localhost:19530', 'bench'));
+//	    client.search(gen.next(1), 10, { vectorField: 'embedding', filter: tenants.filter() });
+//	}
+type TenantSimulator struct {
+	vu      modules.VU
+	config  TenantSimulatorConfig
+	zipf    *zipfTable // Tenant popularity of assign "zipf"
+	stream  seedStream
+	connect func(*ClientConfig) (*Client, error) // Client of a tenant database
+	current int                                  // Tenant last picked, -1 before the first
+}
+
+// TenantSimulator creates a tenant simulator
+func (m *Milvus) TenantSimulator(configInput map[string]interface{}) (*TenantSimulator, error) {
+	var config TenantSimulatorConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid tenant simulator config: %v", err)
+	}
+	switch config.Strategy {
+	case tenantStrategyDatabase, tenantStrategyPartitionKey, tenantStrategyCollection:
+	default:
+		return nil, fmt.Errorf("tenant simulator strategy must be database, partitionKey or collection, got %q", config.Strategy)
+	}
+	if config.Count <= 0 {
+		return nil, fmt.Errorf("tenant simulator count must be positive, got %d", config.Count)
+	}
+	if config.Buckets < 0 {
+		return nil, fmt.Errorf("tenant simulator buckets must not be negative, got %d", config.Buckets)
+	}
+	if config.Prefix == "" {
+		config.Prefix = defaultTenantPrefix
+	}
+	if config.Field == "" {
+		config.Field = defaultTenantField
+	}
+	config.Buckets = min(optionalPositive(config.Buckets, defaultTenantBuckets), config.Count)
+
+	s := &TenantSimulator{vu: m.vu, config: config, stream: newSeedStream(m.vu, config.Seed, false), connect: m.cachedClient, current: -1}
+	switch config.Assign {
+	case "":
+		s.config.Assign = "vu"
+	case "vu", "uniform":
+	case "zipf":
+		table, err := zipfTableFor(m.shared, config.Count, optionalFloat(config.Skew, defaultZipfSkew))
+		if err != nil {
+			return nil, fmt.Errorf("tenant simulator: %v", err)
+		}
+		s.zipf = table
+	default:
+		return nil, fmt.Errorf("tenant simulator assign must be vu, uniform or zipf, got %q", config.Assign)
+	}
+	return s, nil
+}
+
+// Next picks the tenant of the next call and returns its index: the VU's own tenant with assign
+// "vu", VU n getting tenant (n-1) mod count, or a tenant drawn uniformly or by Zipfian
+// popularity. filter() and keys() use the tenant last picked.
+func (s *TenantSimulator) Next() int {
+	switch s.config.Assign {
+	case "uniform":
+		s.current = s.stream.rand().Intn(s.config.Count)
+	case "zipf":
+		s.current = s.zipf.sample(s.stream.rand())
+	default:
+		var vuID uint64
+		if s.vu != nil && s.vu.State() != nil {
+			vuID = s.vu.State().VUID
+		}
+		s.current = int((max(vuID, 1) - 1) % uint64(s.config.Count))
+	}
+	return s.current
+}
+
+// tenant returns the tenant last picked, picking one on the first call
+func (s *TenantSimulator) tenant() int {
+	if s.current < 0 {
+		return s.Next()
+	}
+	return s.current
+}
+
+// Name returns the name of a tenant: its database, collection or partition key value
+func (s *TenantSimulator) Name(index int) (string, error) {
+	if index < 0 || index >= s.config.Count {
+		return "", fmt.Errorf("tenant index must be between 0 and %d, got %d", s.config.Count-1, index)
+	}
+	return s.config.Prefix + "_" + strconv.Itoa(index), nil
+}
+
+// Names returns the names of all tenants, e.g. to create their databases or collections in setup()
+func (s *TenantSimulator) Names() []string {
+	names := make([]string, s.config.Count)
+	for i := range names {
+		names[i], _ = s.Name(i)
+	}
+	return names
+}
+
+// Bucket returns the tenant_bucket tag of a tenant: the range of tenant indices of its bucket,
+// e.g. "100-199"
+func (s *TenantSimulator) Bucket(index int) (string, error) {
+	if index < 0 || index >= s.config.Count {
+		return "", fmt.Errorf("tenant index must be between 0 and %d, got %d", s.config.Count-1, index)
+	}
+	count, buckets := s.config.Count, s.config.Buckets
+	bucket := index * buckets / count
+	first := (bucket*count + buckets - 1) / buckets
+	last := ((bucket+1)*count+buckets-1)/buckets - 1
+	if first == last {
+		return strconv.Itoa(first), nil
+	}
+	return fmt.Sprintf("%d-%d", first, last), nil
+}
+
+// Current returns the tenant last picked as { index, name, bucket }, or null before the first
+func (s *TenantSimulator) Current() map[string]interface{} {
+	if s.current < 0 {
+		return nil
+	}
+	name, _ := s.Name(s.current)
+	bucket, _ := s.Bucket(s.current)
+	return map[string]interface{}{"index": s.current, "name": name, "bucket": bucket}
+}
+
+// Bind picks the tenant of the call, as next(), and returns the client scoped to it, with every
+// metric of its calls tagged with tenant_bucket. With the database strategy it is a client of the
+// same connection config in the tenant's database, cached per VU; with the collection strategy,
+// calls without a collection name target the tenant's collection. With the partitionKey strategy,
+// the client is only tagged, and searches and queries are scoped with filter().
+func (s *TenantSimulator) Bind(client *Client) (*Client, error) {
+	if client == nil {
+		return nil, fmt.Errorf("bind requires a client")
+	}
+	index := s.Next()
+	name, _ := s.Name(index)
+	bucket, _ := s.Bucket(index)
+	switch s.config.Strategy {
+	case tenantStrategyDatabase:
+		if client.config == nil {
+			return nil, fmt.Errorf("bind with the database strategy requires a gRPC client")
+		}
+		config := *client.config
+		config.DBName = name
+		tenantClient, err := s.connect(&config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to tenant database %s: %v", name, err)
+		}
+		// Tags of the given client, e.g. of collectionSet().bind(), carry over
+		bound := tenantClient.withTags(client.tags).withTags(map[string]string{"tenant_bucket": bucket})
+		bound.defaultCollection = client.getCollectionName()
+		return bound, nil
+	case tenantStrategyCollection:
+		bound := client.withTags(map[string]string{"tenant_bucket": bucket})
+		bound.defaultCollection = name
+		return bound, nil
+	default:
+		return client.withTags(map[string]string{"tenant_bucket": bucket}), nil
+	}
+}
+
+// Filter returns the filter expression scoped to the tenant last picked: with the partitionKey
+// strategy, the partition key condition, joined to expr if given; with the other strategies,
+// expr as it is, as the tenant's database or collection holds only its own rows
+func (s *TenantSimulator) Filter(expr ...string) string {
+	var filter string
+	if len(expr) > 0 {
+		filter = expr[0]
+	}
+	if s.config.Strategy != tenantStrategyPartitionKey {
+		return filter
+	}
+	condition := fmt.Sprintf("%s == %s", s.config.Field, s.key(s.tenant()))
+	if filter == "" {
+		return condition
+	}
+	return fmt.Sprintf("(%s) and %s", filter, condition)
+}
+
+// key returns the partition key value of a tenant in a filter expression
+func (s *TenantSimulator) key(index int) string {
+	if s.config.IntKeys {
+		return strconv.Itoa(index)
+	}
+	name, _ := s.Name(index)
+	return strconv.Quote(name)
+}
+
+// Keys returns the partition key column of count rows of the tenant last picked, to insert rows
+// of the tenant with the partitionKey strategy: tenant names, or indices with intKeys
+func (s *TenantSimulator) Keys(count int) (interface{}, error) {
+	if s.config.Strategy != tenantStrategyPartitionKey {
+		return nil, fmt.Errorf("keys requires the partitionKey strategy, got %s", s.config.Strategy)
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", count)
+	}
+	index := s.tenant()
+	if s.config.IntKeys {
+		keys := make([]int64, count)
+		for i := range keys {
+			keys[i] = int64(index)
+		}
+		return keys, nil
+	}
+	name, _ := s.Name(index)
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = name
+	}
+	return keys, nil
+}
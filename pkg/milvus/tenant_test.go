@@ -0,0 +1,140 @@
+package milvus
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+)
+
+func TestTenantSimulatorPartitionKey(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	vu.state.VUID = 3
+	client := benchClient(t, &workloadServer{}, vu)
+
+	tenants, err := (&Milvus{vu: vu, shared: &sync.Map{}}).TenantSimulator(map[string]interface{}{"strategy": "partitionKey", "count": 4})
+	require.NoError(t, err)
+	assert.Nil(t, tenants.Current())
+
+	bound, err := tenants.Bind(client)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"index": 2, "name": "tenant_2", "bucket": "2"}, tenants.Current(), "VU 3 gets tenant 2")
+	assert.Equal(t, `tenant_id == "tenant_2"`, tenants.Filter())
+	assert.Equal(t, `(price > 10) and tenant_id == "tenant_2"`, tenants.Filter("price > 10"))
+	keys, err := tenants.Keys(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant_2", "tenant_2"}, keys)
+
+	result := bound.Search([][]float32{{1, 0}}, 2, map[string]interface{}{"vectorField": "embedding", "filter": tenants.Filter()}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	buckets := map[string]bool{}
+	for _, sample := range drainSamples(samples) {
+		if bucket, ok := sample.Tags.Get("tenant_bucket"); ok {
+			buckets[bucket] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"2": true}, buckets)
+
+	numeric, err := (&Milvus{vu: vu, shared: &sync.Map{}}).TenantSimulator(map[string]interface{}{"strategy": "partitionKey", "count": 4, "field": "org", "intKeys": true})
+	require.NoError(t, err)
+	assert.Equal(t, "org == 2", numeric.Filter(), "the first filter picks the tenant")
+	keys, err = numeric.Keys(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, keys)
+}
+
+func TestTenantSimulatorCollection(t *testing.T) {
+	tenants, err := (&Milvus{shared: &sync.Map{}}).TenantSimulator(map[string]interface{}{"strategy": "collection", "count": 3, "prefix": "org"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org_0", "org_1", "org_2"}, tenants.Names())
+
+	bound, err := tenants.Bind(&Client{})
+	require.NoError(t, err)
+	assert.Equal(t, "org_0", bound.getCollectionName(), "VU 0 gets tenant 0")
+	assert.Equal(t, map[string]string{"tenant_bucket": "0"}, bound.tags)
+	assert.Equal(t, "id > 1", tenants.Filter("id > 1"), "collections hold one tenant")
+	_, err = tenants.Keys(1)
+	assert.Error(t, err)
+}
+
+func TestTenantSimulatorDatabase(t *testing.T) {
+	vu := &metricsVU{state: &lib.State{VUID: 2}}
+	client := benchClient(t, &workloadServer{}, &metricsVU{})
+	m := &Milvus{vu: vu, shared: &sync.Map{}, clients: make(map[string]*Client)}
+	t.Cleanup(func() {
+		for _, c := range m.clients {
+			_ = c.Close()
+		}
+	})
+	tenants, err := m.TenantSimulator(map[string]interface{}{"strategy": "database", "count": 2})
+	require.NoError(t, err)
+
+	bound, err := tenants.Bind(client)
+	require.NoError(t, err)
+	assert.Equal(t, "tenant_1", bound.config.DBName)
+	assert.Equal(t, "bench", bound.getCollectionName(), "collection of the given client")
+	assert.Equal(t, "", client.config.DBName, "the given client is unchanged")
+	again, err := tenants.Bind(client)
+	require.NoError(t, err)
+	assert.Same(t, bound.root(), again.root(), "tenant clients are cached")
+
+	_, err = tenants.Bind(&Client{})
+	assert.Error(t, err, "REST-style client without a gRPC config")
+}
+
+func TestTenantSimulatorAssign(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{state: &lib.State{VUID: 1}}, shared: &sync.Map{}}
+	zipf, err := m.TenantSimulator(map[string]interface{}{"strategy": "partitionKey", "count": 100, "assign": "zipf", "skew": 1.5, "seed": 3})
+	require.NoError(t, err)
+	uniform, err := m.TenantSimulator(map[string]interface{}{"strategy": "partitionKey", "count": 100, "assign": "uniform", "seed": 3})
+	require.NoError(t, err)
+	hot := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		if zipf.Next() == 0 {
+			hot["zipf"]++
+		}
+		if uniform.Next() == 0 {
+			hot["uniform"]++
+		}
+	}
+	assert.Greater(t, hot["zipf"], 300, "tenant 0 is hot")
+	assert.Less(t, hot["uniform"], 50)
+}
+
+func TestTenantSimulatorBuckets(t *testing.T) {
+	tenants, err := (&Milvus{shared: &sync.Map{}}).TenantSimulator(map[string]interface{}{"strategy": "collection", "count": 1000})
+	require.NoError(t, err)
+	for index, bucket := range map[int]string{0: "0-99", 99: "0-99", 100: "100-199", 999: "900-999"} {
+		got, err := tenants.Bucket(index)
+		require.NoError(t, err)
+		assert.Equal(t, bucket, got, index)
+	}
+	_, err = tenants.Bucket(1000)
+	assert.Error(t, err)
+
+	uneven, err := (&Milvus{shared: &sync.Map{}}).TenantSimulator(map[string]interface{}{"strategy": "collection", "count": 5, "buckets": 2})
+	require.NoError(t, err)
+	var got []string
+	for i := 0; i < 5; i++ {
+		bucket, _ := uneven.Bucket(i)
+		got = append(got, bucket)
+	}
+	assert.Equal(t, []string{"0-2", "0-2", "0-2", "3-4", "3-4"}, got)
+}
+
+func TestTenantSimulatorConfig(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	for _, config := range []map[string]interface{}{
+		{"count": 10},
+		{"strategy": "partition", "count": 10},
+		{"strategy": "collection"},
+		{"strategy": "collection", "count": 10, "assign": "random"},
+		{"strategy": "collection", "count": 10, "buckets": -1},
+		{"strategy": "collection", "count": 10, "assign": "zipf", "skew": -1},
+	} {
+		_, err := m.TenantSimulator(config)
+		assert.Error(t, err, config)
+	}
+}
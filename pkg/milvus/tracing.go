@@ -0,0 +1,96 @@
+package milvus
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/v3/util/merr"
+	"go.k6.io/k6/lib"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// tracerName identifies the spans started by the extension
+const tracerName = "github.com/mmga-lab/xk6-milvus"
+
+// traceContext injects traceparent and tracestate headers
+var traceContext = propagation.TraceContext{}
+
+// traceRPC propagates a W3C trace context to Milvus on every RPC of a client with
+// tracePropagation or traceSpans set. With traceSpans, each RPC runs in a client span started
+// on the VU's tracer provider, which k6 exports with --traces-output. Otherwise, or when k6
+// has no traces output, each RPC gets a new sampled trace ID so the server records its trace.
+func traceRPC(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	c, ok := ctx.Value(clientContextKey{}).(*Client)
+	if !ok || c.config == nil || (!c.config.TracePropagation && !c.config.TraceSpans) {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	var span trace.Span
+	if provider := c.tracerProvider(); provider != nil {
+		ctx, span = provider.Tracer(tracerName).Start(ctx, strings.TrimPrefix(method, "/"),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(rpcAttributes(method)...),
+		)
+		defer span.End()
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, newSpanContext())
+	}
+
+	carrier := propagation.MapCarrier{}
+	traceContext.Inject(ctx, carrier)
+	for key, value := range carrier {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if span != nil {
+		if rpcErr := merr.CheckRPCCall(reply, err); rpcErr != nil {
+			span.RecordError(rpcErr)
+			span.SetStatus(otelcodes.Error, rpcErr.Error())
+			span.SetAttributes(attribute.String("error.type", errorType(rpcErr)))
+		}
+	}
+	return err
+}
+
+// tracerProvider returns k6's tracer provider when the client is configured to start spans
+func (c *Client) tracerProvider() lib.TracerProvider {
+	if !c.config.TraceSpans || c.vu == nil {
+		return nil
+	}
+	state := c.vu.State()
+	if state == nil {
+		return nil
+	}
+	return state.TracerProvider
+}
+
+// rpcAttributes returns the OpenTelemetry RPC semantic convention attributes for a gRPC method
+func rpcAttributes(method string) []attribute.KeyValue {
+	service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	return []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", name),
+	}
+}
+
+// newSpanContext returns a sampled span context with random trace and span IDs
+func newSpanContext() trace.SpanContext {
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	_, _ = rand.Read(traceID[:])
+	_, _ = rand.Read(spanID[:])
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+}
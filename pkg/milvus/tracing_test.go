@@ -0,0 +1,86 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const searchMethod = "/milvus.proto.milvus.MilvusService/Search"
+
+// tracedCall runs traceRPC for the client and returns the span context sent in traceparent
+func tracedCall(t *testing.T, client *Client, rpcErr error) trace.SpanContext {
+	t.Helper()
+	var sent metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return rpcErr
+	}
+	_ = traceRPC(client.context(), searchMethod, nil, &milvuspb.SearchResults{}, nil, invoker)
+
+	carrier := propagation.MapCarrier{}
+	for _, key := range []string{"traceparent", "tracestate"} {
+		if values := sent.Get(key); len(values) > 0 {
+			carrier[key] = values[0]
+		}
+	}
+	return trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+}
+
+func TestTraceRPCDisabled(t *testing.T) {
+	client := &Client{client: &milvusclient.Client{}, ctx: context.Background(), config: DefaultClientConfig()}
+	assert.False(t, tracedCall(t, client, nil).IsValid())
+}
+
+func TestTraceRPCPropagation(t *testing.T) {
+	config := DefaultClientConfig()
+	config.TracePropagation = true
+	client := &Client{client: &milvusclient.Client{}, ctx: context.Background(), config: config}
+
+	first := tracedCall(t, client, nil)
+	second := tracedCall(t, client, nil)
+	require.True(t, first.IsValid())
+	assert.True(t, first.IsSampled())
+	assert.NotEqual(t, first.TraceID(), second.TraceID())
+}
+
+func TestTraceRPCSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	config := DefaultClientConfig()
+	config.TraceSpans = true
+	vu := &metricsVU{state: &lib.State{TracerProvider: provider}}
+	client := &Client{client: &milvusclient.Client{}, ctx: context.Background(), vu: vu, config: config}
+
+	sent := tracedCall(t, client, status.Error(codes.Unavailable, "down"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "milvus.proto.milvus.MilvusService/Search", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, otelcodes.Error, span.Status().Code)
+	assert.Equal(t, span.SpanContext().TraceID(), sent.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), sent.SpanID())
+
+	attrs := map[string]string{}
+	for _, attr := range span.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	assert.Equal(t, "Search", attrs["rpc.method"])
+	assert.Equal(t, "unavailable", attrs["error.type"])
+}
@@ -0,0 +1,295 @@
+package milvus
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+)
+
+// TTL probe defaults
+const (
+	defaultTTLMaxLag   = 10 * time.Minute
+	defaultTTLInterval = time.Second
+	// ttlProperty is the collection property holding the TTL in seconds
+	ttlProperty = "collection.ttl.seconds"
+)
+
+// TTLProbeConfig configures milvus.ttlProbe()
+type TTLProbeConfig struct {
+	CollectionName   string `json:"collectionName,omitempty"`   // Default: the collection of the client
+	TTL              string `json:"ttl"`                        // Collection TTL in whole seconds, e.g. "60s"
+	TimestampField   string `json:"timestampField,omitempty"`   // Int64 field set to the insert time in epoch milliseconds, unless the data holds it
+	ConsistencyLevel string `json:"consistencyLevel,omitempty"` // Consistency level of the expiry queries (default: "Strong")
+	Interval         string `json:"interval,omitempty"`         // Wait between the checks of waitForExpiry() (default: "1s")
+	MaxLag           string `json:"maxLag,omitempty"`           // Rows still visible this long after their expiry are overdue (default: "10m")
+}
+
+// ttlBatch is a batch of rows inserted by the probe, waiting for expiry
+type ttlBatch struct {
+	coll     string
+	ids      column.Column // Primary keys returned by the insert
+	rows     []int64       // Rows of ids still visible
+	expected time.Time     // Insert time plus the TTL
+}
+
+// TTLProbe measures how long after their TTL entities disappear from query results. It creates
+// collections with the collection.ttl.seconds property and inserts rows, optionally stamped
+// with their insert time, remembering when each batch should expire. check() queries the
+// primary keys of the batches past their expected expiry: once no row of a batch is visible,
+// the delay between the expected and the observed expiry is reported in the
+// milvus_ttl_expiry_lag Trend, tagged with collection. The lag is observed at the checks, so
+// its resolution is the interval between them. Batches are tracked per VU.
+//
+// Usage in k6:
+//
+//	const probe = milvus.ttlProbe({ ttl: '60s', timestampField: 'inserted_at' });
+//	export default function () {
+//	    probe.insert(client, { id: ids, embedding: gen.next(100) });
+//	    probe.check(client);
+//	}
+type TTLProbe struct {
+	config   TTLProbeConfig
+	ttl      time.Duration
+	maxLag   time.Duration
+	interval time.Duration
+	level    entity.ConsistencyLevel
+	batches  []*ttlBatch
+}
+
+// TTLProbe creates a TTL expiry probe
+func (m *Milvus) TTLProbe(configInput map[string]interface{}) (*TTLProbe, error) {
+	var config TTLProbeConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid TTL probe config: %v", err)
+	}
+	ttl, err := time.ParseDuration(config.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TTL probe ttl %q: %v", config.TTL, err)
+	}
+	if ttl < time.Second || ttl%time.Second != 0 {
+		return nil, fmt.Errorf("TTL probe ttl must be a whole number of seconds, got %s", config.TTL)
+	}
+	p := &TTLProbe{config: config, ttl: ttl, maxLag: defaultTTLMaxLag, interval: defaultTTLInterval}
+	for _, option := range []struct {
+		name  string
+		value string
+		into  *time.Duration
+	}{{"maxLag", config.MaxLag, &p.maxLag}, {"interval", config.Interval, &p.interval}} {
+		if option.value == "" {
+			continue
+		}
+		if *option.into, err = time.ParseDuration(option.value); err != nil || *option.into <= 0 {
+			return nil, fmt.Errorf("TTL probe %s must be a positive duration, got %q", option.name, option.value)
+		}
+	}
+	if p.level, err = parseConsistencyLevel(config.ConsistencyLevel); err != nil {
+		return nil, fmt.Errorf("TTL probe: %v", err)
+	}
+	return p, nil
+}
+
+// CreateCollection creates a collection as client.createCollection(), with the TTL of the probe
+// in its properties. The schema name defaults to the collection of the probe or the client.
+func (p *TTLProbe) CreateCollection(client *Client, schema map[string]interface{}) interface{} {
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "createCollection requires a client"})
+	}
+	scoped := make(map[string]interface{}, len(schema)+1)
+	for key, value := range schema {
+		scoped[key] = value
+	}
+	if name, _ := stringOption(schema, "name"); name == "" {
+		scoped["name"] = client.getCollectionName(p.collection()...)
+	}
+	properties := make(map[string]interface{})
+	if existing, ok := schema["properties"].(map[string]interface{}); ok {
+		for key, value := range existing {
+			properties[key] = value
+		}
+	}
+	properties[ttlProperty] = strconv.Itoa(int(p.ttl / time.Second))
+	scoped["properties"] = properties
+	return client.CreateCollection(scoped)
+}
+
+// collection returns the collection name argument of the probe's calls
+func (p *TTLProbe) collection() []string {
+	if p.config.CollectionName == "" {
+		return nil
+	}
+	return []string{p.config.CollectionName}
+}
+
+// Insert inserts rows as client.insert(), adding the insert time to timestampField, and tracks
+// them until they expire. The result holds insert_count and expected_expiry, the epoch
+// milliseconds at which the rows should stop being visible.
+func (p *TTLProbe) Insert(client *Client, data map[string]interface{}) interface{} {
+	start := time.Now()
+	fail := func(err string, cause error) interface{} {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        err,
+			Cause:        cause,
+		})
+	}
+	if client == nil {
+		return fail("insert requires a client", nil)
+	}
+	coll := client.getCollectionName(p.collection()...)
+	if coll == "" {
+		return fail(ErrCollectionNameRequired.Error(), nil)
+	}
+	columns, err := client.convertDataToColumns(data)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to convert data: %v", err), nil)
+	}
+	if field := p.config.TimestampField; field != "" && data[field] == nil {
+		stamps := make([]int64, columns[0].Len())
+		for i := range stamps {
+			stamps[i] = start.UnixMilli()
+		}
+		columns = append(columns, column.NewColumnInt64(field, stamps))
+	}
+	chunks, err := insertChunks(columns, 0)
+	if err != nil {
+		return fail(err.Error(), nil)
+	}
+	client.insertChunks(coll, "", chunks, 1)
+	if chunks[0].err != nil {
+		return fail(fmt.Sprintf("failed to insert: %v", chunks[0].err), chunks[0].err)
+	}
+
+	// The TTL runs from the insert timestamp, assigned while the RPC is in flight
+	ids := chunks[0].result.IDs
+	expected := start.Add(p.ttl)
+	p.batches = append(p.batches, &ttlBatch{coll: coll, ids: ids, rows: rowIndices(0, ids.Len()), expected: expected})
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       map[string]interface{}{"insert_count": ids.Len(), "expected_expiry": expected.UnixMilli()},
+	})
+}
+
+// ttlCheck is the outcome of checks of a TTL probe
+type ttlCheck struct {
+	expired int       // Rows seen expired
+	overdue int       // Rows dropped as overdue
+	lags    []float64 // Expiry lags in milliseconds of the batches done
+}
+
+// Check queries the rows of the batches past their expected expiry. Batches with no visible
+// row left are done, and their expiry lag is pushed to milvus_ttl_expiry_lag; batches still
+// visible maxLag after their expected expiry are dropped as overdue, which fails the call. The
+// result holds pending (rows not yet expired), expired and overdue (rows of this check), and
+// lags, the expiry lags in milliseconds of the batches done.
+func (p *TTLProbe) Check(client *Client) interface{} {
+	start := time.Now()
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "check requires a client"})
+	}
+	outcome := ttlCheck{lags: []float64{}}
+	err := p.check(client, &outcome)
+	return p.checkResult(start, outcome, err)
+}
+
+// check runs one check, adding its outcome to total
+func (p *TTLProbe) check(client *Client, total *ttlCheck) error {
+	ctx := client.context()
+	remaining := p.batches[:0]
+	var readErr error
+	for _, batch := range p.batches {
+		if time.Now().Before(batch.expected) || readErr != nil {
+			remaining = append(remaining, batch)
+			continue
+		}
+		verifier := &rowVerifier{coll: batch.coll, mode: "query", level: p.level, ids: batch.ids}
+		missing, err := verifier.missing(ctx, client.milvus(), batch.rows)
+		if err != nil {
+			readErr = err
+			remaining = append(remaining, batch)
+			continue
+		}
+		gone := make(map[int64]bool, len(missing))
+		for _, row := range missing {
+			gone[row] = true
+		}
+		visible := batch.rows[:0]
+		for _, row := range batch.rows {
+			if !gone[row] {
+				visible = append(visible, row)
+			}
+		}
+		total.expired += len(batch.rows) - len(visible)
+		batch.rows = visible
+
+		switch lag := time.Since(batch.expected); {
+		case len(visible) == 0:
+			total.lags = append(total.lags, float64(lag.Milliseconds()))
+			if client.metrics != nil {
+				client.pushMetric(client.metrics.TTLExpiryLag, float64(lag.Milliseconds()), map[string]string{"collection": batch.coll})
+			}
+		case lag > p.maxLag:
+			total.overdue += len(visible)
+		default:
+			remaining = append(remaining, batch)
+		}
+	}
+	p.batches = remaining
+	return readErr
+}
+
+// checkResult returns the result of checks since start
+func (p *TTLProbe) checkResult(start time.Time, outcome ttlCheck, err error) interface{} {
+	result := map[string]interface{}{"pending": p.Pending(), "expired": outcome.expired, "overdue": outcome.overdue, "lags": outcome.lags}
+	if err != nil {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("failed to query expired rows: %v", err),
+			Cause:        err,
+			Result:       result,
+		})
+	}
+	if outcome.overdue > 0 {
+		return toMap(&OperationResult{
+			Success:      false,
+			ResponseTime: float64(time.Since(start).Milliseconds()),
+			Error:        fmt.Sprintf("%d rows still visible %s after their expiry", outcome.overdue, p.maxLag),
+			Result:       result,
+		})
+	}
+	return toMap(&OperationResult{
+		Success:      true,
+		ResponseTime: float64(time.Since(start).Milliseconds()),
+		Result:       result,
+	})
+}
+
+// WaitForExpiry checks every interval until all rows have expired or been dropped as overdue,
+// e.g. in teardown(). The result is that of check(), with expired, overdue and lags totalled
+// over the checks.
+func (p *TTLProbe) WaitForExpiry(client *Client) interface{} {
+	start := time.Now()
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "waitForExpiry requires a client"})
+	}
+	total := ttlCheck{lags: []float64{}}
+	for {
+		if err := p.check(client, &total); err != nil || len(p.batches) == 0 || !sleepContext(client.context(), p.interval) {
+			return p.checkResult(start, total, err)
+		}
+	}
+}
+
+// Pending returns the number of inserted rows not yet seen expired
+func (p *TTLProbe) Pending() int {
+	rows := 0
+	for _, batch := range p.batches {
+		rows += len(batch.rows)
+	}
+	return rows
+}
@@ -0,0 +1,161 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ttlServer reads back inserted keys as verifyServer, with an inserted_at field, and records the
+// collection properties and the inserted timestamps
+type ttlServer struct {
+	verifyServer
+	properties map[string]string
+	stamps     []int64
+}
+
+func (s *ttlServer) DescribeCollection(context.Context, *milvuspb.DescribeCollectionRequest) (*milvuspb.DescribeCollectionResponse, error) {
+	schema := entity.NewSchema().WithName("bench").
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("embedding").WithDataType(entity.FieldTypeFloatVector).WithDim(2)).
+		WithField(entity.NewField().WithName("inserted_at").WithDataType(entity.FieldTypeInt64))
+	return &milvuspb.DescribeCollectionResponse{Status: &commonpb.Status{}, CollectionName: "bench", CollectionID: 1, Schema: schema.ProtoMessage()}, nil
+}
+
+func (s *ttlServer) Insert(ctx context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+	for _, data := range req.GetFieldsData() {
+		if data.GetFieldName() == "inserted_at" {
+			s.mu.Lock()
+			s.stamps = append(s.stamps, data.GetScalars().GetLongData().GetData()...)
+			s.mu.Unlock()
+		}
+	}
+	return s.verifyServer.Insert(ctx, req)
+}
+
+func (s *ttlServer) CreateCollection(_ context.Context, req *milvuspb.CreateCollectionRequest) (*commonpb.Status, error) {
+	s.record(req.GetProperties())
+	return &commonpb.Status{}, nil
+}
+
+func (s *ttlServer) AlterCollection(_ context.Context, req *milvuspb.AlterCollectionRequest) (*commonpb.Status, error) {
+	s.record(req.GetProperties())
+	return &commonpb.Status{}, nil
+}
+
+func (s *ttlServer) record(properties []*commonpb.KeyValuePair) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.properties = make(map[string]string)
+	for _, property := range properties {
+		s.properties[property.GetKey()] = property.GetValue()
+	}
+}
+
+// expire hides the inserted keys from reads
+func (s *ttlServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lost = make(map[int64]bool)
+	for _, batch := range s.batches {
+		for _, id := range batch {
+			s.lost[id] = true
+		}
+	}
+}
+
+func TestTTLProbe(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	service := &ttlServer{}
+	client := benchClient(t, service, vu)
+
+	probe, err := (&Milvus{}).TTLProbe(map[string]interface{}{"ttl": "1s", "timestampField": "inserted_at", "interval": "50ms"})
+	require.NoError(t, err)
+	before := time.Now().UnixMilli()
+	result := probe.Insert(client, verifyData(3)).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	inserted := result["result"].(map[string]interface{})
+	assert.Equal(t, float64(3), inserted["insert_count"])
+	assert.InDelta(t, before+1000, inserted["expected_expiry"], 100)
+	require.Len(t, service.stamps, 3)
+	assert.InDelta(t, before, service.stamps[0], 100)
+	assert.Equal(t, 3, probe.Pending())
+
+	result = probe.Check(client).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, map[string]interface{}{"pending": float64(3), "expired": float64(0), "overdue": float64(0), "lags": []interface{}{}}, result["result"], "not due yet")
+
+	time.AfterFunc(1200*time.Millisecond, service.expire)
+	result = probe.WaitForExpiry(client).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	checked := result["result"].(map[string]interface{})
+	assert.Equal(t, float64(3), checked["expired"])
+	require.Len(t, checked["lags"], 1)
+	assert.InDelta(t, 200, checked["lags"].([]interface{})[0], 150, "expired about 200ms after the TTL")
+	assert.Equal(t, 0, probe.Pending())
+
+	var lags []float64
+	for _, sample := range drainSamples(samples) {
+		if sample.Metric.Name == "milvus_ttl_expiry_lag" {
+			collection, _ := sample.Tags.Get("collection")
+			assert.Equal(t, "bench", collection)
+			lags = append(lags, sample.Value)
+		}
+	}
+	assert.Len(t, lags, 1)
+}
+
+func TestTTLProbeOverdue(t *testing.T) {
+	client := benchClient(t, &ttlServer{}, &metricsVU{})
+	probe, err := (&Milvus{}).TTLProbe(map[string]interface{}{"ttl": "1s", "maxLag": "100ms", "interval": "50ms"})
+	require.NoError(t, err)
+	require.Equal(t, true, probe.Insert(client, verifyData(2)).(map[string]interface{})["success"])
+
+	result := probe.WaitForExpiry(client).(map[string]interface{})
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "2 rows still visible")
+	assert.Equal(t, float64(2), result["result"].(map[string]interface{})["overdue"])
+	assert.Equal(t, 0, probe.Pending(), "overdue rows are dropped")
+}
+
+func TestTTLProbeCollection(t *testing.T) {
+	service := &ttlServer{}
+	client := benchClient(t, service, &metricsVU{})
+	probe, err := (&Milvus{}).TTLProbe(map[string]interface{}{"ttl": "2m"})
+	require.NoError(t, err)
+
+	schema := map[string]interface{}{
+		"fields":     []interface{}{map[string]interface{}{"name": "id", "dataType": "Int64", "isPrimaryKey": true}},
+		"properties": map[string]interface{}{"mmap.enabled": true},
+	}
+	result := probe.CreateCollection(client, schema).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, map[string]string{"collection.ttl.seconds": "120", "mmap.enabled": "true"}, service.properties)
+	assert.Nil(t, schema["name"], "schema is not modified")
+
+	result = client.AlterCollectionProperties(map[string]interface{}{"collection.ttl.seconds": 30}).(map[string]interface{})
+	require.Equal(t, true, result["success"], result["error"])
+	assert.Equal(t, map[string]string{"collection.ttl.seconds": "30"}, service.properties)
+	assert.Equal(t, false, client.AlterCollectionProperties(nil).(map[string]interface{})["success"])
+}
+
+func TestTTLProbeConfig(t *testing.T) {
+	m := &Milvus{}
+	for _, config := range []map[string]interface{}{
+		{},
+		{"ttl": "500ms"},
+		{"ttl": "1.5s"},
+		{"ttl": "1m", "maxLag": "0s"},
+		{"ttl": "1m", "interval": "soon"},
+		{"ttl": "1m", "consistencyLevel": "Eventual!"},
+	} {
+		_, err := m.TTLProbe(config)
+		assert.Error(t, err, config)
+	}
+}
@@ -0,0 +1,85 @@
+package milvus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/grafana/sobek"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// typedResultsParam is the search param returning the hits of each query as typed arrays
+const typedResultsParam = "typedResults"
+
+// typedHits are the IDs and scores of the hits of one query, in rank order. In JS they are
+// { ids: BigInt64Array, scores: Float32Array }.
+type typedHits struct {
+	IDs    []int64
+	Scores []float32
+}
+
+// searchTypedHits returns the hits of each query without building a result object per hit
+func searchTypedHits(resultSets []milvusclient.ResultSet) ([]typedHits, error) {
+	hits := make([]typedHits, len(resultSets))
+	for i, resultSet := range resultSets {
+		hits[i].Scores = resultSet.Scores[:resultSet.ResultCount]
+		if resultSet.ResultCount == 0 {
+			hits[i].IDs = []int64{}
+			continue
+		}
+		ids, ok := resultSet.IDs.(*column.ColumnInt64)
+		if !ok {
+			return nil, fmt.Errorf("%s requires an Int64 primary key, got %s IDs", typedResultsParam, resultSet.IDs.Type())
+		}
+		hits[i].IDs = ids.Data()[:resultSet.ResultCount]
+	}
+	return hits, nil
+}
+
+// jsResult replaces the typed hits of a search result with JS typed arrays. It runs in the VU
+// goroutine; off it, and without a JS runtime as in Go tests, the hits are left as they are.
+func (c *Client) jsResult(result interface{}) interface{} {
+	m, ok := result.(map[string]interface{})
+	if !ok || c.offLoop || c.vu == nil || c.vu.Runtime() == nil {
+		return result
+	}
+	hits, ok := m["result"].([]typedHits)
+	if !ok {
+		return result
+	}
+	rt := c.vu.Runtime()
+	memory := c.trackedMemory()
+	queries := make([]interface{}, len(hits))
+	for i, h := range hits {
+		ids := make([]byte, 8*len(h.IDs))
+		for j, id := range h.IDs {
+			binary.LittleEndian.PutUint64(ids[8*j:], uint64(id))
+		}
+		scores := make([]byte, 4*len(h.Scores))
+		for j, score := range h.Scores {
+			binary.LittleEndian.PutUint32(scores[4*j:], math.Float32bits(score))
+		}
+		if memory != nil {
+			holdBuffer(&memory.results, ids)
+			holdBuffer(&memory.results, scores)
+		}
+		idArray, err := typedArray(rt, "BigInt64Array", ids)
+		if err != nil {
+			return result
+		}
+		scoreArray, err := typedArray(rt, "Float32Array", scores)
+		if err != nil {
+			return result
+		}
+		queries[i] = map[string]interface{}{"ids": idArray, "scores": scoreArray}
+	}
+	m["result"] = queries
+	return m
+}
+
+// typedArray returns a new typed array of the given constructor over the bytes
+func typedArray(rt *sobek.Runtime, constructor string, data []byte) (*sobek.Object, error) {
+	return rt.New(rt.Get(constructor), rt.ToValue(rt.NewArrayBuffer(data)))
+}
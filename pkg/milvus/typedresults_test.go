@@ -0,0 +1,41 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTypedResults(t *testing.T) {
+	rt, _ := asyncRuntime(t, &searchServer{})
+
+	_, err := rt.RunOnEventLoop(`
+		const params = { vectorField: "embedding", typedResults: true };
+		const check = (r) => r.success && r.result.length === 2 &&
+			r.result[1].ids instanceof BigInt64Array && r.result[1].ids[1] === 2n &&
+			r.result[1].scores instanceof Float32Array && Math.abs(r.result[1].scores[1] - 0.2) < 1e-6;
+		globalThis.sync = check(client.search([[1, 0], [0, 1]], 2, params));
+		client.searchAsync([[1, 0], [0, 1]], 2, params).then((r) => { globalThis.async = check(r); });
+		const res = client.searchMany([[[1, 0], [0, 1]], [[1, 1], [0, 1]]], 2, params);
+		globalThis.many = res.success && res.result.every(check);
+	`)
+	require.NoError(t, err)
+	for _, name := range []string{"sync", "async", "many"} {
+		assert.Equal(t, true, rt.VU.Runtime().Get(name).Export(), name)
+	}
+}
+
+func TestSearchTypedHits(t *testing.T) {
+	hits, err := searchTypedHits([]milvusclient.ResultSet{
+		{ResultCount: 2, IDs: column.NewColumnInt64("id", []int64{7, 3}), Scores: []float32{0.5, 0.9}},
+		{ResultCount: 0, Scores: []float32{}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []typedHits{{IDs: []int64{7, 3}, Scores: []float32{0.5, 0.9}}, {IDs: []int64{}, Scores: []float32{}}}, hits)
+
+	_, err = searchTypedHits([]milvusclient.ResultSet{{ResultCount: 1, IDs: column.NewColumnVarChar("id", []string{"a"}), Scores: []float32{1}}})
+	assert.ErrorContains(t, err, "requires an Int64 primary key")
+}
@@ -2,6 +2,9 @@ package milvus
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
 	"go.k6.io/k6/js/modules"
@@ -10,12 +13,17 @@ import (
 // OperationResult represents unified result structure for all operations
 // Following Locust's design pattern for consistent metrics collection
 type OperationResult struct {
-	Success      bool        `json:"success"`
-	ResponseTime float64     `json:"response_time_ms"`
-	Result       interface{} `json:"result,omitempty"`
-	Error        string      `json:"error,omitempty"`
-	Empty        bool        `json:"empty"`
-	Recall       float32     `json:"recall"`
+	Success        bool               `json:"success"`
+	ResponseTime   float64            `json:"response_time_ms"`
+	Result         interface{}        `json:"result,omitempty"`
+	Error          string             `json:"error,omitempty"`
+	ErrorCode      string             `json:"error_code,omitempty"` // Milvus error code, or gRPC status code name
+	ErrorType      string             `json:"error_type,omitempty"` // Error class, as in the milvus_error_types error_type tag
+	Cause          error              `json:"-"`                    // Underlying RPC error, classified by toMap
+	Empty          bool               `json:"empty"`
+	Recall         float32            `json:"recall"`                     // Mean recall over the search's query vectors
+	RecallPerQuery []float32          `json:"recall_per_query,omitempty"` // Recall of each query vector, with groundTruth or server recall
+	Quality        map[string]float32 `json:"quality,omitempty"`          // Mean precision / ndcg / mrr selected with qualityMetrics
 }
 
 // Client represents a Milvus client instance
@@ -24,7 +32,28 @@ type Client struct {
 	ctx               context.Context
 	vu                modules.VU
 	config            *ClientConfig
-	defaultCollection string // Collection binding (Locust pattern) - deprecated, use config.DefaultCollection
+	metrics           *milvusMetrics
+	pooled            bool              // Connection owned by the RootModule pool
+	base              *Client           // Client owning the connection, for clients derived by withHeaders() or per-call tags
+	headers           map[string]string // gRPC metadata attached to every call
+	tags              map[string]string // Per-call metric tags added to every sample
+	tracker           *connTracker
+	redial            func() (*milvusclient.Client, *connTracker, error) // nil when auto-reconnect is disabled
+	lastRedial        time.Time
+	connections       *atomic.Int64     // Test-wide open connection count, nil for connections owned by the pool
+	inflight          *atomic.Int64     // Test-wide in-progress RPC count
+	summary           *operationSummary // Test-wide per-operation totals for milvus.summary()
+	shared            *sync.Map         // Test-wide datasets and coordination state, for downloads by fileLoader()
+	resources         *resourceRegistry // Test-wide resources created through clients, for milvus.cleanup()
+	memory            *memoryUsage      // Test-wide memory held by the extension, for milvus_memory
+	slowQuery         time.Duration     // Searches and queries slower than this are logged, 0 to disable
+	workers           *workerPool       // Runs flushAsync() and other blocking calls in the background
+	closed            bool
+	offLoop           bool                   // Runs outside the VU goroutine, see detached(), so results hold no JS values
+	version           string                 // Cached server version
+	indexTypes        map[string]string      // Index type by "collection/field", for the index_type tag
+	searchSpecs       map[string]*searchSpec // Parsed search params by collection and content, see searchSpec()
+	defaultCollection string                 // Collection binding (Locust pattern) - deprecated, use config.DefaultCollection
 }
 
 // Field represents a field definition for schema
@@ -61,6 +90,8 @@ type Schema struct {
 	Fields      []Field    `json:"fields"`
 	Functions   []Function `json:"functions,omitempty"`
 	NumShards   int32      `json:"numShards,omitempty"`
+	// Collection properties such as collection.ttl.seconds
+	Properties map[string]interface{} `json:"properties,omitempty"`
 }
 
 // SearchResult represents a single search result entry
@@ -0,0 +1,249 @@
+package milvus
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VectorDBBench defaults, so results compare with its runs
+const (
+	defaultVDBBenchDataDir    = "s3://assets.zilliz.com/benchmark"
+	defaultVDBBenchCollection = "VectorDBBenchCollection"
+	defaultVDBBenchTopK       = 100
+	defaultVDBBenchBatch      = 100
+	defaultVDBBenchStage      = "30s"
+	// vdbbenchGracefulStop is the pause of each search stage for the iterations of the previous one
+	vdbbenchGracefulStop = 5 * time.Second
+)
+
+// defaultVDBBenchConcurrencies are the search concurrencies of VectorDBBench
+var defaultVDBBenchConcurrencies = []int{1, 5, 10, 15, 20, 25, 30, 35, 40, 45, 50, 55, 60, 65, 70, 75, 80, 85, 90, 95, 100}
+
+// vdbbenchCase is a VectorDBBench case: a dataset, and the share of rows filtered out by searches
+type vdbbenchCase struct {
+	dataset    string // Directory of the dataset files
+	size       int
+	dim        int
+	metricType string
+	trainFiles int     // Shuffled train files the dataset is split into
+	filterRate float64 // Share of rows filtered out by the id filter; 0 for no filter
+	capacity   bool    // Insert until the collection fails, without searches
+}
+
+// vdbbenchCases are the VectorDBBench cases by name
+var vdbbenchCases = map[string]vdbbenchCase{
+	"CapacityDim128":          {dataset: "sift_small_500k", size: 500_000, dim: 128, metricType: "L2", trainFiles: 1, capacity: true},
+	"CapacityDim960":          {dataset: "gist_small_100k", size: 100_000, dim: 960, metricType: "L2", trainFiles: 1, capacity: true},
+	"Performance768D1M":       {dataset: "cohere_medium_1m", size: 1_000_000, dim: 768, metricType: "COSINE", trainFiles: 1},
+	"Performance768D1M1P":     {dataset: "cohere_medium_1m", size: 1_000_000, dim: 768, metricType: "COSINE", trainFiles: 1, filterRate: 0.01},
+	"Performance768D1M99P":    {dataset: "cohere_medium_1m", size: 1_000_000, dim: 768, metricType: "COSINE", trainFiles: 1, filterRate: 0.99},
+	"Performance768D10M":      {dataset: "cohere_large_10m", size: 10_000_000, dim: 768, metricType: "COSINE", trainFiles: 10},
+	"Performance768D10M1P":    {dataset: "cohere_large_10m", size: 10_000_000, dim: 768, metricType: "COSINE", trainFiles: 10, filterRate: 0.01},
+	"Performance768D10M99P":   {dataset: "cohere_large_10m", size: 10_000_000, dim: 768, metricType: "COSINE", trainFiles: 10, filterRate: 0.99},
+	"Performance1536D500K":    {dataset: "openai_medium_500k", size: 500_000, dim: 1536, metricType: "COSINE", trainFiles: 1},
+	"Performance1536D500K1P":  {dataset: "openai_medium_500k", size: 500_000, dim: 1536, metricType: "COSINE", trainFiles: 1, filterRate: 0.01},
+	"Performance1536D500K99P": {dataset: "openai_medium_500k", size: 500_000, dim: 1536, metricType: "COSINE", trainFiles: 1, filterRate: 0.99},
+	"Performance1536D5M":      {dataset: "openai_large_5m", size: 5_000_000, dim: 1536, metricType: "COSINE", trainFiles: 10},
+	"Performance1536D5M1P":    {dataset: "openai_large_5m", size: 5_000_000, dim: 1536, metricType: "COSINE", trainFiles: 10, filterRate: 0.01},
+	"Performance1536D5M99P":   {dataset: "openai_large_5m", size: 5_000_000, dim: 1536, metricType: "COSINE", trainFiles: 10, filterRate: 0.99},
+}
+
+// VDBBenchConfig configures milvus.vdbbenchPreset(): a VectorDBBench case and the parameters to
+// change.
+type VDBBenchConfig struct {
+	Case           string                 `json:"case"`                     // VectorDBBench case name, e.g. "Performance768D1M"
+	DataDir        string                 `json:"dataDir,omitempty"`        // Directory or URL of the dataset directories (default: the VectorDBBench bucket)
+	CollectionName string                 `json:"collectionName,omitempty"` // Default: "VectorDBBenchCollection"
+	TopK           int                    `json:"topK,omitempty"`           // Results per search (default: 100)
+	Index          map[string]interface{} `json:"index,omitempty"`          // Index params (default: AUTOINDEX with the dataset metric)
+	InsertBatch    int                    `json:"insertBatch,omitempty"`    // Rows per insert (default: 100)
+	Concurrencies  []int                  `json:"concurrencies,omitempty"`  // VUs of each search stage (default: 1, 5, 10, then by 5 up to 100)
+	StageDuration  string                 `json:"stageDuration,omitempty"`  // Duration of each search stage (default: "30s")
+	Exec           string                 `json:"exec,omitempty"`           // Function of the search scenarios (default: "default")
+}
+
+// VDBBenchFiles are the dataset files of a VectorDBBench case
+type VDBBenchFiles struct {
+	Train     []string `json:"train"`     // Vectors to insert, in one or more shuffled files
+	Test      string   `json:"test"`      // Query vectors
+	Neighbors string   `json:"neighbors"` // Ground truth of the queries, with the filter of the case
+}
+
+// VDBBenchPreset holds the parameters of a VectorDBBench case, returned to JavaScript as a plain
+// object for the calls of a script and its k6 options
+type VDBBenchPreset struct {
+	Case           string                            `json:"case"`
+	Dataset        string                            `json:"dataset"`
+	Size           int                               `json:"size"`
+	Dim            int                               `json:"dim"`
+	MetricType     string                            `json:"metricType"`
+	Capacity       bool                              `json:"capacity"`
+	FilterRate     float64                           `json:"filterRate"`
+	Filter         string                            `json:"filter"` // Search filter of the case; "" without filter
+	CollectionName string                            `json:"collectionName"`
+	Schema         Schema                            `json:"schema"`
+	Fields         map[string]string                 `json:"fields"` // Schema field to Parquet column, for parquetReader()
+	Index          map[string]interface{}            `json:"index"`
+	TopK           int                               `json:"topK"`
+	InsertBatch    int                               `json:"insertBatch"`
+	Files          VDBBenchFiles                     `json:"files"`
+	Concurrencies  []int                             `json:"concurrencies"`
+	StageDuration  string                            `json:"stageDuration"`
+	Scenarios      map[string]map[string]interface{} `json:"scenarios"` // One constant-vus scenario per concurrency, one after the other
+}
+
+// VDBBenchPreset returns the parameters of a VectorDBBench case, so a script runs the case with
+// the same dataset, schema, filter, topK and search concurrencies and its results compare with
+// VectorDBBench runs. The preset is a plain object: its scenarios go into the k6 options, and its
+// files, schema and index into the calls that load the collection.
+//
+// Usage in k6:
+//
+//	const preset = milvus.vdbbenchPreset({ case: 'Performance768D1M1P' });
+//	export const options = { scenarios: preset.scenarios };
+//	export default function () {
+//	    client.search(queries, preset.topK, { vectorField: 'vector', filter: preset.filter });
+//	}
+func (m *Milvus) VDBBenchPreset(configInput map[string]interface{}) (map[string]interface{}, error) {
+	var config VDBBenchConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid vdbbench preset config: %v", err)
+	}
+	name, c, err := lookupVDBBenchCase(config.Case)
+	if err != nil {
+		return nil, err
+	}
+	if config.TopK < 0 || config.InsertBatch < 0 {
+		return nil, fmt.Errorf("vdbbench preset topK and insertBatch must not be negative")
+	}
+
+	p := &VDBBenchPreset{
+		Case:           name,
+		Dataset:        c.dataset,
+		Size:           c.size,
+		Dim:            c.dim,
+		MetricType:     c.metricType,
+		Capacity:       c.capacity,
+		FilterRate:     c.filterRate,
+		CollectionName: config.CollectionName,
+		Fields:         map[string]string{"pk": "id", "id": "id", "vector": "emb"},
+		Index:          config.Index,
+		TopK:           optionalPositive(config.TopK, defaultVDBBenchTopK),
+		InsertBatch:    optionalPositive(config.InsertBatch, defaultVDBBenchBatch),
+		Concurrencies:  config.Concurrencies,
+		StageDuration:  config.StageDuration,
+	}
+	if p.CollectionName == "" {
+		p.CollectionName = defaultVDBBenchCollection
+	}
+	if c.filterRate > 0 {
+		// VectorDBBench filters out the rows of the lowest ids
+		p.Filter = fmt.Sprintf("id >= %d", int(float64(c.size)*c.filterRate))
+	}
+	// The schema of the VectorDBBench Milvus client: the dataset id is both the key and the filtered field
+	p.Schema = Schema{Name: p.CollectionName, Fields: []Field{
+		{Name: "pk", DataType: "Int64", IsPrimaryKey: true},
+		{Name: "id", DataType: "Int64"},
+		{Name: "vector", DataType: "FloatVector", Dimension: int64(c.dim)},
+	}}
+	if p.Index == nil {
+		p.Index = map[string]interface{}{"indexType": "AUTOINDEX"}
+	}
+	if params := flattenIndexParams(p.Index); params["metricType"] == nil && params["metric_type"] == nil {
+		p.Index["metricType"] = c.metricType
+	}
+	p.Files = vdbbenchFiles(config.DataDir, c)
+
+	if !c.capacity {
+		if p.Concurrencies == nil {
+			p.Concurrencies = defaultVDBBenchConcurrencies
+		}
+		if p.StageDuration == "" {
+			p.StageDuration = defaultVDBBenchStage
+		}
+		if p.Scenarios, err = vdbbenchScenarios(p.Concurrencies, p.StageDuration, config.Exec); err != nil {
+			return nil, err
+		}
+	}
+
+	var preset map[string]interface{}
+	if err := convertViaJSON(p, &preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// lookupVDBBenchCase returns a case by its name, ignoring case
+func lookupVDBBenchCase(name string) (string, vdbbenchCase, error) {
+	for caseName, c := range vdbbenchCases {
+		if strings.EqualFold(caseName, name) {
+			return caseName, c, nil
+		}
+	}
+	names := make([]string, 0, len(vdbbenchCases))
+	for caseName := range vdbbenchCases {
+		names = append(names, caseName)
+	}
+	sort.Strings(names)
+	return "", vdbbenchCase{}, fmt.Errorf("unknown vdbbench case %q: expected one of %s", name, strings.Join(names, ", "))
+}
+
+// vdbbenchFiles returns the files of the dataset of a case, in the layout of VectorDBBench
+func vdbbenchFiles(dataDir string, c vdbbenchCase) VDBBenchFiles {
+	if dataDir == "" {
+		dataDir = defaultVDBBenchDataDir
+	}
+	dir := strings.TrimSuffix(dataDir, "/") + "/" + c.dataset + "/"
+	files := VDBBenchFiles{Test: dir + "test.parquet", Neighbors: dir + "neighbors.parquet"}
+	if c.trainFiles == 1 {
+		files.Train = []string{dir + "shuffle_train.parquet"}
+	} else {
+		for i := 0; i < c.trainFiles; i++ {
+			files.Train = append(files.Train, fmt.Sprintf("%sshuffle_train-%02d-of-%02d.parquet", dir, i, c.trainFiles))
+		}
+	}
+	switch c.filterRate {
+	case 0.01:
+		files.Neighbors = dir + "neighbors_head_1p.parquet"
+	case 0.99:
+		files.Neighbors = dir + "neighbors_tail_1p.parquet"
+	}
+	return files
+}
+
+// vdbbenchScenarios returns a constant-vus scenario per concurrency, each starting once the
+// previous one has stopped, tagged with its concurrency
+func vdbbenchScenarios(concurrencies []int, stageDuration, exec string) (map[string]map[string]interface{}, error) {
+	duration, err := time.ParseDuration(stageDuration)
+	if err != nil || duration <= 0 {
+		return nil, fmt.Errorf("invalid vdbbench stageDuration %q", stageDuration)
+	}
+	if exec == "" {
+		exec = "default"
+	}
+	scenarios := make(map[string]map[string]interface{}, len(concurrencies))
+	var startTime time.Duration
+	for _, vus := range concurrencies {
+		if vus <= 0 {
+			return nil, fmt.Errorf("vdbbench concurrencies must be positive, got %d", vus)
+		}
+		name := fmt.Sprintf("search_%03d", vus)
+		if _, ok := scenarios[name]; ok {
+			return nil, fmt.Errorf("vdbbench concurrencies repeat %d", vus)
+		}
+		scenarios[name] = map[string]interface{}{
+			"executor":     "constant-vus",
+			"vus":          vus,
+			"duration":     duration.String(),
+			"startTime":    startTime.String(),
+			"gracefulStop": vdbbenchGracefulStop.String(),
+			"exec":         exec,
+			"tags":         map[string]string{"concurrency": strconv.Itoa(vus)},
+		}
+		startTime += duration + vdbbenchGracefulStop
+	}
+	return scenarios, nil
+}
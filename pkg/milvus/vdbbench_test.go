@@ -0,0 +1,64 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVDBBenchPreset(t *testing.T) {
+	m := &Milvus{}
+	preset, err := m.VDBBenchPreset(map[string]interface{}{"case": "performance768d1m1p", "concurrencies": []interface{}{1, 10}, "stageDuration": "1m"})
+	require.NoError(t, err)
+	assert.Equal(t, "Performance768D1M1P", preset["case"])
+	assert.Equal(t, float64(768), preset["dim"])
+	assert.Equal(t, "id >= 10000", preset["filter"])
+	assert.Equal(t, float64(100), preset["topK"])
+	assert.Equal(t, "VectorDBBenchCollection", preset["collectionName"])
+	assert.Equal(t, map[string]interface{}{"indexType": "AUTOINDEX", "metricType": "COSINE"}, preset["index"])
+	files := preset["files"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"s3://assets.zilliz.com/benchmark/cohere_medium_1m/shuffle_train.parquet"}, files["train"])
+	assert.Equal(t, "s3://assets.zilliz.com/benchmark/cohere_medium_1m/neighbors_head_1p.parquet", files["neighbors"])
+
+	scenarios := preset["scenarios"].(map[string]interface{})
+	require.Len(t, scenarios, 2)
+	second := scenarios["search_010"].(map[string]interface{})
+	assert.Equal(t, float64(10), second["vus"])
+	assert.Equal(t, "1m5s", second["startTime"], "each stage starts after the previous one stopped")
+	assert.Equal(t, "default", second["exec"])
+	assert.Equal(t, map[string]interface{}{"concurrency": "10"}, second["tags"])
+}
+
+func TestVDBBenchPresetCases(t *testing.T) {
+	m := &Milvus{}
+	preset, err := m.VDBBenchPreset(map[string]interface{}{
+		"case":    "Performance1536D5M",
+		"dataDir": "data/",
+		"index":   map[string]interface{}{"indexType": "HNSW", "params": map[string]interface{}{"metric_type": "IP"}},
+	})
+	require.NoError(t, err)
+	files := preset["files"].(map[string]interface{})
+	assert.Len(t, files["train"], 10)
+	assert.Equal(t, "data/openai_large_5m/shuffle_train-09-of-10.parquet", files["train"].([]interface{})[9])
+	assert.Equal(t, "data/openai_large_5m/neighbors.parquet", files["neighbors"])
+	assert.Empty(t, preset["filter"])
+	assert.NotContains(t, preset["index"], "metricType", "the metric of the index params is kept")
+	assert.Len(t, preset["scenarios"], 21)
+
+	preset, err = m.VDBBenchPreset(map[string]interface{}{"case": "CapacityDim960"})
+	require.NoError(t, err)
+	assert.Equal(t, true, preset["capacity"])
+	assert.Nil(t, preset["scenarios"], "capacity cases do not search")
+
+	for _, config := range []map[string]interface{}{
+		{"case": "Performance768D1M2P"},
+		{"case": "Performance768D1M", "concurrencies": []interface{}{0}},
+		{"case": "Performance768D1M", "concurrencies": []interface{}{5, 5}},
+		{"case": "Performance768D1M", "stageDuration": "long"},
+		{"case": "Performance768D1M", "topK": -1},
+	} {
+		_, err := m.VDBBenchPreset(config)
+		assert.Error(t, err, config)
+	}
+}
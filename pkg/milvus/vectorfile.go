@@ -0,0 +1,171 @@
+package milvus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// vectorFile reads the rows of an .fvecs, .bvecs or .npy file of float vectors. Rows have a
+// fixed size, so the reader can seek to any row without scanning the file.
+type vectorFile struct {
+	path      string
+	file      *os.File
+	reader    *bufio.Reader
+	dim       int
+	rows      int64
+	dataStart int64 // Byte offset of row 0
+	rowSize   int   // Bytes per row, including the dimension prefix of fvecs and bvecs rows
+	prefixed  bool  // Rows start with their int32 dimension
+	decode    func(data []byte, vector []float32)
+	buf       []byte
+	next      int64 // Row read by the next read() call
+}
+
+// openVectorFile opens a vector file, with the format given by its extension
+func openVectorFile(path string) (*vectorFile, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".fvecs" && ext != ".bvecs" && ext != ".npy" {
+		return nil, fmt.Errorf("file %s must be .fvecs, .bvecs or .npy", path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vector file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to open vector file: %v", err)
+	}
+	f := &vectorFile{path: path, file: file, reader: bufio.NewReaderSize(file, 1<<20)}
+	if ext == ".npy" {
+		err = f.readNpyLayout()
+	} else {
+		err = f.readVecsLayout(ext, info.Size())
+	}
+	if err == nil && f.dataStart+f.rows*int64(f.rowSize) > info.Size() {
+		err = fmt.Errorf("truncated data, expected %d rows", f.rows)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("vector file %s: %v", path, err)
+	}
+	f.buf = make([]byte, f.rowSize)
+	return f, nil
+}
+
+// readVecsLayout reads the dimension of the first row of an .fvecs or .bvecs file, as in
+// sift_base.fvecs and bigann_base.bvecs. Every row must have the same dimension.
+func (f *vectorFile) readVecsLayout(ext string, fileSize int64) error {
+	var header [4]byte
+	if _, err := io.ReadFull(f.reader, header[:]); err != nil {
+		return fmt.Errorf("no vectors")
+	}
+	f.dim = int(int32(binary.LittleEndian.Uint32(header[:])))
+	if f.dim <= 0 {
+		return fmt.Errorf("row 0 has invalid dimension %d", f.dim)
+	}
+	f.prefixed = true
+	if ext == ".fvecs" {
+		f.rowSize = 4 + 4*f.dim
+		f.decode = decodeFloat32s
+	} else {
+		f.rowSize = 4 + f.dim
+		f.decode = decodeUint8s
+	}
+	f.rows = fileSize / int64(f.rowSize)
+	if fileSize%int64(f.rowSize) != 0 {
+		return fmt.Errorf("truncated row %d", f.rows)
+	}
+	return f.seek(0)
+}
+
+// readNpyLayout reads the header of a 2D float32, float64 or uint8 .npy array
+func (f *vectorFile) readNpyLayout() error {
+	counter := &countingReader{r: f.reader}
+	descr, rows, cols, err := readNpyHeader(counter)
+	if err != nil {
+		return err
+	}
+	switch descr {
+	case "<f4":
+		f.rowSize, f.decode = 4*cols, decodeFloat32s
+	case "<f8":
+		f.rowSize, f.decode = 8*cols, decodeFloat64s
+	case "|u1":
+		f.rowSize, f.decode = cols, decodeUint8s
+	default:
+		return fmt.Errorf("unsupported dtype %s, expected <f4, <f8 or |u1", descr)
+	}
+	if rows == 0 || cols == 0 {
+		return fmt.Errorf("no vectors")
+	}
+	f.dim, f.rows, f.dataStart = cols, int64(rows), counter.n
+	return nil
+}
+
+// seek positions the reader at a row
+func (f *vectorFile) seek(row int64) error {
+	if _, err := f.file.Seek(f.dataStart+row*int64(f.rowSize), io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek vector file %s: %v", f.path, err)
+	}
+	f.reader.Reset(f.file)
+	f.next = row
+	return nil
+}
+
+// read decodes the next row into vector, which has the file dimension
+func (f *vectorFile) read(vector []float32) error {
+	if _, err := io.ReadFull(f.reader, f.buf); err != nil {
+		return fmt.Errorf("vector file %s: truncated row %d", f.path, f.next)
+	}
+	data := f.buf
+	if f.prefixed {
+		if dim := int(int32(binary.LittleEndian.Uint32(data))); dim != f.dim {
+			return fmt.Errorf("vector file %s: row %d has dimension %d, expected %d", f.path, f.next, dim, f.dim)
+		}
+		data = data[4:]
+	}
+	f.decode(data, vector)
+	f.next++
+	return nil
+}
+
+func (f *vectorFile) close() error {
+	return f.file.Close()
+}
+
+func decodeFloat32s(data []byte, vector []float32) {
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+}
+
+func decodeFloat64s(data []byte, vector []float32) {
+	for i := range vector {
+		vector[i] = float32(math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:])))
+	}
+}
+
+func decodeUint8s(data []byte, vector []float32) {
+	for i := range vector {
+		vector[i] = float32(data[i])
+	}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,125 @@
+package milvus
+
+import (
+	"fmt"
+	"math"
+)
+
+// Normalize returns vectors scaled to unit length, as Milvus does for COSINE: one vector, or an
+// array of vectors. Zero vectors are returned unchanged. The input is not modified.
+func (m *Milvus) Normalize(input interface{}) (interface{}, error) {
+	if isVectorColumn(input) {
+		vectors, err := denseVectors(input)
+		if err != nil {
+			return nil, err
+		}
+		normalized := newMatrix[float32](len(vectors), len(vectors[0]))
+		for i, vector := range vectors {
+			copy(normalized[i], vector)
+			normalize(normalized[i])
+		}
+		return normalized, nil
+	}
+	vector, err := floatVector(input, "vector")
+	if err != nil {
+		return nil, err
+	}
+	normalized := append([]float32(nil), vector...)
+	normalize(normalized)
+	return normalized, nil
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors, the COSINE score of
+// Milvus; 0 when either is a zero vector
+func (m *Milvus) CosineSimilarity(a, b interface{}) (float64, error) {
+	x, y, err := vectorPair(a, b)
+	if err != nil {
+		return 0, err
+	}
+	norms := math.Sqrt(dot(x, x)) * math.Sqrt(dot(y, y))
+	if norms == 0 {
+		return 0, nil
+	}
+	return dot(x, y) / norms, nil
+}
+
+// L2Distance returns the squared Euclidean distance between two vectors, the L2 score of Milvus
+func (m *Milvus) L2Distance(a, b interface{}) (float64, error) {
+	x, y, err := vectorPair(a, b)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for i, v := range x {
+		d := float64(v) - float64(y[i])
+		sum += d * d
+	}
+	return sum, nil
+}
+
+// InnerProduct returns the dot product of two vectors, the IP score of Milvus
+func (m *Milvus) InnerProduct(a, b interface{}) (float64, error) {
+	x, y, err := vectorPair(a, b)
+	if err != nil {
+		return 0, err
+	}
+	return dot(x, y), nil
+}
+
+// vectorPair reads two vectors of the same dimension
+func vectorPair(a, b interface{}) ([]float32, []float32, error) {
+	x, err := floatVector(a, "a")
+	if err != nil {
+		return nil, nil, err
+	}
+	y, err := floatVector(b, "b")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(x) != len(y) {
+		return nil, nil, fmt.Errorf("vectors have dimensions %d and %d", len(x), len(y))
+	}
+	return x, y, nil
+}
+
+// floatVector reads one non-empty vector: an array of numbers, or a vector returned by other
+// module functions, which is not copied
+func floatVector(input interface{}, name string) ([]float32, error) {
+	switch v := input.(type) {
+	case []float32:
+		if len(v) > 0 {
+			return v, nil
+		}
+	case []float64:
+		if len(v) > 0 {
+			vector := make([]float32, len(v))
+			for i, x := range v {
+				vector[i] = float32(x)
+			}
+			return vector, nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			break
+		}
+		vector := make([]float32, len(v))
+		for i, item := range v {
+			switch x := item.(type) {
+			case float64:
+				vector[i] = float32(x)
+			case int64:
+				vector[i] = float32(x)
+			case float32:
+				vector[i] = x
+			case int:
+				vector[i] = float32(x)
+			default:
+				return nil, fmt.Errorf("%s[%d] must be a number, got %T", name, i, item)
+			}
+		}
+		return vector, nil
+	default:
+		return nil, fmt.Errorf("%s must be an array of numbers, got %T", name, input)
+	}
+	return nil, fmt.Errorf("%s must not be empty", name)
+}
@@ -0,0 +1,67 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	m := &Milvus{}
+	vector, err := m.Normalize([]interface{}{3.0, int64(4)})
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float32{0.6, 0.8}, vector, 1e-6)
+
+	input := [][]float32{{0, 2}, {0, 0}}
+	vectors, err := m.Normalize(input)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0, 1}, {0, 0}}, vectors, "zero vectors are unchanged")
+	assert.Equal(t, [][]float32{{0, 2}, {0, 0}}, input, "the input is not modified")
+
+	vectors, err = m.Normalize([]interface{}{[]interface{}{1.0, 0.0}, []interface{}{0.0, -5.0}})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, -1}}, vectors)
+
+	_, err = m.Normalize([]interface{}{})
+	assert.Error(t, err)
+	_, err = m.Normalize("vector")
+	assert.Error(t, err)
+}
+
+func TestVectorScores(t *testing.T) {
+	m := &Milvus{}
+	a := []interface{}{1.0, 2.0, 2.0}
+	b := []float32{2, 0, 1}
+
+	ip, err := m.InnerProduct(a, b)
+	require.NoError(t, err)
+	assert.InDelta(t, 4.0, ip, 1e-9)
+
+	l2, err := m.L2Distance(a, b)
+	require.NoError(t, err)
+	assert.InDelta(t, 6.0, l2, 1e-9, "squared, as Milvus L2 scores")
+
+	cosine, err := m.CosineSimilarity(a, b)
+	require.NoError(t, err)
+	assert.InDelta(t, 4/(3*2.2360679775), cosine, 1e-9)
+	cosine, err = m.CosineSimilarity([]float64{0, 0}, []float64{1, 1})
+	require.NoError(t, err)
+	assert.Zero(t, cosine)
+
+	// The scores match the brute force ground truth
+	base := [][]float32{b}
+	query, _ := floatVector(a, "a")
+	for metric, expected := range map[string]float64{"L2": l2, "IP": -ip} {
+		distance, err := bruteForceDistance(metric, base)
+		require.NoError(t, err)
+		assert.InDelta(t, expected, distance.to(distance.query(query), 0), 1e-9, metric)
+	}
+
+	_, err = m.L2Distance(a, []float32{1, 2})
+	assert.ErrorContains(t, err, "dimensions 3 and 2")
+	_, err = m.InnerProduct([]interface{}{1.0, "x"}, a)
+	assert.ErrorContains(t, err, "a[1] must be a number")
+	_, err = m.CosineSimilarity(a, nil)
+	assert.ErrorContains(t, err, "b must be an array of numbers")
+}
@@ -0,0 +1,18 @@
+package milvus
+
+// workerPool bounds the blocking calls a client runs in the background at once. Calls beyond
+// its size wait for a free worker, in the order they were made.
+type workerPool struct {
+	slots chan struct{}
+}
+
+// newWorkerPool returns a pool of size workers
+func newWorkerPool(size int) *workerPool {
+	return &workerPool{slots: make(chan struct{}, size)}
+}
+
+// acquire waits for a free worker and returns the function releasing it
+func (p *workerPool) acquire() func() {
+	p.slots <- struct{}{}
+	return func() { <-p.slots }
+}
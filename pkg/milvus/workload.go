@@ -0,0 +1,302 @@
+package milvus
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Mixed workload defaults
+const (
+	defaultWorkloadTopK        = 10
+	defaultWorkloadInsertBatch = 100
+	defaultWorkloadDeleteBatch = 10
+)
+
+// workloadOperations are the operations a mixed workload can weigh
+var workloadOperations = []string{"search", "insert", "delete"}
+
+// MixedWorkloadConfig configures milvus.mixedWorkload()
+type MixedWorkloadConfig struct {
+	Weights        map[string]float64     `json:"weights"`                  // Relative weight of search, insert and delete
+	CollectionName string                 `json:"collectionName,omitempty"` // Default: the collection of the client
+	TopK           int                    `json:"topK,omitempty"`           // Results per search (default: 10)
+	NQ             int                    `json:"nq,omitempty"`             // Query vectors per search (default: 1)
+	SearchParams   map[string]interface{} `json:"searchParams,omitempty"`   // search() params, e.g. vectorField
+	InsertBatch    int                    `json:"insertBatch,omitempty"`    // Rows per insert (default: 100)
+	DeleteBatch    int                    `json:"deleteBatch,omitempty"`    // Keys per delete (default: 10)
+	VectorField    string                 `json:"vectorField,omitempty"`    // Vector field of inserts (default: searchParams.vectorField, else "vector")
+	PKField        string                 `json:"pkField,omitempty"`        // Int64 primary key of inserts and deletes (default: "id")
+	IDStart        int64                  `json:"idStart,omitempty"`        // First primary key of inserted rows (default: 0)
+	Seed           int64                  `json:"seed,omitempty"`           // Seed of the operation draws, together with the VU ID
+}
+
+// MixedWorkload runs one operation per call, drawn by weight among search, insert and delete,
+// the most common benchmark pattern. Every metric of an operation is tagged with workload_op.
+// Inserted rows get fresh primary keys from a counter shared by all VUs, and each delete
+// removes the oldest rows its VU inserted, so deletes always hit live rows. Until a VU has
+// inserted rows, delete is left out of its draws.
+//
+// Usage in k6:
+//
+//	const workload = milvus.mixedWorkload({
+//	    weights: { search: 80, insert: 15, delete: 5 },
+//	    queries: ds, data: gen, searchParams: { vectorField: 'embedding' }, idStart: 1e9,
+//	});
+//	export default function () {
+//	    const client = milvus.getClient('localhost:19530', 'bench');
+//	    const result = workload.run(client);
+//	    check(result, { [`${result.operation} ok`]: (r) => r.success });
+//	}
+type MixedWorkload struct {
+	config   MixedWorkloadConfig
+	queries  interface{}
+	data     interface{}
+	scalars  *DataFaker
+	shared   *sync.Map
+	stream   seedStream
+	ops      []string  // Operations of positive weight, in workloadOperations order
+	weights  []float64 // Weight of each op
+	inserted []idRange // Keys inserted by this workload and not yet deleted, oldest first
+}
+
+// idRange is a run of consecutive primary keys
+type idRange struct {
+	start, count int64
+}
+
+// MixedWorkload creates a mixed read/write workload runner
+func (m *Milvus) MixedWorkload(configInput map[string]interface{}) (*MixedWorkload, error) {
+	var config MixedWorkloadConfig
+	if err := convertViaJSON(splitModuleObjects(configInput, "queries", "data", "scalars"), &config); err != nil {
+		return nil, fmt.Errorf("invalid mixed workload config: %v", err)
+	}
+	w := &MixedWorkload{config: config, shared: m.shared, stream: newSeedStream(m.vu, config.Seed, false)}
+	if err := w.configure(configInput); err != nil {
+		return nil, fmt.Errorf("mixed workload: %v", err)
+	}
+	return w, nil
+}
+
+// configure checks the config, fills in its defaults and reads the module objects
+func (w *MixedWorkload) configure(configInput map[string]interface{}) error {
+	names := make([]string, 0, len(w.config.Weights))
+	for name := range w.config.Weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		weight := w.config.Weights[name]
+		if !slices.Contains(workloadOperations, name) {
+			return fmt.Errorf("unknown operation %q in weights: expected %s", name, strings.Join(workloadOperations, ", "))
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight of %s must not be negative, got %v", name, weight)
+		}
+	}
+	for _, name := range workloadOperations {
+		if weight := w.config.Weights[name]; weight > 0 {
+			w.ops = append(w.ops, name)
+			w.weights = append(w.weights, weight)
+		}
+	}
+	if len(w.ops) == 0 {
+		return fmt.Errorf("weights must give a positive weight to search, insert or delete")
+	}
+
+	if w.config.TopK < 0 || w.config.NQ < 0 || w.config.InsertBatch < 0 || w.config.DeleteBatch < 0 {
+		return fmt.Errorf("topK, nq, insertBatch and deleteBatch must not be negative")
+	}
+	w.config.TopK = optionalPositive(w.config.TopK, defaultWorkloadTopK)
+	w.config.NQ = optionalPositive(w.config.NQ, 1)
+	w.config.InsertBatch = optionalPositive(w.config.InsertBatch, defaultWorkloadInsertBatch)
+	w.config.DeleteBatch = optionalPositive(w.config.DeleteBatch, defaultWorkloadDeleteBatch)
+	if w.config.SearchParams == nil {
+		w.config.SearchParams = map[string]interface{}{}
+	}
+	if w.config.VectorField == "" {
+		w.config.VectorField, _ = stringOption(w.config.SearchParams, "vectorField")
+	}
+	if w.config.VectorField == "" {
+		w.config.VectorField = "vector"
+	}
+	if w.config.PKField == "" {
+		w.config.PKField = "id"
+	}
+
+	w.queries = configInput["queries"]
+	w.data = configInput["data"]
+	if w.data == nil {
+		w.data = w.queries
+	}
+	if w.config.Weights["search"] > 0 && !isVectorSource(w.queries) {
+		return fmt.Errorf("queries must be a vectorGenerator, annDataset or sharedVectors, got %T", w.queries)
+	}
+	if w.config.Weights["insert"] > 0 && !isVectorSource(w.data) {
+		return fmt.Errorf("data must be a vectorGenerator, annDataset or sharedVectors, got %T", w.data)
+	}
+	if value, ok := configInput["scalars"]; ok && value != nil {
+		if w.scalars, ok = value.(*DataFaker); !ok {
+			return fmt.Errorf("scalars must be a milvus.dataFaker() object, got %T", value)
+		}
+	}
+	return nil
+}
+
+func optionalPositive(value, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// Run draws one operation by weight and runs it with the client. The result is that of the
+// operation, with its name in "operation".
+func (w *MixedWorkload) Run(client *Client) interface{} {
+	if client == nil {
+		return toMap(&OperationResult{Success: false, Error: "run requires a client"})
+	}
+	coll := client.getCollectionName(w.config.CollectionName)
+	op := w.draw(w.stream.rand())
+	if op == "" {
+		result := toMap(&OperationResult{Success: false, Error: "no inserted rows to delete"})
+		result["operation"] = "delete"
+		return result
+	}
+
+	tagged := client.withTags(map[string]string{"workload_op": op})
+	var result map[string]interface{}
+	switch op {
+	case "search":
+		result = w.search(tagged, coll)
+	case "insert":
+		result = w.insert(tagged, coll)
+	case "delete":
+		result = w.delete(tagged, coll)
+	}
+	result["operation"] = op
+	return result
+}
+
+// draw picks an operation by weight, leaving delete out while there is nothing to delete
+func (w *MixedWorkload) draw(rng *rand.Rand) string {
+	total := 0.0
+	for i, op := range w.ops {
+		if op != "delete" || len(w.inserted) > 0 {
+			total += w.weights[i]
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	pick := rng.Float64() * total
+	last := ""
+	for i, op := range w.ops {
+		if op == "delete" && len(w.inserted) == 0 {
+			continue
+		}
+		last = op
+		if pick -= w.weights[i]; pick < 0 {
+			return op
+		}
+	}
+	return last
+}
+
+func (w *MixedWorkload) search(client *Client, coll string) map[string]interface{} {
+	vectors, err := workloadVectors(w.queries, w.stream.rand(), w.config.NQ, true)
+	if err != nil {
+		return toMap(&OperationResult{Success: false, Error: err.Error()})
+	}
+	return client.Search(vectors, w.config.TopK, w.config.SearchParams, coll).(map[string]interface{})
+}
+
+func (w *MixedWorkload) insert(client *Client, coll string) map[string]interface{} {
+	count := w.config.InsertBatch
+	vectors, err := workloadVectors(w.data, w.stream.rand(), count, false)
+	if err != nil {
+		return toMap(&OperationResult{Success: false, Error: err.Error()})
+	}
+	data := map[string]interface{}{w.config.VectorField: vectors}
+	if w.scalars != nil {
+		values, err := w.scalars.Next(count)
+		if err != nil {
+			return toMap(&OperationResult{Success: false, Error: err.Error()})
+		}
+		for field, column := range values {
+			data[field] = column
+		}
+	}
+
+	// All VUs draw keys from the counter of client.prepareBatch() newIds, so keys never repeat
+	ids, err := idCounter(w.shared, coll, w.config.PKField, w.config.IDStart)
+	if err != nil {
+		return toMap(&OperationResult{Success: false, Error: err.Error()})
+	}
+	first := ids.Add(int64(count)) - int64(count)
+	data[w.config.PKField] = rowIndices(int(first), int(first)+count)
+
+	result := client.Insert(data, coll).(map[string]interface{})
+	if result["success"] == true {
+		w.inserted = append(w.inserted, idRange{start: first, count: int64(count)})
+	}
+	return result
+}
+
+func (w *MixedWorkload) delete(client *Client, coll string) map[string]interface{} {
+	ids := w.takeOldest(w.config.DeleteBatch)
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = strconv.FormatInt(id, 10)
+	}
+	filter := fmt.Sprintf("%s in [%s]", w.config.PKField, strings.Join(keys, ","))
+	return client.Delete(filter, coll).(map[string]interface{})
+}
+
+// takeOldest removes and returns up to n of the oldest inserted keys
+func (w *MixedWorkload) takeOldest(n int) []int64 {
+	var ids []int64
+	for len(ids) < n && len(w.inserted) > 0 {
+		r := &w.inserted[0]
+		take := min(int64(n-len(ids)), r.count)
+		for i := int64(0); i < take; i++ {
+			ids = append(ids, r.start+i)
+		}
+		r.start += take
+		r.count -= take
+		if r.count == 0 {
+			w.inserted = w.inserted[1:]
+		}
+	}
+	return ids
+}
+
+// workloadVectors draws count vectors from a source: the next vectors of a generator, or
+// consecutive rows of a dataset from a random offset. Searches read the test queries of an
+// annDataset and inserts its train vectors.
+func workloadVectors(source interface{}, rng *rand.Rand, count int, queries bool) ([][]float32, error) {
+	switch s := source.(type) {
+	case *VectorGenerator:
+		return s.Next(count)
+	case *AnnDataset:
+		if queries {
+			return s.Test(randomOffset(rng, s.TestSize(), count), count)
+		}
+		return s.Train(randomOffset(rng, s.TrainSize(), count), count)
+	case *SharedVectors:
+		return s.Vectors(randomOffset(rng, s.Size(), count), count)
+	}
+	return nil, fmt.Errorf("no vector source")
+}
+
+// randomOffset returns a random offset of count rows within size rows, or 0 when they do not fit
+func randomOffset(rng *rand.Rand, size, count int) int {
+	if size <= count {
+		return 0
+	}
+	return rng.Intn(size - count + 1)
+}
@@ -0,0 +1,93 @@
+package milvus
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v3/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v3/schemapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// workloadServer serves searches, inserts and deletes, recording the expression of each delete
+type workloadServer struct {
+	searchServer
+	deletes []string
+}
+
+func (s *workloadServer) Delete(_ context.Context, req *milvuspb.DeleteRequest) (*milvuspb.MutationResult, error) {
+	s.mu.Lock()
+	s.deletes = append(s.deletes, req.GetExpr())
+	s.mu.Unlock()
+	return &milvuspb.MutationResult{Status: &commonpb.Status{}, DeleteCnt: 1, IDs: &schemapb.IDs{}}, nil
+}
+
+func TestMixedWorkload(t *testing.T) {
+	vu, samples := newMetricsVU(t)
+	vu.state.VUID = 1
+	service := &workloadServer{}
+	client := benchClient(t, service, vu)
+
+	m := &Milvus{vu: vu, shared: &sync.Map{}}
+	gen, err := m.VectorGenerator(map[string]interface{}{"dim": 2})
+	require.NoError(t, err)
+	workload, err := m.MixedWorkload(map[string]interface{}{
+		"weights":      map[string]interface{}{"search": 2, "insert": 1, "delete": 1},
+		"queries":      gen,
+		"searchParams": map[string]interface{}{"vectorField": "embedding"},
+		"insertBatch":  4,
+		"deleteBatch":  3,
+		"idStart":      100,
+		"seed":         7,
+	})
+	require.NoError(t, err)
+
+	counts := map[string]int{}
+	for i := 0; i < 40; i++ {
+		result := workload.Run(client).(map[string]interface{})
+		require.Equal(t, true, result["success"], result["error"])
+		counts[result["operation"].(string)]++
+	}
+	assert.Greater(t, counts["search"], counts["insert"])
+	assert.Positive(t, counts["insert"])
+	assert.Positive(t, counts["delete"])
+	assert.Equal(t, [][]int64{{100, 101, 102, 103}}, service.batches[:1], "fresh keys from idStart")
+	require.NotEmpty(t, service.deletes)
+	assert.Equal(t, "id in [100,101,102]", service.deletes[0], "the oldest inserted rows are deleted first")
+
+	ops := map[string]bool{}
+	for _, sample := range drainSamples(samples) {
+		if op, ok := sample.Tags.Get("workload_op"); ok {
+			ops[op] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"search": true, "insert": true, "delete": true}, ops)
+}
+
+func TestMixedWorkloadDraw(t *testing.T) {
+	m := &Milvus{shared: &sync.Map{}}
+	vectors := &SharedVectors{vectors: [][]float32{{1, 0}}}
+	workload, err := m.MixedWorkload(map[string]interface{}{"weights": map[string]interface{}{"delete": 1}, "queries": vectors})
+	require.NoError(t, err)
+	rng := workload.stream.rand()
+	assert.Empty(t, workload.draw(rng), "nothing to delete yet")
+
+	workload.inserted = []idRange{{start: 10, count: 2}, {start: 20, count: 5}}
+	assert.Equal(t, "delete", workload.draw(rng))
+	assert.Equal(t, []int64{10, 11, 20}, workload.takeOldest(3))
+	assert.Equal(t, []idRange{{start: 21, count: 4}}, workload.inserted)
+
+	for _, config := range []map[string]interface{}{
+		{"weights": map[string]interface{}{"upsert": 1}, "queries": vectors},
+		{"weights": map[string]interface{}{"search": -1}, "queries": vectors},
+		{"weights": map[string]interface{}{}},
+		{"weights": map[string]interface{}{"search": 1}},
+		{"weights": map[string]interface{}{"insert": 1}, "queries": vectors, "scalars": "faker"},
+	} {
+		_, err := m.MixedWorkload(config)
+		assert.Error(t, err, config)
+	}
+}
@@ -0,0 +1,139 @@
+package milvus
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Zipf generator limits and defaults
+const (
+	defaultZipfSkew = 1.0
+	maxZipfValues   = 100_000_000 // 800 MB of cumulative weights
+)
+
+// ZipfGeneratorConfig configures milvus.zipfGenerator()
+type ZipfGeneratorConfig struct {
+	N    int      `json:"n"`              // Number of distinct values, 0 to n-1
+	Skew *float64 `json:"skew,omitempty"` // Zipf exponent: 0 is uniform, higher is more skewed (default: 1)
+	Seed int64    `json:"seed,omitempty"` // Seed of the values, together with the VU ID
+	// Restart the value stream at every iteration, from the seed, VU ID and iteration
+	PerIteration bool `json:"perIteration,omitempty"`
+}
+
+// ZipfGenerator draws integers in [0, n) with Zipfian popularity: value k is drawn with
+// probability proportional to 1/(k+1)^skew, so a few values are hot and most are cold. Use it
+// for query IDs, tenants or partition keys to simulate hot-key access patterns.
+//
+// Usage in k6:
+//
+//	const tenants = milvus.zipfGenerator({ n: 1000, skew: 1.1, seed: 7 });
+//	export default function () {
+//	    const tenant = tenants.next(1)[0];
+//	    client.search(gen.next(1), 10, { vectorField: 'embedding', filter: `tenant_id == ${tenant}` });
+//	}
+type ZipfGenerator struct {
+	table  *zipfTable
+	stream seedStream
+}
+
+// zipfTable holds the cumulative weights of a Zipf distribution. It is read-only, so one
+// table is shared by all VUs.
+type zipfTable struct {
+	cumulative []float64
+}
+
+// memorySize returns the bytes of the cumulative weights
+func (t *zipfTable) memorySize() int64 {
+	return 8 * int64(len(t.cumulative))
+}
+
+// ZipfGenerator creates a Zipfian integer generator
+func (m *Milvus) ZipfGenerator(configInput interface{}) (*ZipfGenerator, error) {
+	var config ZipfGeneratorConfig
+	if err := convertViaJSON(configInput, &config); err != nil {
+		return nil, fmt.Errorf("invalid zipf generator config: %v", err)
+	}
+	table, err := zipfTableFor(m.shared, config.N, optionalFloat(config.Skew, defaultZipfSkew))
+	if err != nil {
+		return nil, fmt.Errorf("zipf generator: %v", err)
+	}
+	return &ZipfGenerator{table: table, stream: newSeedStream(m.vu, config.Seed, config.PerIteration)}, nil
+}
+
+// zipfTableFor returns the table of n values and skew, computed once per test
+func zipfTableFor(shared *sync.Map, n int, skew float64) (*zipfTable, error) {
+	if n <= 0 || n > maxZipfValues {
+		return nil, fmt.Errorf("n must be between 1 and %d, got %d", maxZipfValues, n)
+	}
+	if skew < 0 || math.IsNaN(skew) || math.IsInf(skew, 0) {
+		return nil, fmt.Errorf("skew must not be negative, got %v", skew)
+	}
+	key := "zipf\x00" + strconv.Itoa(n) + "\x00" + strconv.FormatFloat(skew, 'g', -1, 64)
+	return sharedValue(shared, key, func() (*zipfTable, error) {
+		cumulative := make([]float64, n)
+		total := 0.0
+		for k := range cumulative {
+			total += math.Pow(float64(k+1), -skew)
+			cumulative[k] = total
+		}
+		return &zipfTable{cumulative: cumulative}, nil
+	})
+}
+
+// sample draws a value rank, 0 being the most popular
+func (z *zipfTable) sample(rng *rand.Rand) int {
+	return weightedIndex(z.cumulative, rng)
+}
+
+// probability returns the probability of rank k
+func (z *zipfTable) probability(k int) float64 {
+	if k < 0 || k >= len(z.cumulative) {
+		return 0
+	}
+	weight := z.cumulative[k]
+	if k > 0 {
+		weight -= z.cumulative[k-1]
+	}
+	return weight / z.cumulative[len(z.cumulative)-1]
+}
+
+// weightedIndex draws an index with probability proportional to its weight, given the
+// cumulative weights. Zero weights are never drawn.
+func weightedIndex(cumulative []float64, rng *rand.Rand) int {
+	target := rng.Float64() * cumulative[len(cumulative)-1]
+	return sort.Search(len(cumulative)-1, func(i int) bool { return cumulative[i] > target })
+}
+
+// Next returns count values in [0, n). Each VU gets its own value stream, derived from the
+// seed and the VU ID, while all VUs share the same hot values.
+func (g *ZipfGenerator) Next(count int) ([]int64, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", count)
+	}
+	rng := g.stream.rand()
+	values := make([]int64, count)
+	for i := range values {
+		values[i] = int64(g.table.sample(rng))
+	}
+	return values, nil
+}
+
+// Replay restarts the value stream of a VU, or of one of its iterations with perIteration
+func (g *ZipfGenerator) Replay(vuID int64, iteration ...int64) error {
+	return g.stream.replay(vuID, iteration)
+}
+
+// Probability returns the probability of drawing value k, e.g. to estimate the share of
+// requests that hit the hottest keys
+func (g *ZipfGenerator) Probability(k int) float64 {
+	return g.table.probability(k)
+}
+
+// N returns the number of distinct values
+func (g *ZipfGenerator) N() int {
+	return len(g.table.cumulative)
+}
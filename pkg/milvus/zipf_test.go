@@ -0,0 +1,107 @@
+package milvus
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/lib"
+)
+
+func TestZipfGenerator(t *testing.T) {
+	m := &Milvus{vu: &metricsVU{state: &lib.State{VUID: 1}}, shared: &sync.Map{}}
+	gen, err := m.ZipfGenerator(map[string]interface{}{"n": 100, "seed": 5})
+	require.NoError(t, err)
+	assert.Equal(t, 100, gen.N())
+
+	// With skew 1, P(k) = 1/(k+1) / H(100), and H(100) is about 5.187
+	assert.InDelta(t, 1/5.187, gen.Probability(0), 1e-3)
+	assert.InDelta(t, gen.Probability(0)/2, gen.Probability(1), 1e-9)
+	assert.Zero(t, gen.Probability(100))
+
+	const n = 20000
+	values, err := gen.Next(n)
+	require.NoError(t, err)
+	counts := make([]int, 100)
+	for _, v := range values {
+		require.True(t, v >= 0 && v < 100, v)
+		counts[v]++
+	}
+	assert.InDelta(t, gen.Probability(0), float64(counts[0])/n, 0.015)
+	assert.Greater(t, counts[0], counts[9])
+
+	// The table is shared; the stream depends on the VU
+	other, err := (&Milvus{vu: &metricsVU{state: &lib.State{VUID: 2}}, shared: m.shared}).ZipfGenerator(map[string]interface{}{"n": 100, "seed": 5})
+	require.NoError(t, err)
+	assert.Same(t, gen.table, other.table)
+	otherValues, err := other.Next(50)
+	require.NoError(t, err)
+	assert.NotEqual(t, values[:50], otherValues)
+
+	// Skew 0 is uniform
+	uniform, err := m.ZipfGenerator(map[string]interface{}{"n": 4, "skew": 0})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.25, uniform.Probability(3), 1e-9)
+}
+
+func TestZipfGeneratorInvalidConfig(t *testing.T) {
+	m := &Milvus{}
+	for _, config := range []map[string]interface{}{
+		{},
+		{"n": -1},
+		{"n": maxZipfValues + 1},
+		{"n": 10, "skew": -0.5},
+		{"n": "ten"},
+	} {
+		_, err := m.ZipfGenerator(config)
+		assert.Error(t, err, config)
+	}
+	gen, err := m.ZipfGenerator(map[string]interface{}{"n": 1})
+	require.NoError(t, err)
+	_, err = gen.Next(-1)
+	assert.Error(t, err)
+}
+
+func TestDataFakerZipf(t *testing.T) {
+	faker, err := newDataFaker(nil, nil, DataFakerConfig{Fields: map[string]FakerFieldConfig{
+		"tenant":  {Type: "varchar", Values: []interface{}{"hot", "warm", "cold"}, Distribution: "zipf", Skew: ptr(2.0)},
+		"user_id": {Type: "int32", Min: ptr(1000.0), Max: ptr(1999.0), Distribution: "zipf"},
+	}})
+	require.NoError(t, err)
+	batch, err := faker.Next(10000)
+	require.NoError(t, err)
+
+	// P(hot) = 1 / (1 + 1/4 + 1/9)
+	hot := 0
+	for _, tenant := range batch["tenant"].([]string) {
+		if tenant == "hot" {
+			hot++
+		}
+	}
+	assert.InDelta(t, 1/(1+0.25+1.0/9), float64(hot)/10000, 0.02)
+
+	lowest := 0
+	for _, id := range batch["user_id"].([]int32) {
+		require.True(t, id >= 1000 && id <= 1999, id)
+		if id == 1000 {
+			lowest++
+		}
+	}
+	assert.Greater(t, lowest, 1000) // About 13% with skew 1 over 1000 values
+
+	for _, field := range []FakerFieldConfig{
+		{Type: "double", Distribution: "zipf"},
+		{Type: "varchar", Values: []interface{}{"a"}, Weights: []float64{1}, Distribution: "zipf"},
+		{Type: "int64", Distribution: "pareto"},
+		{Type: "int64", Skew: ptr(1.0)},
+		{Type: "int64", Min: ptr(0.0), Max: ptr(2e9), Distribution: "zipf"},
+	} {
+		_, err := newDataFaker(nil, nil, DataFakerConfig{Fields: map[string]FakerFieldConfig{"x": field}})
+		assert.Error(t, err, field)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}